@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Telemetria de uso das secret keys
+ALTER TABLE secret_keys ADD COLUMN last_used_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE secret_keys ADD COLUMN last_used_ip VARCHAR(45) DEFAULT NULL;
+ALTER TABLE secret_keys ADD COLUMN last_used_agent VARCHAR(255) DEFAULT NULL;
+ALTER TABLE secret_keys ADD COLUMN usage_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_secret_keys_last_used_at ON secret_keys(last_used_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_secret_keys_last_used_at;
+
+ALTER TABLE secret_keys DROP COLUMN usage_count;
+ALTER TABLE secret_keys DROP COLUMN last_used_agent;
+ALTER TABLE secret_keys DROP COLUMN last_used_ip;
+ALTER TABLE secret_keys DROP COLUMN last_used_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Métricas de avaliação de toggles agregadas por bucket de tempo
+CREATE TABLE toggle_metrics (
+    id VARCHAR(26) PRIMARY KEY,
+    app_id VARCHAR(26) NOT NULL,
+    toggle_id VARCHAR(26) NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    true_count INTEGER NOT NULL DEFAULT 0,
+    false_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (app_id) REFERENCES applications(id) ON DELETE CASCADE,
+    FOREIGN KEY (toggle_id) REFERENCES toggles(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_toggle_metrics_bucket ON toggle_metrics(toggle_id, bucket_start);
+CREATE INDEX idx_toggle_metrics_app_id ON toggle_metrics(app_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_toggle_metrics_app_id;
+DROP INDEX IF EXISTS idx_toggle_metrics_bucket;
+DROP TABLE IF EXISTS toggle_metrics;
+
+-- +goose StatementEnd
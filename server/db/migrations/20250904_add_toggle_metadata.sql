@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Metadados dos toggles: descrição, time responsável, tags, tipo e data prevista de remoção
+ALTER TABLE toggles ADD COLUMN description TEXT DEFAULT '';
+ALTER TABLE toggles ADD COLUMN owner VARCHAR(100) DEFAULT '';
+ALTER TABLE toggles ADD COLUMN tags TEXT DEFAULT '[]';
+ALTER TABLE toggles ADD COLUMN kind VARCHAR(20) DEFAULT 'release';
+ALTER TABLE toggles ADD COLUMN expires_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX idx_toggles_owner ON toggles(owner);
+CREATE INDEX idx_toggles_kind ON toggles(kind);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_toggles_kind;
+DROP INDEX IF EXISTS idx_toggles_owner;
+ALTER TABLE toggles DROP COLUMN expires_at;
+ALTER TABLE toggles DROP COLUMN kind;
+ALTER TABLE toggles DROP COLUMN tags;
+ALTER TABLE toggles DROP COLUMN owner;
+ALTER TABLE toggles DROP COLUMN description;
+
+-- +goose StatementEnd
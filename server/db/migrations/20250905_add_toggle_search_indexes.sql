@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Índices para as consultas por caminho e hierarquia. A busca de toggles compara por
+-- substring, que não usa índices B-tree; ela é atendida pelo índice FTS5 da migração 20250915.
+CREATE INDEX idx_toggles_app_path ON toggles(app_id, path);
+CREATE INDEX idx_toggles_parent_id ON toggles(parent_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_toggles_parent_id;
+DROP INDEX IF EXISTS idx_toggles_app_path;
+
+-- +goose StatementEnd
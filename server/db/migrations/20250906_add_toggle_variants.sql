@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Variantes dos toggles (array JSON com nome, tipo de payload, payload e peso) e variante selecionada pela regra
+ALTER TABLE toggles ADD COLUMN variants TEXT DEFAULT '[]';
+ALTER TABLE toggles ADD COLUMN rule_variant VARCHAR(100) DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE toggles DROP COLUMN rule_variant;
+ALTER TABLE toggles DROP COLUMN variants;
+
+-- +goose StatementEnd
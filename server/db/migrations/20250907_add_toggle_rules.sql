@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Regras compostas dos toggles: regras ordenadas (OR) com condições em array JSON (AND)
+CREATE TABLE toggle_rules (
+    id VARCHAR(26) PRIMARY KEY,
+    toggle_id VARCHAR(26) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    conditions TEXT NOT NULL DEFAULT '[]',
+    variant VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (toggle_id) REFERENCES toggles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_toggle_rules_toggle_id ON toggle_rules(toggle_id);
+
+-- Converte a regra de ativação simples de cada toggle em uma regra com uma única condição.
+-- As colunas rule_* são mantidas como espelho para SDKs que ainda não conhecem as regras compostas.
+INSERT INTO toggle_rules (id, toggle_id, position, conditions, variant)
+SELECT
+    upper(hex(randomblob(13))),
+    id,
+    0,
+    json_array(
+        CASE
+            WHEN rule_config IS NULL OR rule_config = '' THEN json_object('type', rule_type, 'value', COALESCE(rule_value, ''))
+            ELSE json_object('type', rule_type, 'value', COALESCE(rule_value, ''), 'config', json(rule_config))
+        END
+    ),
+    COALESCE(rule_variant, '')
+FROM toggles
+WHERE has_activation_rule = 1 AND rule_type IS NOT NULL AND rule_type != '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_toggle_rules_toggle_id;
+DROP TABLE IF EXISTS toggle_rules;
+
+-- +goose StatementEnd
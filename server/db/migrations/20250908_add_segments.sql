@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Segmentos reutilizáveis referenciados pelas regras do tipo segment.
+-- Segmentos com app_id nulo são globais e ficam disponíveis para todas as aplicações.
+CREATE TABLE segments (
+    id VARCHAR(26) PRIMARY KEY,
+    app_id VARCHAR(26),
+    name VARCHAR(100) NOT NULL,
+    description TEXT DEFAULT '',
+    constraints TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (app_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_segments_app_id ON segments(app_id);
+CREATE UNIQUE INDEX idx_segments_app_name ON segments(COALESCE(app_id, ''), name);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_segments_app_name;
+DROP INDEX IF EXISTS idx_segments_app_id;
+DROP TABLE IF EXISTS segments;
+
+-- +goose StatementEnd
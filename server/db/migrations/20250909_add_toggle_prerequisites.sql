@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Pré-requisitos de um toggle: outros toggles da mesma aplicação que precisam
+-- estar no estado indicado por enabled para que o toggle seja avaliado.
+-- Um toggle exigido por outro não pode ser removido definitivamente enquanto o
+-- pré-requisito existir. A remoção comum só o move para a lixeira.
+CREATE TABLE toggle_prerequisites (
+    id VARCHAR(26) PRIMARY KEY,
+    toggle_id VARCHAR(26) NOT NULL,
+    prerequisite_id VARCHAR(26) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (toggle_id) REFERENCES toggles(id) ON DELETE CASCADE,
+    FOREIGN KEY (prerequisite_id) REFERENCES toggles(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_toggle_prerequisites_toggle_id ON toggle_prerequisites(toggle_id);
+CREATE INDEX idx_toggle_prerequisites_prerequisite_id ON toggle_prerequisites(prerequisite_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_toggle_prerequisites_prerequisite_id;
+DROP INDEX IF EXISTS idx_toggle_prerequisites_toggle_id;
+DROP TABLE IF EXISTS toggle_prerequisites;
+
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Revisões imutáveis dos toggles, gravadas a cada alteração. Não há chave estrangeira
+-- para toggles: o histórico continua disponível depois que o toggle é removido.
+CREATE TABLE toggle_revisions (
+    id VARCHAR(26) PRIMARY KEY,
+    toggle_id VARCHAR(26) NOT NULL,
+    app_id VARCHAR(26) NOT NULL,
+    revision INTEGER NOT NULL,
+    path VARCHAR(1000) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    state TEXT NOT NULL DEFAULT '{}',
+    restored_from INTEGER,
+    actor_id VARCHAR(26) DEFAULT '',
+    actor VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (app_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_toggle_revisions_toggle_revision ON toggle_revisions(toggle_id, revision);
+CREATE INDEX idx_toggle_revisions_app_id ON toggle_revisions(app_id);
+
+-- Snapshots de todos os toggles de uma aplicação, restaurados em uma única transação
+CREATE TABLE application_snapshots (
+    id VARCHAR(26) PRIMARY KEY,
+    app_id VARCHAR(26) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    toggles TEXT NOT NULL DEFAULT '[]',
+    actor_id VARCHAR(26) DEFAULT '',
+    actor VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (app_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_application_snapshots_app_id ON application_snapshots(app_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_application_snapshots_app_id;
+DROP TABLE IF EXISTS application_snapshots;
+DROP INDEX IF EXISTS idx_toggle_revisions_app_id;
+DROP INDEX IF EXISTS idx_toggle_revisions_toggle_revision;
+DROP TABLE IF EXISTS toggle_revisions;
+
+-- +goose StatementEnd
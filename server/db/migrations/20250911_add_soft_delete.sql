@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Remoção lógica de toggles e aplicações: os registros removidos ficam na lixeira
+-- até serem restaurados ou expurgados depois do período de retenção
+ALTER TABLE toggles ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE applications ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX idx_toggles_deleted_at ON toggles(deleted_at);
+CREATE INDEX idx_applications_deleted_at ON applications(deleted_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_applications_deleted_at;
+DROP INDEX IF EXISTS idx_toggles_deleted_at;
+ALTER TABLE applications DROP COLUMN deleted_at;
+ALTER TABLE toggles DROP COLUMN deleted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Kill switch da aplicação: força todos os toggles a desligados sem alterar o estado de cada um
+ALTER TABLE applications ADD COLUMN kill_switch BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE applications ADD COLUMN kill_switch_at TIMESTAMP;
+
+-- Eventos de auditoria das ações sobre a aplicação que não pertencem a um único toggle
+CREATE TABLE audit_events (
+    id VARCHAR(26) PRIMARY KEY,
+    app_id VARCHAR(26) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    detail TEXT DEFAULT '',
+    actor_id VARCHAR(26) DEFAULT '',
+    actor VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (app_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_audit_events_app_id ON audit_events(app_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_audit_events_app_id;
+DROP TABLE IF EXISTS audit_events;
+ALTER TABLE applications DROP COLUMN kill_switch_at;
+ALTER TABLE applications DROP COLUMN kill_switch;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Janelas de congelamento: períodos em que os toggles não podem ser alterados.
+-- app_id nulo indica uma janela global, válida para todas as aplicações.
+CREATE TABLE freeze_windows (
+    id VARCHAR(26) PRIMARY KEY,
+    app_id VARCHAR(26),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    exempt_users TEXT NOT NULL DEFAULT '[]',
+    actor_id VARCHAR(26) DEFAULT '',
+    actor VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (app_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_freeze_windows_app_id ON freeze_windows(app_id);
+CREATE INDEX idx_freeze_windows_period ON freeze_windows(starts_at, ends_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_freeze_windows_period;
+DROP INDEX IF EXISTS idx_freeze_windows_app_id;
+DROP TABLE IF EXISTS freeze_windows;
+
+-- +goose StatementEnd
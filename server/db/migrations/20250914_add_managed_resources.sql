@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Recursos definidos pela sincronização declarativa a partir de um arquivo de flags
+ALTER TABLE applications ADD COLUMN managed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE segments ADD COLUMN managed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE toggles ADD COLUMN managed BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE toggles DROP COLUMN managed;
+ALTER TABLE segments DROP COLUMN managed;
+ALTER TABLE applications DROP COLUMN managed;
+
+-- +goose StatementEnd
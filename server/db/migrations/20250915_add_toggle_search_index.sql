@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Índice da busca de toggles: uma tabela FTS5 com o tokenizador trigram, que atende buscas por
+-- substring. Cada toggle tem um documento com o caminho, a descrição e os valores das regras, e
+-- os triggers abaixo mantêm o índice em dia. O servidor precisa ser compilado com a build tag
+-- sqlite_fts5 para gravar nos toggles depois desta migração.
+CREATE VIRTUAL TABLE toggle_search USING fts5(toggle_id, document, tokenize = 'trigram');
+
+-- Texto pesquisável de cada toggle
+CREATE VIEW toggle_search_documents AS
+SELECT toggles.id AS toggle_id,
+	toggles.path || char(10) || COALESCE(toggles.description, '') || char(10) || COALESCE(toggles.rule_value, '') || char(10) ||
+		COALESCE((SELECT group_concat(json_extract(condition.value, '$.value'), char(10))
+			FROM toggle_rules, json_each(toggle_rules.conditions) AS condition
+			WHERE toggle_rules.toggle_id = toggles.id), '') AS document
+FROM toggles;
+
+-- Inserir um ID nesta view reindexa o toggle; um toggle que não existe mais apenas sai do índice.
+-- O documento antigo é encontrado pelo próprio índice: os IDs são ULIDs, com mais de três caracteres.
+CREATE VIEW toggle_search_refresh AS SELECT id AS toggle_id FROM toggles WHERE 0;
+
+CREATE TRIGGER toggle_search_refresh_insert INSTEAD OF INSERT ON toggle_search_refresh
+BEGIN
+	DELETE FROM toggle_search WHERE toggle_id MATCH '"' || NEW.toggle_id || '"' AND toggle_id = NEW.toggle_id;
+	INSERT INTO toggle_search (toggle_id, document)
+	SELECT toggle_id, document FROM toggle_search_documents WHERE toggle_id = NEW.toggle_id;
+END;
+
+CREATE TRIGGER toggles_search_insert AFTER INSERT ON toggles
+BEGIN
+	INSERT INTO toggle_search_refresh (toggle_id) VALUES (NEW.id);
+END;
+
+CREATE TRIGGER toggles_search_update AFTER UPDATE OF path, description, rule_value ON toggles
+BEGIN
+	INSERT INTO toggle_search_refresh (toggle_id) VALUES (NEW.id);
+END;
+
+CREATE TRIGGER toggles_search_delete AFTER DELETE ON toggles
+BEGIN
+	INSERT INTO toggle_search_refresh (toggle_id) VALUES (OLD.id);
+END;
+
+CREATE TRIGGER toggle_rules_search_insert AFTER INSERT ON toggle_rules
+BEGIN
+	INSERT INTO toggle_search_refresh (toggle_id) VALUES (NEW.toggle_id);
+END;
+
+CREATE TRIGGER toggle_rules_search_update AFTER UPDATE OF toggle_id, conditions ON toggle_rules
+BEGIN
+	INSERT INTO toggle_search_refresh (toggle_id) VALUES (NEW.toggle_id);
+	INSERT INTO toggle_search_refresh (toggle_id) SELECT OLD.toggle_id WHERE OLD.toggle_id <> NEW.toggle_id;
+END;
+
+CREATE TRIGGER toggle_rules_search_delete AFTER DELETE ON toggle_rules
+BEGIN
+	INSERT INTO toggle_search_refresh (toggle_id) VALUES (OLD.toggle_id);
+END;
+
+-- Indexa os toggles existentes
+INSERT INTO toggle_search (toggle_id, document) SELECT toggle_id, document FROM toggle_search_documents;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS toggle_rules_search_delete;
+DROP TRIGGER IF EXISTS toggle_rules_search_update;
+DROP TRIGGER IF EXISTS toggle_rules_search_insert;
+DROP TRIGGER IF EXISTS toggles_search_delete;
+DROP TRIGGER IF EXISTS toggles_search_update;
+DROP TRIGGER IF EXISTS toggles_search_insert;
+DROP TRIGGER IF EXISTS toggle_search_refresh_insert;
+DROP VIEW IF EXISTS toggle_search_refresh;
+DROP VIEW IF EXISTS toggle_search_documents;
+DROP TABLE IF EXISTS toggle_search;
+
+-- +goose StatementEnd
@@ -0,0 +1,85 @@
+// Package apitest sobe o servidor completo sobre um banco SQLite temporário para os testes
+// dos clientes da API (CLI, SDK, provider OpenFeature e relay).
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/handler"
+	"github.com/manorfm/totoogle/internal/app/router"
+)
+
+// Server é o servidor de teste com acesso ao banco usado pelos handlers
+type Server struct {
+	*httptest.Server
+	DB   *gorm.DB
+	Down atomic.Bool // Enquanto ligado o servidor responde 503
+}
+
+// New sobe o router completo num banco temporário com todas as tabelas criadas
+func New(t testing.TB, options handler.Options) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "toggles.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	err = db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.ToggleRule{}, &entity.TogglePrerequisite{}, &entity.ToggleRevision{}, &entity.ApplicationSnapshot{}, &entity.AuditEvent{}, &entity.FreezeWindow{}, &entity.Segment{}, &entity.User{}, &entity.Team{}, &entity.TeamApplication{}, &entity.TeamUser{}, &entity.SecretKey{}, &entity.ToggleMetric{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	handler.InitHandlersWithOptions(db, options)
+	engine := gin.New()
+	router.Init(engine)
+
+	s := &Server{DB: db}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Down.Load() {
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		engine.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// CreateToggle grava o toggle mantendo o enabled informado
+func (s *Server) CreateToggle(t testing.TB, toggle *entity.Toggle) *entity.Toggle {
+	t.Helper()
+	enabled := toggle.Enabled
+	if err := s.DB.Create(toggle).Error; err != nil {
+		t.Fatalf("Failed to create toggle %s: %v", toggle.Path, err)
+	}
+	// O default do banco ignora o false na criação
+	if !enabled {
+		s.DB.Model(toggle).Update("enabled", false)
+	}
+	return toggle
+}
+
+// CreateSecretKey gera uma secret key para a aplicação e retorna o valor em texto puro
+func (s *Server) CreateSecretKey(t testing.TB, appID, name string) string {
+	t.Helper()
+	key := &entity.SecretKey{ID: "test-secret-id", Name: name, ApplicationID: appID, CreatedBy: "test-user-id"}
+	secretKey, err := key.SetSecretKey()
+	if err != nil {
+		t.Fatalf("Failed to generate secret key: %v", err)
+	}
+	if err := s.DB.Create(key).Error; err != nil {
+		t.Fatalf("Failed to create secret key: %v", err)
+	}
+	return secretKey
+}
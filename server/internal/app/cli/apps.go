@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+)
+
+// newAppsCommand cria o grupo de comandos de aplicações
+func newAppsCommand() *cobra.Command {
+	apps := newGroupCommand("apps", "Manage applications")
+
+	apps.AddCommand(newAppsListCommand(), newAppsCreateCommand())
+
+	return apps
+}
+
+// newAppsListCommand cria o comando que lista as aplicações acessíveis ao usuário
+func newAppsListCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the applications with their toggle counts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			apps, err := listApplications(client)
+			if err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), apps)
+			}
+
+			rows := make([][]string, 0, len(apps))
+			for _, app := range apps {
+				rows = append(rows, []string{app.ID, app.Name, strconv.Itoa(app.TotalToggles), strconv.Itoa(app.EnabledToggles), strconv.Itoa(app.DisabledToggles)})
+			}
+			return writeTable(cmd.OutOrStdout(), []string{"ID", "NAME", "TOGGLES", "ENABLED", "DISABLED"}, rows)
+		},
+	}
+
+	options.setFlags(cmd.Flags(), true)
+
+	return cmd
+}
+
+// newAppsCreateCommand cria o comando que cria uma aplicação em um time
+func newAppsCreateCommand() *cobra.Command {
+	options := &clientOptions{}
+	var name, teamID string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an application owned by a team",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" || teamID == "" {
+				return fmt.Errorf("--name and --team are required")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+
+			var app entity.Application
+			if err := client.do(http.MethodPost, "/applications", map[string]string{"name": name, "team_id": teamID}, &app); err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), app)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created application %s (%s)\n", app.Name, app.ID)
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, true)
+	fs.StringVar(&name, "name", "", "application name (required)")
+	fs.StringVar(&teamID, "team", "", "ID of the team that owns the application (required)")
+
+	return cmd
+}
+
+// listApplications busca as aplicações acessíveis ao usuário
+func listApplications(client *apiClient) ([]*entity.ApplicationWithCounts, error) {
+	var apps []*entity.ApplicationWithCounts
+	if err := client.do(http.MethodGet, "/applications", nil, &apps); err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// resolveApplication aceita o ID ou o nome da aplicação; um nome repetido precisa ser informado pelo ID
+func resolveApplication(client *apiClient, ref string) (*entity.ApplicationWithCounts, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("--app is required")
+	}
+	apps, err := listApplications(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *entity.ApplicationWithCounts
+	for _, app := range apps {
+		if app.ID == ref {
+			return app, nil
+		}
+		if app.Name == ref {
+			if found != nil {
+				return nil, fmt.Errorf("more than one application is named %q, use the application ID", ref)
+			}
+			found = app
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("application %q not found", ref)
+	}
+	return found, nil
+}
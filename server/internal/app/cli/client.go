@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultServerURL é o endereço usado pelos comandos de administração quando nenhum é configurado
+const DefaultServerURL = "http://localhost:8081"
+
+// apiClient faz as chamadas à API REST do servidor com o token obtido no login
+type apiClient struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// newAPIClient cria um cliente para o servidor informado
+func newAPIClient(server, token string) *apiClient {
+	return &apiClient{
+		server: strings.TrimRight(server, "/"),
+		token:  token,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiError é um erro retornado pelo servidor. A API responde tanto no formato de AppError
+// (code, message e details) quanto com um campo error.
+type apiError struct {
+	Status  int              `json:"-"`
+	Code    string           `json:"code"`
+	Message string           `json:"message"`
+	Err     string           `json:"error"`
+	Details []apiErrorDetail `json:"details"`
+	body    []byte           // Corpo da resposta, para os comandos que respondem com mais que o erro
+}
+
+// apiErrorDetail é o erro de um campo da requisição
+type apiErrorDetail struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error descreve o erro com o status HTTP, o código e os detalhes por campo
+func (e *apiError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Err
+	}
+	if message == "" {
+		message = http.StatusText(e.Status)
+	}
+	if e.Code != "" {
+		message = fmt.Sprintf("%s (%s)", message, e.Code)
+	}
+	for _, detail := range e.Details {
+		message += fmt.Sprintf("; %s: %s", detail.Field, detail.Message)
+	}
+	return fmt.Sprintf("server returned %d: %s", e.Status, message)
+}
+
+// do envia a requisição com o corpo em JSON e decodifica a resposta em out, quando informado
+func (c *apiClient) do(method, path string, body, out interface{}) error {
+	_, err := c.send(method, path, body, out)
+	return err
+}
+
+// send envia a requisição e retorna a resposta, já lida, para quem precisa dos cookies
+func (c *apiClient) send(method, path string, body, out interface{}) (*http.Response, error) {
+	if body == nil {
+		return c.request(method, path, "", nil, out)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.request(method, path, "application/json", bytes.NewReader(data), out)
+}
+
+// upload envia o conteúdo de um arquivo como corpo, sem conversão, e decodifica a resposta em out
+func (c *apiClient) upload(method, path, contentType string, data []byte, out interface{}) error {
+	_, err := c.request(method, path, contentType, bytes.NewReader(data), out)
+	return err
+}
+
+// request envia a requisição autenticada e decodifica a resposta em out, quando informado
+func (c *apiClient) request(method, path, contentType string, body io.Reader, out interface{}) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.server+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not reach %s: %w", c.server, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{Status: resp.StatusCode, body: data}
+		json.Unmarshal(data, apiErr)
+		return nil, apiErr
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return nil, fmt.Errorf("invalid response from %s %s: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
+
+// escape protege um segmento de caminho da URL
+func escape(segment string) string {
+	return url.PathEscape(segment)
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// clientConfig é o arquivo de configuração dos comandos de administração, com o servidor e
+// as credenciais obtidas no login. O arquivo é gravado com permissão apenas para o dono.
+type clientConfig struct {
+	Server   string `yaml:"server"`
+	Username string `yaml:"username,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+// defaultClientConfigPath retorna o caminho do arquivo de configuração: TOTOOGLE_CONFIG ou
+// totoogle/config.yaml no diretório de configuração do usuário
+func defaultClientConfigPath() string {
+	if path := os.Getenv("TOTOOGLE_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "totoogle.yaml"
+	}
+	return filepath.Join(dir, "totoogle", "config.yaml")
+}
+
+// loadClientConfig lê o arquivo de configuração; um arquivo inexistente resulta na configuração padrão
+func loadClientConfig(path string) (*clientConfig, error) {
+	config := &clientConfig{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	if config.Server == "" {
+		config.Server = DefaultServerURL
+	}
+	return config, nil
+}
+
+// save grava o arquivo de configuração, criando o diretório quando necessário
+func (c *clientConfig) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// clientOptions são as flags comuns aos comandos que falam com a API
+type clientOptions struct {
+	configPath string
+	server     string
+	output     string
+}
+
+// setFlags registra as flags comuns; withOutput inclui o formato de saída
+func (o *clientOptions) setFlags(fs *pflag.FlagSet, withOutput bool) {
+	fs.StringVar(&o.configPath, "config", defaultClientConfigPath(), "path of the client config file (env TOTOOGLE_CONFIG)")
+	fs.StringVar(&o.server, "server", os.Getenv("TOTOOGLE_SERVER"), "server URL, overrides the config file (env TOTOOGLE_SERVER)")
+	if withOutput {
+		fs.StringVar(&o.output, "output", OutputTable, "output format: table or json")
+	}
+}
+
+// client lê a configuração e cria o cliente autenticado. TOTOOGLE_TOKEN substitui o token do arquivo.
+func (o *clientOptions) client() (*apiClient, error) {
+	if o.output != "" {
+		if err := validateOutput(o.output); err != nil {
+			return nil, err
+		}
+	}
+	config, err := loadClientConfig(o.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if o.server != "" {
+		config.Server = o.server
+	}
+	if token := os.Getenv("TOTOOGLE_TOKEN"); token != "" {
+		config.Token = token
+	}
+	if config.Token == "" {
+		return nil, fmt.Errorf("not logged in to %s, run totoogle login first", config.Server)
+	}
+	return newAPIClient(config.Server, config.Token), nil
+}
+
+// json indica se a saída foi pedida em JSON
+func (o *clientOptions) json() bool {
+	return o.output == OutputJSON
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/apitest"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/handler"
+)
+
+// adminTestServer sobe o servidor completo com um usuário root e aponta o arquivo de
+// configuração dos comandos para um diretório temporário
+func adminTestServer(t *testing.T) (server string, configPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	configPath = filepath.Join(dir, "config", "config.yaml")
+	t.Setenv("TOTOOGLE_CONFIG", configPath)
+	t.Setenv("TOTOOGLE_TOKEN", "")
+	t.Setenv("TOTOOGLE_SERVER", "")
+
+	api := apitest.New(t, handler.Options{})
+
+	// Troca a senha aleatória do root criado pelos handlers por uma conhecida
+	root := &entity.User{}
+	root.SetPassword("secret123")
+	err := api.DB.Model(&entity.User{}).Where("username = ?", "root").
+		Updates(map[string]interface{}{"password": root.Password, "must_change_password": false}).Error
+	if err != nil {
+		t.Fatalf("Failed to reset root password: %v", err)
+	}
+
+	return api.URL, configPath
+}
+
+// runCLI executa o comando e retorna a saída padrão
+func runCLI(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := Execute(NewRootCommand(), args, &stdout, &stderr)
+	return stdout.String() + stderr.String(), err
+}
+
+// mustRunCLI executa o comando e falha o teste em caso de erro
+func mustRunCLI(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := runCLI(args...)
+	if err != nil {
+		t.Fatalf("%s: expected no error, got %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return output
+}
+
+func TestAdminCommands(t *testing.T) {
+	server, configPath := adminTestServer(t)
+
+	if _, err := runCLI("apps", "list"); err == nil || !strings.Contains(err.Error(), "not logged in") {
+		t.Fatalf("Expected not logged in error, got %v", err)
+	}
+	if _, err := runCLI("login", "--server", server, "--username", "root", "--password", "wrong"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Expected login to fail with 401, got %v", err)
+	}
+
+	stdin = strings.NewReader("secret123\n")
+	defer func() { stdin = os.Stdin }()
+	mustRunCLI(t, "login", "--server", server, "--username", "root", "--password-stdin")
+
+	info, err := os.Stat(configPath)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("Expected config file readable only by the owner, got %v %v", info, err)
+	}
+	config, _ := loadClientConfig(configPath)
+	if config.Server != server || config.Username != "root" || config.Token == "" {
+		t.Fatalf("Unexpected config %+v", config)
+	}
+
+	// Times, aplicações e toggles
+	var team entity.Team
+	json.Unmarshal([]byte(mustRunCLI(t, "teams", "create", "--name", "core", "--output", "json")), &team)
+	if team.ID == "" {
+		t.Fatal("Expected the team to be created")
+	}
+	mustRunCLI(t, "apps", "create", "--name", "Shop", "--team", team.ID)
+	if output := mustRunCLI(t, "apps", "list"); !strings.Contains(output, "Shop") || !strings.HasPrefix(output, "ID") {
+		t.Errorf("Expected Shop in the applications table, got\n%s", output)
+	}
+
+	app, err := resolveApplication(newAPIClient(server, config.Token), "Shop")
+	if err != nil {
+		t.Fatalf("Expected to resolve the application, got %v", err)
+	}
+	client := newAPIClient(server, config.Token)
+	for _, path := range []string{"checkout.new-flow", "checkout.legacy", "search"} {
+		if err := client.do("POST", "/applications/"+app.ID+"/toggles", map[string]string{"toggle": path}, nil); err != nil {
+			t.Fatalf("Expected toggle %s to be created, got %v", path, err)
+		}
+	}
+
+	mustRunCLI(t, "toggles", "disable", "--app", "Shop", "checkout")
+	rules := filepath.Join(t.TempDir(), "rules.yaml")
+	os.WriteFile(rules, []byte("rules:\n  - conditions:\n      - type: user_id\n        value: u1, u2\n"), 0o600)
+	if output := mustRunCLI(t, "toggles", "set-rules", "--app", app.ID, "--file", rules, "search"); !strings.Contains(output, "Set 1 rules on search") {
+		t.Errorf("Unexpected set-rules output %q", output)
+	}
+
+	expected := "Shop (" + app.ID + ")\n" +
+		"├── checkout [off]\n" +
+		"│   ├── legacy [off]\n" +
+		"│   └── new-flow [off]\n" +
+		"└── search [on, 1 rules]\n"
+	if output := mustRunCLI(t, "toggles", "tree", "--app", "Shop"); output != expected {
+		t.Errorf("Unexpected tree\n%s\nexpected\n%s", output, expected)
+	}
+
+	var tree []*toggleNode
+	json.Unmarshal([]byte(mustRunCLI(t, "toggles", "tree", "--app", "Shop", "--output", "json")), &tree)
+	if len(tree) != 2 || len(tree[0].Children) != 2 || tree[1].Rules != 1 {
+		t.Errorf("Unexpected JSON tree %+v", tree)
+	}
+
+	mustRunCLI(t, "toggles", "set-rules", "--app", "Shop", "--clear", "search")
+	mustRunCLI(t, "toggles", "enable", "--app", "Shop", "checkout.legacy")
+	if output := mustRunCLI(t, "toggles", "tree", "--app", "Shop"); !strings.Contains(output, "legacy [on]") || !strings.Contains(output, "search [on]\n") {
+		t.Errorf("Expected legacy enabled and search without rules, got\n%s", output)
+	}
+
+	// Erros do servidor e de uso
+	if _, err := runCLI("toggles", "enable", "--app", "Shop", "missing"); err == nil || !strings.Contains(err.Error(), `toggle "missing" not found`) {
+		t.Errorf("Expected toggle not found, got %v", err)
+	}
+	if _, err := runCLI("apps", "create", "--name", "", "--team", team.ID); err == nil {
+		t.Error("Expected missing name to fail")
+	}
+
+	// Sincronização declarativa pela API
+	flags := filepath.Join(t.TempDir(), "flags.yaml")
+	os.WriteFile(flags, []byte("applications:\n  - name: Billing\n    toggles:\n      - path: invoices\n        enabled: false\n"), 0o600)
+	if output := mustRunCLI(t, "sync", "--file", flags, "--dry-run"); !strings.Contains(output, "+ toggle Billing/invoices") || !strings.Contains(output, "Dry run") {
+		t.Errorf("Unexpected dry run output %q", output)
+	}
+	if output := mustRunCLI(t, "sync", "--file", flags); !strings.Contains(output, "Plan: 2 to create") || strings.Contains(output, "Dry run") {
+		t.Errorf("Unexpected sync output %q", output)
+	}
+	if output := mustRunCLI(t, "sync", "--file", flags); !strings.HasPrefix(output, "No changes.") {
+		t.Errorf("Expected the second sync to change nothing, got %q", output)
+	}
+
+	// Relatório de obsoletos pela API
+	if output := mustRunCLI(t, "report", "stale", "--app", "Billing", "--days", "1"); !strings.Contains(output, "Stale toggles for Billing") || !strings.Contains(output, "threshold 1 days: 0 found") {
+		t.Errorf("Unexpected stale report output %q", output)
+	}
+
+	// Secret keys
+	if output := mustRunCLI(t, "keys", "generate", "--app", "Shop"); !strings.Contains(output, "Secret key for Shop: ") {
+		t.Errorf("Unexpected generate output %q", output)
+	}
+	var keys []*entity.SecretKey
+	json.Unmarshal([]byte(mustRunCLI(t, "keys", "list", "--app", "Shop", "--output", "json")), &keys)
+	if len(keys) != 1 {
+		t.Fatalf("Expected one secret key, got %+v", keys)
+	}
+	mustRunCLI(t, "keys", "delete", keys[0].ID)
+
+	// Usuários e times
+	if output := mustRunCLI(t, "users", "create", "--username", "alice", "--role", "admin"); !strings.Contains(output, "Created user alice") {
+		t.Errorf("Unexpected users create output %q", output)
+	}
+	mustRunCLI(t, "teams", "add-user", "core", "alice")
+	if output := mustRunCLI(t, "users", "list"); !strings.Contains(output, "alice") || !strings.Contains(output, "core") {
+		t.Errorf("Expected alice in team core, got\n%s", output)
+	}
+	if output := mustRunCLI(t, "teams", "list"); !strings.Contains(output, "core") {
+		t.Errorf("Expected core in the teams table, got\n%s", output)
+	}
+	mustRunCLI(t, "users", "delete", "alice")
+
+	mustRunCLI(t, "logout")
+	if config, _ := loadClientConfig(configPath); config.Token != "" || config.Server != server {
+		t.Errorf("Expected logout to keep the server and drop the token, got %+v", config)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	appErr := &apiError{Status: 409, Code: "T0010", Message: "toggle is managed", Details: []apiErrorDetail{{Field: "path", Message: "invalid"}}}
+	if appErr.Error() != "server returned 409: toggle is managed (T0010); path: invalid" {
+		t.Errorf("Unexpected error %q", appErr.Error())
+	}
+
+	plain := &apiError{Status: 500, Err: "Failed to retrieve users"}
+	if plain.Error() != "server returned 500: Failed to retrieve users" {
+		t.Errorf("Unexpected error %q", plain.Error())
+	}
+	if (&apiError{Status: 404}).Error() != "server returned 404: Not Found" {
+		t.Error("Expected the status text when the body has no message")
+	}
+}
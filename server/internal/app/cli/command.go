@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// ErrUsage indica que o comando foi chamado com argumentos inválidos
+var ErrUsage = errors.New("invalid usage")
+
+// usageError é um erro de argumentos ou flags, impresso junto com a ajuda do comando
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// Is faz com que erros de uso sejam reconhecidos como ErrUsage
+func (e *usageError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// Execute executa o comando raiz com os argumentos e as saídas informados. Erros de uso são
+// impressos com a ajuda do comando e retornados como ErrUsage; os demais são apenas retornados.
+func Execute(root *cobra.Command, args []string, stdout, stderr io.Writer) error {
+	// Sem argumentos o cobra usaria os de os.Args
+	if args == nil {
+		args = []string{}
+	}
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+
+	cmd, err := root.ExecuteC()
+	if errors.Is(err, ErrUsage) {
+		fmt.Fprintf(stderr, "Error: %v\n\n%s", err, cmd.UsageString())
+		return ErrUsage
+	}
+	return err
+}
+
+// newGroupCommand cria um comando que apenas agrupa subcomandos e imprime a ajuda quando chamado sozinho
+func newGroupCommand(name, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   name,
+		Short: short,
+		Args:  noSubcommand,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+}
+
+// noSubcommand rejeita argumentos posicionais em comandos com subcomandos: um nome que não é um
+// subcomando é um erro de uso, e não um argumento para o comando (na raiz, isso iniciaria o servidor)
+func noSubcommand(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return &usageError{err: fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())}
+	}
+	return nil
+}
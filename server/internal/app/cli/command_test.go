@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newTestRoot monta uma árvore de comandos como a do binário, com um Run na raiz
+func newTestRoot(ran *bool, name *string, gotArgs *[]string) *cobra.Command {
+	root := &cobra.Command{
+		Use:  "totoogle",
+		Args: noSubcommand,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			*ran = true
+			return nil
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+
+	group := newGroupCommand("report", "Generate reports")
+	leaf := &cobra.Command{
+		Use:   "stale",
+		Short: "List stale toggles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			*gotArgs = args
+			return nil
+		},
+	}
+	leaf.Flags().StringVar(name, "app", "", "application ID")
+	group.AddCommand(leaf)
+	root.AddCommand(group)
+
+	return root
+}
+
+func TestExecute_SubcommandWithFlags(t *testing.T) {
+	var ran bool
+	var name string
+	var gotArgs []string
+	root := newTestRoot(&ran, &name, &gotArgs)
+
+	var stdout, stderr bytes.Buffer
+	err := Execute(root, []string{"report", "stale", "extra", "--app", "APP1"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if name != "APP1" {
+		t.Errorf("Expected flag app to be APP1, got %q", name)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "extra" {
+		t.Errorf("Expected positional args [extra], got %v", gotArgs)
+	}
+	if ran {
+		t.Error("Expected root Run not to be called")
+	}
+}
+
+func TestExecute_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"unknown command at the root", []string{"reprot"}, `unknown command "reprot" for "totoogle"`},
+		{"unknown command in a group", []string{"report", "unknown"}, `unknown command "unknown" for "totoogle report"`},
+		{"unknown flag", []string{"report", "stale", "--application", "APP1"}, "unknown flag: --application"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran bool
+			var name string
+			var gotArgs []string
+			root := newTestRoot(&ran, &name, &gotArgs)
+
+			var stdout, stderr bytes.Buffer
+			err := Execute(root, tt.args, &stdout, &stderr)
+			if !errors.Is(err, ErrUsage) {
+				t.Errorf("Expected ErrUsage, got %v", err)
+			}
+			if ran || gotArgs != nil {
+				t.Error("Expected no command to run")
+			}
+			if !strings.Contains(stderr.String(), tt.message) || !strings.Contains(stderr.String(), "Usage:") {
+				t.Errorf("Expected %q with the usage, got %q", tt.message, stderr.String())
+			}
+		})
+	}
+}
+
+func TestExecute_RootWithoutArguments(t *testing.T) {
+	var ran bool
+	var name string
+	var gotArgs []string
+	root := newTestRoot(&ran, &name, &gotArgs)
+
+	var stdout, stderr bytes.Buffer
+	if err := Execute(root, nil, &stdout, &stderr); err != nil || !ran {
+		t.Errorf("Expected root Run to be called without arguments, got %v", err)
+	}
+}
+
+func TestExecute_GroupPrintsHelp(t *testing.T) {
+	var ran bool
+	var name string
+	var gotArgs []string
+	root := newTestRoot(&ran, &name, &gotArgs)
+
+	var stdout, stderr bytes.Buffer
+	if err := Execute(root, []string{"report"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "stale") || !strings.Contains(stdout.String(), "List stale toggles") {
+		t.Errorf("Expected help listing subcommands, got %q", stdout.String())
+	}
+}
+
+func TestExecute_RuntimeErrorIsNotUsage(t *testing.T) {
+	root := &cobra.Command{
+		Use: "totoogle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("server unavailable")
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := Execute(root, []string{}, &stdout, &stderr)
+	if err == nil || errors.Is(err, ErrUsage) {
+		t.Errorf("Expected the runtime error, got %v", err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("Expected nothing printed, got %q", stderr.String())
+	}
+}
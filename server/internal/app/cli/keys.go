@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+)
+
+// newKeysCommand cria o grupo de comandos de secret keys das aplicações
+func newKeysCommand() *cobra.Command {
+	keys := newGroupCommand("keys", "Manage the secret keys used by the SDKs")
+
+	keys.AddCommand(newKeysListCommand(), newKeysGenerateCommand(), newKeysDeleteCommand())
+
+	return keys
+}
+
+// newKeysListCommand cria o comando que lista as secret keys da aplicação com a telemetria de uso
+func newKeysListCommand() *cobra.Command {
+	options := &clientOptions{}
+	var appRef string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the secret keys of an application and when they were last used",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			app, err := resolveApplication(client, appRef)
+			if err != nil {
+				return err
+			}
+
+			var response struct {
+				SecretKeys []*entity.SecretKey `json:"secret_keys"`
+			}
+			if err := client.do(http.MethodGet, "/applications/"+escape(app.ID)+"/secret-keys", nil, &response); err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), response.SecretKeys)
+			}
+
+			rows := make([][]string, 0, len(response.SecretKeys))
+			for _, key := range response.SecretKeys {
+				lastUsed := "never"
+				if key.LastUsedAt != nil {
+					lastUsed = key.LastUsedAt.Format("2006-01-02 15:04")
+				}
+				rows = append(rows, []string{key.ID, key.Name, key.CreatedAt.Format("2006-01-02"), lastUsed, strconv.FormatInt(key.UsageCount, 10), strconv.FormatBool(key.Unused)})
+			}
+			return writeTable(cmd.OutOrStdout(), []string{"ID", "NAME", "CREATED", "LAST USED", "USES", "UNUSED"}, rows)
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, true)
+	fs.StringVar(&appRef, "app", "", "application ID or name (required)")
+
+	return cmd
+}
+
+// newKeysGenerateCommand cria o comando que gera uma nova secret key, invalidando as anteriores
+func newKeysGenerateCommand() *cobra.Command {
+	options := &clientOptions{}
+	var appRef string
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a new secret key for an application, revoking the previous ones",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			app, err := resolveApplication(client, appRef)
+			if err != nil {
+				return err
+			}
+
+			var response struct {
+				SecretKey *entity.SecretKey `json:"secret_key"`
+				PlainKey  string            `json:"plain_key"`
+			}
+			if err := client.do(http.MethodPost, "/applications/"+escape(app.ID)+"/generate-secret", map[string]string{}, &response); err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), response)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Secret key for %s: %s\n", app.Name, response.PlainKey)
+			fmt.Fprintln(cmd.ErrOrStderr(), "This key will only be shown once. Please store it securely.")
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, true)
+	fs.StringVar(&appRef, "app", "", "application ID or name (required)")
+
+	return cmd
+}
+
+// newKeysDeleteCommand cria o comando que remove uma secret key pelo ID
+func newKeysDeleteCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "delete <key-id>",
+		Short: "Delete a secret key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected the secret key ID as the only argument")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			if err := client.do(http.MethodDelete, "/secret-keys/"+escape(args[0]), nil, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted secret key %s\n", args[0])
+			return nil
+		},
+	}
+
+	options.setFlags(cmd.Flags(), false)
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// stdin é a entrada usada por --password-stdin, substituída nos testes
+var stdin io.Reader = os.Stdin
+
+// newLoginCommand cria o comando que autentica no servidor e grava o token no arquivo de configuração
+func newLoginCommand() *cobra.Command {
+	options := &clientOptions{}
+	var username, password string
+	var passwordStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to a ToToogle server and store the credentials in the config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if username == "" {
+				return fmt.Errorf("--username is required")
+			}
+			if passwordStdin {
+				line, err := bufio.NewReader(stdin).ReadString('\n')
+				if err != nil && err != io.EOF {
+					return err
+				}
+				password = strings.TrimRight(line, "\r\n")
+			}
+			if password == "" {
+				return fmt.Errorf("--password, --password-stdin or TOTOOGLE_PASSWORD is required")
+			}
+
+			config, err := loadClientConfig(options.configPath)
+			if err != nil {
+				return err
+			}
+			if options.server != "" {
+				config.Server = options.server
+			}
+
+			var result struct {
+				MustChangePassword bool `json:"must_change_password"`
+			}
+			client := newAPIClient(config.Server, "")
+			resp, err := client.send(http.MethodPost, "/auth/login", map[string]string{"username": username, "password": password}, &result)
+			if err != nil {
+				return err
+			}
+			if result.MustChangePassword {
+				return fmt.Errorf("user %s must change the password in the web UI before logging in", username)
+			}
+
+			// O token é entregue no cookie de autenticação, o mesmo usado pela interface web
+			for _, cookie := range resp.Cookies() {
+				if cookie.Name == "auth_token" {
+					config.Token = cookie.Value
+				}
+			}
+			if config.Token == "" {
+				return fmt.Errorf("the server did not return an authentication token")
+			}
+			config.Username = username
+			if err := config.save(options.configPath); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s as %s\n", config.Server, username)
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, false)
+	fs.StringVar(&username, "username", "", "username (required)")
+	fs.StringVar(&password, "password", os.Getenv("TOTOOGLE_PASSWORD"), "password (env TOTOOGLE_PASSWORD)")
+	fs.BoolVar(&passwordStdin, "password-stdin", false, "read the password from the standard input")
+
+	return cmd
+}
+
+// newLogoutCommand cria o comando que remove o token do arquivo de configuração
+func newLogoutCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored credentials",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadClientConfig(options.configPath)
+			if err != nil {
+				return err
+			}
+			if config.Token == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), "Not logged in")
+				return nil
+			}
+
+			// O logout no servidor apenas limpa o cookie; falhas não impedem remover o token local
+			newAPIClient(config.Server, config.Token).do(http.MethodPost, "/auth/logout", nil, nil)
+			config.Token = ""
+			if err := config.save(options.configPath); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged out of %s\n", config.Server)
+			return nil
+		},
+	}
+
+	options.setFlags(cmd.Flags(), false)
+
+	return cmd
+}
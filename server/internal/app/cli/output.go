@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Formatos de saída suportados pelos comandos
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// validateOutput verifica se o formato de saída é suportado
+func validateOutput(output string) error {
+	if output != OutputTable && output != OutputJSON {
+		return fmt.Errorf("unsupported output %q, use %q or %q", output, OutputTable, OutputJSON)
+	}
+	return nil
+}
+
+// writeJSON imprime o valor como JSON indentado
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeTable imprime linhas alinhadas em colunas com um cabeçalho
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	writeRow(tw, header)
+	for _, row := range rows {
+		writeRow(tw, row)
+	}
+	return tw.Flush()
+}
+
+// writeRow imprime uma linha separada por tabulações
+func writeRow(w io.Writer, columns []string) {
+	for i, column := range columns {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, column)
+	}
+	fmt.Fprintln(w)
+}
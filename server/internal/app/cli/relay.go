@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/infrastructure/geoip"
+	"github.com/manorfm/totoogle/internal/app/relay"
+	"github.com/manorfm/totoogle/internal/app/router"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// newRelayCommand cria o comando que inicia o binário em modo relay
+func newRelayCommand() *cobra.Command {
+	options := &relayOptions{}
+	cmd := &cobra.Command{
+		Use:   "relay",
+		Short: "Serve the public toggles API from snapshots synchronized with an upstream server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			relayOptions, routerOptions, err := options.options()
+			if err != nil {
+				return err
+			}
+			return runRelay(relayOptions, routerOptions)
+		},
+	}
+
+	options.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// relayOptions são as flags do modo relay
+type relayOptions struct {
+	upstream        string
+	secretKeys      string
+	dataDir         string
+	refreshInterval time.Duration
+	streaming       bool
+	trustedProxies  string
+	geoIPDatabase   string
+}
+
+// setFlags registra as flags do relay; os valores padrão vêm das variáveis de ambiente,
+// que evitam expor as secret keys na lista de processos
+func (o *relayOptions) setFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.upstream, "upstream", os.Getenv("TOTOOGLE_RELAY_UPSTREAM"),
+		"URL of the upstream ToToogle server (env TOTOOGLE_RELAY_UPSTREAM)")
+	fs.StringVar(&o.secretKeys, "keys", os.Getenv("TOTOOGLE_RELAY_KEYS"),
+		"comma-separated secret keys served by the relay (env TOTOOGLE_RELAY_KEYS)")
+	fs.StringVar(&o.dataDir, "data-dir", envString("TOTOOGLE_RELAY_DATA_DIR", "relay-data"),
+		"directory where the last snapshot of each key is persisted (env TOTOOGLE_RELAY_DATA_DIR)")
+	fs.DurationVar(&o.refreshInterval, "refresh-interval", envDuration("TOTOOGLE_RELAY_REFRESH_INTERVAL", relay.DefaultRefreshInterval),
+		"interval between polls, or between reconnections when streaming (env TOTOOGLE_RELAY_REFRESH_INTERVAL)")
+	fs.BoolVar(&o.streaming, "streaming", envBool("TOTOOGLE_RELAY_STREAMING", true),
+		"receive changes from the upstream stream instead of polling (env TOTOOGLE_RELAY_STREAMING)")
+	fs.StringVar(&o.trustedProxies, "trusted-proxies", os.Getenv("TOTOOGLE_TRUSTED_PROXIES"),
+		"comma-separated IPs or CIDR blocks of proxies whose X-Forwarded-For is trusted (env TOTOOGLE_TRUSTED_PROXIES)")
+	fs.StringVar(&o.geoIPDatabase, "geoip-db", os.Getenv("TOTOOGLE_GEOIP_DB"),
+		"path of a MaxMind DB (mmdb) country database used to resolve the country from the IP, reloaded on change (env TOTOOGLE_GEOIP_DB)")
+}
+
+// options converte as flags nas opções do relay e do servidor HTTP
+func (o *relayOptions) options() (relay.Options, router.Options, error) {
+	if strings.TrimSpace(o.upstream) == "" {
+		return relay.Options{}, router.Options{}, fmt.Errorf("--upstream is required")
+	}
+	keys := splitList(o.secretKeys)
+	if len(keys) == 0 {
+		return relay.Options{}, router.Options{}, fmt.Errorf("--keys is required")
+	}
+	if o.refreshInterval <= 0 {
+		return relay.Options{}, router.Options{}, fmt.Errorf("--refresh-interval must be positive")
+	}
+
+	relayOptions := relay.Options{
+		Upstream:        o.upstream,
+		SecretKeys:      keys,
+		DataDir:         o.dataDir,
+		RefreshInterval: o.refreshInterval,
+		Streaming:       o.streaming,
+	}
+	routerOptions := router.Options{
+		TrustedProxies: splitList(o.trustedProxies),
+		GeoIPDatabase:  strings.TrimSpace(o.geoIPDatabase),
+	}
+	return relayOptions, routerOptions, nil
+}
+
+// runRelay inicia a sincronização das chaves com o upstream e o servidor HTTP do relay, que
+// atende desde o início com os snapshots gravados em disco
+func runRelay(relayOptions relay.Options, routerOptions router.Options) error {
+	if routerOptions.GeoIPDatabase != "" {
+		resolver, err := geoip.NewResolver(routerOptions.GeoIPDatabase)
+		if err != nil {
+			return err
+		}
+		resolver.Watch(geoip.DefaultReloadInterval)
+		defer resolver.Close()
+		relayOptions.CountryResolver = resolver
+	}
+
+	r, err := relay.New(relayOptions)
+	if err != nil {
+		return err
+	}
+	r.Start(context.Background())
+	defer r.Close()
+
+	return router.InitializeRelay(r.Register, routerOptions)
+}
+
+// envString lê um texto da variável de ambiente, usando o valor padrão quando ausente
+func envString(name, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envDuration lê uma duração da variável de ambiente, usando o valor padrão quando ausente ou inválida
+func envDuration(name string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(strings.TrimSpace(os.Getenv(name)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envBool lê um booleano da variável de ambiente, usando o valor padrão quando ausente ou inválido
+func envBool(name string, fallback bool) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(name)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+)
+
+// newReportCommand cria o grupo de comandos de relatórios
+func newReportCommand() *cobra.Command {
+	report := newGroupCommand("report", "Generate reports about toggles")
+
+	report.AddCommand(newReportStaleCommand())
+
+	return report
+}
+
+// newReportStaleCommand cria o comando que lista toggles obsoletos de uma aplicação pela API
+func newReportStaleCommand() *cobra.Command {
+	options := &clientOptions{}
+	var appRef string
+	var days int
+
+	cmd := &cobra.Command{
+		Use:   "stale",
+		Short: "List stale toggles of an application with a suggested action",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			app, err := resolveApplication(client, appRef)
+			if err != nil {
+				return err
+			}
+
+			path := "/applications/" + escape(app.ID) + "/reports/stale?days=" + strconv.Itoa(days)
+			var report entity.StaleReport
+			if err := client.do(http.MethodGet, path, nil, &report); err != nil {
+				return err
+			}
+
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), report)
+			}
+			return writeStaleReportTable(cmd.OutOrStdout(), &report)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&appRef, "app", "", "application name or ID (required)")
+	fs.IntVar(&days, "days", entity.DefaultStaleDays, "days without changes or evaluations to consider a toggle stale")
+	options.setFlags(fs, true)
+
+	return cmd
+}
+
+// writeStaleReportTable imprime o relatório de toggles obsoletos como tabela
+func writeStaleReportTable(w io.Writer, report *entity.StaleReport) error {
+	fmt.Fprintf(w, "Stale toggles for %s (%s), threshold %d days: %d found\n\n",
+		report.AppName, report.AppID, report.StaleDays, len(report.Toggles))
+
+	if len(report.Toggles) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(report.Toggles))
+	for _, toggle := range report.Toggles {
+		reasons := make([]string, len(toggle.Reasons))
+		for i, reason := range toggle.Reasons {
+			reasons[i] = string(reason)
+		}
+
+		rows = append(rows, []string{
+			toggle.Path,
+			strconv.FormatBool(toggle.Enabled),
+			toggle.UpdatedAt.Format("2006-01-02"),
+			strings.Join(reasons, ","),
+			toggle.SuggestedAction,
+		})
+	}
+
+	return writeTable(w, []string{"PATH", "ENABLED", "LAST CHANGE", "REASONS", "SUGGESTED ACTION"}, rows)
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/config"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/router"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewRootCommand cria o comando raiz do binário totoogle.
+// Sem argumentos o servidor é iniciado, mantendo o comportamento original.
+func NewRootCommand() *cobra.Command {
+	options := &serveOptions{}
+	root := &cobra.Command{
+		Use:   "totoogle",
+		Short: "ToToogle feature toggle server and tooling",
+		Args:  noSubcommand,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(options)
+		},
+		// Os erros são tratados por Execute, que imprime a ajuda apenas nos erros de uso
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+	options.setFlags(root.Flags())
+
+	root.AddCommand(
+		newServeCommand(),
+		newReportCommand(),
+		newSyncCommand(),
+		newRelayCommand(),
+		newLoginCommand(),
+		newLogoutCommand(),
+		newAppsCommand(),
+		newTogglesCommand(),
+		newKeysCommand(),
+		newTeamsCommand(),
+		newUsersCommand(),
+	)
+
+	return root
+}
+
+// newServeCommand cria o comando que inicia o servidor HTTP
+func newServeCommand() *cobra.Command {
+	options := &serveOptions{}
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the ToToogle server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(options)
+		},
+	}
+
+	options.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// serveOptions são as flags do servidor, aceitas pelo comando raiz e por serve
+type serveOptions struct {
+	trustedProxies     string
+	geoIPDatabase      string
+	trashRetentionDays int
+	managedPolicy      string
+}
+
+// setFlags registra as flags do servidor; os valores padrão vêm das variáveis de ambiente
+func (o *serveOptions) setFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.trustedProxies, "trusted-proxies", os.Getenv("TOTOOGLE_TRUSTED_PROXIES"),
+		"comma-separated IPs or CIDR blocks of proxies whose X-Forwarded-For is trusted (env TOTOOGLE_TRUSTED_PROXIES)")
+	fs.StringVar(&o.geoIPDatabase, "geoip-db", os.Getenv("TOTOOGLE_GEOIP_DB"),
+		"path of a MaxMind DB (mmdb) country database used to resolve the country from the IP, reloaded on change (env TOTOOGLE_GEOIP_DB)")
+	fs.IntVar(&o.trashRetentionDays, "trash-retention-days", envInt("TOTOOGLE_TRASH_RETENTION_DAYS", entity.DefaultTrashRetentionDays),
+		"days deleted toggles and applications stay in the trash before being purged, 0 keeps them forever (env TOTOOGLE_TRASH_RETENTION_DAYS)")
+	fs.StringVar(&o.managedPolicy, "managed-policy", os.Getenv("TOTOOGLE_MANAGED_POLICY"),
+		"manual changes to resources managed by sync: warn accepts them, block rejects them (env TOTOOGLE_MANAGED_POLICY, default warn)")
+}
+
+// envInt lê um inteiro da variável de ambiente, usando o valor padrão quando ausente ou inválido
+func envInt(name string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// splitList separa uma lista separada por vírgulas, ignorando os itens vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// routerOptions converte as flags nas opções do servidor HTTP
+func (o *serveOptions) routerOptions() (router.Options, error) {
+	retention := time.Duration(0)
+	if o.trashRetentionDays > 0 {
+		retention = time.Duration(o.trashRetentionDays) * 24 * time.Hour
+	}
+	policy, err := entity.ParseManagedPolicy(o.managedPolicy)
+	if err != nil {
+		return router.Options{}, err
+	}
+	return router.Options{
+		TrustedProxies: splitList(o.trustedProxies),
+		GeoIPDatabase:  strings.TrimSpace(o.geoIPDatabase),
+		TrashRetention: retention,
+		ManagedPolicy:  policy,
+	}, nil
+}
+
+// serve inicializa a configuração e inicia o servidor
+func serve(options *serveOptions) error {
+	routerOptions, err := options.routerOptions()
+	if err != nil {
+		return err
+	}
+	if err := config.Init(); err != nil {
+		return err
+	}
+
+	return router.Initialize(routerOptions)
+}
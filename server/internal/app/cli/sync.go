@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+)
+
+// newSyncCommand cria o comando que envia um arquivo de flags para POST /sync do servidor
+func newSyncCommand() *cobra.Command {
+	options := &clientOptions{}
+	var file string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Apply a declarative flags file, creating, updating and deleting managed resources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return fmt.Errorf("--file is required")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			path := "/sync"
+			if dryRun {
+				path += "?dry_run=true"
+			}
+			var plan entity.SyncPlan
+			err = client.upload(http.MethodPost, path, "application/yaml", data, &plan)
+			// Quando uma alteração falha o servidor responde com o plano e o resultado de cada alteração
+			var apiErr *apiError
+			if errors.As(err, &apiErr) && json.Unmarshal(apiErr.body, &plan) == nil && plan.Failed() != nil {
+				err = syncFailure(plan.Failed())
+			} else if err != nil {
+				return err
+			}
+
+			if options.json() {
+				if writeErr := writeJSON(cmd.OutOrStdout(), plan); writeErr != nil {
+					return writeErr
+				}
+				return err
+			}
+			if writeErr := writeSyncPlan(cmd.OutOrStdout(), &plan); writeErr != nil {
+				return writeErr
+			}
+			return err
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&file, "file", "", "path of the YAML or JSON flags file (required)")
+	fs.BoolVar(&dryRun, "dry-run", false, "print the plan without applying it")
+	options.setFlags(fs, true)
+
+	return cmd
+}
+
+// writeSyncPlan imprime o plano como diff: uma linha por recurso e os campos alterados abaixo
+func writeSyncPlan(w io.Writer, plan *entity.SyncPlan) error {
+	if len(plan.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes. The server matches the flags file.")
+		return err
+	}
+
+	for _, change := range plan.Changes {
+		line := change.String()
+		if change.Status == entity.SyncStatusFailed || change.Status == entity.SyncStatusSkipped {
+			line += fmt.Sprintf(" (%s)", change.Status)
+		}
+		fmt.Fprintln(w, line)
+		for _, field := range change.Diff {
+			fmt.Fprintf(w, "    %s\n", field)
+		}
+		if change.Error != nil {
+			fmt.Fprintf(w, "    error: %s\n", change.Error.Message)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, plan.Summary())
+	if plan.Failed() != nil {
+		_, err := fmt.Fprintf(w, "Sync stopped: %d applied, 1 failed, %d skipped.\n", countSyncStatus(plan, entity.SyncStatusApplied), countSyncStatus(plan, entity.SyncStatusSkipped))
+		return err
+	}
+	if !plan.Applied {
+		_, err := fmt.Fprintln(w, "Dry run: nothing was applied.")
+		return err
+	}
+	return nil
+}
+
+// countSyncStatus conta as alterações do plano com o resultado informado
+func countSyncStatus(plan *entity.SyncPlan, status entity.SyncChangeStatus) int {
+	count := 0
+	for _, change := range plan.Changes {
+		if change.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// syncFailure descreve a alteração que interrompeu a sincronização
+func syncFailure(change *entity.SyncChange) error {
+	message := "unknown error"
+	if change.Error != nil {
+		message = fmt.Sprintf("%s (%s)", change.Error.Message, change.Error.Code)
+		for _, detail := range change.Error.Details {
+			message += fmt.Sprintf("; %s: %s", detail.Field, detail.Message)
+		}
+	}
+	return fmt.Errorf("sync stopped at %s: %s", strings.TrimSpace(change.String()), message)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestWriteSyncPlan(t *testing.T) {
+	plan := &entity.SyncPlan{Changes: []*entity.SyncChange{
+		{Action: entity.SyncActionCreate, Resource: entity.SyncResourceSegment, Application: "Shop", Name: "beta"},
+		{Action: entity.SyncActionUpdate, Resource: entity.SyncResourceToggle, Application: "Shop", Name: "checkout", Diff: []string{"enabled: true -> false"}},
+	}}
+
+	var out bytes.Buffer
+	if err := writeSyncPlan(&out, plan); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "+ segment Shop/beta\n~ toggle Shop/checkout\n    enabled: true -> false\n\nPlan: 1 to create, 1 to update, 0 to delete.\nDry run: nothing was applied.\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	// Um plano interrompido mostra a alteração que falhou e as que ficaram de fora
+	plan.Applied = true
+	plan.Changes[0].Status = entity.SyncStatusApplied
+	plan.Changes[1].Status = entity.SyncStatusFailed
+	plan.Changes[1].Error = entity.NewAppError(entity.ErrCodeFrozen, "application is frozen")
+	plan.Changes = append(plan.Changes, &entity.SyncChange{Action: entity.SyncActionDelete, Resource: entity.SyncResourceSegment, Application: "Shop", Name: "old", Status: entity.SyncStatusSkipped})
+	out.Reset()
+	if err := writeSyncPlan(&out, plan); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected = "+ segment Shop/beta\n~ toggle Shop/checkout (failed)\n    enabled: true -> false\n    error: application is frozen\n- segment Shop/old (skipped)\n\nPlan: 1 to create, 1 to update, 1 to delete.\nSync stopped: 1 applied, 1 failed, 1 skipped.\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+	if err := syncFailure(plan.Failed()); err.Error() != "sync stopped at ~ toggle Shop/checkout: application is frozen (T0009)" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	out.Reset()
+	writeSyncPlan(&out, &entity.SyncPlan{})
+	if !strings.HasPrefix(out.String(), "No changes.") {
+		t.Errorf("Unexpected output for an empty plan: %s", out.String())
+	}
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+)
+
+// newTeamsCommand cria o grupo de comandos de times
+func newTeamsCommand() *cobra.Command {
+	teams := newGroupCommand("teams", "Manage teams, their members and applications (root only)")
+
+	teams.AddCommand(newTeamsListCommand(), newTeamsCreateCommand(), newTeamsAddUserCommand(), newTeamsAddAppCommand())
+
+	return teams
+}
+
+// newTeamsListCommand cria o comando que lista os times com o número de membros e aplicações
+func newTeamsListCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the teams",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			teams, err := listTeams(client)
+			if err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), teams)
+			}
+
+			rows := make([][]string, 0, len(teams))
+			for _, team := range teams {
+				rows = append(rows, []string{team.ID, team.Name, strconv.Itoa(team.UserCount), strconv.Itoa(team.ApplicationCount), team.Description})
+			}
+			return writeTable(cmd.OutOrStdout(), []string{"ID", "NAME", "USERS", "APPLICATIONS", "DESCRIPTION"}, rows)
+		},
+	}
+
+	options.setFlags(cmd.Flags(), true)
+
+	return cmd
+}
+
+// newTeamsCreateCommand cria o comando que cria um time
+func newTeamsCreateCommand() *cobra.Command {
+	options := &clientOptions{}
+	var name, description string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a team",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+
+			var response struct {
+				Team *entity.Team `json:"team"`
+			}
+			if err := client.do(http.MethodPost, "/teams", map[string]string{"name": name, "description": description}, &response); err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), response.Team)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created team %s (%s)\n", response.Team.Name, response.Team.ID)
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, true)
+	fs.StringVar(&name, "name", "", "team name (required)")
+	fs.StringVar(&description, "description", "", "team description")
+
+	return cmd
+}
+
+// newTeamsAddUserCommand cria o comando que adiciona um usuário a um time
+func newTeamsAddUserCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "add-user <team> <user>",
+		Short: "Add a user to a team",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("expected the team and the user (ID or name) as arguments")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			user, err := resolveUser(client, args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := client.do(http.MethodPost, "/teams/"+escape(team.ID)+"/users", map[string]string{"user_id": user.ID}, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added %s to team %s\n", user.Username, team.Name)
+			return nil
+		},
+	}
+
+	options.setFlags(cmd.Flags(), false)
+
+	return cmd
+}
+
+// newTeamsAddAppCommand cria o comando que dá a um time acesso a uma aplicação
+func newTeamsAddAppCommand() *cobra.Command {
+	options := &clientOptions{}
+	var permission string
+
+	cmd := &cobra.Command{
+		Use:   "add-app <team> <app>",
+		Short: "Give a team access to an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("expected the team and the application (ID or name) as arguments")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			app, err := resolveApplication(client, args[1])
+			if err != nil {
+				return err
+			}
+
+			body := map[string]string{"application_id": app.ID, "permission": permission}
+			if err := client.do(http.MethodPost, "/teams/"+escape(team.ID)+"/applications", body, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Gave team %s %s access to %s\n", team.Name, permission, app.Name)
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, false)
+	fs.StringVar(&permission, "permission", string(entity.PermissionRead), "permission of the team: read, write or admin")
+
+	return cmd
+}
+
+// listTeams busca todos os times
+func listTeams(client *apiClient) ([]*entity.TeamWithCounts, error) {
+	var response struct {
+		Teams []*entity.TeamWithCounts `json:"teams"`
+	}
+	if err := client.do(http.MethodGet, "/teams", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Teams, nil
+}
+
+// resolveTeam aceita o ID ou o nome do time
+func resolveTeam(client *apiClient, ref string) (*entity.TeamWithCounts, error) {
+	teams, err := listTeams(client)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		if team.ID == ref || team.Name == ref {
+			return team, nil
+		}
+	}
+	return nil, fmt.Errorf("team %q not found", ref)
+}
@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// newTogglesCommand cria o grupo de comandos de toggles
+func newTogglesCommand() *cobra.Command {
+	toggles := newGroupCommand("toggles", "Inspect and change the toggles of an application")
+
+	toggles.AddCommand(
+		newTogglesTreeCommand(),
+		newTogglesEnabledCommand("enable", true),
+		newTogglesEnabledCommand("disable", false),
+		newTogglesSetRulesCommand(),
+	)
+
+	return toggles
+}
+
+// toggleNode é um toggle da árvore impressa pelo comando tree
+type toggleNode struct {
+	ID       string        `json:"id"`
+	Value    string        `json:"value"`
+	Path     string        `json:"path"`
+	Enabled  bool          `json:"enabled"`
+	Rules    int           `json:"rules"`
+	Variants int           `json:"variants"`
+	Managed  bool          `json:"managed"`
+	Children []*toggleNode `json:"children"`
+}
+
+// newTogglesTreeCommand cria o comando que imprime a hierarquia de toggles da aplicação
+func newTogglesTreeCommand() *cobra.Command {
+	options := &clientOptions{}
+	var appRef string
+
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Print the toggle hierarchy of an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			app, err := resolveApplication(client, appRef)
+			if err != nil {
+				return err
+			}
+			toggles, err := listToggles(client, app.ID)
+			if err != nil {
+				return err
+			}
+
+			roots := buildToggleTree(toggles)
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), roots)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", app.Name, app.ID)
+			writeToggleTree(cmd.OutOrStdout(), roots, "")
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, true)
+	fs.StringVar(&appRef, "app", "", "application ID or name (required)")
+
+	return cmd
+}
+
+// newTogglesEnabledCommand cria os comandos enable e disable, que alteram o toggle e seus descendentes
+func newTogglesEnabledCommand(name string, enabled bool) *cobra.Command {
+	options := &clientOptions{}
+	var appRef string
+	label := "Enable"
+	if !enabled {
+		label = "Disable"
+	}
+
+	cmd := &cobra.Command{
+		Use:   name + " --app <app> <path>",
+		Short: label + " a toggle and its descendants",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected the toggle path as the only argument")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			app, toggle, err := resolveToggle(client, appRef, args[0])
+			if err != nil {
+				return err
+			}
+
+			path := fmt.Sprintf("/applications/%s/toggle/%s", escape(app.ID), escape(toggle.ID))
+			if err := client.do(http.MethodPut, path, map[string]bool{"enabled": enabled}, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%sd %s in %s\n", label, toggle.Path, app.Name)
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, false)
+	fs.StringVar(&appRef, "app", "", "application ID or name (required)")
+
+	return cmd
+}
+
+// rulesFile é o arquivo lido por set-rules: as regras compostas e, opcionalmente, as variantes.
+// As condições do tipo segment referenciam os segmentos pelo ID, como na API.
+type rulesFile struct {
+	Rules    []*entity.ToggleRule  `json:"rules"`
+	Variants entity.ToggleVariants `json:"variants,omitempty"`
+}
+
+// setRulesRequest é o corpo enviado para substituir as regras mantendo o estado do toggle
+type setRulesRequest struct {
+	Enabled  bool                  `json:"enabled"`
+	Rules    []*entity.ToggleRule  `json:"rules"`
+	Variants entity.ToggleVariants `json:"variants,omitempty"`
+}
+
+// newTogglesSetRulesCommand cria o comando que substitui as regras de um toggle a partir de um arquivo
+func newTogglesSetRulesCommand() *cobra.Command {
+	options := &clientOptions{}
+	var appRef, file string
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "set-rules --app <app> (--file rules.yaml | --clear) <path>",
+		Short: "Replace the rules (and optionally the variants) of a toggle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected the toggle path as the only argument")
+			}
+			if (file == "") == !clear {
+				return fmt.Errorf("use either --file or --clear")
+			}
+
+			request := &setRulesRequest{Rules: []*entity.ToggleRule{}}
+			if file != "" {
+				rules, err := readRulesFile(file)
+				if err != nil {
+					return err
+				}
+				request.Variants = rules.Variants
+				// Os IDs são gerados pelo servidor; arquivos exportados da API podem trazê-los
+				for _, rule := range rules.Rules {
+					if rule != nil {
+						rule.ID = ""
+						request.Rules = append(request.Rules, rule)
+					}
+				}
+			}
+
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			app, toggle, err := resolveToggle(client, appRef, args[0])
+			if err != nil {
+				return err
+			}
+			request.Enabled = toggle.Enabled
+
+			path := fmt.Sprintf("/applications/%s/toggles/%s", escape(app.ID), escape(toggle.ID))
+			if err := client.do(http.MethodPut, path, request, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Set %d rules on %s in %s\n", len(request.Rules), toggle.Path, app.Name)
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, false)
+	fs.StringVar(&appRef, "app", "", "application ID or name (required)")
+	fs.StringVar(&file, "file", "", "YAML or JSON file with rules and optional variants, - reads the standard input")
+	fs.BoolVar(&clear, "clear", false, "remove all rules of the toggle")
+
+	return cmd
+}
+
+// readRulesFile lê o arquivo de regras em YAML ou JSON, rejeitando campos desconhecidos
+func readRulesFile(path string) (*rulesFile, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(converted))
+	decoder.DisallowUnknownFields()
+	var rules rulesFile
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+	return &rules, nil
+}
+
+// listToggles busca todos os toggles da aplicação
+func listToggles(client *apiClient, appID string) ([]*entity.Toggle, error) {
+	var toggles []*entity.Toggle
+	if err := client.do(http.MethodGet, "/applications/"+escape(appID)+"/toggles", nil, &toggles); err != nil {
+		return nil, err
+	}
+	return toggles, nil
+}
+
+// resolveToggle busca a aplicação e o toggle pelo caminho
+func resolveToggle(client *apiClient, appRef, path string) (*entity.ApplicationWithCounts, *entity.Toggle, error) {
+	app, err := resolveApplication(client, appRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	toggles, err := listToggles(client, app.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, toggle := range toggles {
+		if toggle.Path == path {
+			return app, toggle, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("toggle %q not found in %s", path, app.Name)
+}
+
+// buildToggleTree monta a árvore a partir da lista de toggles, com os filhos ordenados pelo nome
+func buildToggleTree(toggles []*entity.Toggle) []*toggleNode {
+	nodes := make(map[string]*toggleNode, len(toggles))
+	for _, toggle := range toggles {
+		nodes[toggle.ID] = &toggleNode{
+			ID:       toggle.ID,
+			Value:    toggle.Value,
+			Path:     toggle.Path,
+			Enabled:  toggle.Enabled,
+			Rules:    len(toggle.Rules),
+			Variants: len(toggle.Variants),
+			Managed:  toggle.Managed,
+			Children: []*toggleNode{},
+		}
+	}
+
+	roots := make([]*toggleNode, 0)
+	for _, toggle := range toggles {
+		node := nodes[toggle.ID]
+		if toggle.ParentID != nil && nodes[*toggle.ParentID] != nil {
+			parent := nodes[*toggle.ParentID]
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+
+	sortToggleNodes(roots)
+	return roots
+}
+
+// sortToggleNodes ordena os nós e seus descendentes pelo nome
+func sortToggleNodes(nodes []*toggleNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Value < nodes[j].Value })
+	for _, node := range nodes {
+		sortToggleNodes(node.Children)
+	}
+}
+
+// writeToggleTree imprime a árvore com o estado de cada toggle e o número de regras e variantes
+func writeToggleTree(w io.Writer, nodes []*toggleNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		state := "off"
+		if node.Enabled {
+			state = "on"
+		}
+		details := []string{state}
+		if node.Rules > 0 {
+			details = append(details, fmt.Sprintf("%d rules", node.Rules))
+		}
+		if node.Variants > 0 {
+			details = append(details, fmt.Sprintf("%d variants", node.Variants))
+		}
+		if node.Managed {
+			details = append(details, "managed")
+		}
+
+		fmt.Fprintf(w, "%s%s%s [%s]\n", prefix, branch, node.Value, strings.Join(details, ", "))
+		writeToggleTree(w, node.Children, prefix+indent)
+	}
+}
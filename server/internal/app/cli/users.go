@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/spf13/cobra"
+)
+
+// newUsersCommand cria o grupo de comandos de usuários
+func newUsersCommand() *cobra.Command {
+	users := newGroupCommand("users", "Manage users (root only)")
+
+	users.AddCommand(newUsersListCommand(), newUsersCreateCommand(), newUsersDeleteCommand())
+
+	return users
+}
+
+// newUsersListCommand cria o comando que lista os usuários
+func newUsersListCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the users",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			users, err := listUsers(client)
+			if err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), users)
+			}
+
+			rows := make([][]string, 0, len(users))
+			for _, user := range users {
+				teams := make([]string, 0, len(user.Teams))
+				for _, team := range user.Teams {
+					teams = append(teams, team.Name)
+				}
+				rows = append(rows, []string{user.ID, user.Username, string(user.Role), strings.Join(teams, ","), user.CreatedAt.Format("2006-01-02")})
+			}
+			return writeTable(cmd.OutOrStdout(), []string{"ID", "USERNAME", "ROLE", "TEAMS", "CREATED"}, rows)
+		},
+	}
+
+	options.setFlags(cmd.Flags(), true)
+
+	return cmd
+}
+
+// newUsersCreateCommand cria o comando que cria um usuário com uma senha temporária
+func newUsersCreateCommand() *cobra.Command {
+	options := &clientOptions{}
+	var username, role string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user with a temporary password",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if username == "" {
+				return fmt.Errorf("--username is required")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+
+			var response struct {
+				User     *entity.User `json:"user"`
+				Password string       `json:"password"`
+			}
+			if err := client.do(http.MethodPost, "/users", map[string]string{"username": username, "role": role}, &response); err != nil {
+				return err
+			}
+			if options.json() {
+				return writeJSON(cmd.OutOrStdout(), response)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created user %s (%s) with temporary password %s\n", response.User.Username, response.User.ID, response.Password)
+			fmt.Fprintln(cmd.ErrOrStderr(), "The user must change the password on the first login.")
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	options.setFlags(fs, true)
+	fs.StringVar(&username, "username", "", "username (required)")
+	fs.StringVar(&role, "role", string(entity.UserRoleUser), "role: root, admin or user")
+
+	return cmd
+}
+
+// newUsersDeleteCommand cria o comando que remove um usuário
+func newUsersDeleteCommand() *cobra.Command {
+	options := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "delete <user>",
+		Short: "Delete a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected the user (ID or name) as the only argument")
+			}
+			client, err := options.client()
+			if err != nil {
+				return err
+			}
+			user, err := resolveUser(client, args[0])
+			if err != nil {
+				return err
+			}
+			if err := client.do(http.MethodDelete, "/users/"+escape(user.ID), nil, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted user %s\n", user.Username)
+			return nil
+		},
+	}
+
+	options.setFlags(cmd.Flags(), false)
+
+	return cmd
+}
+
+// listUsers busca todos os usuários
+func listUsers(client *apiClient) ([]entity.User, error) {
+	var response struct {
+		Users []entity.User `json:"users"`
+	}
+	if err := client.do(http.MethodGet, "/users", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Users, nil
+}
+
+// resolveUser aceita o ID ou o nome do usuário
+func resolveUser(client *apiClient, ref string) (*entity.User, error) {
+	users, err := listUsers(client)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].ID == ref || users[i].Username == ref {
+			return &users[i], nil
+		}
+	}
+	return nil, fmt.Errorf("user %q not found", ref)
+}
@@ -41,5 +41,11 @@ func InitializeDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// O índice da busca de toggles é uma tabela FTS5, mantida por triggers a cada gravação
+	var fts5 bool
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil || !fts5 {
+		logger.Warn("SQLite was built without FTS5: the toggle search index cannot be read or kept in sync; build with -tags sqlite_fts5")
+	}
+
 	return db, nil
 }
@@ -3,19 +3,25 @@ package entity
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
 )
 
-// ActivationRuleType define os tipos de regras de ativação
-type ActivationRuleType string
+// ActivationRuleType define os tipos de regras de ativação; os tipos são definidos em rules,
+// que o avaliador também usa
+type ActivationRuleType = rules.Type
 
 const (
-	ActivationRuleTypePercentage    ActivationRuleType = "percentage"
-	ActivationRuleTypeParameter     ActivationRuleType = "parameter"
-	ActivationRuleTypeUserID        ActivationRuleType = "user_id"
-	ActivationRuleTypeIP            ActivationRuleType = "ip"
-	ActivationRuleTypeCountry       ActivationRuleType = "country"
-	ActivationRuleTypeTime          ActivationRuleType = "time"
-	ActivationRuleTypeCanary        ActivationRuleType = "canary"
+	ActivationRuleTypePercentage = rules.TypePercentage
+	ActivationRuleTypeParameter  = rules.TypeParameter
+	ActivationRuleTypeUserID     = rules.TypeUserID
+	ActivationRuleTypeIP         = rules.TypeIP
+	ActivationRuleTypeCountry    = rules.TypeCountry
+	ActivationRuleTypeTime       = rules.TypeTime
+	ActivationRuleTypeCanary     = rules.TypeCanary
+	ActivationRuleTypeSegment    = rules.TypeSegment
+	ActivationRuleTypeSemver     = rules.TypeSemver
+	ActivationRuleTypeNumber     = rules.TypeNumber
 )
 
 // ActivationRule representa uma regra de ativação para um toggle
@@ -23,6 +29,9 @@ type ActivationRule struct {
 	Type   ActivationRuleType `json:"type" gorm:"type:varchar(50)"`
 	Value  string             `json:"value" gorm:"type:varchar(255)"`
 	Config json.RawMessage    `json:"config,omitempty" gorm:"type:text"`
+
+	// Variant é a variante retornada quando a regra é satisfeita; vazio usa a distribuição por peso
+	Variant string `json:"variant,omitempty" gorm:"type:varchar(100)"`
 }
 
 // ValidateRule valida se a regra de ativação está correta
@@ -42,20 +51,28 @@ func (ar *ActivationRule) ValidateRule() error {
 			return fmt.Errorf("valor do user ID é obrigatório")
 		}
 	case ActivationRuleTypeIP:
-		if ar.Value == "" {
-			return fmt.Errorf("valor do IP é obrigatório")
+		if _, err := NormalizeIPList(ar.Value); err != nil {
+			return err
 		}
 	case ActivationRuleTypeCountry:
-		if ar.Value == "" {
-			return fmt.Errorf("valor do país é obrigatório")
+		if _, err := NormalizeCountryList(ar.Value); err != nil {
+			return err
 		}
 	case ActivationRuleTypeTime:
 		if ar.Value == "" {
 			return fmt.Errorf("valor do tempo é obrigatório")
 		}
 	case ActivationRuleTypeCanary:
-		if ar.Value == "" {
-			return fmt.Errorf("valor do canary é obrigatório")
+		if _, err := rules.ParseCanaryRule(ar.Value, ar.Config); err != nil {
+			return err
+		}
+	case ActivationRuleTypeSegment:
+		if len(ParseSegmentIDs(ar.Value)) == 0 {
+			return fmt.Errorf("ID do segmento é obrigatório")
+		}
+	case ActivationRuleTypeSemver, ActivationRuleTypeNumber:
+		if _, err := rules.ParseComparisonRule(ar.Type, ar.Value, ar.Config); err != nil {
+			return err
 		}
 	default:
 		return fmt.Errorf("tipo de regra inválido: %s", ar.Type)
@@ -72,6 +89,9 @@ func GetRuleTypeOptions() map[ActivationRuleType]string {
 		ActivationRuleTypeIP:         "IP Address - Ativar para IPs específicos",
 		ActivationRuleTypeCountry:    "Country - Ativar para países específicos",
 		ActivationRuleTypeTime:       "Time - Ativar em horários específicos",
-		ActivationRuleTypeCanary:     "Canary - Ativar para releases canário",
+		ActivationRuleTypeCanary:     "Canary - Ativar apenas nas instâncias canário (lista de instâncias ou porcentagem de instâncias)",
+		ActivationRuleTypeSegment:    "Segment - Ativar para os usuários de segmentos específicos",
+		ActivationRuleTypeSemver:     "Semver - Ativar comparando a versão semântica de um atributo (ex.: app_version)",
+		ActivationRuleTypeNumber:     "Number - Ativar comparando um atributo numérico",
 	}
 }
\ No newline at end of file
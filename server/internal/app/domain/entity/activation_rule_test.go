@@ -52,6 +52,110 @@ func TestActivationRule_ValidateRule(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "valid segment rule",
+			rule: ActivationRule{
+				Type:  ActivationRuleTypeSegment,
+				Value: "01JZNM42NKSANGHZ3G4KKXGCNW",
+			},
+			expectError: false,
+		},
+		{
+			name: "segment rule without IDs",
+			rule: ActivationRule{
+				Type:  ActivationRuleTypeSegment,
+				Value: " , ",
+			},
+			expectError: true,
+			errorMsg:    "ID do segmento é obrigatório",
+		},
+		{
+			name: "valid semver range rule",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeSemver,
+				Value:  "2.0.0-rc.1, 3.0.0",
+				Config: json.RawMessage(`{"attribute": "app_version", "operator": "range"}`),
+			},
+			expectError: false,
+		},
+		{
+			name: "semver rule without config",
+			rule: ActivationRule{
+				Type:  ActivationRuleTypeSemver,
+				Value: "2.0.0",
+			},
+			expectError: true,
+			errorMsg:    "config com attribute e operator é obrigatório",
+		},
+		{
+			name: "semver rule with invalid version",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeSemver,
+				Value:  "2.0",
+				Config: json.RawMessage(`{"attribute": "app_version", "operator": "gte"}`),
+			},
+			expectError: true,
+			errorMsg:    "versão semântica inválida '2.0'",
+		},
+		{
+			name: "semver rule with number operator",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeSemver,
+				Value:  "1.0.0,2.0.0",
+				Config: json.RawMessage(`{"attribute": "app_version", "operator": "between"}`),
+			},
+			expectError: true,
+			errorMsg:    "operador inválido para semver: between (use eq, gt, gte, lt, lte ou range)",
+		},
+		{
+			name: "semver range with inverted bounds",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeSemver,
+				Value:  "2.0.0,2.0.0-beta",
+				Config: json.RawMessage(`{"attribute": "app_version", "operator": "range"}`),
+			},
+			expectError: true,
+			errorMsg:    "o mínimo do range precisa ser menor que o máximo",
+		},
+		{
+			name: "valid number between rule",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeNumber,
+				Value:  "18,65.5",
+				Config: json.RawMessage(`{"attribute": "age", "operator": "between"}`),
+			},
+			expectError: false,
+		},
+		{
+			name: "number rule with invalid number",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeNumber,
+				Value:  "NaN",
+				Config: json.RawMessage(`{"attribute": "age", "operator": "gt"}`),
+			},
+			expectError: true,
+			errorMsg:    "número inválido 'NaN'",
+		},
+		{
+			name: "number rule with two values for gt",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeNumber,
+				Value:  "1,2",
+				Config: json.RawMessage(`{"attribute": "age", "operator": "gt"}`),
+			},
+			expectError: true,
+			errorMsg:    "o operador gt exige um único valor",
+		},
+		{
+			name: "number rule with unknown config field",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeNumber,
+				Value:  "1",
+				Config: json.RawMessage(`{"attribute": "age", "operator": "gt", "strict": true}`),
+			},
+			expectError: true,
+			errorMsg:    "config inválido: json: unknown field \"strict\"",
+		},
 		{
 			name: "valid time rule",
 			rule: ActivationRule{
@@ -68,6 +172,15 @@ func TestActivationRule_ValidateRule(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "valid canary percentage of instances",
+			rule: ActivationRule{
+				Type:   ActivationRuleTypeCanary,
+				Value:  "10",
+				Config: json.RawMessage(`{"attribute": "host", "strategy": "percentage"}`),
+			},
+			expectError: false,
+		},
 		{
 			name: "empty percentage value",
 			rule: ActivationRule{
@@ -209,6 +322,9 @@ func TestGetRuleTypeOptions(t *testing.T) {
 		ActivationRuleTypeCountry,
 		ActivationRuleTypeTime,
 		ActivationRuleTypeCanary,
+		ActivationRuleTypeSegment,
+		ActivationRuleTypeSemver,
+		ActivationRuleTypeNumber,
 	}
 
 	// Verify all expected types are present
@@ -2,14 +2,25 @@ package entity
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Application representa uma aplicação no sistema
 type Application struct {
-	ID        string    `json:"id" gorm:"primaryKey;type:varchar(26)"`
-	Name      string    `json:"name" gorm:"not null;type:varchar(255)"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string         `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	Name      string         `json:"name" gorm:"not null;type:varchar(255)"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"` // Preenchido enquanto a aplicação está na lixeira
+
+	// KillSwitch força todos os toggles da aplicação a desligados para os SDKs e a avaliação,
+	// sem alterar o estado de cada toggle; KillSwitchAt é o instante em que foi ativado
+	KillSwitch   bool       `json:"kill_switch" gorm:"not null;default:false"`
+	KillSwitchAt *time.Time `json:"kill_switch_at,omitempty"`
+
+	// Managed indica que a aplicação foi criada pela sincronização declarativa
+	Managed bool `json:"managed" gorm:"not null;default:false"`
 
 	// Relacionamentos - usar ponteiro para evitar importação circular
 	Teams []*Team `json:"teams,omitempty" gorm:"many2many:team_applications;"`
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditAction identifica a ação registrada em um evento de auditoria
+type AuditAction string
+
+const (
+	AuditActionKillSwitchActivated   AuditAction = "kill_switch.activated"   // Todos os toggles da aplicação foram forçados a desligados
+	AuditActionKillSwitchDeactivated AuditAction = "kill_switch.deactivated" // Os toggles voltaram ao estado individual
+	AuditActionFreezeOverridden      AuditAction = "freeze.overridden"       // Um usuário root alterou toggles durante uma janela de congelamento
+)
+
+// AuditEvent é um registro imutável de uma ação sobre a aplicação que não pertence a um único toggle
+type AuditEvent struct {
+	ID        string      `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	AppID     string      `json:"app_id" gorm:"not null;type:varchar(26);index"`
+	Action    AuditAction `json:"action" gorm:"not null;type:varchar(50)"`
+	Detail    string      `json:"detail" gorm:"type:text"`
+	ActorID   string      `json:"actor_id" gorm:"type:varchar(26)"`
+	Actor     string      `json:"actor" gorm:"type:varchar(100)"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// NewAuditEvent cria o evento de auditoria; sem usuário a ação é atribuída ao sistema
+func NewAuditEvent(appID string, action AuditAction, detail string, actor *User) *AuditEvent {
+	event := &AuditEvent{
+		AppID:  appID,
+		Action: action,
+		Detail: detail,
+		Actor:  "system",
+	}
+	if actor != nil {
+		event.ActorID = actor.ID
+		event.Actor = actor.Username
+	}
+	return event
+}
+
+// BeforeCreate hook para gerar ID único
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = generateULID()
+	}
+	return nil
+}
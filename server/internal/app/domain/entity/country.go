@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// CountryResolver resolve o país (código ISO 3166-1 alfa-2) de um endereço IP
+type CountryResolver interface {
+	Country(addr netip.Addr) (string, bool)
+}
+
+// countryCodes são os códigos ISO 3166-1 alfa-2 atribuídos oficialmente
+var countryCodes = func() map[string]bool {
+	codes := make(map[string]bool)
+	for _, code := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+		BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+		EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+		HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+		LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+		NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+		SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+		TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`) {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// IsCountryCode verifica se o valor é um código de país ISO 3166-1 alfa-2, sem diferenciar maiúsculas
+func IsCountryCode(value string) bool {
+	return countryCodes[strings.ToUpper(strings.TrimSpace(value))]
+}
+
+// ResolveCountry valida e normaliza o país informado ou, na ausência dele, o obtém a partir do IP.
+// resolver é opcional; sem ele ou sem um IP válido o país fica vazio.
+func ResolveCountry(country, ip string, resolver CountryResolver) (string, error) {
+	if country != "" {
+		if !IsCountryCode(country) {
+			appErr := NewAppError(ErrCodeValidation, "validation failed")
+			appErr.AddDetail("context.country", "Country must be an ISO 3166-1 alpha-2 code")
+			return "", appErr
+		}
+		return strings.ToUpper(strings.TrimSpace(country)), nil
+	}
+
+	if resolver == nil || ip == "" {
+		return "", nil
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", nil
+	}
+	resolved, _ := resolver.Country(addr)
+	return resolved, nil
+}
+
+// NormalizeCountryList valida e normaliza a lista separada por vírgulas de uma regra do tipo country:
+// os códigos ficam em maiúsculas e sem repetições
+func NormalizeCountryList(value string) (string, error) {
+	codes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		code := strings.ToUpper(strings.TrimSpace(item))
+		if code == "" {
+			continue
+		}
+		if !countryCodes[code] {
+			return "", fmt.Errorf("código de país ISO 3166-1 inválido '%s'", strings.TrimSpace(item))
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	if len(codes) == 0 {
+		return "", fmt.Errorf("valor do país é obrigatório")
+	}
+	return strings.Join(codes, ","), nil
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIsCountryCode(t *testing.T) {
+	for _, code := range []string{"BR", "us", " pt "} {
+		if !IsCountryCode(code) {
+			t.Errorf("Expected %q to be a country code", code)
+		}
+	}
+	for _, code := range []string{"", "XX", "BRA", "Brazil", "UK"} {
+		if IsCountryCode(code) {
+			t.Errorf("Expected %q not to be a country code", code)
+		}
+	}
+}
+
+// staticResolver resolve todos os IPs para o mesmo país
+type staticResolver string
+
+func (r staticResolver) Country(addr netip.Addr) (string, bool) {
+	return string(r), r != ""
+}
+
+func TestResolveCountry(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		ip       string
+		resolver CountryResolver
+		expected string
+		wantErr  bool
+	}{
+		{"informed country is upper-cased", " br ", "", nil, "BR", false},
+		{"informed country wins over the IP", "pt", "203.0.113.7", staticResolver("BR"), "PT", false},
+		{"invalid informed country", "UK", "", nil, "", true},
+		{"country from the IP", "", "203.0.113.7", staticResolver("BR"), "BR", false},
+		{"unknown IP", "", "203.0.113.7", staticResolver(""), "", false},
+		{"invalid IP", "", "not-an-ip", staticResolver("BR"), "", false},
+		{"no resolver", "", "203.0.113.7", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			country, err := ResolveCountry(tt.country, tt.ip, tt.resolver)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if country != tt.expected {
+				t.Errorf("Expected country %q, got %q", tt.expected, country)
+			}
+		})
+	}
+}
+
+func TestNormalizeCountryList(t *testing.T) {
+	got, err := NormalizeCountryList(" br, PT,,br ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != "BR,PT" {
+		t.Errorf("Expected BR,PT, got %q", got)
+	}
+
+	if _, err := NormalizeCountryList("BR,UK"); err == nil || err.Error() != "código de país ISO 3166-1 inválido 'UK'" {
+		t.Errorf("Expected invalid code error, got %v", err)
+	}
+	if _, err := NormalizeCountryList(" , "); err == nil {
+		t.Error("Expected empty list to be invalid")
+	}
+}
@@ -55,4 +55,8 @@ const (
 	ErrCodeInternal      = "T0005"
 	ErrCodeInvalidPath   = "T0006"
 	ErrCodeInvalidToggle = "T0007"
+	ErrCodeInUse         = "T0008"
+	ErrCodeFrozen        = "T0009" // Alteração bloqueada por uma janela de congelamento
+	ErrCodeManaged       = "T0010" // Alteração manual de um recurso gerenciado pela sincronização declarativa
+	ErrCodeConflict      = "T0011" // Alteração feita sobre um estado que outra operação já modificou
 )
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limites das janelas de congelamento
+const (
+	MaxFreezeReasonLength = 500
+	MaxFreezeExemptUsers  = 100
+)
+
+// FreezeWindow é um período em que os toggles não podem ser alterados, como um evento de pico.
+// Sem aplicação, a janela é global e vale para todas as aplicações.
+type FreezeWindow struct {
+	ID          string            `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	AppID       *string           `json:"app_id" gorm:"type:varchar(26);index"`
+	StartsAt    time.Time         `json:"starts_at" gorm:"not null"`
+	EndsAt      time.Time         `json:"ends_at" gorm:"not null"`
+	Reason      string            `json:"reason" gorm:"not null;type:varchar(500)"`
+	ExemptUsers FreezeExemptUsers `json:"exempt_users" gorm:"type:text"` // IDs ou nomes dos usuários que podem alterar toggles durante a janela
+	ActorID     string            `json:"actor_id" gorm:"type:varchar(26)"`
+	Actor       string            `json:"actor" gorm:"type:varchar(100)"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// NewFreezeWindow cria uma janela de congelamento; sem usuário a janela é atribuída ao sistema
+func NewFreezeWindow(appID *string, startsAt, endsAt time.Time, reason string, exemptUsers []string, actor *User) *FreezeWindow {
+	window := &FreezeWindow{
+		AppID:       appID,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Reason:      strings.TrimSpace(reason),
+		ExemptUsers: FreezeExemptUsers{},
+		Actor:       "system",
+	}
+	for _, user := range exemptUsers {
+		if user = strings.TrimSpace(user); user != "" {
+			window.ExemptUsers = append(window.ExemptUsers, user)
+		}
+	}
+	if actor != nil {
+		window.ActorID = actor.ID
+		window.Actor = actor.Username
+	}
+	return window
+}
+
+// Validate valida a janela antes de gravá-la
+func (w *FreezeWindow) Validate() *ValidationResult {
+	result := NewValidationResult()
+	if w.StartsAt.IsZero() {
+		result.AddError("starts_at", "Start is required")
+	}
+	if w.EndsAt.IsZero() {
+		result.AddError("ends_at", "End is required")
+	} else if !w.EndsAt.After(w.StartsAt) {
+		result.AddError("ends_at", "End must be after the start")
+	}
+	if w.Reason == "" {
+		result.AddError("reason", "Reason is required")
+	} else if len(w.Reason) > MaxFreezeReasonLength {
+		result.AddError("reason", fmt.Sprintf("Reason must be at most %d characters", MaxFreezeReasonLength))
+	}
+	if len(w.ExemptUsers) > MaxFreezeExemptUsers {
+		result.AddError("exempt_users", fmt.Sprintf("At most %d exempt users are allowed", MaxFreezeExemptUsers))
+	}
+	return result
+}
+
+// IsActive indica se a janela está em vigor no instante informado; o fim não faz parte da janela
+func (w *FreezeWindow) IsActive(at time.Time) bool {
+	return !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+}
+
+// Exempts indica se o usuário está isento da janela, pelo ID ou pelo nome
+func (w *FreezeWindow) Exempts(user *User) bool {
+	if user == nil {
+		return false
+	}
+	for _, exempt := range w.ExemptUsers {
+		if exempt == user.ID || exempt == user.Username {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate hook para gerar ID único
+func (w *FreezeWindow) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = generateULID()
+	}
+	return nil
+}
+
+// FreezeExemptUsers é a lista de usuários isentos de uma janela, gravada como JSON
+type FreezeExemptUsers []string
+
+// Value serializa os usuários para o banco de dados
+func (u FreezeExemptUsers) Value() (driver.Value, error) {
+	if u == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(u))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa os usuários lidos do banco de dados
+func (u *FreezeExemptUsers) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*u = FreezeExemptUsers{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type for freeze exempt users: %T", value)
+	}
+
+	if len(data) == 0 {
+		*u = FreezeExemptUsers{}
+		return nil
+	}
+
+	var users []string
+	if err := json.Unmarshal(data, &users); err != nil {
+		return err
+	}
+	*u = users
+	return nil
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFreezeWindow_Validate(t *testing.T) {
+	start := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		window *FreezeWindow
+		field  string
+	}{
+		{"valid", NewFreezeWindow(nil, start, start.Add(time.Hour), "black friday", nil, nil), ""},
+		{"missing start", NewFreezeWindow(nil, time.Time{}, start, "black friday", nil, nil), "starts_at"},
+		{"end before start", NewFreezeWindow(nil, start, start.Add(-time.Hour), "black friday", nil, nil), "ends_at"},
+		{"missing reason", NewFreezeWindow(nil, start, start.Add(time.Hour), "  ", nil, nil), "reason"},
+		{"long reason", NewFreezeWindow(nil, start, start.Add(time.Hour), strings.Repeat("a", MaxFreezeReasonLength+1), nil, nil), "reason"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.window.Validate()
+			if tt.field == "" {
+				if !result.IsValid {
+					t.Errorf("Expected valid window, got %v", result.Errors)
+				}
+				return
+			}
+			if result.IsValid || result.Errors[0].Field != tt.field {
+				t.Errorf("Expected error on %s, got %v", tt.field, result.Errors)
+			}
+		})
+	}
+}
+
+func TestFreezeWindow_IsActiveAndExempts(t *testing.T) {
+	start := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	window := NewFreezeWindow(nil, start, start.Add(time.Hour), "black friday", []string{"release-manager", "01JZNM42NKSANGHZ3G4KKXGCNW"}, &User{ID: "u1", Username: "admin"})
+
+	if window.IsActive(start.Add(-time.Second)) || !window.IsActive(start) || !window.IsActive(start.Add(59*time.Minute)) || window.IsActive(start.Add(time.Hour)) {
+		t.Error("Expected the window to include the start and exclude the end")
+	}
+
+	if !window.Exempts(&User{ID: "x", Username: "release-manager"}) || !window.Exempts(&User{ID: "01JZNM42NKSANGHZ3G4KKXGCNW", Username: "ops"}) {
+		t.Error("Expected users exempted by name or ID")
+	}
+	if window.Exempts(&User{ID: "u2", Username: "dev"}) || window.Exempts(nil) {
+		t.Error("Expected other users not to be exempt")
+	}
+	if window.Actor != "admin" || window.ActorID != "u1" {
+		t.Errorf("Expected actor to be recorded, got %q", window.Actor)
+	}
+}
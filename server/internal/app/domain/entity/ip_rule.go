@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// MaxIPRuleEntries limita os endereços e blocos CIDR de uma regra do tipo ip
+const MaxIPRuleEntries = 10000
+
+// NormalizeIPList valida e normaliza a lista separada por vírgulas de uma regra do tipo ip.
+// Aceita endereços IPv4 e IPv6 e blocos CIDR; endereços isolados são escritos sem o tamanho
+// do prefixo, blocos são mascarados (10.1.2.3/8 vira 10.0.0.0/8) e entradas repetidas são removidas.
+func NormalizeIPList(value string) (string, error) {
+	entries := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		prefix, err := rules.ParsePrefix(item)
+		if err != nil {
+			return "", fmt.Errorf("IP ou bloco CIDR inválido '%s'", item)
+		}
+
+		normalized := prefix.String()
+		if prefix.IsSingleIP() {
+			normalized = prefix.Addr().String()
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			entries = append(entries, normalized)
+		}
+	}
+
+	if len(entries) == 0 {
+		return "", fmt.Errorf("valor do IP é obrigatório")
+	}
+	if len(entries) > MaxIPRuleEntries {
+		return "", fmt.Errorf("a regra de IP aceita no máximo %d endereços ou blocos", MaxIPRuleEntries)
+	}
+	return strings.Join(entries, ","), nil
+}
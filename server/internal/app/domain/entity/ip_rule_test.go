@@ -0,0 +1,55 @@
+package entity
+
+import "testing"
+
+func TestNormalizeIPList(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{"single ipv4", "10.0.0.1", "10.0.0.1", false},
+		{"ipv4 host prefix", "10.0.0.1/32", "10.0.0.1", false},
+		{"masks cidr", " 10.1.2.3/8 , 192.168.0.0/16", "10.0.0.0/8,192.168.0.0/16", false},
+		{"ipv6 compressed", "2001:0db8:0000::0001, 2001:db8::/32", "2001:db8::1,2001:db8::/32", false},
+		{"mapped ipv4", "::ffff:10.0.0.1", "10.0.0.1", false},
+		{"removes duplicates", "10.0.0.1,10.0.0.1/32,,10.0.0.1", "10.0.0.1", false},
+		{"empty", " , ", "", true},
+		{"invalid address", "10.0.0.256", "", true},
+		{"invalid prefix", "10.0.0.0/33", "", true},
+		{"hostname", "example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeIPList(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestToggle_SetRules_NormalizesIPConditions(t *testing.T) {
+	toggle := NewToggle("checkout", true, "checkout", 0, nil, "app")
+	toggle.SetRules([]*ToggleRule{
+		{Conditions: RuleConditions{{Type: ActivationRuleTypeIP, Value: "10.1.2.3/8, 2001:DB8::1"}}},
+	})
+
+	if got := toggle.Rules[0].Conditions[0].Value; got != "10.0.0.0/8,2001:db8::1" {
+		t.Errorf("Expected normalized rule value, got %q", got)
+	}
+	if toggle.ActivationRule == nil || toggle.ActivationRule.Value != "10.0.0.0/8,2001:db8::1" {
+		t.Errorf("Expected normalized legacy mirror, got %+v", toggle.ActivationRule)
+	}
+}
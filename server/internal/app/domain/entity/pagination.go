@@ -0,0 +1,166 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Limites da paginação das listagens
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ErrInvalidCursor indica que o cursor de paginação informado não pôde ser decodificado
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortOrder define a direção da ordenação
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// ListFields define os campos de ordenação e filtro aceitos por uma listagem
+type ListFields struct {
+	Sort         []string
+	DefaultSort  string
+	DefaultOrder SortOrder
+	Filters      []string
+}
+
+// Campos aceitos por cada listagem paginada
+var (
+	ToggleListFields = ListFields{
+		Sort:         []string{"path", "value", "level", "created_at", "updated_at"},
+		DefaultSort:  "path",
+		DefaultOrder: SortAsc,
+		Filters:      []string{"path", "enabled", "owner", "kind", "tag"},
+	}
+	ApplicationListFields = ListFields{
+		Sort:         []string{"name", "created_at", "updated_at"},
+		DefaultSort:  "created_at",
+		DefaultOrder: SortDesc,
+		Filters:      []string{"name"},
+	}
+	UserListFields = ListFields{
+		Sort:         []string{"username", "role", "created_at"},
+		DefaultSort:  "username",
+		DefaultOrder: SortAsc,
+		Filters:      []string{"username", "role"},
+	}
+	TeamListFields = ListFields{
+		Sort:         []string{"name", "created_at"},
+		DefaultSort:  "created_at",
+		DefaultOrder: SortDesc,
+		Filters:      []string{"name"},
+	}
+)
+
+// PageRequest representa os parâmetros de uma listagem paginada por cursor
+type PageRequest struct {
+	Limit   int
+	Cursor  string
+	Sort    string
+	Order   SortOrder
+	Filters map[string][]string
+}
+
+// Filter retorna o primeiro valor de um filtro, ou vazio se não informado
+func (r *PageRequest) Filter(name string) string {
+	if values := r.Filters[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Normalize aplica os valores padrão e valida a requisição contra os campos aceitos pela listagem
+func (r *PageRequest) Normalize(fields ListFields) *ValidationResult {
+	result := NewValidationResult()
+
+	if r.Limit == 0 {
+		r.Limit = DefaultPageLimit
+	}
+	if r.Limit < 1 || r.Limit > MaxPageLimit {
+		result.AddError("limit", fmt.Sprintf("Limit must be between 1 and %d", MaxPageLimit))
+	}
+
+	if r.Sort == "" {
+		r.Sort = fields.DefaultSort
+		if r.Order == "" {
+			r.Order = fields.DefaultOrder
+		}
+	}
+	if !containsString(fields.Sort, r.Sort) {
+		result.AddError("sort", "Sort must be one of: "+strings.Join(fields.Sort, ", "))
+	}
+
+	r.Order = SortOrder(strings.ToLower(string(r.Order)))
+	if r.Order == "" {
+		r.Order = SortAsc
+	}
+	if r.Order != SortAsc && r.Order != SortDesc {
+		result.AddError("order", "Order must be asc or desc")
+	}
+
+	for name := range r.Filters {
+		if !containsString(fields.Filters, name) {
+			result.AddError(name, "Unsupported filter")
+		}
+	}
+
+	if r.Cursor != "" {
+		if _, err := DecodeCursor(r.Cursor); err != nil {
+			result.AddError("cursor", "Invalid cursor")
+		}
+	}
+
+	return result
+}
+
+// PageCursor representa a posição do último item de uma página: o valor do campo ordenado e o ID
+type PageCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// EncodeCursor gera um cursor opaco a partir do valor ordenado e do ID do último item
+func EncodeCursor(value, id string) string {
+	data, _ := json.Marshal(&PageCursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodifica um cursor gerado por EncodeCursor
+func DecodeCursor(cursor string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var decoded PageCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &decoded, nil
+}
+
+// Page representa o envelope padrão das listagens paginadas
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	Total      int64  `json:"total"`
+}
+
+// containsString verifica se o valor está presente na lista
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
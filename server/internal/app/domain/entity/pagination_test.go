@@ -0,0 +1,72 @@
+package entity
+
+import "testing"
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := EncodeCursor("feature.checkout", "01JZNM42NKSANGHZ3G4KKXGCNW")
+
+	decoded, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded.Value != "feature.checkout" || decoded.ID != "01JZNM42NKSANGHZ3G4KKXGCNW" {
+		t.Errorf("Unexpected cursor %+v", decoded)
+	}
+
+	for _, invalid := range []string{"not-base64!", "bm90LWpzb24", EncodeCursor("value", "")} {
+		if _, err := DecodeCursor(invalid); err != ErrInvalidCursor {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", invalid, err)
+		}
+	}
+}
+
+func TestPageRequest_Normalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		req := &PageRequest{}
+		if result := req.Normalize(ApplicationListFields); !result.IsValid {
+			t.Fatalf("Expected valid request, got %v", result.Errors)
+		}
+		if req.Limit != DefaultPageLimit || req.Sort != "created_at" || req.Order != SortDesc {
+			t.Errorf("Unexpected defaults %+v", req)
+		}
+	})
+
+	t.Run("explicit sort defaults to ascending", func(t *testing.T) {
+		req := &PageRequest{Sort: "name"}
+		req.Normalize(ApplicationListFields)
+		if req.Order != SortAsc {
+			t.Errorf("Expected asc order, got %s", req.Order)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		req   *PageRequest
+		field string
+	}{
+		{"limit too large", &PageRequest{Limit: MaxPageLimit + 1}, "limit"},
+		{"negative limit", &PageRequest{Limit: -1}, "limit"},
+		{"unknown sort", &PageRequest{Sort: "password"}, "sort"},
+		{"invalid order", &PageRequest{Order: "sideways"}, "order"},
+		{"unsupported filter", &PageRequest{Filters: map[string][]string{"secret": {"x"}}}, "secret"},
+		{"invalid cursor", &PageRequest{Cursor: "???"}, "cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.req.Normalize(UserListFields)
+			if result.IsValid {
+				t.Fatal("Expected validation error")
+			}
+			found := false
+			for _, validationErr := range result.Errors {
+				if validationErr.Field == tt.field {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected error on %s", tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Limites da busca de toggles
+const (
+	MinSearchTermLength = 2
+	MaxSearchTermLength = 255
+	DefaultSearchLimit  = 20
+	MaxSearchLimit      = 100
+)
+
+// SearchMatchField indica em qual campo o termo de busca foi encontrado
+type SearchMatchField string
+
+const (
+	SearchMatchPath        SearchMatchField = "path"
+	SearchMatchValue       SearchMatchField = "value"
+	SearchMatchDescription SearchMatchField = "description"
+	SearchMatchRuleValue   SearchMatchField = "rule_value"
+)
+
+// Pontuações usadas para ordenar os resultados da busca, da correspondência mais forte para a mais fraca
+const (
+	SearchScoreExactPath    = 100
+	SearchScoreExactValue   = 90
+	SearchScorePathPrefix   = 80
+	SearchScoreValuePrefix  = 70
+	SearchScorePathContains = 60
+	SearchScoreDescription  = 40
+	SearchScoreRuleValue    = 20
+)
+
+// ToggleSearchQuery representa uma busca de toggles restrita às aplicações acessíveis
+type ToggleSearchQuery struct {
+	Term            string
+	AppIDs          []string // Aplicações acessíveis quando AllApplications é falso
+	AllApplications bool
+	Limit           int
+	Offset          int
+}
+
+// ToggleSearchResult representa um toggle encontrado na busca
+type ToggleSearchResult struct {
+	ToggleID     string           `json:"toggle_id"`
+	AppID        string           `json:"app_id"`
+	AppName      string           `json:"app_name"`
+	Path         string           `json:"path"`
+	Value        string           `json:"value"`
+	Description  string           `json:"description"`
+	Enabled      bool             `json:"enabled"`
+	RuleValue    string           `json:"rule_value,omitempty"`
+	MatchedField SearchMatchField `json:"matched_field"`
+	Score        int              `json:"score"`
+}
+
+// ToggleSearchResponse representa uma página de resultados da busca
+type ToggleSearchResponse struct {
+	Query  string                `json:"query"`
+	Items  []*ToggleSearchResult `json:"items"`
+	Total  int64                 `json:"total"`
+	Limit  int                   `json:"limit"`
+	Offset int                   `json:"offset"`
+}
+
+// NormalizeSearchTerm remove espaços e converte o termo de busca para minúsculas
+func NormalizeSearchTerm(term string) string {
+	return strings.ToLower(strings.TrimSpace(term))
+}
+
+// ValidateSearchQuery valida o termo e a paginação de uma busca
+func ValidateSearchQuery(term string, limit, offset int) *ValidationResult {
+	result := NewValidationResult()
+
+	length := utf8.RuneCountInString(term)
+	if length < MinSearchTermLength {
+		result.AddError("q", "Search term must have at least 2 characters")
+	} else if length > MaxSearchTermLength {
+		result.AddError("q", "Search term must be at most 255 characters")
+	}
+
+	if limit < 1 || limit > MaxSearchLimit {
+		result.AddError("limit", "Limit must be between 1 and 100")
+	}
+
+	if offset < 0 {
+		result.AddError("offset", "Offset must not be negative")
+	}
+
+	return result
+}
+
+// SearchMatchFieldForScore retorna o campo correspondente a uma pontuação calculada pelo banco
+func SearchMatchFieldForScore(score int) SearchMatchField {
+	switch score {
+	case SearchScoreExactPath, SearchScorePathPrefix, SearchScorePathContains:
+		return SearchMatchPath
+	case SearchScoreExactValue, SearchScoreValuePrefix:
+		return SearchMatchValue
+	case SearchScoreDescription:
+		return SearchMatchDescription
+	case SearchScoreRuleValue:
+		return SearchMatchRuleValue
+	}
+	return ""
+}
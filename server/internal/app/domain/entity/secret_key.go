@@ -20,6 +20,13 @@ type SecretKey struct {
 	CreatedAt     time.Time   `json:"created_at"`
 	UpdatedAt     time.Time   `json:"updated_at"`
 
+	// Telemetria de uso (atualizada em lote pelo SecretKeyUsageTracker)
+	LastUsedAt      *time.Time `json:"last_used_at" gorm:"default:null"`
+	LastUsedIP      string     `json:"last_used_ip" gorm:"type:varchar(45)"`
+	LastUsedAgent   string     `json:"last_used_user_agent" gorm:"type:varchar(255)"`
+	UsageCount      int64      `json:"usage_count" gorm:"not null;default:0"`
+	Unused          bool       `json:"unused" gorm:"-"` // Calculado na leitura, não persistido
+
 	// Relacionamentos
 	Application Application `json:"application,omitempty" gorm:"foreignKey:ApplicationID"`
 	Creator     User        `json:"creator,omitempty" gorm:"foreignKey:CreatedBy"`
@@ -90,6 +97,24 @@ func (sk *SecretKey) Validate() error {
 	return nil
 }
 
+// IsUnusedSince verifica se a chave não é usada desde o instante informado.
+// Chaves que nunca foram usadas consideram a data de criação.
+func (sk *SecretKey) IsUnusedSince(since time.Time) bool {
+	if sk.LastUsedAt == nil {
+		return sk.CreatedAt.Before(since)
+	}
+	return sk.LastUsedAt.Before(since)
+}
+
+// SecretKeyUsage representa o uso acumulado de uma secret key ainda não persistido
+type SecretKeyUsage struct {
+	SecretKeyID string
+	Count       int64
+	LastUsedAt  time.Time
+	LastIP      string
+	LastAgent   string
+}
+
 // GetMaskedKey retorna uma versão mascarada da chave para exibição
 func (sk *SecretKey) GetMaskedKey() string {
 	return "sk_****...****"
@@ -0,0 +1,254 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+	"github.com/manorfm/totoogle/internal/app/domain/semver"
+	"gorm.io/gorm"
+)
+
+// Limites dos segmentos
+const (
+	MaxSegmentNameLength        = 100
+	MaxSegmentDescriptionLength = 1000
+	MaxSegmentConstraints       = 20
+	MaxSegmentConstraintValues  = 10000
+)
+
+var segmentNameRegex = regexp.MustCompile(`^[a-zA-Z0-9\s\-_\.]+$`)
+
+// SegmentOperator define como um atributo do contexto é comparado com os valores de uma restrição;
+// os operadores são definidos em rules, que o avaliador também usa
+type SegmentOperator = rules.SegmentOperator
+
+const (
+	SegmentOperatorIn        = rules.SegmentOperatorIn
+	SegmentOperatorNotIn     = rules.SegmentOperatorNotIn
+	SegmentOperatorCIDR      = rules.SegmentOperatorCIDR
+	SegmentOperatorEq        = rules.SegmentOperatorEq
+	SegmentOperatorGt        = rules.SegmentOperatorGt
+	SegmentOperatorGte       = rules.SegmentOperatorGte
+	SegmentOperatorLt        = rules.SegmentOperatorLt
+	SegmentOperatorLte       = rules.SegmentOperatorLte
+	SegmentOperatorSemverEq  = rules.SegmentOperatorSemverEq
+	SegmentOperatorSemverGt  = rules.SegmentOperatorSemverGt
+	SegmentOperatorSemverGte = rules.SegmentOperatorSemverGte
+	SegmentOperatorSemverLt  = rules.SegmentOperatorSemverLt
+	SegmentOperatorSemverLte = rules.SegmentOperatorSemverLte
+)
+
+// SegmentConstraint representa uma restrição de um segmento sobre um atributo do contexto.
+// Atributos conhecidos (key, user_id, parameter, ip, country) vêm do próprio contexto;
+// os demais são buscados nos atributos livres.
+type SegmentConstraint struct {
+	Attribute string          `json:"attribute"`
+	Operator  SegmentOperator `json:"operator"`
+	Values    []string        `json:"values"`
+}
+
+// SegmentConstraints representa as restrições de um segmento, persistidas como um array JSON
+type SegmentConstraints []*SegmentConstraint
+
+// Value serializa as restrições para o banco de dados
+func (c SegmentConstraints) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]*SegmentConstraint(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa as restrições lidas do banco de dados
+func (c *SegmentConstraints) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = SegmentConstraints{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type for segment constraints: %T", value)
+	}
+
+	if len(data) == 0 {
+		*c = SegmentConstraints{}
+		return nil
+	}
+
+	var constraints []*SegmentConstraint
+	if err := json.Unmarshal(data, &constraints); err != nil {
+		return err
+	}
+	*c = constraints
+	return nil
+}
+
+// Segment representa um grupo reutilizável de usuários definido por restrições (todas precisam ser satisfeitas).
+// Segmentos sem aplicação são globais e podem ser referenciados pelos toggles de qualquer aplicação.
+type Segment struct {
+	ID          string             `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	AppID       *string            `json:"app_id" gorm:"type:varchar(26);index"`
+	Name        string             `json:"name" gorm:"not null;type:varchar(100)"`
+	Description string             `json:"description" gorm:"type:text"`
+	Constraints SegmentConstraints `json:"constraints" gorm:"type:text"`
+	Managed     bool               `json:"managed" gorm:"not null;default:false"` // Definido pela sincronização declarativa
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// BeforeCreate hook para gerar ID único
+func (s *Segment) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = generateULID()
+	}
+	return nil
+}
+
+// NewSegment cria uma nova instância de Segment; appID nil cria um segmento global
+func NewSegment(appID *string, name, description string, constraints SegmentConstraints) *Segment {
+	if constraints == nil {
+		constraints = SegmentConstraints{}
+	}
+	return &Segment{
+		ID:          generateULID(),
+		AppID:       appID,
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		Constraints: constraints,
+	}
+}
+
+// IsGlobal indica se o segmento não pertence a uma aplicação
+func (s *Segment) IsGlobal() bool {
+	return s.AppID == nil
+}
+
+// AvailableTo indica se o segmento pode ser referenciado pelos toggles da aplicação
+func (s *Segment) AvailableTo(appID string) bool {
+	return s.IsGlobal() || *s.AppID == appID
+}
+
+// Validate valida o nome, a descrição e as restrições do segmento
+func (s *Segment) Validate() *ValidationResult {
+	result := NewValidationResult()
+
+	switch {
+	case strings.TrimSpace(s.Name) == "":
+		result.AddError("name", "Segment name is required")
+	case utf8.RuneCountInString(s.Name) > MaxSegmentNameLength:
+		result.AddError("name", fmt.Sprintf("Segment name must be at most %d characters", MaxSegmentNameLength))
+	case !segmentNameRegex.MatchString(s.Name):
+		result.AddError("name", "Segment name contains invalid characters. Only letters, numbers, spaces, hyphens, underscores and dots are allowed")
+	}
+
+	if utf8.RuneCountInString(s.Description) > MaxSegmentDescriptionLength {
+		result.AddError("description", fmt.Sprintf("Description must be at most %d characters", MaxSegmentDescriptionLength))
+	}
+
+	if len(s.Constraints) == 0 {
+		result.AddError("constraints", "A segment needs at least one constraint")
+	}
+	if len(s.Constraints) > MaxSegmentConstraints {
+		result.AddError("constraints", fmt.Sprintf("A segment can have at most %d constraints", MaxSegmentConstraints))
+	}
+	for i, constraint := range s.Constraints {
+		field := fmt.Sprintf("constraints[%d]", i)
+		if constraint == nil {
+			result.AddError(field, "Constraint is required")
+			continue
+		}
+		if err := constraint.Validate(); err != nil {
+			result.AddError(field, err.Error())
+		}
+	}
+
+	return result
+}
+
+// Validate valida o atributo, o operador e os valores da restrição
+func (c *SegmentConstraint) Validate() error {
+	if !rules.IsAttributeName(c.Attribute) {
+		return fmt.Errorf("Attribute is required and may only contain letters, numbers, hyphens, underscores and dots")
+	}
+	if !c.Operator.IsValid() {
+		return fmt.Errorf("Operator must be one of: in, not_in, cidr, eq, gt, gte, lt, lte, semver_eq, semver_gt, semver_gte, semver_lt, semver_lte")
+	}
+	if len(c.Values) == 0 {
+		return fmt.Errorf("At least one value is required")
+	}
+	if len(c.Values) > MaxSegmentConstraintValues {
+		return fmt.Errorf("A constraint can have at most %d values", MaxSegmentConstraintValues)
+	}
+	if (c.Operator.IsNumeric() || c.Operator.IsSemver()) && len(c.Values) != 1 {
+		return fmt.Errorf("Operator %s takes exactly one value", c.Operator)
+	}
+
+	for _, value := range c.Values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("Values cannot be empty")
+		}
+		switch {
+		case c.Operator == SegmentOperatorCIDR:
+			if _, err := rules.ParsePrefix(value); err != nil {
+				return fmt.Errorf("Invalid IP or CIDR block '%s'", value)
+			}
+		case c.Operator.IsNumeric():
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("Invalid number '%s'", value)
+			}
+		case c.Operator.IsSemver():
+			if _, err := semver.Parse(value); err != nil {
+				return fmt.Errorf("Invalid semantic version '%s'", value)
+			}
+		}
+	}
+	return nil
+}
+
+// SegmentIDs retorna os IDs dos segmentos referenciados pelas regras do toggle, sem repetições
+func (t *Toggle) SegmentIDs() []string {
+	return RuleSegmentIDs(t.Rules)
+}
+
+// ParseSegmentIDs lê a lista de IDs de segmentos separados por vírgula de uma regra do tipo segment
+func ParseSegmentIDs(value string) []string {
+	return rules.ParseSegmentIDs(value)
+}
+
+// RuleSegmentIDs retorna os IDs dos segmentos referenciados pelas condições das regras, sem repetições
+func RuleSegmentIDs(toggleRules []*ToggleRule) []string {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, rule := range toggleRules {
+		if rule == nil {
+			continue
+		}
+		for _, condition := range rule.Conditions {
+			if condition == nil || condition.Type != ActivationRuleTypeSegment {
+				continue
+			}
+			for _, id := range ParseSegmentIDs(condition.Value) {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return ids
+}
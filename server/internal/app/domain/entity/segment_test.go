@@ -0,0 +1,125 @@
+package entity
+
+import "testing"
+
+func TestSegment_Validate(t *testing.T) {
+	valid := func() *Segment {
+		return NewSegment(nil, "Beta testers", "", SegmentConstraints{
+			{Attribute: "user_id", Operator: SegmentOperatorIn, Values: []string{"u1", "u2"}},
+		})
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(s *Segment)
+		expectErr  bool
+		errorField string
+	}{
+		{name: "valid segment", modify: func(s *Segment) {}},
+		{name: "missing name", modify: func(s *Segment) { s.Name = " " }, expectErr: true, errorField: "name"},
+		{name: "invalid name", modify: func(s *Segment) { s.Name = "<beta>" }, expectErr: true, errorField: "name"},
+		{name: "no constraints", modify: func(s *Segment) { s.Constraints = SegmentConstraints{} }, expectErr: true, errorField: "constraints"},
+		{
+			name: "valid cidr, number and semver constraints",
+			modify: func(s *Segment) {
+				s.Constraints = SegmentConstraints{
+					{Attribute: "ip", Operator: SegmentOperatorCIDR, Values: []string{"10.0.0.0/8", "2001:db8::/32", "192.168.1.1"}},
+					{Attribute: "age", Operator: SegmentOperatorGte, Values: []string{"18"}},
+					{Attribute: "app_version", Operator: SegmentOperatorSemverGte, Values: []string{"2.1.0-rc.1"}},
+				}
+			},
+		},
+		{
+			name:       "invalid attribute",
+			modify:     func(s *Segment) { s.Constraints[0].Attribute = "user id" },
+			expectErr:  true,
+			errorField: "constraints[0]",
+		},
+		{
+			name:       "unknown operator",
+			modify:     func(s *Segment) { s.Constraints[0].Operator = "contains" },
+			expectErr:  true,
+			errorField: "constraints[0]",
+		},
+		{
+			name:       "empty values",
+			modify:     func(s *Segment) { s.Constraints[0].Values = []string{} },
+			expectErr:  true,
+			errorField: "constraints[0]",
+		},
+		{
+			name: "invalid cidr",
+			modify: func(s *Segment) {
+				s.Constraints[0] = &SegmentConstraint{Attribute: "ip", Operator: SegmentOperatorCIDR, Values: []string{"10.0.0.0/33"}}
+			},
+			expectErr:  true,
+			errorField: "constraints[0]",
+		},
+		{
+			name: "numeric operator with several values",
+			modify: func(s *Segment) {
+				s.Constraints[0] = &SegmentConstraint{Attribute: "age", Operator: SegmentOperatorGt, Values: []string{"1", "2"}}
+			},
+			expectErr:  true,
+			errorField: "constraints[0]",
+		},
+		{
+			name: "invalid semver",
+			modify: func(s *Segment) {
+				s.Constraints[0] = &SegmentConstraint{Attribute: "app_version", Operator: SegmentOperatorSemverLt, Values: []string{"2.1"}}
+			},
+			expectErr:  true,
+			errorField: "constraints[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segment := valid()
+			tt.modify(segment)
+			result := segment.Validate()
+
+			if tt.expectErr {
+				if result.IsValid {
+					t.Fatalf("Expected validation error")
+				}
+				if result.Errors[0].Field != tt.errorField {
+					t.Errorf("Expected error on field %s, got %s", tt.errorField, result.Errors[0].Field)
+				}
+				return
+			}
+			if !result.IsValid {
+				t.Errorf("Expected valid segment, got %+v", result.Errors[0])
+			}
+		})
+	}
+}
+
+func TestSegment_AvailableTo(t *testing.T) {
+	appID := "app1"
+	global := NewSegment(nil, "global", "", nil)
+	scoped := NewSegment(&appID, "scoped", "", nil)
+
+	if !global.AvailableTo("app1") || !global.AvailableTo("app2") {
+		t.Errorf("Expected global segment to be available to every application")
+	}
+	if !scoped.AvailableTo("app1") || scoped.AvailableTo("app2") {
+		t.Errorf("Expected application segment to be available only to its application")
+	}
+}
+
+func TestToggle_SegmentIDs(t *testing.T) {
+	toggle := &Toggle{}
+	toggle.SetRules([]*ToggleRule{
+		{Conditions: RuleConditions{{Type: ActivationRuleTypeSegment, Value: "seg1, seg2"}}},
+		{Conditions: RuleConditions{
+			{Type: ActivationRuleTypeUserID, Value: "seg3"},
+			{Type: ActivationRuleTypeSegment, Value: "seg2"},
+		}},
+	})
+
+	ids := toggle.SegmentIDs()
+	if len(ids) != 2 || ids[0] != "seg1" || ids[1] != "seg2" {
+		t.Errorf("Expected [seg1 seg2], got %v", ids)
+	}
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStaleDays é o número padrão de dias sem alteração ou avaliação para um toggle ser considerado obsoleto
+const DefaultStaleDays = 30
+
+// StaleReason define os motivos pelos quais um toggle é considerado obsoleto
+type StaleReason string
+
+const (
+	StaleReasonFullyOn      StaleReason = "fully_on"      // Habilitado, sem regra e sem alterações há N dias
+	StaleReasonFullyOff     StaleReason = "fully_off"     // Desabilitado e sem alterações há N dias
+	StaleReasonNotEvaluated StaleReason = "not_evaluated" // Nenhuma avaliação reportada nos últimos N dias
+	StaleReasonFullRollout  StaleReason = "full_rollout"  // Regra de porcentagem em 100%
+	StaleReasonNoRollout    StaleReason = "no_rollout"    // Regra de porcentagem em 0%
+)
+
+// Ações sugeridas para toggles obsoletos
+const (
+	StaleActionRemoveKeepCode = "remove the toggle and keep the enabled code path"
+	StaleActionRemoveDeadCode = "remove the toggle and the disabled code path"
+	StaleActionVerifyUsage    = "verify the toggle is still referenced by any service, then remove it"
+)
+
+// StaleToggle representa um toggle obsoleto no relatório de limpeza
+type StaleToggle struct {
+	ToggleID        string        `json:"toggle_id"`
+	Path            string        `json:"path"`
+	Enabled         bool          `json:"enabled"` // Estado efetivo considerando a hierarquia
+	HasRule         bool          `json:"has_activation_rule"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	Reasons         []StaleReason `json:"reasons"`
+	SuggestedAction string        `json:"suggested_action"`
+}
+
+// StaleReport representa o relatório de toggles obsoletos de uma aplicação
+type StaleReport struct {
+	AppID       string         `json:"app_id"`
+	AppName     string         `json:"app_name"`
+	StaleDays   int            `json:"stale_days"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Toggles     []*StaleToggle `json:"toggles"`
+}
+
+// SuggestAction define a ação sugerida a partir dos motivos encontrados.
+// Um toggle que sempre resolve para o mesmo valor pode ser removido mantendo o caminho correspondente.
+func (s *StaleToggle) SuggestAction() string {
+	for _, reason := range s.Reasons {
+		switch reason {
+		case StaleReasonFullyOn, StaleReasonFullRollout:
+			return StaleActionRemoveKeepCode
+		case StaleReasonFullyOff, StaleReasonNoRollout:
+			return StaleActionRemoveDeadCode
+		}
+	}
+	return StaleActionVerifyUsage
+}
+
+// PercentageRollout retorna a porcentagem de uma regra do tipo percentage, se aplicável
+func (ar *ActivationRule) PercentageRollout() (float64, bool) {
+	if ar == nil || ar.Type != ActivationRuleTypePercentage {
+		return 0, false
+	}
+	percentage, err := strconv.ParseFloat(strings.TrimSpace(ar.Value), 64)
+	if err != nil {
+		return 0, false
+	}
+	return percentage, true
+}
+
+// PercentageRollout resume as regras do toggle em uma porcentagem, quando possível. As regras
+// são combinadas com OU e as condições de cada regra com E: uma regra apenas com porcentagens
+// em 100% liga o toggle para todos, e uma condição em 0% em todas as regras o desliga para todos.
+func (t *Toggle) PercentageRollout() (float64, bool) {
+	if len(t.Rules) == 0 {
+		return t.ActivationRule.PercentageRollout()
+	}
+
+	none := true
+	for _, rule := range t.Rules {
+		full, off := len(rule.Conditions) > 0, false
+		for _, condition := range rule.Conditions {
+			percentage, ok := (&ActivationRule{Type: condition.Type, Value: condition.Value}).PercentageRollout()
+			if !ok || percentage < 100 {
+				full = false
+			}
+			if ok && percentage <= 0 {
+				off = true
+			}
+		}
+		if full {
+			return 100, true
+		}
+		if !off {
+			none = false
+		}
+	}
+	if none {
+		return 0, true
+	}
+	return 0, false
+}
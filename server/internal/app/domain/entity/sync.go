@@ -0,0 +1,272 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManagedPolicy define o que acontece quando um usuário altera manualmente um recurso
+// gerenciado pela sincronização declarativa
+type ManagedPolicy string
+
+const (
+	ManagedPolicyWarn  ManagedPolicy = "warn"  // A alteração é aceita; a interface avisa que a próxima sincronização a desfaz
+	ManagedPolicyBlock ManagedPolicy = "block" // Apenas a sincronização altera recursos gerenciados
+)
+
+// ParseManagedPolicy converte a política informada na configuração; vazio usa warn
+func ParseManagedPolicy(policy string) (ManagedPolicy, error) {
+	switch ManagedPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case "", ManagedPolicyWarn:
+		return ManagedPolicyWarn, nil
+	case ManagedPolicyBlock:
+		return ManagedPolicyBlock, nil
+	}
+	return "", fmt.Errorf("managed policy must be %s or %s", ManagedPolicyWarn, ManagedPolicyBlock)
+}
+
+// Check bloqueia a alteração manual de um recurso gerenciado quando a política é block
+func (p ManagedPolicy) Check(managed bool, actor *User, resource string) error {
+	if !managed || p != ManagedPolicyBlock || actor.IsSync() {
+		return nil
+	}
+	return NewAppError(ErrCodeManaged, fmt.Sprintf("%s is managed by the flags file and can only be changed by sync", resource))
+}
+
+// NewSyncActor cria o autor das alterações da sincronização a partir do usuário que a executou.
+// Sem usuário, como na linha de comando, as alterações são atribuídas a "sync".
+func NewSyncActor(user *User) *User {
+	actor := &User{Username: "sync"}
+	if user != nil {
+		copied := *user
+		actor = &copied
+	}
+	actor.Sync = true
+	return actor
+}
+
+// SyncFile é o arquivo de flags com o estado desejado das aplicações.
+// As aplicações são identificadas pelo nome e nunca são removidas pela sincronização.
+type SyncFile struct {
+	Applications []*SyncApplication `json:"applications"`
+}
+
+// SyncApplication declara os segmentos e toggles de uma aplicação
+type SyncApplication struct {
+	Name     string         `json:"name"`
+	Segments []*SyncSegment `json:"segments,omitempty"`
+	Toggles  []*SyncToggle  `json:"toggles,omitempty"`
+}
+
+// SyncSegment declara um segmento da aplicação, identificado pelo nome
+type SyncSegment struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Constraints SegmentConstraints `json:"constraints"`
+}
+
+// SyncToggle declara um toggle pelo caminho. As condições do tipo segment referenciam
+// os segmentos pelo nome, que é trocado pelo ID ao aplicar o plano.
+type SyncToggle struct {
+	Path        string         `json:"path"`
+	Enabled     *bool          `json:"enabled,omitempty"` // Ausente declara o toggle habilitado
+	Description string         `json:"description,omitempty"`
+	Owner       string         `json:"owner,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Kind        ToggleKind     `json:"kind,omitempty"`
+	ExpiresAt   *time.Time     `json:"expires_at,omitempty"`
+	Variants    ToggleVariants `json:"variants,omitempty"`
+	Rules       []*ToggleRule  `json:"rules,omitempty"`
+}
+
+// IsEnabled retorna o estado declarado do toggle
+func (t *SyncToggle) IsEnabled() bool {
+	return t.Enabled == nil || *t.Enabled
+}
+
+// Metadata retorna os metadados declarados, já normalizados
+func (t *SyncToggle) Metadata() *ToggleMetadata {
+	metadata := &ToggleMetadata{Description: t.Description, Owner: t.Owner, Tags: t.Tags, Kind: t.Kind, ExpiresAt: t.ExpiresAt}
+	metadata.Normalize()
+	return metadata
+}
+
+// ParseSyncFile lê o arquivo de flags em YAML ou JSON. O documento é convertido para JSON para
+// reaproveitar o formato das regras, variantes e restrições da API; campos desconhecidos são rejeitados.
+func ParseSyncFile(data []byte) (*SyncFile, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid flags file: %w", err)
+	}
+	if document == nil {
+		return nil, fmt.Errorf("invalid flags file: the file is empty")
+	}
+
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flags file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(converted))
+	decoder.DisallowUnknownFields()
+	var file SyncFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid flags file: %w", err)
+	}
+	return &file, nil
+}
+
+// Validate valida a forma do arquivo: nomes e caminhos únicos, segmentos, metadados, variantes e regras.
+// As referências aos segmentos são verificadas ao montar o plano.
+func (f *SyncFile) Validate() *ValidationResult {
+	result := NewValidationResult()
+
+	applications := make(map[string]bool)
+	for i, app := range f.Applications {
+		field := fmt.Sprintf("applications[%d]", i)
+		if app == nil {
+			result.AddError(field, "Application is required")
+			continue
+		}
+		for _, err := range ValidateApplicationName(app.Name).Errors {
+			result.AddError(field+"."+err.Field, err.Message)
+		}
+		if applications[app.Name] {
+			result.AddError(field+".name", fmt.Sprintf("Application '%s' is declared more than once", app.Name))
+		}
+		applications[app.Name] = true
+
+		segments := make(map[string]bool)
+		for j, declared := range app.Segments {
+			segmentField := fmt.Sprintf("%s.segments[%d]", field, j)
+			if declared == nil {
+				result.AddError(segmentField, "Segment is required")
+				continue
+			}
+			segment := NewSegment(nil, declared.Name, declared.Description, declared.Constraints)
+			for _, err := range segment.Validate().Errors {
+				result.AddError(segmentField+"."+err.Field, err.Message)
+			}
+			if segments[segment.Name] {
+				result.AddError(segmentField+".name", fmt.Sprintf("Segment '%s' is declared more than once", segment.Name))
+			}
+			segments[segment.Name] = true
+		}
+
+		toggles := make(map[string]bool)
+		for j, declared := range app.Toggles {
+			toggleField := fmt.Sprintf("%s.toggles[%d]", field, j)
+			if declared == nil {
+				result.AddError(toggleField, "Toggle is required")
+				continue
+			}
+			for _, err := range ValidateTogglePath(declared.Path).Errors {
+				result.AddError(toggleField+"."+err.Field, err.Message)
+			}
+			if toggles[declared.Path] {
+				result.AddError(toggleField+".path", fmt.Sprintf("Toggle '%s' is declared more than once", declared.Path))
+			}
+			toggles[declared.Path] = true
+
+			validation := ValidateToggleMetadata(declared.Metadata())
+			for _, err := range ValidateVariants(declared.Variants).Errors {
+				validation.AddError(err.Field, err.Message)
+			}
+			for _, err := range ValidateToggleRules(declared.Rules, declared.Variants).Errors {
+				validation.AddError(err.Field, err.Message)
+			}
+			for _, err := range validation.Errors {
+				result.AddError(toggleField+"."+err.Field, err.Message)
+			}
+		}
+	}
+
+	return result
+}
+
+// SyncAction é a alteração que o plano faz em um recurso
+type SyncAction string
+
+const (
+	SyncActionCreate SyncAction = "create"
+	SyncActionUpdate SyncAction = "update"
+	SyncActionDelete SyncAction = "delete" // Apenas recursos gerenciados que saíram do arquivo são removidos
+)
+
+// SyncResource é o tipo de recurso alterado pelo plano
+type SyncResource string
+
+const (
+	SyncResourceApplication SyncResource = "application"
+	SyncResourceSegment     SyncResource = "segment"
+	SyncResourceToggle      SyncResource = "toggle"
+)
+
+// SyncChangeStatus é o resultado de uma alteração quando o plano é aplicado
+type SyncChangeStatus string
+
+const (
+	SyncStatusApplied SyncChangeStatus = "applied"
+	SyncStatusFailed  SyncChangeStatus = "failed"
+	SyncStatusSkipped SyncChangeStatus = "skipped" // Não aplicada porque uma alteração anterior falhou
+)
+
+// SyncChange é uma alteração do plano. Diff lista os campos alterados no formato "campo: atual -> desejado".
+// Status e Error ficam vazios no dry run.
+type SyncChange struct {
+	Action      SyncAction       `json:"action"`
+	Resource    SyncResource     `json:"resource"`
+	Application string           `json:"application"`
+	Name        string           `json:"name"`
+	Diff        []string         `json:"diff,omitempty"`
+	Status      SyncChangeStatus `json:"status,omitempty"`
+	Error       *AppError        `json:"error,omitempty"`
+}
+
+// String descreve a alteração em uma linha, como no diff da linha de comando
+func (c *SyncChange) String() string {
+	symbol := map[SyncAction]string{SyncActionCreate: "+", SyncActionUpdate: "~", SyncActionDelete: "-"}[c.Action]
+	if c.Resource == SyncResourceApplication {
+		return fmt.Sprintf("%s %s %s", symbol, c.Resource, c.Application)
+	}
+	return fmt.Sprintf("%s %s %s/%s", symbol, c.Resource, c.Application, c.Name)
+}
+
+// SyncPlan é o conjunto de alterações que leva o servidor ao estado do arquivo.
+// Applied indica se alguma alteração foi gravada; um plano vazio significa que nada mudou.
+type SyncPlan struct {
+	Changes []*SyncChange `json:"changes"`
+	Applied bool          `json:"applied"`
+}
+
+// Count retorna o número de alterações com a ação informada
+func (p *SyncPlan) Count(action SyncAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed retorna a alteração que interrompeu a aplicação do plano, se houver
+func (p *SyncPlan) Failed() *SyncChange {
+	for _, change := range p.Changes {
+		if change.Status == SyncStatusFailed {
+			return change
+		}
+	}
+	return nil
+}
+
+// Summary resume o plano em uma linha
+func (p *SyncPlan) Summary() string {
+	return fmt.Sprintf("Plan: %d to create, %d to update, %d to delete.",
+		p.Count(SyncActionCreate), p.Count(SyncActionUpdate), p.Count(SyncActionDelete))
+}
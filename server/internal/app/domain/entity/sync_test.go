@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSyncFile(t *testing.T) {
+	file, err := ParseSyncFile([]byte(`
+applications:
+  - name: Shop
+    toggles:
+      - path: checkout
+        expires_at: 2030-01-02T00:00:00Z
+        variants:
+          - name: config
+            payload_type: json
+            payload: {"retries": 3}
+            weight: 100
+`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	toggle := file.Applications[0].Toggles[0]
+	if !toggle.IsEnabled() || toggle.ExpiresAt == nil || toggle.ExpiresAt.Year() != 2030 {
+		t.Errorf("Unexpected toggle %+v", toggle)
+	}
+	if string(toggle.Variants[0].Payload) != `{"retries":3}` {
+		t.Errorf("Expected the payload converted to JSON, got %s", toggle.Variants[0].Payload)
+	}
+	if toggle.Metadata().Kind != ToggleKindRelease {
+		t.Errorf("Expected default kind release, got %s", toggle.Metadata().Kind)
+	}
+
+	invalid := []string{
+		"",
+		"applications: [",
+		"applications:\n  - name: Shop\n    owner: team\n",
+	}
+	for _, data := range invalid {
+		if _, err := ParseSyncFile([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
+
+func TestSyncFile_Validate(t *testing.T) {
+	file, _ := ParseSyncFile([]byte(`
+applications:
+  - name: Shop
+    segments:
+      - name: beta
+        constraints: []
+    toggles:
+      - path: checkout
+        kind: unknown
+      - path: checkout
+        rules:
+          - conditions:
+              - type: percentage
+                value: "10"
+            variant: missing
+  - name: Shop
+`))
+	validation := file.Validate()
+	if validation.IsValid {
+		t.Fatal("Expected validation errors")
+	}
+
+	fields := make([]string, 0)
+	for _, err := range validation.Errors {
+		fields = append(fields, err.Field)
+	}
+	for _, expected := range []string{
+		"applications[0].segments[0].constraints",
+		"applications[0].toggles[0].kind",
+		"applications[0].toggles[1].path",
+		"applications[0].toggles[1].rules[0].variant",
+		"applications[1].name",
+	} {
+		if !strings.Contains(strings.Join(fields, " "), expected) {
+			t.Errorf("Expected error on %s, got %v", expected, fields)
+		}
+	}
+}
+
+func TestManagedPolicy(t *testing.T) {
+	if policy, err := ParseManagedPolicy(""); err != nil || policy != ManagedPolicyWarn {
+		t.Errorf("Expected warn by default, got %q %v", policy, err)
+	}
+	if policy, err := ParseManagedPolicy(" BLOCK "); err != nil || policy != ManagedPolicyBlock {
+		t.Errorf("Expected block, got %q %v", policy, err)
+	}
+	if _, err := ParseManagedPolicy("deny"); err == nil {
+		t.Error("Expected error for an unknown policy")
+	}
+
+	user := &User{ID: "u1", Role: UserRoleRoot}
+	if err := ManagedPolicyWarn.Check(true, user, "toggle a"); err != nil {
+		t.Errorf("Expected warn to accept the change, got %v", err)
+	}
+	if err := ManagedPolicyBlock.Check(false, user, "toggle a"); err != nil {
+		t.Errorf("Expected unmanaged resources to be accepted, got %v", err)
+	}
+	if err, ok := ManagedPolicyBlock.Check(true, user, "toggle a").(*AppError); !ok || err.Code != ErrCodeManaged {
+		t.Errorf("Expected managed error, got %v", err)
+	}
+	if err := ManagedPolicyBlock.Check(true, NewSyncActor(user), "toggle a"); err != nil {
+		t.Errorf("Expected sync to be accepted, got %v", err)
+	}
+	if user.Sync || NewSyncActor(nil).Username != "sync" {
+		t.Error("Expected the sync actor to be a copy named sync when there is no user")
+	}
+}
+
+func TestSyncPlan_Summary(t *testing.T) {
+	plan := &SyncPlan{Changes: []*SyncChange{
+		{Action: SyncActionCreate, Resource: SyncResourceApplication, Application: "Shop"},
+		{Action: SyncActionUpdate, Resource: SyncResourceToggle, Application: "Shop", Name: "checkout"},
+		{Action: SyncActionDelete, Resource: SyncResourceSegment, Application: "Shop", Name: "beta"},
+	}}
+	if plan.Summary() != "Plan: 1 to create, 1 to update, 1 to delete." {
+		t.Errorf("Unexpected summary %q", plan.Summary())
+	}
+	if plan.Changes[0].String() != "+ application Shop" || plan.Changes[2].String() != "- segment Shop/beta" {
+		t.Errorf("Unexpected changes %s, %s", plan.Changes[0], plan.Changes[2])
+	}
+}
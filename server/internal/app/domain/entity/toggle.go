@@ -1,23 +1,39 @@
 package entity
 
 import (
+	"errors"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// ErrToggleChanged indica que o toggle foi alterado ou removido por outra operação depois de ser lido
+var ErrToggleChanged = errors.New("toggle was changed by another operation")
+
 // Toggle representa um feature toggle com estrutura hierárquica
 type Toggle struct {
-	ID                string          `json:"id" gorm:"primaryKey;type:varchar(26)"`
-	Value             string          `json:"value" gorm:"not null;type:varchar(255)"`
-	Enabled           bool            `json:"enabled" gorm:"not null;default:true"`
-	Path              string          `json:"path" gorm:"not null;type:varchar(1000)"`
-	Level             int             `json:"level" gorm:"not null;default:0"`
-	ParentID          *string         `json:"parent_id" gorm:"type:varchar(26)"`
-	AppID             string          `json:"app_id" gorm:"not null;type:varchar(26)"`
-	HasActivationRule bool            `json:"has_activation_rule" gorm:"default:false"`
-	ActivationRule    *ActivationRule `json:"activation_rule,omitempty" gorm:"embedded;embeddedPrefix:rule_"`
-	CreatedAt         time.Time       `json:"created_at"`
-	UpdatedAt         time.Time       `json:"updated_at"`
+	ID                string                `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	Value             string                `json:"value" gorm:"not null;type:varchar(255)"`
+	Enabled           bool                  `json:"enabled" gorm:"not null;default:true"`
+	Path              string                `json:"path" gorm:"not null;type:varchar(1000);index:idx_toggles_app_path,priority:2"`
+	Level             int                   `json:"level" gorm:"not null;default:0"`
+	ParentID          *string               `json:"parent_id" gorm:"type:varchar(26);index"`
+	AppID             string                `json:"app_id" gorm:"not null;type:varchar(26);index:idx_toggles_app_path,priority:1"`
+	HasActivationRule bool                  `json:"has_activation_rule" gorm:"default:false"`
+	ActivationRule    *ActivationRule       `json:"activation_rule,omitempty" gorm:"embedded;embeddedPrefix:rule_"`
+	Rules             []*ToggleRule         `json:"rules" gorm:"foreignKey:ToggleID"`
+	Prerequisites     []*TogglePrerequisite `json:"prerequisites" gorm:"foreignKey:ToggleID"`
+	Variants          ToggleVariants        `json:"variants" gorm:"type:text"`
+	Description       string                `json:"description" gorm:"type:text"`
+	Owner             string                `json:"owner" gorm:"type:varchar(100);index"`
+	Tags              ToggleTags            `json:"tags" gorm:"type:text"`
+	Kind              ToggleKind            `json:"kind" gorm:"type:varchar(20);default:'release';index"`
+	ExpiresAt         *time.Time            `json:"expires_at"`
+	Managed           bool                  `json:"managed" gorm:"not null;default:false"` // Definido pela sincronização declarativa
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt        `json:"deleted_at,omitempty" gorm:"index"` // Preenchido enquanto o toggle está na lixeira
 
 	// Relacionamentos
 	Parent   *Toggle   `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
@@ -36,28 +52,29 @@ func NewToggle(value string, enabled bool, path string, level int, parentID *str
 		AppID:             appID,
 		HasActivationRule: false,
 		ActivationRule:    nil,
+		Tags:              ToggleTags{},
+		Kind:              ToggleKindRelease,
+		Variants:          ToggleVariants{},
 	}
 }
 
-// SetActivationRule define uma regra de ativação para o toggle
+// SetActivationRule define uma regra de ativação para o toggle, substituindo as regras
+// compostas por uma única regra com uma condição
 func (t *Toggle) SetActivationRule(rule *ActivationRule) error {
 	if rule != nil {
 		if err := rule.ValidateRule(); err != nil {
 			return err
 		}
-		t.ActivationRule = rule
-		t.HasActivationRule = true
+		t.SetRules([]*ToggleRule{NewToggleRuleFromActivationRule(rule)})
 	} else {
-		t.ActivationRule = nil
-		t.HasActivationRule = false
+		t.SetRules(nil)
 	}
 	return nil
 }
 
-// ClearActivationRule remove a regra de ativação do toggle
+// ClearActivationRule remove as regras de ativação do toggle
 func (t *Toggle) ClearActivationRule() {
-	t.ActivationRule = nil
-	t.HasActivationRule = false
+	t.SetRules(nil)
 }
 
 // IsEnabled verifica se o toggle está habilitado considerando a hierarquia
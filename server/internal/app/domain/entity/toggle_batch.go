@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Limites das operações em lote
+const (
+	MaxBatchOperations = 100
+)
+
+// BatchOperationType identifica a alteração feita por uma operação do lote
+type BatchOperationType string
+
+const (
+	BatchOperationCreate  BatchOperationType = "create"   // Cria o toggle e os ancestrais que faltam
+	BatchOperationEnable  BatchOperationType = "enable"   // Habilita o toggle, sem alterar os descendentes
+	BatchOperationDisable BatchOperationType = "disable"  // Desabilita o toggle, sem alterar os descendentes
+	BatchOperationSetRule BatchOperationType = "set_rule" // Substitui as regras e, se informadas, as variantes
+	BatchOperationDelete  BatchOperationType = "delete"   // Move o toggle e seus descendentes para a lixeira
+)
+
+// BatchMode define o que acontece com o lote quando alguma operação falha
+type BatchMode string
+
+const (
+	BatchModeAtomic     BatchMode = "atomic"      // Nenhuma operação é aplicada se alguma falhar
+	BatchModeBestEffort BatchMode = "best_effort" // As operações válidas são aplicadas e as que falham são ignoradas
+)
+
+// BatchOperationStatus é o resultado de uma operação do lote
+type BatchOperationStatus string
+
+const (
+	BatchStatusApplied BatchOperationStatus = "applied"
+	BatchStatusFailed  BatchOperationStatus = "failed"
+	BatchStatusSkipped BatchOperationStatus = "skipped" // Operação válida não aplicada porque o lote atômico falhou
+)
+
+// BatchOperation é uma operação do lote. O toggle é identificado pelo caminho ou pelo ID;
+// a criação aceita apenas o caminho.
+type BatchOperation struct {
+	Op             BatchOperationType `json:"op"`
+	Path           string             `json:"path,omitempty"`
+	ToggleID       string             `json:"toggle_id,omitempty"`
+	Enabled        *bool              `json:"enabled,omitempty"`         // Estado do toggle criado; ausente cria habilitado
+	ActivationRule *ActivationRule    `json:"activation_rule,omitempty"` // Regra simples de set_rule
+	Rules          []*ToggleRule      `json:"rules,omitempty"`           // Regras compostas de set_rule; substituem activation_rule
+	Variants       ToggleVariants     `json:"variants,omitempty"`        // Ausente mantém as variantes atuais
+}
+
+// Validate valida a forma da operação; as referências aos toggles são verificadas ao aplicar o lote
+func (o *BatchOperation) Validate() *ValidationResult {
+	result := NewValidationResult()
+
+	switch o.Op {
+	case BatchOperationCreate:
+		if o.ToggleID != "" {
+			result.AddError("toggle_id", "Create operations identify the toggle by path")
+		}
+		for _, err := range ValidateTogglePath(o.Path).Errors {
+			result.AddError(err.Field, err.Message)
+		}
+	case BatchOperationEnable, BatchOperationDisable, BatchOperationSetRule, BatchOperationDelete:
+		if (o.Path == "") == (o.ToggleID == "") {
+			result.AddError("path", "Either path or toggle_id is required")
+		}
+		if o.Enabled != nil {
+			result.AddError("enabled", "Enabled is only accepted by create operations")
+		}
+	default:
+		result.AddError("op", fmt.Sprintf("Operation must be one of %s, %s, %s, %s or %s",
+			BatchOperationCreate, BatchOperationEnable, BatchOperationDisable, BatchOperationSetRule, BatchOperationDelete))
+		return result
+	}
+
+	if o.Op != BatchOperationSetRule && (o.ActivationRule != nil || o.Rules != nil || o.Variants != nil) {
+		result.AddError("rules", "Rules and variants are only accepted by set_rule operations")
+	}
+	return result
+}
+
+// Target descreve o toggle alvo da operação nas mensagens
+func (o *BatchOperation) Target() string {
+	if o.Path != "" {
+		return o.Path
+	}
+	return o.ToggleID
+}
+
+// BatchOperationResult é o resultado de uma operação, na mesma posição da requisição
+type BatchOperationResult struct {
+	Index    int                  `json:"index"`
+	Op       BatchOperationType   `json:"op"`
+	Path     string               `json:"path,omitempty"`
+	ToggleID string               `json:"toggle_id,omitempty"`
+	Status   BatchOperationStatus `json:"status"`
+	Error    *AppError            `json:"error,omitempty"`
+}
+
+// BatchResult é o resultado de um lote. Applied indica se alguma alteração foi gravada.
+type BatchResult struct {
+	Mode    BatchMode               `json:"mode"`
+	Applied bool                    `json:"applied"`
+	Results []*BatchOperationResult `json:"results"`
+}
+
+// Failed retorna o número de operações que falharam
+func (r *BatchResult) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if result.Status == BatchStatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ParseBatchMode converte o modo informado na requisição; vazio usa o modo atômico
+func ParseBatchMode(mode string) (BatchMode, error) {
+	switch BatchMode(strings.TrimSpace(mode)) {
+	case "", BatchModeAtomic:
+		return BatchModeAtomic, nil
+	case BatchModeBestEffort:
+		return BatchModeBestEffort, nil
+	}
+	return "", fmt.Errorf("batch mode must be %s or %s", BatchModeAtomic, BatchModeBestEffort)
+}
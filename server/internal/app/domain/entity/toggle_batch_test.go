@@ -0,0 +1,50 @@
+package entity
+
+import "testing"
+
+func TestBatchOperation_Validate(t *testing.T) {
+	enabled := false
+	tests := []struct {
+		name      string
+		operation *BatchOperation
+		field     string
+	}{
+		{"create by path", &BatchOperation{Op: BatchOperationCreate, Path: "checkout.v2", Enabled: &enabled}, ""},
+		{"create by ID", &BatchOperation{Op: BatchOperationCreate, ToggleID: "t1"}, "toggle_id"},
+		{"create with invalid path", &BatchOperation{Op: BatchOperationCreate, Path: " "}, "path"},
+		{"enable by ID", &BatchOperation{Op: BatchOperationEnable, ToggleID: "t1"}, ""},
+		{"disable without target", &BatchOperation{Op: BatchOperationDisable}, "path"},
+		{"delete with path and ID", &BatchOperation{Op: BatchOperationDelete, Path: "checkout", ToggleID: "t1"}, "path"},
+		{"enabled outside create", &BatchOperation{Op: BatchOperationEnable, Path: "checkout", Enabled: &enabled}, "enabled"},
+		{"set rule", &BatchOperation{Op: BatchOperationSetRule, Path: "checkout", Rules: []*ToggleRule{}}, ""},
+		{"rules outside set rule", &BatchOperation{Op: BatchOperationEnable, Path: "checkout", Rules: []*ToggleRule{}}, "rules"},
+		{"unknown operation", &BatchOperation{Op: "rename", Path: "checkout"}, "op"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.operation.Validate()
+			if tt.field == "" {
+				if !result.IsValid {
+					t.Errorf("Expected valid operation, got %v", result.Errors)
+				}
+				return
+			}
+			if result.IsValid || result.Errors[0].Field != tt.field {
+				t.Errorf("Expected error on %s, got %v", tt.field, result.Errors)
+			}
+		})
+	}
+}
+
+func TestParseBatchMode(t *testing.T) {
+	if mode, err := ParseBatchMode(""); err != nil || mode != BatchModeAtomic {
+		t.Errorf("Expected atomic by default, got %q %v", mode, err)
+	}
+	if mode, err := ParseBatchMode("best_effort"); err != nil || mode != BatchModeBestEffort {
+		t.Errorf("Expected best_effort, got %q %v", mode, err)
+	}
+	if _, err := ParseBatchMode("partial"); err == nil {
+		t.Error("Expected error for an unknown mode")
+	}
+}
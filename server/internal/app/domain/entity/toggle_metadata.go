@@ -0,0 +1,218 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limites dos metadados de um toggle
+const (
+	MaxToggleDescriptionLength = 1000
+	MaxToggleOwnerLength       = 100
+	MaxToggleTags              = 20
+	MaxToggleTagLength         = 50
+)
+
+var (
+	toggleOwnerRegex = regexp.MustCompile(`^[a-zA-Z0-9\s\-_\.]+$`)
+	toggleTagRegex   = regexp.MustCompile(`^[a-z0-9][a-z0-9\-_:\.]*$`)
+)
+
+// ToggleKind define o propósito de um toggle
+type ToggleKind string
+
+const (
+	ToggleKindRelease    ToggleKind = "release"    // Libera funcionalidades em desenvolvimento
+	ToggleKindExperiment ToggleKind = "experiment" // Testes A/B e experimentos
+	ToggleKindOps        ToggleKind = "ops"        // Controles operacionais e circuit breakers
+	ToggleKindPermission ToggleKind = "permission" // Acesso a funcionalidades por perfil de usuário
+)
+
+// IsValid verifica se o tipo de toggle é conhecido
+func (k ToggleKind) IsValid() bool {
+	switch k {
+	case ToggleKindRelease, ToggleKindExperiment, ToggleKindOps, ToggleKindPermission:
+		return true
+	}
+	return false
+}
+
+// ToggleTags representa as tags livres de um toggle, persistidas como um array JSON
+type ToggleTags []string
+
+// Value serializa as tags para o banco de dados
+func (t ToggleTags) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(t))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa as tags lidas do banco de dados
+func (t *ToggleTags) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*t = ToggleTags{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type for toggle tags: %T", value)
+	}
+
+	if len(data) == 0 {
+		*t = ToggleTags{}
+		return nil
+	}
+
+	var tags []string
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return err
+	}
+	*t = tags
+	return nil
+}
+
+// Has verifica se o toggle possui a tag informada
+func (t ToggleTags) Has(tag string) bool {
+	for _, existing := range t {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeToggleTags remove espaços, converte para minúsculas, remove duplicadas e ordena as tags
+func NormalizeToggleTags(tags []string) ToggleTags {
+	normalized := ToggleTags{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ToggleMetadata representa os metadados editáveis de um toggle
+type ToggleMetadata struct {
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	Tags        []string   `json:"tags"`
+	Kind        ToggleKind `json:"kind"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// Normalize aplica os valores padrão e normaliza os campos dos metadados
+func (m *ToggleMetadata) Normalize() {
+	m.Description = strings.TrimSpace(m.Description)
+	m.Owner = strings.TrimSpace(m.Owner)
+	m.Tags = NormalizeToggleTags(m.Tags)
+	if m.Kind == "" {
+		m.Kind = ToggleKindRelease
+	}
+	if m.ExpiresAt != nil {
+		expiresAt := m.ExpiresAt.UTC()
+		m.ExpiresAt = &expiresAt
+	}
+}
+
+// ValidateToggleMetadata valida os metadados de um toggle já normalizados
+func ValidateToggleMetadata(metadata *ToggleMetadata) *ValidationResult {
+	result := NewValidationResult()
+
+	if utf8.RuneCountInString(metadata.Description) > MaxToggleDescriptionLength {
+		result.AddError("description", fmt.Sprintf("Description must be at most %d characters", MaxToggleDescriptionLength))
+	}
+
+	if metadata.Owner != "" {
+		if utf8.RuneCountInString(metadata.Owner) > MaxToggleOwnerLength {
+			result.AddError("owner", fmt.Sprintf("Owner must be at most %d characters", MaxToggleOwnerLength))
+		}
+		if !toggleOwnerRegex.MatchString(metadata.Owner) {
+			result.AddError("owner", "Owner contains invalid characters. Only letters, numbers, spaces, hyphens, underscores and dots are allowed")
+		}
+	}
+
+	if len(metadata.Tags) > MaxToggleTags {
+		result.AddError("tags", fmt.Sprintf("A toggle can have at most %d tags", MaxToggleTags))
+	}
+	for _, tag := range metadata.Tags {
+		if utf8.RuneCountInString(tag) > MaxToggleTagLength {
+			result.AddError("tags", fmt.Sprintf("Tag '%s' must be at most %d characters", tag, MaxToggleTagLength))
+			continue
+		}
+		if !toggleTagRegex.MatchString(tag) {
+			result.AddError("tags", fmt.Sprintf("Tag '%s' contains invalid characters. Only lowercase letters, numbers, hyphens, underscores, colons and dots are allowed", tag))
+		}
+	}
+
+	if !metadata.Kind.IsValid() {
+		result.AddError("kind", "Kind must be one of: release, experiment, ops, permission")
+	}
+
+	return result
+}
+
+// SetMetadata aplica metadados já validados ao toggle
+func (t *Toggle) SetMetadata(metadata *ToggleMetadata) {
+	t.Description = metadata.Description
+	t.Owner = metadata.Owner
+	t.Tags = NormalizeToggleTags(metadata.Tags)
+	t.Kind = metadata.Kind
+	t.ExpiresAt = metadata.ExpiresAt
+}
+
+// IsExpired verifica se a data prevista de remoção do toggle já passou
+func (t *Toggle) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && t.ExpiresAt.Before(now)
+}
+
+// ToggleFilter define os filtros de listagem de toggles por metadados
+type ToggleFilter struct {
+	Tags  []string
+	Owner string
+	Kind  ToggleKind
+}
+
+// IsEmpty verifica se nenhum filtro foi informado
+func (f *ToggleFilter) IsEmpty() bool {
+	return f == nil || (len(f.Tags) == 0 && f.Owner == "" && f.Kind == "")
+}
+
+// Matches verifica se o toggle atende a todos os filtros
+func (f *ToggleFilter) Matches(toggle *Toggle) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if f.Owner != "" && toggle.Owner != f.Owner {
+		return false
+	}
+	if f.Kind != "" && toggle.Kind != f.Kind {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !toggle.Tags.Has(tag) {
+			return false
+		}
+	}
+	return true
+}
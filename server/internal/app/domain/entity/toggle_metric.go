@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetricsBucketSize define a granularidade dos buckets de métricas de avaliação
+const MetricsBucketSize = time.Hour
+
+// MetricsRetention é a idade máxima de uma janela de avaliações aceita de um SDK
+const MetricsRetention = 90 * 24 * time.Hour
+
+// MaxEvaluationCount limita o contador aceito em um único registro enviado pelo SDK
+const MaxEvaluationCount = 10000000
+
+// ToggleMetric representa as avaliações de um toggle agregadas em um bucket de tempo
+type ToggleMetric struct {
+	ID          string    `json:"-" gorm:"primaryKey;type:varchar(26)"`
+	AppID       string    `json:"app_id" gorm:"not null;type:varchar(26);index"`
+	ToggleID    string    `json:"toggle_id" gorm:"not null;type:varchar(26);uniqueIndex:idx_toggle_metrics_bucket"`
+	BucketStart time.Time `json:"bucket_start" gorm:"not null;uniqueIndex:idx_toggle_metrics_bucket"`
+	TrueCount   int64     `json:"true_count" gorm:"not null;default:0"`
+	FalseCount  int64     `json:"false_count" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"-"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewToggleMetric cria um bucket de métricas vazio para o toggle
+func NewToggleMetric(appID, toggleID string, bucketStart time.Time) *ToggleMetric {
+	return &ToggleMetric{
+		ID:          generateULID(),
+		AppID:       appID,
+		ToggleID:    toggleID,
+		BucketStart: bucketStart,
+	}
+}
+
+// Add soma avaliações ao bucket de acordo com o resultado
+func (m *ToggleMetric) Add(result bool, count int64) {
+	if result {
+		m.TrueCount += count
+	} else {
+		m.FalseCount += count
+	}
+}
+
+// MetricsBucketStart retorna o início do bucket ao qual o instante pertence
+func MetricsBucketStart(t time.Time) time.Time {
+	return t.UTC().Truncate(MetricsBucketSize)
+}
+
+// EvaluationCount representa um lote de avaliações reportado por um SDK
+type EvaluationCount struct {
+	Toggle      string    `json:"toggle"`
+	Result      bool      `json:"result"`
+	Count       int64     `json:"count"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+}
+
+// Validate valida um lote de avaliações reportado por um SDK
+func (e *EvaluationCount) Validate(now time.Time) error {
+	if e.Toggle == "" {
+		return fmt.Errorf("toggle path is required")
+	}
+
+	if e.Count <= 0 || e.Count > MaxEvaluationCount {
+		return fmt.Errorf("count must be between 1 and %d", MaxEvaluationCount)
+	}
+
+	if e.WindowStart.IsZero() {
+		return fmt.Errorf("window_start is required")
+	}
+
+	if !e.WindowEnd.IsZero() && e.WindowEnd.Before(e.WindowStart) {
+		return fmt.Errorf("window_end must not be before window_start")
+	}
+
+	// A janela inteira é contabilizada no bucket de window_start, então não pode ser maior que um bucket
+	if !e.WindowEnd.IsZero() && e.WindowEnd.Sub(e.WindowStart) > MetricsBucketSize {
+		return fmt.Errorf("window must not be longer than %s", MetricsBucketSize)
+	}
+
+	if e.WindowStart.Before(now.Add(-MetricsRetention)) {
+		return fmt.Errorf("window_start is older than the metrics retention of %d days", int(MetricsRetention.Hours()/24))
+	}
+
+	// Tolera pequenas diferenças de relógio entre SDK e servidor
+	if e.WindowStart.After(now.Add(5 * time.Minute)) {
+		return fmt.Errorf("window_start is in the future")
+	}
+
+	return nil
+}
+
+// ToggleMetricsSummary representa as métricas de um toggle em um intervalo de tempo
+type ToggleMetricsSummary struct {
+	ToggleID   string          `json:"toggle_id"`
+	Path       string          `json:"path"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	BucketSize string          `json:"bucket_size"`
+	TrueCount  int64           `json:"true_count"`
+	FalseCount int64           `json:"false_count"`
+	Total      int64           `json:"total"`
+	Buckets    []*ToggleMetric `json:"buckets"`
+}
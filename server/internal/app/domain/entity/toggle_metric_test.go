@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluationCount_Validate(t *testing.T) {
+	now := time.Date(2025, 9, 3, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		count       EvaluationCount
+		expectError bool
+	}{
+		{
+			name:        "valid",
+			count:       EvaluationCount{Toggle: "checkout", Result: true, Count: 10, WindowStart: now.Add(-time.Minute), WindowEnd: now},
+			expectError: false,
+		},
+		{
+			name:        "missing toggle",
+			count:       EvaluationCount{Count: 10, WindowStart: now},
+			expectError: true,
+		},
+		{
+			name:        "zero count",
+			count:       EvaluationCount{Toggle: "checkout", WindowStart: now},
+			expectError: true,
+		},
+		{
+			name:        "missing window start",
+			count:       EvaluationCount{Toggle: "checkout", Count: 1},
+			expectError: true,
+		},
+		{
+			name:        "window end before start",
+			count:       EvaluationCount{Toggle: "checkout", Count: 1, WindowStart: now, WindowEnd: now.Add(-time.Minute)},
+			expectError: true,
+		},
+		{
+			name:        "window of exactly one bucket",
+			count:       EvaluationCount{Toggle: "checkout", Count: 1, WindowStart: now.Add(-time.Hour), WindowEnd: now},
+			expectError: false,
+		},
+		{
+			name:        "window longer than one bucket",
+			count:       EvaluationCount{Toggle: "checkout", Count: 1, WindowStart: now.Add(-3 * time.Hour), WindowEnd: now},
+			expectError: true,
+		},
+		{
+			name:        "window older than the retention",
+			count:       EvaluationCount{Toggle: "checkout", Count: 1, WindowStart: now.Add(-MetricsRetention - time.Minute)},
+			expectError: true,
+		},
+		{
+			name:        "window in the future",
+			count:       EvaluationCount{Toggle: "checkout", Count: 1, WindowStart: now.Add(time.Hour)},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.count.Validate(now)
+			if tt.expectError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestToggleMetric_Add(t *testing.T) {
+	bucket := MetricsBucketStart(time.Date(2025, 9, 3, 12, 30, 0, 0, time.UTC))
+	if !bucket.Equal(time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected bucket to start at 12:00, got %v", bucket)
+	}
+
+	metric := NewToggleMetric("app", "toggle", bucket)
+	metric.Add(true, 3)
+	metric.Add(false, 2)
+	metric.Add(true, 1)
+
+	if metric.TrueCount != 4 || metric.FalseCount != 2 {
+		t.Errorf("Expected 4 true / 2 false, got %d / %d", metric.TrueCount, metric.FalseCount)
+	}
+}
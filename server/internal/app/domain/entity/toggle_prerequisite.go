@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxTogglePrerequisites limita os pré-requisitos declarados por um toggle
+const MaxTogglePrerequisites = 10
+
+// TogglePrerequisite representa um toggle da mesma aplicação, em qualquer ramo da hierarquia,
+// que precisa estar no estado exigido para que o toggle seja avaliado como ativo
+type TogglePrerequisite struct {
+	ID             string    `json:"-" gorm:"primaryKey;type:varchar(26)"`
+	ToggleID       string    `json:"-" gorm:"not null;type:varchar(26);index"`
+	PrerequisiteID string    `json:"toggle_id" gorm:"not null;type:varchar(26);index"`
+	Enabled        bool      `json:"enabled" gorm:"not null"` // Estado exigido do pré-requisito
+	CreatedAt      time.Time `json:"-"`
+
+	// Prerequisite é o toggle exigido, ligado em memória para a avaliação
+	Prerequisite *Toggle `json:"-" gorm:"-"`
+}
+
+// BeforeCreate hook para gerar ID único
+func (p *TogglePrerequisite) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = generateULID()
+	}
+	return nil
+}
+
+// SetPrerequisites substitui os pré-requisitos do toggle
+func (t *Toggle) SetPrerequisites(prerequisites []*TogglePrerequisite) {
+	if prerequisites == nil {
+		prerequisites = []*TogglePrerequisite{}
+	}
+	for _, prerequisite := range prerequisites {
+		prerequisite.ToggleID = t.ID
+	}
+	t.Prerequisites = prerequisites
+}
+
+// ValidatePrerequisites valida os pré-requisitos de um toggle contra os toggles da aplicação:
+// cada pré-requisito precisa existir, não pode se repetir nem ser o próprio toggle
+func ValidatePrerequisites(toggle *Toggle, prerequisites []*TogglePrerequisite, appToggles []*Toggle) *ValidationResult {
+	result := NewValidationResult()
+
+	if len(prerequisites) > MaxTogglePrerequisites {
+		result.AddError("prerequisites", fmt.Sprintf("A toggle can have at most %d prerequisites", MaxTogglePrerequisites))
+	}
+
+	byID := make(map[string]*Toggle, len(appToggles))
+	for _, appToggle := range appToggles {
+		byID[appToggle.ID] = appToggle
+	}
+
+	seen := make(map[string]bool, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		field := fmt.Sprintf("prerequisites[%d]", i)
+		switch {
+		case prerequisite == nil || prerequisite.PrerequisiteID == "":
+			result.AddError(field+".toggle_id", "Prerequisite toggle ID is required")
+		case prerequisite.PrerequisiteID == toggle.ID:
+			result.AddError(field+".toggle_id", "A toggle cannot be its own prerequisite")
+		case byID[prerequisite.PrerequisiteID] == nil:
+			result.AddError(field+".toggle_id", fmt.Sprintf("Toggle '%s' does not exist in this application", prerequisite.PrerequisiteID))
+		case seen[prerequisite.PrerequisiteID]:
+			result.AddError(field+".toggle_id", fmt.Sprintf("Toggle '%s' is duplicated", prerequisite.PrerequisiteID))
+		default:
+			seen[prerequisite.PrerequisiteID] = true
+		}
+	}
+
+	return result
+}
+
+// FindPrerequisiteCycle procura um ciclo criado ao definir os pré-requisitos do toggle.
+// Um toggle depende do seu pai e dos seus pré-requisitos; exigir um descendente, por exemplo,
+// fecha um ciclo pela hierarquia. Retorna os caminhos do ciclo, começando e terminando no toggle,
+// ou nil quando não há ciclo.
+func FindPrerequisiteCycle(toggle *Toggle, prerequisites []*TogglePrerequisite, appToggles []*Toggle) []string {
+	byID := make(map[string]*Toggle, len(appToggles))
+	for _, appToggle := range appToggles {
+		byID[appToggle.ID] = appToggle
+	}
+
+	dependencies := func(id string) []string {
+		current := byID[id]
+		if current == nil {
+			return nil
+		}
+		deps := make([]string, 0)
+		if current.ParentID != nil {
+			deps = append(deps, *current.ParentID)
+		}
+		list := current.Prerequisites
+		if id == toggle.ID {
+			list = prerequisites
+		}
+		for _, prerequisite := range list {
+			if prerequisite != nil {
+				deps = append(deps, prerequisite.PrerequisiteID)
+			}
+		}
+		return deps
+	}
+
+	// Busca em profundidade a partir do toggle; os demais toggles já formam um grafo sem ciclos
+	visited := make(map[string]bool)
+	var path []string
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		path = append(path, id)
+		for _, dep := range dependencies(id) {
+			if dep == toggle.ID {
+				path = append(path, dep)
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if !visit(toggle.ID) {
+		return nil
+	}
+
+	cycle := make([]string, len(path))
+	for i, id := range path {
+		cycle[i] = id
+		if found := byID[id]; found != nil {
+			cycle[i] = found.Path
+		}
+	}
+	return cycle
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func prerequisiteTree() (*Toggle, *Toggle, *Toggle, *Toggle) {
+	checkout := &Toggle{ID: "checkout", Path: "checkout", AppID: "app"}
+	payment := &Toggle{ID: "payment", Path: "checkout.payment", AppID: "app", ParentID: &checkout.ID}
+	billing := &Toggle{ID: "billing", Path: "billing", AppID: "app"}
+	invoice := &Toggle{ID: "invoice", Path: "billing.invoice", AppID: "app", ParentID: &billing.ID}
+	return checkout, payment, billing, invoice
+}
+
+func TestValidatePrerequisites(t *testing.T) {
+	checkout, payment, billing, invoice := prerequisiteTree()
+	appToggles := []*Toggle{checkout, payment, billing, invoice}
+
+	tests := []struct {
+		name          string
+		prerequisites []*TogglePrerequisite
+		field         string
+	}{
+		{"valid", []*TogglePrerequisite{{PrerequisiteID: "invoice", Enabled: true}, {PrerequisiteID: "billing"}}, ""},
+		{"missing toggle id", []*TogglePrerequisite{{Enabled: true}}, "prerequisites[0].toggle_id"},
+		{"self", []*TogglePrerequisite{{PrerequisiteID: "payment", Enabled: true}}, "prerequisites[0].toggle_id"},
+		{"unknown toggle", []*TogglePrerequisite{{PrerequisiteID: "other", Enabled: true}}, "prerequisites[0].toggle_id"},
+		{"duplicated", []*TogglePrerequisite{{PrerequisiteID: "invoice", Enabled: true}, {PrerequisiteID: "invoice"}}, "prerequisites[1].toggle_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidatePrerequisites(payment, tt.prerequisites, appToggles)
+			if tt.field == "" {
+				if !result.IsValid {
+					t.Errorf("Expected valid prerequisites, got %v", result.Errors)
+				}
+				return
+			}
+			if result.IsValid {
+				t.Fatal("Expected invalid prerequisites")
+			}
+			if result.Errors[0].Field != tt.field {
+				t.Errorf("Expected error on %s, got %s", tt.field, result.Errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestValidatePrerequisites_Limit(t *testing.T) {
+	toggle := &Toggle{ID: "main", Path: "main"}
+	appToggles := []*Toggle{toggle}
+	prerequisites := make([]*TogglePrerequisite, 0)
+	for i := 0; i <= MaxTogglePrerequisites; i++ {
+		other := &Toggle{ID: generateULID(), Path: "other"}
+		appToggles = append(appToggles, other)
+		prerequisites = append(prerequisites, &TogglePrerequisite{PrerequisiteID: other.ID, Enabled: true})
+	}
+
+	if result := ValidatePrerequisites(toggle, prerequisites, appToggles); result.IsValid {
+		t.Error("Expected prerequisites above the limit to be invalid")
+	}
+}
+
+func TestFindPrerequisiteCycle(t *testing.T) {
+	checkout, payment, billing, invoice := prerequisiteTree()
+	appToggles := []*Toggle{checkout, payment, billing, invoice}
+
+	// Sem ciclo: checkout.payment exige billing.invoice
+	if cycle := FindPrerequisiteCycle(payment, []*TogglePrerequisite{{PrerequisiteID: "invoice", Enabled: true}}, appToggles); cycle != nil {
+		t.Errorf("Expected no cycle, got %v", cycle)
+	}
+
+	// Ciclo direto: billing.invoice já exige checkout.payment
+	invoice.Prerequisites = []*TogglePrerequisite{{ToggleID: "invoice", PrerequisiteID: "payment", Enabled: true}}
+	cycle := FindPrerequisiteCycle(payment, []*TogglePrerequisite{{PrerequisiteID: "invoice", Enabled: true}}, appToggles)
+	expected := []string{"checkout.payment", "billing.invoice", "checkout.payment"}
+	if !reflect.DeepEqual(cycle, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycle)
+	}
+
+	// Ciclo pela hierarquia: billing exigir checkout.payment fecha o ciclo através do pai de billing.invoice
+	invoice.Prerequisites = nil
+	payment.Prerequisites = []*TogglePrerequisite{{ToggleID: "payment", PrerequisiteID: "invoice", Enabled: true}}
+	cycle = FindPrerequisiteCycle(billing, []*TogglePrerequisite{{PrerequisiteID: "payment", Enabled: true}}, appToggles)
+	expected = []string{"billing", "checkout.payment", "billing.invoice", "billing"}
+	if !reflect.DeepEqual(cycle, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycle)
+	}
+
+	// Exigir um descendente também fecha um ciclo
+	cycle = FindPrerequisiteCycle(checkout, []*TogglePrerequisite{{PrerequisiteID: "payment", Enabled: true}}, appToggles)
+	expected = []string{"checkout", "checkout.payment", "checkout"}
+	if !reflect.DeepEqual(cycle, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycle)
+	}
+}
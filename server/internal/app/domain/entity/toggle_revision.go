@@ -0,0 +1,287 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limites das revisões e snapshots
+const (
+	MaxSnapshotNameLength = 100
+)
+
+// RevisionAction identifica a alteração que gerou uma revisão
+type RevisionAction string
+
+const (
+	RevisionActionCreated    RevisionAction = "created"     // O toggle foi criado
+	RevisionActionUpdated    RevisionAction = "updated"     // Estado, regras, variantes, pré-requisitos ou metadados alterados
+	RevisionActionDeleted    RevisionAction = "deleted"     // O toggle foi removido; o estado é o último antes da remoção
+	RevisionActionRolledBack RevisionAction = "rolled_back" // O estado de uma revisão anterior foi restaurado
+	RevisionActionRestored   RevisionAction = "restored"    // O toggle voltou da lixeira ou de um snapshot da aplicação
+)
+
+// ToggleState é o estado configurável de um toggle guardado nas revisões e snapshots
+type ToggleState struct {
+	Enabled       bool                  `json:"enabled"`
+	Rules         []*ToggleRule         `json:"rules"`
+	Variants      ToggleVariants        `json:"variants"`
+	Prerequisites []*TogglePrerequisite `json:"prerequisites"`
+	Description   string                `json:"description"`
+	Owner         string                `json:"owner"`
+	Tags          ToggleTags            `json:"tags"`
+	Kind          ToggleKind            `json:"kind"`
+	ExpiresAt     *time.Time            `json:"expires_at"`
+}
+
+// NewToggleState copia o estado atual do toggle, sem compartilhar regras e pré-requisitos com ele
+func NewToggleState(toggle *Toggle) *ToggleState {
+	state := &ToggleState{
+		Enabled:       toggle.Enabled,
+		Rules:         make([]*ToggleRule, 0, len(toggle.Rules)),
+		Variants:      append(ToggleVariants{}, toggle.Variants...),
+		Prerequisites: make([]*TogglePrerequisite, 0, len(toggle.Prerequisites)),
+		Description:   toggle.Description,
+		Owner:         toggle.Owner,
+		Tags:          append(ToggleTags{}, toggle.Tags...),
+		Kind:          toggle.Kind,
+		ExpiresAt:     toggle.ExpiresAt,
+	}
+	for _, rule := range toggle.Rules {
+		conditions := make(RuleConditions, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			copied := *condition
+			conditions = append(conditions, &copied)
+		}
+		state.Rules = append(state.Rules, &ToggleRule{Position: rule.Position, Conditions: conditions, Variant: rule.Variant})
+	}
+	for _, prerequisite := range toggle.Prerequisites {
+		state.Prerequisites = append(state.Prerequisites, &TogglePrerequisite{PrerequisiteID: prerequisite.PrerequisiteID, Enabled: prerequisite.Enabled})
+	}
+	return state
+}
+
+// Metadata retorna os metadados guardados no estado
+func (s *ToggleState) Metadata() *ToggleMetadata {
+	return &ToggleMetadata{
+		Description: s.Description,
+		Owner:       s.Owner,
+		Tags:        append([]string{}, s.Tags...),
+		Kind:        s.Kind,
+		ExpiresAt:   s.ExpiresAt,
+	}
+}
+
+// Apply aplica o estado ao toggle; as regras e pré-requisitos recebem novos IDs ao serem gravados
+func (s *ToggleState) Apply(toggle *Toggle) {
+	copied := NewToggleState(&Toggle{Rules: s.Rules, Prerequisites: s.Prerequisites})
+
+	toggle.Enabled = s.Enabled
+	toggle.Variants = append(ToggleVariants{}, s.Variants...)
+	toggle.SetRules(copied.Rules)
+	toggle.SetPrerequisites(copied.Prerequisites)
+	toggle.SetMetadata(s.Metadata())
+}
+
+// Value serializa o estado para o banco de dados
+func (s ToggleState) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa o estado lido do banco de dados
+func (s *ToggleState) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*s = ToggleState{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type for toggle state: %T", value)
+	}
+
+	if len(data) == 0 {
+		*s = ToggleState{}
+		return nil
+	}
+	return json.Unmarshal(data, s)
+}
+
+// ToggleRevision é uma revisão imutável de um toggle, gravada a cada alteração.
+// Revision é sequencial por toggle, começando em 1.
+type ToggleRevision struct {
+	ID           string         `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	ToggleID     string         `json:"toggle_id" gorm:"not null;type:varchar(26);uniqueIndex:idx_toggle_revisions_toggle_revision,priority:1"`
+	AppID        string         `json:"app_id" gorm:"not null;type:varchar(26);index"`
+	Revision     int            `json:"revision" gorm:"not null;uniqueIndex:idx_toggle_revisions_toggle_revision,priority:2"`
+	Path         string         `json:"path" gorm:"not null;type:varchar(1000)"`
+	Action       RevisionAction `json:"action" gorm:"not null;type:varchar(20)"`
+	State        ToggleState    `json:"state" gorm:"type:text"`
+	RestoredFrom *int           `json:"restored_from,omitempty"` // Revisão restaurada, nas revisões rolled_back
+	ActorID      string         `json:"actor_id" gorm:"type:varchar(26)"`
+	Actor        string         `json:"actor" gorm:"type:varchar(100)"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// NewToggleRevision cria a revisão com o estado atual do toggle; o número é definido ao gravar.
+// Sem usuário a alteração é atribuída ao sistema.
+func NewToggleRevision(toggle *Toggle, action RevisionAction, actor *User) *ToggleRevision {
+	revision := &ToggleRevision{
+		ToggleID: toggle.ID,
+		AppID:    toggle.AppID,
+		Path:     toggle.Path,
+		Action:   action,
+		State:    *NewToggleState(toggle),
+		Actor:    "system",
+	}
+	if actor != nil {
+		revision.ActorID = actor.ID
+		revision.Actor = actor.Username
+	}
+	return revision
+}
+
+// BeforeCreate hook para gerar ID único
+func (r *ToggleRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateULID()
+	}
+	return nil
+}
+
+// SnapshotToggle é um toggle guardado em um snapshot: a posição na hierarquia e o estado
+type SnapshotToggle struct {
+	ID        string      `json:"id"`
+	Value     string      `json:"value"`
+	Path      string      `json:"path"`
+	Level     int         `json:"level"`
+	ParentID  *string     `json:"parent_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	State     ToggleState `json:"state"`
+}
+
+// Toggle reconstrói o toggle guardado no snapshot para a aplicação informada
+func (s *SnapshotToggle) Toggle(appID string) *Toggle {
+	toggle := &Toggle{
+		ID:        s.ID,
+		Value:     s.Value,
+		Path:      s.Path,
+		Level:     s.Level,
+		ParentID:  s.ParentID,
+		AppID:     appID,
+		CreatedAt: s.CreatedAt,
+	}
+	s.State.Apply(toggle)
+	return toggle
+}
+
+// SnapshotToggles é a lista de toggles de um snapshot, gravada como JSON
+type SnapshotToggles []*SnapshotToggle
+
+// Value serializa os toggles para o banco de dados
+func (t SnapshotToggles) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]*SnapshotToggle(t))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa os toggles lidos do banco de dados
+func (t *SnapshotToggles) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*t = SnapshotToggles{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type for snapshot toggles: %T", value)
+	}
+
+	if len(data) == 0 {
+		*t = SnapshotToggles{}
+		return nil
+	}
+
+	var toggles []*SnapshotToggle
+	if err := json.Unmarshal(data, &toggles); err != nil {
+		return err
+	}
+	*t = toggles
+	return nil
+}
+
+// ApplicationSnapshot é uma cópia de todos os toggles de uma aplicação, que pode ser restaurada de uma vez
+type ApplicationSnapshot struct {
+	ID        string          `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	AppID     string          `json:"app_id" gorm:"not null;type:varchar(26);index"`
+	Name      string          `json:"name" gorm:"not null;type:varchar(100)"`
+	Toggles   SnapshotToggles `json:"toggles" gorm:"type:text"`
+	ActorID   string          `json:"actor_id" gorm:"type:varchar(26)"`
+	Actor     string          `json:"actor" gorm:"type:varchar(100)"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewApplicationSnapshot cria o snapshot com o estado atual dos toggles da aplicação
+func NewApplicationSnapshot(appID string, name string, toggles []*Toggle, actor *User) *ApplicationSnapshot {
+	snapshot := &ApplicationSnapshot{
+		AppID:   appID,
+		Name:    strings.TrimSpace(name),
+		Toggles: make(SnapshotToggles, 0, len(toggles)),
+		Actor:   "system",
+	}
+	for _, toggle := range toggles {
+		snapshot.Toggles = append(snapshot.Toggles, &SnapshotToggle{
+			ID:        toggle.ID,
+			Value:     toggle.Value,
+			Path:      toggle.Path,
+			Level:     toggle.Level,
+			ParentID:  toggle.ParentID,
+			CreatedAt: toggle.CreatedAt,
+			State:     *NewToggleState(toggle),
+		})
+	}
+	if actor != nil {
+		snapshot.ActorID = actor.ID
+		snapshot.Actor = actor.Username
+	}
+	return snapshot
+}
+
+// Validate valida o snapshot antes de gravá-lo
+func (s *ApplicationSnapshot) Validate() *ValidationResult {
+	result := NewValidationResult()
+	if s.Name == "" {
+		result.AddError("name", "Snapshot name is required")
+	} else if len(s.Name) > MaxSnapshotNameLength {
+		result.AddError("name", fmt.Sprintf("Snapshot name must be at most %d characters", MaxSnapshotNameLength))
+	}
+	return result
+}
+
+// BeforeCreate hook para gerar ID único
+func (s *ApplicationSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = generateULID()
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewToggleState_CopiesRulesAndPrerequisites(t *testing.T) {
+	toggle := &Toggle{ID: "promo", AppID: "app", Path: "promo", Enabled: true, Owner: "growth", Tags: ToggleTags{"q3"}}
+	toggle.SetRules([]*ToggleRule{{Conditions: RuleConditions{{Type: ActivationRuleTypeUserID, Value: "u1"}}}})
+	toggle.SetPrerequisites([]*TogglePrerequisite{{PrerequisiteID: "billing", Enabled: true}})
+
+	state := NewToggleState(toggle)
+	toggle.Rules[0].Conditions[0].Value = "u2"
+	toggle.Prerequisites[0].Enabled = false
+	toggle.Tags[0] = "q4"
+
+	if state.Rules[0].Conditions[0].Value != "u1" || !state.Prerequisites[0].Enabled || state.Tags[0] != "q3" {
+		t.Errorf("Expected state to be independent from the toggle, got %+v", state)
+	}
+	if state.Rules[0].ID != "" || state.Prerequisites[0].ToggleID != "" {
+		t.Errorf("Expected state without row IDs, got %+v", state)
+	}
+}
+
+func TestToggleState_Apply(t *testing.T) {
+	source := &Toggle{ID: "promo", AppID: "app", Path: "promo", Enabled: false, Description: "old", Kind: ToggleKindExperiment}
+	source.SetRules([]*ToggleRule{{Conditions: RuleConditions{{Type: ActivationRuleTypePercentage, Value: "10"}}}})
+	state := NewToggleState(source)
+
+	toggle := &Toggle{ID: "promo", AppID: "app", Path: "promo", Enabled: true, Description: "new"}
+	state.Apply(toggle)
+
+	if toggle.Enabled || toggle.Description != "old" || toggle.Kind != ToggleKindExperiment {
+		t.Errorf("Expected state to be applied, got %+v", toggle)
+	}
+	if len(toggle.Rules) != 1 || toggle.Rules[0].ToggleID != "promo" || toggle.ActivationRule == nil {
+		t.Errorf("Expected rules to be applied, got %+v", toggle.Rules)
+	}
+
+	// Alterar o toggle não altera o estado aplicado
+	toggle.Rules[0].Conditions[0].Value = "90"
+	if state.Rules[0].Conditions[0].Value != "10" {
+		t.Errorf("Expected state to be independent from the toggle, got %+v", state.Rules[0].Conditions[0])
+	}
+}
+
+func TestToggleState_ValueScan(t *testing.T) {
+	toggle := &Toggle{ID: "promo", Enabled: true, Tags: ToggleTags{"q3"}}
+	toggle.SetRules([]*ToggleRule{{Conditions: RuleConditions{{Type: ActivationRuleTypeUserID, Value: "u1"}}, Variant: "gold"}})
+	toggle.Variants = ToggleVariants{{Name: "gold", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"gold"`), Weight: 100}}
+
+	value, err := NewToggleState(toggle).Value()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var state ToggleState
+	if err := state.Scan(value); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !state.Enabled || len(state.Rules) != 1 || state.Rules[0].Variant != "gold" || len(state.Variants) != 1 || state.Tags[0] != "q3" {
+		t.Errorf("Unexpected state after roundtrip %+v", state)
+	}
+
+	if err := state.Scan(42); err == nil {
+		t.Error("Expected error for unsupported type")
+	}
+}
+
+func TestApplicationSnapshot_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"before release", true},
+		{"   ", false},
+		{strings.Repeat("a", MaxSnapshotNameLength+1), false},
+	}
+
+	for _, tt := range tests {
+		snapshot := NewApplicationSnapshot("app", tt.name, nil, nil)
+		if result := snapshot.Validate(); result.IsValid != tt.valid {
+			t.Errorf("Expected valid %v for %q, got %v", tt.valid, tt.name, result.Errors)
+		}
+		if snapshot.Actor != "system" {
+			t.Errorf("Expected system actor without user, got %q", snapshot.Actor)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limites das regras compostas de um toggle
+const (
+	MaxToggleRules       = 20
+	MaxConditionsPerRule = 10
+)
+
+// RuleCondition representa uma condição de uma regra composta.
+// Todas as condições de uma regra precisam ser satisfeitas (AND).
+type RuleCondition struct {
+	Type   ActivationRuleType `json:"type"`
+	Value  string             `json:"value"`
+	Config json.RawMessage    `json:"config,omitempty"`
+}
+
+// Validate valida a condição com as mesmas regras de uma regra de ativação simples
+func (c *RuleCondition) Validate() error {
+	return (&ActivationRule{Type: c.Type, Value: c.Value, Config: c.Config}).ValidateRule()
+}
+
+// Normalize reescreve o valor da condição na forma canônica do seu tipo.
+// Valores inválidos são mantidos para que a validação os reporte.
+func (c *RuleCondition) Normalize() {
+	switch c.Type {
+	case ActivationRuleTypeIP:
+		if normalized, err := NormalizeIPList(c.Value); err == nil {
+			c.Value = normalized
+		}
+	case ActivationRuleTypeCountry:
+		if normalized, err := NormalizeCountryList(c.Value); err == nil {
+			c.Value = normalized
+		}
+	}
+}
+
+// RuleConditions representa as condições de uma regra, persistidas como um array JSON
+type RuleConditions []*RuleCondition
+
+// Value serializa as condições para o banco de dados
+func (c RuleConditions) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]*RuleCondition(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa as condições lidas do banco de dados
+func (c *RuleConditions) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = RuleConditions{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type for rule conditions: %T", value)
+	}
+
+	if len(data) == 0 {
+		*c = RuleConditions{}
+		return nil
+	}
+
+	var conditions []*RuleCondition
+	if err := json.Unmarshal(data, &conditions); err != nil {
+		return err
+	}
+	*c = conditions
+	return nil
+}
+
+// ToggleRule representa uma regra de um toggle. As regras são avaliadas na ordem
+// de Position e a primeira satisfeita ativa o toggle (OR entre regras).
+type ToggleRule struct {
+	ID         string         `json:"id" gorm:"primaryKey;type:varchar(26)"`
+	ToggleID   string         `json:"-" gorm:"not null;type:varchar(26);index"`
+	Position   int            `json:"position" gorm:"not null;default:0"`
+	Conditions RuleConditions `json:"conditions" gorm:"type:text"`
+	Variant    string         `json:"variant,omitempty" gorm:"type:varchar(100)"` // Variante retornada quando a regra é satisfeita
+	CreatedAt  time.Time      `json:"-"`
+	UpdatedAt  time.Time      `json:"-"`
+}
+
+// BeforeCreate hook para gerar ID único
+func (r *ToggleRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateULID()
+	}
+	return nil
+}
+
+// NewToggleRuleFromActivationRule converte uma regra de ativação simples em uma regra com uma única condição
+func NewToggleRuleFromActivationRule(rule *ActivationRule) *ToggleRule {
+	return &ToggleRule{
+		Conditions: RuleConditions{{Type: rule.Type, Value: rule.Value, Config: rule.Config}},
+		Variant:    rule.Variant,
+	}
+}
+
+// ValidateToggleRules valida as regras de um toggle e as variantes que elas referenciam
+func ValidateToggleRules(rules []*ToggleRule, variants ToggleVariants) *ValidationResult {
+	result := NewValidationResult()
+
+	if len(rules) > MaxToggleRules {
+		result.AddError("rules", fmt.Sprintf("A toggle can have at most %d rules", MaxToggleRules))
+	}
+
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if rule == nil {
+			result.AddError(field, "Rule is required")
+			continue
+		}
+
+		if len(rule.Conditions) == 0 {
+			result.AddError(field+".conditions", "A rule needs at least one condition")
+		}
+		if len(rule.Conditions) > MaxConditionsPerRule {
+			result.AddError(field+".conditions", fmt.Sprintf("A rule can have at most %d conditions", MaxConditionsPerRule))
+		}
+		for j, condition := range rule.Conditions {
+			conditionField := fmt.Sprintf("%s.conditions[%d]", field, j)
+			if condition == nil {
+				result.AddError(conditionField, "Condition is required")
+				continue
+			}
+			if err := condition.Validate(); err != nil {
+				result.AddError(conditionField, err.Error())
+			}
+		}
+
+		if rule.Variant != "" && variants.Find(rule.Variant) == nil {
+			result.AddError(field+".variant", fmt.Sprintf("Variant '%s' is not defined for this toggle", rule.Variant))
+		}
+	}
+
+	return result
+}
+
+// SetRules substitui as regras do toggle, renumerando as posições na ordem recebida
+// e normalizando os valores das condições.
+// A regra de ativação simples é mantida como espelho para SDKs que ainda não conhecem
+// as regras compostas: só é preenchida quando há uma única regra com uma única condição.
+func (t *Toggle) SetRules(rules []*ToggleRule) {
+	if rules == nil {
+		rules = []*ToggleRule{}
+	}
+	for i, rule := range rules {
+		rule.ToggleID = t.ID
+		rule.Position = i
+		for _, condition := range rule.Conditions {
+			if condition != nil {
+				condition.Normalize()
+			}
+		}
+	}
+	t.Rules = rules
+	t.HasActivationRule = len(rules) > 0
+	t.ActivationRule = nil
+
+	if len(rules) == 1 && len(rules[0].Conditions) == 1 {
+		condition := rules[0].Conditions[0]
+		t.ActivationRule = &ActivationRule{
+			Type:    condition.Type,
+			Value:   condition.Value,
+			Config:  condition.Config,
+			Variant: rules[0].Variant,
+		}
+	}
+}
+
+// LegacyCompositeRuleType é o tipo enviado no activation_rule de toggles com regras compostas.
+// SDKs antigos não conhecem o tipo e avaliam o toggle como desligado, em vez de ligado para todos.
+const LegacyCompositeRuleType ActivationRuleType = "composite"
+
+// LegacyActivationRule retorna a regra de ativação simples enviada aos SDKs que não conhecem
+// as regras compostas. Sem regras é nil, como o activation_rule sempre foi enviado, e com
+// regras sem espelho é uma regra do tipo LegacyCompositeRuleType.
+func (t *Toggle) LegacyActivationRule() *ActivationRule {
+	if len(t.Rules) == 0 {
+		return nil
+	}
+	if t.ActivationRule != nil && t.ActivationRule.Type != "" {
+		return t.ActivationRule
+	}
+	return &ActivationRule{Type: LegacyCompositeRuleType, Value: fmt.Sprintf("%d", len(t.Rules))}
+}
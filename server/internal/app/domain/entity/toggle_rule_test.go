@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateToggleRules(t *testing.T) {
+	variants := ToggleVariants{{Name: "on", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"on"`), Weight: 100}}
+
+	valid := []*ToggleRule{
+		{Conditions: RuleConditions{
+			{Type: ActivationRuleTypeCountry, Value: "BR,PT"},
+			{Type: ActivationRuleTypePercentage, Value: "10"},
+		}},
+		{Conditions: RuleConditions{{Type: ActivationRuleTypeUserID, Value: "u1"}}, Variant: "on"},
+	}
+	if result := ValidateToggleRules(valid, variants); !result.IsValid {
+		t.Fatalf("Expected valid rules, got %v", result.Errors)
+	}
+
+	tests := []struct {
+		name  string
+		rules []*ToggleRule
+	}{
+		{"rule without conditions", []*ToggleRule{{}}},
+		{"invalid condition type", []*ToggleRule{{Conditions: RuleConditions{{Type: "unknown", Value: "x"}}}}},
+		{"condition without value", []*ToggleRule{{Conditions: RuleConditions{{Type: ActivationRuleTypeIP}}}}},
+		{"undefined variant", []*ToggleRule{{Conditions: RuleConditions{{Type: ActivationRuleTypeUserID, Value: "u1"}}, Variant: "off"}}},
+		{"nil rule", []*ToggleRule{nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ValidateToggleRules(tt.rules, variants); result.IsValid {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestToggle_SetRules(t *testing.T) {
+	toggle := NewToggle("x", true, "x", 1, nil, "app")
+
+	toggle.SetRules([]*ToggleRule{{Conditions: RuleConditions{{Type: ActivationRuleTypeUserID, Value: "u1"}}, Variant: "v"}})
+	if !toggle.HasActivationRule || toggle.ActivationRule == nil || toggle.ActivationRule.Value != "u1" || toggle.ActivationRule.Variant != "v" {
+		t.Errorf("Expected single condition rule to be mirrored, got %+v", toggle.ActivationRule)
+	}
+	if toggle.Rules[0].ToggleID != toggle.ID || toggle.Rules[0].Position != 0 {
+		t.Errorf("Expected rule to be linked to the toggle")
+	}
+
+	toggle.SetRules([]*ToggleRule{
+		{Conditions: RuleConditions{{Type: ActivationRuleTypeUserID, Value: "u1"}}},
+		{Conditions: RuleConditions{{Type: ActivationRuleTypeIP, Value: "10.0.0.1"}}},
+	})
+	if !toggle.HasActivationRule || toggle.ActivationRule != nil || toggle.Rules[1].Position != 1 {
+		t.Errorf("Expected composite rules without mirror, got %+v", toggle)
+	}
+
+	toggle.ClearActivationRule()
+	if toggle.HasActivationRule || toggle.ActivationRule != nil || len(toggle.Rules) != 0 {
+		t.Errorf("Expected rules to be cleared")
+	}
+}
@@ -404,3 +404,110 @@ func TestNewToggle_InitialActivationRuleState(t *testing.T) {
 		t.Errorf("Expected ActivationRule to be nil for new toggle")
 	}
 }
+
+func TestValidateToggleMetadata(t *testing.T) {
+	tests := []struct {
+		name          string
+		metadata      ToggleMetadata
+		expectedValid bool
+		expectedField string
+	}{
+		{
+			name:          "valid metadata",
+			metadata:      ToggleMetadata{Description: "New checkout flow", Owner: "payments-team", Tags: []string{"Checkout", "q3"}, Kind: ToggleKindExperiment},
+			expectedValid: true,
+		},
+		{
+			name:          "empty metadata defaults to release",
+			metadata:      ToggleMetadata{},
+			expectedValid: true,
+		},
+		{
+			name:          "invalid kind",
+			metadata:      ToggleMetadata{Kind: "temporary"},
+			expectedValid: false,
+			expectedField: "kind",
+		},
+		{
+			name:          "invalid tag characters",
+			metadata:      ToggleMetadata{Tags: []string{"bad tag"}},
+			expectedValid: false,
+			expectedField: "tags",
+		},
+		{
+			name:          "invalid owner characters",
+			metadata:      ToggleMetadata{Owner: "<script>"},
+			expectedValid: false,
+			expectedField: "owner",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := tt.metadata
+			metadata.Normalize()
+			result := ValidateToggleMetadata(&metadata)
+
+			if result.IsValid != tt.expectedValid {
+				t.Fatalf("Expected valid=%v, got %v (%v)", tt.expectedValid, result.IsValid, result.Errors)
+			}
+			if !tt.expectedValid && result.Errors[0].Field != tt.expectedField {
+				t.Errorf("Expected error on field %s, got %s", tt.expectedField, result.Errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestNormalizeToggleTags(t *testing.T) {
+	tags := NormalizeToggleTags([]string{" Checkout ", "q3", "checkout", ""})
+
+	if len(tags) != 2 || tags[0] != "checkout" || tags[1] != "q3" {
+		t.Errorf("Expected [checkout q3], got %v", tags)
+	}
+}
+
+func TestToggleTags_ValueAndScan(t *testing.T) {
+	value, err := ToggleTags{"checkout", "q3"}.Value()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value != `["checkout","q3"]` {
+		t.Errorf("Expected JSON array, got %v", value)
+	}
+
+	var tags ToggleTags
+	if err := tags.Scan([]byte(`["checkout","q3"]`)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !tags.Has("q3") || len(tags) != 2 {
+		t.Errorf("Expected scanned tags to contain q3, got %v", tags)
+	}
+
+	if err := tags.Scan(nil); err != nil || len(tags) != 0 {
+		t.Errorf("Expected empty tags for NULL, got %v (%v)", tags, err)
+	}
+}
+
+func TestToggleFilter_Matches(t *testing.T) {
+	toggle := &Toggle{Owner: "payments", Kind: ToggleKindOps, Tags: ToggleTags{"checkout", "q3"}}
+
+	tests := []struct {
+		name     string
+		filter   *ToggleFilter
+		expected bool
+	}{
+		{"nil filter", nil, true},
+		{"matching owner and tag", &ToggleFilter{Owner: "payments", Tags: []string{"checkout"}}, true},
+		{"all tags required", &ToggleFilter{Tags: []string{"checkout", "q4"}}, false},
+		{"different owner", &ToggleFilter{Owner: "search"}, false},
+		{"different kind", &ToggleFilter{Kind: ToggleKindRelease}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(toggle); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
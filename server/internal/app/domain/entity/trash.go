@@ -0,0 +1,11 @@
+package entity
+
+// DefaultTrashRetentionDays é o período padrão em que toggles e aplicações removidos
+// ficam na lixeira antes de serem expurgados
+const DefaultTrashRetentionDays = 30
+
+// TrashPurgeResult é o total de registros removidos definitivamente em um expurgo da lixeira
+type TrashPurgeResult struct {
+	Applications int64 `json:"applications"`
+	Toggles      int64 `json:"toggles"`
+}
@@ -27,6 +27,14 @@ type User struct {
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
 
+	// FreezeOverride é a justificativa informada na requisição para alterar toggles durante
+	// uma janela de congelamento; só é aceita de usuários root e não é gravada
+	FreezeOverride string `json:"-" gorm:"-"`
+
+	// Sync indica que as alterações vêm da sincronização declarativa, que marca os recursos
+	// como gerenciados e pode alterá-los mesmo quando as alterações manuais são bloqueadas
+	Sync bool `json:"-" gorm:"-"`
+
 	// Relacionamentos
 	Applications []Application `json:"applications,omitempty" gorm:"many2many:user_applications;"`
 	Teams        []*Team       `json:"teams,omitempty" gorm:"many2many:team_users;"`
@@ -67,6 +75,11 @@ func (u *User) IsRoot() bool {
 	return u.Role == UserRoleRoot
 }
 
+// IsSync indica se as alterações do usuário vêm da sincronização declarativa
+func (u *User) IsSync() bool {
+	return u != nil && u.Sync
+}
+
 // IsAdmin verifica se o usuário é admin
 func (u *User) IsAdmin() bool {
 	return u.Role == UserRoleAdmin
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// Limites das variantes de um toggle
+const (
+	MaxToggleVariants      = 20
+	MaxVariantNameLength   = 100
+	MaxVariantPayloadBytes = 8192
+	VariantWeightTotal     = rules.VariantWeightTotal // Os pesos das variantes devem somar 100
+)
+
+var variantNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9\-_\.]*$`)
+
+// As variantes são definidas em rules, que o avaliador também usa
+type (
+	VariantPayloadType = rules.VariantPayloadType
+	Variant            = rules.Variant
+	ToggleVariants     = rules.Variants
+)
+
+const (
+	VariantPayloadString = rules.VariantPayloadString
+	VariantPayloadNumber = rules.VariantPayloadNumber
+	VariantPayloadJSON   = rules.VariantPayloadJSON
+)
+
+// ValidateVariants valida as variantes de um toggle: nomes únicos, payload compatível com o tipo e pesos somando 100
+func ValidateVariants(variants ToggleVariants) *ValidationResult {
+	result := NewValidationResult()
+	if len(variants) == 0 {
+		return result
+	}
+
+	if len(variants) > MaxToggleVariants {
+		result.AddError("variants", fmt.Sprintf("A toggle can have at most %d variants", MaxToggleVariants))
+	}
+
+	seen := make(map[string]bool, len(variants))
+	total := 0
+	for i, variant := range variants {
+		field := fmt.Sprintf("variants[%d]", i)
+		if variant == nil {
+			result.AddError(field, "Variant is required")
+			continue
+		}
+
+		switch {
+		case variant.Name == "":
+			result.AddError(field+".name", "Variant name is required")
+		case len(variant.Name) > MaxVariantNameLength:
+			result.AddError(field+".name", fmt.Sprintf("Variant name must be at most %d characters", MaxVariantNameLength))
+		case !variantNameRegex.MatchString(variant.Name):
+			result.AddError(field+".name", "Variant name contains invalid characters. Only letters, numbers, hyphens, underscores and dots are allowed")
+		case seen[variant.Name]:
+			result.AddError(field+".name", fmt.Sprintf("Variant '%s' is duplicated", variant.Name))
+		}
+		seen[variant.Name] = true
+
+		if variant.Weight < 0 || variant.Weight > VariantWeightTotal {
+			result.AddError(field+".weight", fmt.Sprintf("Weight must be between 0 and %d", VariantWeightTotal))
+		}
+		total += variant.Weight
+
+		if err := validateVariantPayload(variant); err != nil {
+			result.AddError(field+".payload", err.Error())
+		}
+	}
+
+	if total != VariantWeightTotal {
+		result.AddError("variants", fmt.Sprintf("Variant weights must add up to %d", VariantWeightTotal))
+	}
+
+	return result
+}
+
+// validateVariantPayload verifica se o payload é um JSON válido e compatível com o tipo declarado
+func validateVariantPayload(variant *Variant) error {
+	if !variant.PayloadType.IsValid() {
+		return fmt.Errorf("Payload type must be one of: string, number, json")
+	}
+	if len(variant.Payload) == 0 {
+		return fmt.Errorf("Payload is required")
+	}
+	if len(variant.Payload) > MaxVariantPayloadBytes {
+		return fmt.Errorf("Payload must be at most %d bytes", MaxVariantPayloadBytes)
+	}
+
+	if !json.Valid(variant.Payload) {
+		return fmt.Errorf("Payload must be valid JSON")
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(variant.Payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return fmt.Errorf("Payload must be valid JSON")
+	}
+
+	switch variant.PayloadType {
+	case VariantPayloadString:
+		if _, ok := decoded.(string); !ok {
+			return fmt.Errorf("Payload must be a JSON string")
+		}
+	case VariantPayloadNumber:
+		if _, ok := decoded.(json.Number); !ok {
+			return fmt.Errorf("Payload must be a JSON number")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateVariants(t *testing.T) {
+	valid := ToggleVariants{
+		{Name: "control", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"blue"`), Weight: 50},
+		{Name: "treatment", PayloadType: VariantPayloadJSON, Payload: json.RawMessage(`{"color":"green","size":2}`), Weight: 30},
+		{Name: "limit", PayloadType: VariantPayloadNumber, Payload: json.RawMessage(`10.5`), Weight: 20},
+	}
+	if result := ValidateVariants(valid); !result.IsValid {
+		t.Fatalf("Expected valid variants, got %v", result.Errors)
+	}
+	if result := ValidateVariants(nil); !result.IsValid {
+		t.Errorf("Expected no variants to be valid")
+	}
+
+	tests := []struct {
+		name     string
+		variants ToggleVariants
+	}{
+		{"weights not adding up", ToggleVariants{{Name: "a", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"a"`), Weight: 40}}},
+		{"negative weight", ToggleVariants{
+			{Name: "a", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"a"`), Weight: 110},
+			{Name: "b", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"b"`), Weight: -10},
+		}},
+		{"duplicated name", ToggleVariants{
+			{Name: "a", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"a"`), Weight: 50},
+			{Name: "a", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"b"`), Weight: 50},
+		}},
+		{"missing name", ToggleVariants{{PayloadType: VariantPayloadString, Payload: json.RawMessage(`"a"`), Weight: 100}}},
+		{"invalid name", ToggleVariants{{Name: "with space", PayloadType: VariantPayloadString, Payload: json.RawMessage(`"a"`), Weight: 100}}},
+		{"unknown payload type", ToggleVariants{{Name: "a", PayloadType: "bool", Payload: json.RawMessage(`true`), Weight: 100}}},
+		{"string type with number payload", ToggleVariants{{Name: "a", PayloadType: VariantPayloadString, Payload: json.RawMessage(`1`), Weight: 100}}},
+		{"number type with string payload", ToggleVariants{{Name: "a", PayloadType: VariantPayloadNumber, Payload: json.RawMessage(`"1"`), Weight: 100}}},
+		{"invalid json", ToggleVariants{{Name: "a", PayloadType: VariantPayloadJSON, Payload: json.RawMessage(`{"a":`), Weight: 100}}},
+		{"missing payload", ToggleVariants{{Name: "a", PayloadType: VariantPayloadJSON, Weight: 100}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ValidateVariants(tt.variants); result.IsValid {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestToggleVariants_Select(t *testing.T) {
+	variants := ToggleVariants{
+		{Name: "a", Weight: 25},
+		{Name: "zero", Weight: 0},
+		{Name: "b", Weight: 75},
+	}
+
+	tests := map[int]string{0: "a", 24: "a", 25: "b", 99: "b"}
+	for bucket, expected := range tests {
+		if variant := variants.Select(bucket); variant == nil || variant.Name != expected {
+			t.Errorf("Expected variant %s for bucket %d, got %v", expected, bucket, variant)
+		}
+	}
+	if variants.Select(100) != nil {
+		t.Error("Expected no variant outside the weight range")
+	}
+}
+
+func TestToggleVariants_ValueScan(t *testing.T) {
+	variants := ToggleVariants{{Name: "a", PayloadType: VariantPayloadJSON, Payload: json.RawMessage(`{"x":1}`), Weight: 100}}
+
+	value, err := variants.Value()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var scanned ToggleVariants
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(scanned) != 1 || scanned[0].Name != "a" || string(scanned[0].Payload) != `{"x":1}` {
+		t.Errorf("Unexpected scanned variants %+v", scanned)
+	}
+
+	if err := scanned.Scan(nil); err != nil || len(scanned) != 0 {
+		t.Errorf("Expected empty variants from NULL, got %v (%v)", scanned, err)
+	}
+}
@@ -0,0 +1,257 @@
+package evaluator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// Reason descreve por que uma avaliação chegou ao resultado retornado
+type Reason string
+
+const (
+	ReasonDisabled       Reason = "disabled"            // O toggle está desligado
+	ReasonParentDisabled Reason = "parent_disabled"     // Algum toggle ancestral está desligado
+	ReasonParentNoMatch  Reason = "parent_no_match"     // A regra de ativação de algum toggle ancestral não foi satisfeita
+	ReasonPrerequisite   Reason = "prerequisite_failed" // Algum pré-requisito não está no estado exigido
+	ReasonRuleMatch      Reason = "rule_match"          // A regra de ativação foi satisfeita
+	ReasonRuleNoMatch    Reason = "rule_no_match"       // A regra de ativação não foi satisfeita
+	ReasonDefault        Reason = "default"             // Toggle ligado sem regra de ativação
+	ReasonKillSwitch     Reason = "kill_switch"         // O kill switch da aplicação está ativo
+)
+
+// Context representa os dados da requisição usados para avaliar as regras de um toggle
+type Context struct {
+	Key        string            `json:"key"` // Identificador estável para rollouts; usa o user_id quando vazio
+	UserID     string            `json:"user_id"`
+	Parameter  string            `json:"parameter"`
+	IP         string            `json:"ip"`
+	Country    string            `json:"country"`
+	Attributes map[string]string `json:"attributes"`
+
+	Now        time.Time           `json:"-"`
+	Segments   map[string]*Segment `json:"-"` // Segmentos disponíveis para as regras do tipo segment, indexados pelo ID
+	KillSwitch bool                `json:"-"` // Kill switch da aplicação; quando ativo todos os toggles ficam desligados
+}
+
+// Attribute retorna o valor de um atributo do contexto; nomes desconhecidos são buscados nos atributos livres
+func (c *Context) Attribute(name string) (string, bool) {
+	switch name {
+	case "key":
+		return c.BucketKey(), c.BucketKey() != ""
+	case "user_id":
+		return c.UserID, c.UserID != ""
+	case "parameter":
+		return c.Parameter, c.Parameter != ""
+	case "ip":
+		return c.IP, c.IP != ""
+	case "country":
+		return c.Country, c.Country != ""
+	}
+	value, ok := c.Attributes[name]
+	return value, ok
+}
+
+// BucketKey retorna a chave usada para distribuir o contexto nas porcentagens e variantes
+func (c *Context) BucketKey() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return c.UserID
+}
+
+// Result representa o resultado da avaliação de um toggle
+type Result struct {
+	Path    string         `json:"path"`
+	Enabled bool           `json:"enabled"`
+	Variant *rules.Variant `json:"variant,omitempty"`
+	Reason  Reason         `json:"reason"`
+}
+
+// Evaluate avalia um toggle para o contexto informado.
+// O toggle precisa ter a cadeia de pais e os pré-requisitos carregados para que sejam considerados.
+// Quando o toggle está ativo, a variante vem da regra satisfeita ou, na ausência dela,
+// da distribuição determinística pelos pesos das variantes.
+func Evaluate(toggle *Toggle, ctx *Context) *Result {
+	if ctx == nil {
+		ctx = &Context{}
+	}
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+	return evaluate(toggle, ctx, nil)
+}
+
+// evaluate avalia o toggle. Com rastro, os pré-requisitos e as regras são avaliados mesmo
+// quando o resultado já está definido, para que o rastro explique todos eles.
+func evaluate(toggle *Toggle, ctx *Context, trace *Trace) *Result {
+	result := &Result{Path: toggle.Path}
+
+	// explaining indica que a avaliação continua apenas para completar o rastro
+	explaining := false
+
+	if ctx.KillSwitch {
+		result.Reason = ReasonKillSwitch
+		explaining = true
+	} else if !toggle.Enabled {
+		result.Reason = ReasonDisabled
+		explaining = true
+	} else if toggle.Parent != nil && !toggle.Parent.IsEnabled() {
+		result.Reason = ReasonParentDisabled
+		explaining = true
+	} else if !ancestorRulesMatch(toggle, ctx) {
+		result.Reason = ReasonParentNoMatch
+		explaining = true
+	}
+	if explaining && trace == nil {
+		return result
+	}
+
+	if !prerequisitesMet(toggle, ctx, trace) && !explaining {
+		result.Reason = ReasonPrerequisite
+		explaining = true
+	}
+	if explaining && trace == nil {
+		return result
+	}
+
+	if !explaining {
+		result.Enabled = true
+		result.Reason = ReasonDefault
+	}
+
+	// As regras são avaliadas em ordem e a primeira satisfeita define o resultado (OR)
+	if len(toggle.Rules) > 0 {
+		matched := matchingRule(toggle, ctx, trace)
+		if explaining {
+			return result
+		}
+		if matched == nil {
+			result.Enabled = false
+			result.Reason = ReasonRuleNoMatch
+			return result
+		}
+		result.Reason = ReasonRuleMatch
+		if matched.Variant != "" {
+			result.Variant = toggle.Variants.Find(matched.Variant)
+			return result
+		}
+	}
+	if explaining {
+		return result
+	}
+
+	result.Variant = selectVariant(toggle, ctx, trace)
+	return result
+}
+
+// ancestorRulesMatch verifica se cada ancestral com regras tem alguma regra satisfeita pelo contexto.
+// Como nos SDKs, um toggle só fica ativo quando todos os seus ancestrais estariam ativos.
+func ancestorRulesMatch(toggle *Toggle, ctx *Context) bool {
+	for parent := toggle.Parent; parent != nil; parent = parent.Parent {
+		if len(parent.Rules) > 0 && matchingRule(parent, ctx, nil) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// prerequisitesMet verifica se os pré-requisitos do toggle e dos seus ancestrais estão no estado exigido.
+// Cada pré-requisito é avaliado para o mesmo contexto; um pré-requisito não carregado nunca é satisfeito.
+func prerequisitesMet(toggle *Toggle, ctx *Context, trace *Trace) bool {
+	met := true
+	for current := toggle; current != nil; current = current.Parent {
+		for _, prerequisite := range current.Prerequisites {
+			var prerequisiteTrace *PrerequisiteTrace
+			if trace != nil {
+				prerequisiteTrace = &PrerequisiteTrace{DeclaredBy: current.Path, Required: prerequisite.Enabled}
+				trace.Prerequisites = append(trace.Prerequisites, prerequisiteTrace)
+			}
+
+			if prerequisite.Toggle == nil {
+				if prerequisiteTrace != nil {
+					prerequisiteTrace.Detail = fmt.Sprintf("prerequisite %s was not found", prerequisite.ID)
+				}
+				met = false
+			} else {
+				result := Evaluate(prerequisite.Toggle, ctx)
+				if prerequisiteTrace != nil {
+					prerequisiteTrace.Path = result.Path
+					prerequisiteTrace.Actual = result.Enabled
+					prerequisiteTrace.Reason = result.Reason
+					prerequisiteTrace.Met = result.Enabled == prerequisite.Enabled
+				}
+				if result.Enabled != prerequisite.Enabled {
+					met = false
+				}
+			}
+
+			if !met && trace == nil {
+				return false
+			}
+		}
+	}
+	return met
+}
+
+// matchingRule retorna a primeira regra do toggle satisfeita pelo contexto
+func matchingRule(toggle *Toggle, ctx *Context, trace *Trace) *Rule {
+	var matched *Rule
+	for _, rule := range toggle.Rules {
+		var ruleTrace *RuleTrace
+		if trace != nil {
+			ruleTrace = &RuleTrace{Position: rule.Position, Variant: rule.Variant, Conditions: make([]*ConditionTrace, 0)}
+			trace.Rules = append(trace.Rules, ruleTrace)
+		}
+
+		if !matchRule(rule, toggle.Path, ctx, ruleTrace) {
+			continue
+		}
+		if ruleTrace != nil {
+			ruleTrace.Matched = true
+			ruleTrace.Selected = matched == nil
+		}
+		if matched == nil {
+			matched = rule
+		}
+		if trace == nil {
+			return matched
+		}
+	}
+	return matched
+}
+
+// SelectVariant escolhe a variante do toggle pelos pesos, de forma determinística para a chave do contexto.
+// Sem chave retorna nil, a menos que uma variante tenha todo o peso.
+func SelectVariant(toggle *Toggle, ctx *Context) *rules.Variant {
+	return selectVariant(toggle, ctx, nil)
+}
+
+// selectVariant escolhe a variante registrando a distribuição no rastro, quando informado.
+// Sem chave só uma variante com todo o peso é escolhida, pois todos os contextos anônimos
+// receberiam a mesma variante.
+func selectVariant(toggle *Toggle, ctx *Context, trace *Trace) *rules.Variant {
+	if len(toggle.Variants) == 0 {
+		return nil
+	}
+	if ctx.BucketKey() == "" {
+		for _, variant := range toggle.Variants {
+			if variant.Weight >= rules.VariantWeightTotal {
+				if trace != nil {
+					trace.Variant = &VariantTrace{Detail: fmt.Sprintf("variant %q has the full weight", variant.Name)}
+				}
+				return variant
+			}
+		}
+		if trace != nil {
+			trace.Variant = &VariantTrace{Detail: "context has no key or user_id"}
+		}
+		return nil
+	}
+	bucket := Bucket(toggle.Path+":variant", ctx.BucketKey()) * rules.VariantWeightTotal / BucketCount
+	if trace != nil {
+		trace.Variant = &VariantTrace{Key: ctx.BucketKey(), Bucket: &bucket}
+	}
+	return toggle.Variants.Select(bucket)
+}
@@ -0,0 +1,435 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+func newVariantToggle() *Toggle {
+	toggle := &Toggle{Path: "checkout", Enabled: true}
+	toggle.Variants = rules.Variants{
+		{Name: "control", PayloadType: rules.VariantPayloadString, Payload: json.RawMessage(`"blue"`), Weight: 50},
+		{Name: "treatment", PayloadType: rules.VariantPayloadString, Payload: json.RawMessage(`"green"`), Weight: 50},
+	}
+	return toggle
+}
+
+// setRules define as regras do toggle numerando as posições como o servidor faz ao salvá-las
+func setRules(toggle *Toggle, toggleRules []*Rule) {
+	for i, rule := range toggleRules {
+		rule.Position = i
+	}
+	toggle.Rules = toggleRules
+}
+
+func TestBucket_Deterministic(t *testing.T) {
+	if Bucket("checkout", "user-1") != Bucket("checkout", "user-1") {
+		t.Error("Expected the same bucket for the same seed and key")
+	}
+	for i := 0; i < 1000; i++ {
+		if bucket := Bucket("checkout", fmt.Sprintf("user-%d", i)); bucket < 0 || bucket >= BucketCount {
+			t.Fatalf("Bucket out of range: %d", bucket)
+		}
+	}
+}
+
+func TestEvaluate_Disabled(t *testing.T) {
+	toggle := newVariantToggle()
+	toggle.Enabled = false
+
+	result := Evaluate(toggle, &Context{UserID: "u1"})
+	if result.Enabled || result.Variant != nil || result.Reason != ReasonDisabled {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	parent := &Toggle{Path: "shop", Enabled: false}
+	child := newVariantToggle()
+	child.Parent = parent
+	result = Evaluate(child, &Context{UserID: "u1"})
+	if result.Enabled || result.Reason != ReasonParentDisabled {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestEvaluate_KillSwitch(t *testing.T) {
+	toggle := newVariantToggle()
+
+	result := Evaluate(toggle, &Context{UserID: "u1", KillSwitch: true})
+	if result.Enabled || result.Variant != nil || result.Reason != ReasonKillSwitch {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	// O kill switch não altera o estado do toggle
+	if !toggle.Enabled {
+		t.Error("Expected toggle to stay enabled")
+	}
+	if result := Evaluate(toggle, &Context{UserID: "u1"}); !result.Enabled {
+		t.Errorf("Expected toggle enabled without kill switch, got %+v", result)
+	}
+}
+
+func TestEvaluate_VariantDistribution(t *testing.T) {
+	toggle := newVariantToggle()
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		ctx := &Context{Key: fmt.Sprintf("user-%d", i)}
+		result := Evaluate(toggle, ctx)
+		if !result.Enabled || result.Variant == nil || result.Reason != ReasonDefault {
+			t.Fatalf("Unexpected result %+v", result)
+		}
+		counts[result.Variant.Name]++
+
+		// A mesma chave sempre recebe a mesma variante
+		if again := Evaluate(toggle, ctx); again.Variant.Name != result.Variant.Name {
+			t.Fatalf("Expected sticky variant for %s", ctx.Key)
+		}
+	}
+
+	for name, count := range counts {
+		if count < 4500 || count > 5500 {
+			t.Errorf("Expected roughly half for %s, got %d", name, count)
+		}
+	}
+}
+
+func TestEvaluate_AnonymousContext(t *testing.T) {
+	// Sem chave um rollout parcial não ativa nenhum contexto, em vez de ativar todos ou nenhum
+	rollout := &Toggle{Path: "rollout", Enabled: true}
+	rollout.Rules = []*Rule{{Conditions: []*Condition{{Type: rules.TypePercentage, Value: "10"}}}}
+	for i := 0; i < 100; i++ {
+		result := Evaluate(rollout, &Context{IP: fmt.Sprintf("10.0.0.%d", i)})
+		if result.Enabled || result.Reason != ReasonRuleNoMatch {
+			t.Fatalf("Expected an anonymous context not to match a partial rollout, got %+v", result)
+		}
+	}
+	trace := Explain(rollout, &Context{})
+	if detail := trace.Rules[0].Conditions[0].Detail; detail != "context has no key or user_id" {
+		t.Errorf("Expected the trace to explain the missing key, got %q", detail)
+	}
+
+	// Nem recebe uma variante da divisão, a menos que uma delas tenha todo o peso
+	toggle := newVariantToggle()
+	result := Evaluate(toggle, &Context{})
+	if !result.Enabled || result.Variant != nil {
+		t.Errorf("Expected no variant for an anonymous context, got %+v", result)
+	}
+	if trace := Explain(toggle, &Context{}); trace.Variant == nil || trace.Variant.Bucket != nil || trace.Variant.Detail != "context has no key or user_id" {
+		t.Errorf("Unexpected variant trace %+v", trace.Variant)
+	}
+	toggle.Variants[0].Weight, toggle.Variants[1].Weight = 0, rules.VariantWeightTotal
+	if result := Evaluate(toggle, &Context{}); result.Variant == nil || result.Variant.Name != "treatment" {
+		t.Errorf("Expected the variant with the full weight, got %+v", result.Variant)
+	}
+}
+
+func TestEvaluate_RuleSelectsVariant(t *testing.T) {
+	toggle := newVariantToggle()
+	toggle.Rules = []*Rule{{Variant: "treatment", Conditions: []*Condition{{Type: rules.TypeUserID, Value: "u1, u2"}}}}
+
+	result := Evaluate(toggle, &Context{UserID: "u2"})
+	if !result.Enabled || result.Reason != ReasonRuleMatch || result.Variant == nil || result.Variant.Name != "treatment" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	result = Evaluate(toggle, &Context{UserID: "u3"})
+	if result.Enabled || result.Reason != ReasonRuleNoMatch || result.Variant != nil {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestMatchCondition(t *testing.T) {
+	now := time.Date(2025, 9, 6, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     Condition
+		ctx      Context
+		expected bool
+	}{
+		{"percentage 100", Condition{Type: rules.TypePercentage, Value: "100"}, Context{Key: "k"}, true},
+		{"percentage 0", Condition{Type: rules.TypePercentage, Value: "0"}, Context{Key: "k"}, false},
+		{"percentage invalid", Condition{Type: rules.TypePercentage, Value: "abc"}, Context{Key: "k"}, false},
+		{"percentage uses user_id", Condition{Type: rules.TypePercentage, Value: "100"}, Context{UserID: "u1"}, true},
+		{"percentage without key", Condition{Type: rules.TypePercentage, Value: "99.99"}, Context{}, false},
+		{"percentage 100 without key", Condition{Type: rules.TypePercentage, Value: "100"}, Context{}, true},
+		{"parameter match", Condition{Type: rules.TypeParameter, Value: "premium"}, Context{Parameter: "premium"}, true},
+		{"parameter mismatch", Condition{Type: rules.TypeParameter, Value: "premium"}, Context{Parameter: "free"}, false},
+		{"user in list", Condition{Type: rules.TypeUserID, Value: "a,b"}, Context{UserID: "b"}, true},
+		{"ip in list", Condition{Type: rules.TypeIP, Value: "10.0.0.1"}, Context{IP: "10.0.0.1"}, true},
+		{"country ignores case", Condition{Type: rules.TypeCountry, Value: "BR,US"}, Context{Country: "br"}, true},
+		{"time window", Condition{Type: rules.TypeTime, Value: "09:00-18:00"}, Context{Now: now}, true},
+		{"time window overnight", Condition{Type: rules.TypeTime, Value: "22:00-06:00"}, Context{Now: now}, false},
+		{"time from instant", Condition{Type: rules.TypeTime, Value: "2025-09-01T00:00:00Z"}, Context{Now: now}, true},
+		{"time range ended", Condition{Type: rules.TypeTime, Value: "2025-08-01T00:00:00Z/2025-09-01T00:00:00Z"}, Context{Now: now}, false},
+		{"unknown type", Condition{Type: "custom", Value: "x"}, Context{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if got := MatchCondition(&tt.rule, "seed", &ctx); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchCondition_Comparison(t *testing.T) {
+	semverRule := func(operator, value string) Condition {
+		return Condition{
+			Type:   rules.TypeSemver,
+			Value:  value,
+			Config: json.RawMessage(fmt.Sprintf(`{"attribute": "app_version", "operator": %q}`, operator)),
+		}
+	}
+	numberRule := func(operator, value string) Condition {
+		return Condition{
+			Type:   rules.TypeNumber,
+			Value:  value,
+			Config: json.RawMessage(fmt.Sprintf(`{"attribute": "age", "operator": %q}`, operator)),
+		}
+	}
+	version := func(v string) Context {
+		return Context{Attributes: map[string]string{"app_version": v}}
+	}
+	age := func(v string) Context {
+		return Context{Attributes: map[string]string{"age": v}}
+	}
+
+	tests := []struct {
+		name     string
+		rule     Condition
+		ctx      Context
+		expected bool
+	}{
+		{"semver gte release", semverRule("gte", "2.1.0"), version("2.1.0"), true},
+		{"semver prerelease below release", semverRule("gte", "2.1.0"), version("2.1.0-rc.1"), false},
+		{"semver prerelease numeric ordering", semverRule("gt", "2.1.0-rc.2"), version("2.1.0-rc.10"), true},
+		{"semver numeric below alphanumeric", semverRule("lt", "1.0.0-alpha"), version("1.0.0-1"), true},
+		{"semver shorter prerelease first", semverRule("lt", "1.0.0-alpha.1"), version("1.0.0-alpha"), true},
+		{"semver build metadata ignored", semverRule("eq", "1.0.0"), version("1.0.0+build.5"), true},
+		{"semver v prefix", semverRule("lt", "10.0.0"), version("v9.12.3"), true},
+		{"semver range includes min", semverRule("range", "2.0.0,3.0.0"), version("2.0.0"), true},
+		{"semver range excludes max", semverRule("range", "2.0.0,3.0.0"), version("3.0.0"), false},
+		{"semver range includes max prerelease", semverRule("range", "2.0.0,3.0.0"), version("3.0.0-beta"), true},
+		{"semver invalid attribute", semverRule("gte", "1.0.0"), version("latest"), false},
+		{"semver missing attribute", semverRule("gte", "1.0.0"), Context{}, false},
+		{"number gt", numberRule("gt", "18"), age("21"), true},
+		{"number lt", numberRule("lt", "18"), age("21"), false},
+		{"number between inclusive", numberRule("between", "18,21"), age("21"), true},
+		{"number between outside", numberRule("between", "18,21"), age("21.5"), false},
+		{"number invalid attribute", numberRule("gt", "18"), age("abc"), false},
+		{"number invalid config", Condition{Type: rules.TypeNumber, Value: "18"}, age("21"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if got := MatchCondition(&tt.rule, "seed", &ctx); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchCondition_Canary(t *testing.T) {
+	instances := Condition{Type: rules.TypeCanary, Value: "pod-a,pod-b"}
+	hosts := Condition{Type: rules.TypeCanary, Value: "web-01", Config: json.RawMessage(`{"attribute": "host"}`)}
+
+	tests := []struct {
+		name     string
+		rule     Condition
+		ctx      Context
+		expected bool
+	}{
+		{"canary instance", instances, Context{Attributes: map[string]string{"instance_id": "pod-b"}}, true},
+		{"stable instance", instances, Context{Attributes: map[string]string{"instance_id": "pod-c"}}, false},
+		{"missing instance", instances, Context{UserID: "pod-a"}, false},
+		{"canary host", hosts, Context{Attributes: map[string]string{"host": "web-01"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if got := MatchCondition(&tt.rule, "seed", &ctx); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchCondition_CanaryPercentageOfInstances(t *testing.T) {
+	rule := Condition{
+		Type:   rules.TypeCanary,
+		Value:  "20",
+		Config: json.RawMessage(`{"strategy": "percentage"}`),
+	}
+
+	canaries := 0
+	for i := 0; i < 1000; i++ {
+		instance := fmt.Sprintf("pod-%d", i)
+		first := MatchCondition(&rule, "checkout", &Context{UserID: "u1", Attributes: map[string]string{"instance_id": instance}})
+
+		// Todas as requisições de uma instância recebem o mesmo resultado
+		for _, user := range []string{"u2", "u3", "u4"} {
+			if MatchCondition(&rule, "checkout", &Context{UserID: user, Attributes: map[string]string{"instance_id": instance}}) != first {
+				t.Fatalf("Expected instance %s to get the same result for every request", instance)
+			}
+		}
+		if first {
+			canaries++
+		}
+	}
+
+	if canaries < 150 || canaries > 250 {
+		t.Errorf("Expected about 20%% of the instances to be canaries, got %d of 1000", canaries)
+	}
+}
+
+func TestEvaluate_CompositeRules(t *testing.T) {
+	toggle := newVariantToggle()
+	// (country in BR,PT AND percentage 100) OR (user_id in admin) -> treatment
+	setRules(toggle, []*Rule{
+		{Conditions: []*Condition{
+			{Type: rules.TypeCountry, Value: "BR,PT"},
+			{Type: rules.TypePercentage, Value: "100"},
+		}},
+		{Conditions: []*Condition{
+			{Type: rules.TypeUserID, Value: "admin"},
+		}, Variant: "treatment"},
+	})
+
+	tests := []struct {
+		name    string
+		ctx     Context
+		enabled bool
+		variant string
+	}{
+		{"first rule matches", Context{Country: "pt", Key: "k"}, true, ""},
+		{"only one condition of the first rule", Context{Country: "US", Key: "k"}, false, ""},
+		{"second rule matches", Context{UserID: "admin", Country: "US"}, true, "treatment"},
+		{"no rule matches", Context{UserID: "guest"}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			result := Evaluate(toggle, &ctx)
+			if result.Enabled != tt.enabled {
+				t.Fatalf("Expected enabled=%v, got %+v", tt.enabled, result)
+			}
+			if tt.variant != "" && (result.Variant == nil || result.Variant.Name != tt.variant) {
+				t.Errorf("Expected variant %s, got %+v", tt.variant, result.Variant)
+			}
+			if tt.enabled && result.Variant == nil {
+				t.Errorf("Expected a variant for an enabled toggle with variants")
+			}
+		})
+	}
+
+	// A primeira regra satisfeita vence, mesmo que uma regra posterior também seja satisfeita
+	result := Evaluate(toggle, &Context{UserID: "admin", Country: "BR", Key: "admin"})
+	if result.Reason != ReasonRuleMatch || result.Variant == nil {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+	if result.Variant.Name != SelectVariant(toggle, &Context{Key: "admin"}).Name {
+		t.Errorf("Expected the weighted variant from the first rule, got %s", result.Variant.Name)
+	}
+}
+
+func TestEvaluate_Prerequisites(t *testing.T) {
+	billing := &Toggle{Path: "billing", Enabled: true}
+	invoice := &Toggle{Path: "billing.invoice", Enabled: true, Parent: billing}
+	checkout := &Toggle{Path: "checkout", Enabled: true}
+	payment := &Toggle{Path: "checkout.payment", Enabled: true, Parent: checkout}
+	payment.Prerequisites = []*Prerequisite{
+		{ID: "invoice", Enabled: true, Toggle: invoice},
+	}
+
+	if result := Evaluate(payment, &Context{}); !result.Enabled {
+		t.Errorf("Expected toggle with met prerequisite to be enabled, got %+v", result)
+	}
+
+	// O pré-requisito de outro ramo é avaliado com a sua própria hierarquia
+	billing.Enabled = false
+	result := Evaluate(payment, &Context{})
+	if result.Enabled || result.Reason != ReasonPrerequisite {
+		t.Errorf("Expected prerequisite_failed, got %+v", result)
+	}
+
+	// Um pré-requisito pode exigir que o outro toggle esteja desativado
+	payment.Prerequisites[0].Enabled = false
+	if result := Evaluate(payment, &Context{}); !result.Enabled {
+		t.Errorf("Expected toggle requiring a disabled prerequisite to be enabled, got %+v", result)
+	}
+
+	// Um pré-requisito não carregado nunca é satisfeito
+	payment.Prerequisites[0].Toggle = nil
+	if result := Evaluate(payment, &Context{}); result.Reason != ReasonPrerequisite {
+		t.Errorf("Expected prerequisite_failed for an unlinked prerequisite, got %+v", result)
+	}
+}
+
+func TestEvaluate_AncestorPrerequisites(t *testing.T) {
+	flag := &Toggle{Path: "flag", Enabled: false}
+	checkout := &Toggle{Path: "checkout", Enabled: true}
+	checkout.Prerequisites = []*Prerequisite{
+		{ID: "flag", Enabled: true, Toggle: flag},
+	}
+	payment := &Toggle{Path: "checkout.payment", Enabled: true, Parent: checkout}
+
+	result := Evaluate(payment, &Context{})
+	if result.Enabled || result.Reason != ReasonPrerequisite {
+		t.Errorf("Expected the ancestor's prerequisite to block the child, got %+v", result)
+	}
+
+	flag.Enabled = true
+	if result := Evaluate(payment, &Context{}); !result.Enabled {
+		t.Errorf("Expected child to be enabled once the ancestor's prerequisite is met, got %+v", result)
+	}
+}
+
+// TestEvaluate_AncestorRules reproduz os casos de areParentsActive do cliente Kotlin: cada
+// ancestral precisa estar ligado e ter a sua regra de ativação satisfeita
+func TestEvaluate_AncestorRules(t *testing.T) {
+	rule := func(ruleType rules.Type, value string) []*Rule {
+		return []*Rule{{Conditions: []*Condition{{Type: ruleType, Value: value}}}}
+	}
+
+	tests := []struct {
+		name            string
+		rootRules       []*Rule
+		parentRules     []*Rule
+		expectedEnabled bool
+		expectedReason  Reason
+	}{
+		{"ancestors without rules", nil, nil, true, ReasonDefault},
+		{"parent rule matched", nil, rule(rules.TypeUserID, "u1"), true, ReasonDefault},
+		{"parent rule not matched", nil, rule(rules.TypeUserID, "u2"), false, ReasonParentNoMatch},
+		{"parent at 0%", nil, rule(rules.TypePercentage, "0"), false, ReasonParentNoMatch},
+		{"parent at 100%", nil, rule(rules.TypePercentage, "100"), true, ReasonDefault},
+		{"root rule not matched", rule(rules.TypeCountry, "PT"), nil, false, ReasonParentNoMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &Toggle{Path: "shop", Enabled: true, Rules: tt.rootRules}
+			parent := &Toggle{Path: "shop.checkout", Enabled: true, Rules: tt.parentRules, Parent: root}
+			child := &Toggle{Path: "shop.checkout.payment", Enabled: true, Parent: parent}
+
+			ctx := &Context{UserID: "u1", Country: "BR"}
+			result := Evaluate(child, ctx)
+			if result.Enabled != tt.expectedEnabled || result.Reason != tt.expectedReason {
+				t.Errorf("Expected (%v, %s), got %+v", tt.expectedEnabled, tt.expectedReason, result)
+			}
+			if trace := Explain(child, ctx); trace.Result.Reason != result.Reason {
+				t.Errorf("Expected explain to agree with evaluate, got %s", trace.Result.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package evaluator
+
+import "hash/fnv"
+
+// BucketCount é a quantidade de buckets usada nas distribuições por porcentagem (0,01% de precisão)
+const BucketCount = 10000
+
+// Bucket distribui de forma determinística uma chave em um dos BucketCount buckets.
+// A mesma combinação de seed e chave sempre cai no mesmo bucket, de modo que um usuário
+// mantém o resultado entre avaliações e entre instâncias do servidor.
+func Bucket(seed, key string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(seed))
+	hash.Write([]byte{':'})
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % BucketCount)
+}
@@ -0,0 +1,114 @@
+package evaluator
+
+import (
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// maxCachedTries limita as árvores de prefixos mantidas em memória
+const maxCachedTries = 512
+
+// trieNode é um nó da árvore de prefixos; cada nível corresponde a um bit do endereço
+type trieNode struct {
+	children [2]*trieNode
+	terminal bool // Algum prefixo termina neste nó
+}
+
+// prefixTrie é uma árvore binária de prefixos IP com raízes separadas para IPv4 e IPv6.
+// A busca percorre no máximo 32 ou 128 níveis, independente da quantidade de blocos.
+type prefixTrie struct {
+	v4 trieNode
+	v6 trieNode
+}
+
+// newPrefixTrie monta a árvore a partir de endereços e blocos CIDR; entradas inválidas são ignoradas
+func newPrefixTrie(entries []string) *prefixTrie {
+	trie := &prefixTrie{}
+	for _, entry := range entries {
+		if prefix, err := rules.ParsePrefix(entry); err == nil {
+			trie.insert(prefix)
+		}
+	}
+	return trie
+}
+
+// insert adiciona um prefixo; blocos IPv4 escritos como IPv6 mapeado são tratados como IPv4
+func (t *prefixTrie) insert(prefix netip.Prefix) {
+	addr, bits := prefix.Addr(), prefix.Bits()
+	if addr.Is4In6() && bits >= 96 {
+		addr, bits = addr.Unmap(), bits-96
+	}
+
+	node := t.root(addr)
+	raw := addr.AsSlice()
+	for i := 0; i < bits; i++ {
+		bit := raw[i/8] >> (7 - i%8) & 1
+		if node.children[bit] == nil {
+			node.children[bit] = &trieNode{}
+		}
+		node = node.children[bit]
+	}
+	node.terminal = true
+}
+
+// Contains verifica se o endereço pertence a algum dos prefixos
+func (t *prefixTrie) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap().WithZone("")
+	node := t.root(addr)
+	raw := addr.AsSlice()
+	for i := 0; node != nil; i++ {
+		if node.terminal {
+			return true
+		}
+		if i == addr.BitLen() {
+			return false
+		}
+		node = node.children[raw[i/8]>>(7-i%8)&1]
+	}
+	return false
+}
+
+// root retorna a raiz correspondente à família do endereço
+func (t *prefixTrie) root(addr netip.Addr) *trieNode {
+	if addr.Is4() {
+		return &t.v4
+	}
+	return &t.v6
+}
+
+// trieCache guarda as árvores já montadas pelo texto das entradas, para que listas grandes
+// não sejam lidas novamente a cada avaliação. Ao atingir o limite o cache é esvaziado.
+type trieCache struct {
+	mu    sync.Mutex
+	tries map[string]*prefixTrie
+}
+
+var tries = &trieCache{tries: make(map[string]*prefixTrie)}
+
+// get retorna a árvore das entradas separadas por vírgula, montando-a quando necessário
+func (c *trieCache) get(list string) *prefixTrie {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if trie, ok := c.tries[list]; ok {
+		return trie
+	}
+	if len(c.tries) >= maxCachedTries {
+		c.tries = make(map[string]*prefixTrie)
+	}
+	trie := newPrefixTrie(strings.Split(list, ","))
+	c.tries[list] = trie
+	return trie
+}
+
+// matchAddress verifica se o IP informado pertence à lista de endereços e blocos CIDR
+func matchAddress(list, ip string) bool {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false
+	}
+	return tries.get(list).Contains(addr)
+}
@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestPrefixTrie_Contains(t *testing.T) {
+	trie := newPrefixTrie([]string{"10.0.0.0/8", "192.168.1.7", "2001:db8::/32", "::ffff:172.16.0.0/108", "invalid"})
+
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"10.255.0.1", true},
+		{"11.0.0.1", false},
+		{"192.168.1.7", true},
+		{"192.168.1.8", false},
+		{"::ffff:10.1.1.1", true},
+		{"172.16.5.5", true},
+		{"172.32.0.1", false},
+		{"2001:db8:1::1", true},
+		{"2001:db9::1", false},
+		{"fe80::1%eth0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := trie.Contains(netip.MustParseAddr(tt.ip)); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if all := newPrefixTrie([]string{"0.0.0.0/0"}); !all.Contains(netip.MustParseAddr("8.8.8.8")) || all.Contains(netip.MustParseAddr("::1")) {
+		t.Error("Expected 0.0.0.0/0 to match every IPv4 address only")
+	}
+}
+
+func TestMatchAddress_LargeList(t *testing.T) {
+	entries := make([]string, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		entries = append(entries, fmt.Sprintf("10.%d.%d.0/24", i/256, i%256))
+	}
+	list := strings.Join(entries, ",")
+
+	if !matchAddress(list, "10.19.135.42") {
+		t.Error("Expected address inside one of the blocks to match")
+	}
+	if matchAddress(list, "10.200.0.1") {
+		t.Error("Expected address outside the blocks not to match")
+	}
+	if matchAddress(list, "not-an-ip") {
+		t.Error("Expected invalid address not to match")
+	}
+}
+
+func BenchmarkMatchAddress(b *testing.B) {
+	entries := make([]string, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		entries = append(entries, fmt.Sprintf("2001:db8:%x::/48", i))
+	}
+	list := strings.Join(entries, ",")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matchAddress(list, "2001:db8:270f::1")
+	}
+}
@@ -0,0 +1,64 @@
+package evaluator
+
+import (
+	"encoding/json"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// Toggle é o toggle como o avaliador o enxerga: o estado, as regras, as variantes e as ligações
+// com o pai e com os pré-requisitos. O servidor o monta a partir das entidades persistidas e o
+// SDK a partir do snapshot recebido, para que ambos avaliem com o mesmo código.
+type Toggle struct {
+	Path          string
+	Enabled       bool
+	Parent        *Toggle
+	Rules         []*Rule
+	Prerequisites []*Prerequisite
+	Variants      rules.Variants
+}
+
+// Rule é uma regra do toggle; todas as condições precisam ser satisfeitas (AND)
+type Rule struct {
+	Position   int
+	Variant    string // Variante retornada quando a regra é satisfeita
+	Conditions []*Condition
+}
+
+// Condition é uma condição de uma regra
+type Condition struct {
+	Type   rules.Type
+	Value  string
+	Config json.RawMessage
+}
+
+// Prerequisite é um toggle que precisa estar no estado exigido; Toggle é nil quando ele não foi encontrado
+type Prerequisite struct {
+	ID      string
+	Enabled bool
+	Toggle  *Toggle
+}
+
+// Segment é um segmento referenciado pelas regras do tipo segment
+type Segment struct {
+	ID          string
+	Name        string
+	Constraints []*Constraint
+}
+
+// Constraint é uma restrição de um segmento sobre um atributo do contexto
+type Constraint struct {
+	Attribute string
+	Operator  rules.SegmentOperator
+	Values    []string
+}
+
+// IsEnabled verifica se o toggle e todos os seus ancestrais estão ligados
+func (t *Toggle) IsEnabled() bool {
+	for current := t; current != nil; current = current.Parent {
+		if !current.Enabled {
+			return false
+		}
+	}
+	return true
+}
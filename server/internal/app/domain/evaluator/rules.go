@@ -0,0 +1,292 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+	"github.com/manorfm/totoogle/internal/app/domain/semver"
+)
+
+// matcher verifica se o contexto satisfaz uma condição. O rastro é opcional (nil fora do explain)
+// e recebe a explicação do resultado.
+type matcher func(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool
+
+// matchers associa cada tipo de condição à sua avaliação
+var matchers = map[rules.Type]matcher{
+	rules.TypePercentage: matchPercentage,
+	rules.TypeCanary:     matchCanary,
+	rules.TypeParameter:  matchParameter,
+	rules.TypeUserID:     matchUserID,
+	rules.TypeIP:         matchIP,
+	rules.TypeCountry:    matchCountry,
+	rules.TypeTime:       matchTime,
+	rules.TypeSegment:    matchSegment,
+	rules.TypeSemver:     matchComparison,
+	rules.TypeNumber:     matchComparison,
+}
+
+// MatchRule verifica se o contexto satisfaz todas as condições da regra (AND)
+func MatchRule(rule *Rule, seed string, ctx *Context) bool {
+	return matchRule(rule, seed, ctx, nil)
+}
+
+// matchRule avalia a regra; com rastro todas as condições são avaliadas para explicar cada uma
+func matchRule(rule *Rule, seed string, ctx *Context, trace *RuleTrace) bool {
+	if len(rule.Conditions) == 0 {
+		return false
+	}
+	matched := true
+	for _, condition := range rule.Conditions {
+		var conditionTrace *ConditionTrace
+		if trace != nil {
+			conditionTrace = newConditionTrace(condition)
+			trace.Conditions = append(trace.Conditions, conditionTrace)
+		}
+		if !matchCondition(condition, seed, ctx, conditionTrace) {
+			matched = false
+			if trace == nil {
+				return false
+			}
+		}
+	}
+	return matched
+}
+
+// MatchCondition verifica se o contexto satisfaz a condição. Tipos desconhecidos nunca são satisfeitos.
+func MatchCondition(condition *Condition, seed string, ctx *Context) bool {
+	return matchCondition(condition, seed, ctx, nil)
+}
+
+// matchCondition avalia a condição registrando o resultado no rastro, quando informado
+func matchCondition(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	match, ok := matchers[condition.Type]
+	if !ok {
+		trace.explain("unknown condition type %q", condition.Type)
+		return false
+	}
+	matched := match(condition, seed, ctx, trace)
+	if trace != nil {
+		trace.Matched = matched
+	}
+	return matched
+}
+
+// matchPercentage ativa a porcentagem configurada dos contextos, distribuídos pela chave.
+// Sem chave só 100% é satisfeita: todos os contextos anônimos cairiam no mesmo bucket.
+func matchPercentage(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	percentage, err := strconv.ParseFloat(strings.TrimSpace(condition.Value), 64)
+	if err != nil {
+		trace.explain("invalid percentage %q", condition.Value)
+		return false
+	}
+	if ctx.BucketKey() == "" {
+		if percentage >= 100 {
+			trace.explain("every context matches 100%%")
+			return true
+		}
+		trace.explain("context has no key or user_id")
+		return false
+	}
+	bucket := Bucket(seed, ctx.BucketKey())
+	threshold := percentage * BucketCount / 100
+	trace.bucket(bucket, threshold, "key %q", ctx.BucketKey())
+	return float64(bucket) < threshold
+}
+
+// matchCanary satisfaz a condição para as instâncias canário, identificadas por um atributo do contexto.
+// Na estratégia percentage as instâncias são distribuídas pelo seu identificador, então todas as
+// requisições de uma instância recebem o mesmo resultado. Sem o atributo a condição não é satisfeita.
+func matchCanary(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	rule, err := rules.ParseCanaryRule(condition.Value, condition.Config)
+	if err != nil {
+		trace.explain("invalid canary rule: %v", err)
+		return false
+	}
+	instance, ok := ctx.Attribute(rule.Attribute)
+	if !ok || instance == "" {
+		trace.explain("context has no %q attribute", rule.Attribute)
+		return false
+	}
+
+	if rule.Strategy == rules.CanaryStrategyPercentage {
+		bucket := Bucket(seed+":canary", instance)
+		threshold := rule.Percentage * BucketCount / 100
+		trace.bucket(bucket, threshold, "instance %q", instance)
+		return float64(bucket) < threshold
+	}
+	return trace.membership(containsItem(rule.Instances, instance), rule.Attribute, instance)
+}
+
+// matchParameter compara o parâmetro informado com o valor da condição
+func matchParameter(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	if ctx.Parameter == "" {
+		trace.explain("context has no parameter")
+		return false
+	}
+	if ctx.Parameter != condition.Value {
+		trace.explain("parameter %q is not %q", ctx.Parameter, condition.Value)
+		return false
+	}
+	trace.explain("parameter is %q", ctx.Parameter)
+	return true
+}
+
+// matchUserID verifica se o usuário está na lista separada por vírgulas
+func matchUserID(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	if ctx.UserID == "" {
+		trace.explain("context has no user_id")
+		return false
+	}
+	return trace.membership(containsValue(condition.Value, ctx.UserID, false), "user_id", ctx.UserID)
+}
+
+// matchIP verifica se o IP pertence a algum dos endereços ou blocos CIDR da lista separada por vírgulas
+func matchIP(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	if ctx.IP == "" {
+		trace.explain("context has no ip")
+		return false
+	}
+	return trace.membership(matchAddress(condition.Value, ctx.IP), "ip", ctx.IP)
+}
+
+// matchCountry verifica se o país está na lista separada por vírgulas, sem diferenciar maiúsculas
+func matchCountry(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	if ctx.Country == "" {
+		trace.explain("context has no country")
+		return false
+	}
+	return trace.membership(containsValue(condition.Value, ctx.Country, true), "country", ctx.Country)
+}
+
+// matchTime satisfaz a condição dentro de uma janela de horário UTC ("09:00-18:00", podendo cruzar a meia-noite)
+// ou a partir de um instante RFC3339, opcionalmente até outro ("2025-01-01T00:00:00Z/2025-02-01T00:00:00Z")
+func matchTime(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	matched := matchTimeWindow(condition.Value, ctx.Now)
+	if matched {
+		trace.explain("%s is inside %s", ctx.Now.UTC().Format(time.RFC3339), condition.Value)
+	} else {
+		trace.explain("%s is outside %s", ctx.Now.UTC().Format(time.RFC3339), condition.Value)
+	}
+	return matched
+}
+
+// matchTimeWindow verifica se o instante está na janela da condição de tempo
+func matchTimeWindow(value string, now time.Time) bool {
+	value = strings.TrimSpace(value)
+
+	if start, end, ok := strings.Cut(value, "-"); ok && len(start) == 5 {
+		from, err := time.Parse("15:04", strings.TrimSpace(start))
+		if err != nil {
+			return false
+		}
+		to, err := time.Parse("15:04", strings.TrimSpace(end))
+		if err != nil {
+			return false
+		}
+		utc := now.UTC()
+		minute := utc.Hour()*60 + utc.Minute()
+		fromMinute := from.Hour()*60 + from.Minute()
+		toMinute := to.Hour()*60 + to.Minute()
+		if fromMinute <= toMinute {
+			return minute >= fromMinute && minute < toMinute
+		}
+		return minute >= fromMinute || minute < toMinute
+	}
+
+	start, end, hasEnd := strings.Cut(value, "/")
+	from, err := time.Parse(time.RFC3339, strings.TrimSpace(start))
+	if err != nil || now.Before(from) {
+		return false
+	}
+	if hasEnd {
+		to, err := time.Parse(time.RFC3339, strings.TrimSpace(end))
+		if err != nil || !now.Before(to) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchComparison compara um atributo do contexto como versão semântica (semver) ou número (number).
+// Atributos ausentes ou que não podem ser lidos nunca satisfazem a condição.
+func matchComparison(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	rule, err := rules.ParseComparisonRule(condition.Type, condition.Value, condition.Config)
+	if err != nil {
+		trace.explain("invalid %s rule: %v", condition.Type, err)
+		return false
+	}
+	value, ok := ctx.Attribute(rule.Attribute)
+	if !ok {
+		trace.explain("context has no %q attribute", rule.Attribute)
+		return false
+	}
+
+	// compare retorna a comparação do atributo com o operando informado
+	var compare func(operand string) int
+	switch condition.Type {
+	case rules.TypeSemver:
+		actual, err := semver.Parse(value)
+		if err != nil {
+			trace.explain("%s %q is not a semantic version", rule.Attribute, value)
+			return false
+		}
+		compare = func(operand string) int {
+			expected, _ := semver.Parse(operand)
+			return actual.Compare(expected)
+		}
+	default:
+		actual, err := rules.ParseRuleNumber(value)
+		if err != nil {
+			trace.explain("%s %q is not a number", rule.Attribute, value)
+			return false
+		}
+		compare = func(operand string) int {
+			expected, _ := rules.ParseRuleNumber(operand)
+			switch {
+			case actual < expected:
+				return -1
+			case actual > expected:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	matched := false
+	switch rule.Operator {
+	case rules.ComparisonOperatorEq:
+		matched = compare(rule.Operands[0]) == 0
+	case rules.ComparisonOperatorGt:
+		matched = compare(rule.Operands[0]) > 0
+	case rules.ComparisonOperatorGte:
+		matched = compare(rule.Operands[0]) >= 0
+	case rules.ComparisonOperatorLt:
+		matched = compare(rule.Operands[0]) < 0
+	case rules.ComparisonOperatorLte:
+		matched = compare(rule.Operands[0]) <= 0
+	case rules.ComparisonOperatorRange:
+		matched = compare(rule.Operands[0]) >= 0 && compare(rule.Operands[1]) < 0
+	case rules.ComparisonOperatorBetween:
+		matched = compare(rule.Operands[0]) >= 0 && compare(rule.Operands[1]) <= 0
+	}
+
+	if matched {
+		trace.explain("%s %q is %s %s", rule.Attribute, value, rule.Operator, strings.Join(rule.Operands, ","))
+	} else {
+		trace.explain("%s %q is not %s %s", rule.Attribute, value, rule.Operator, strings.Join(rule.Operands, ","))
+	}
+	return matched
+}
+
+// containsValue verifica se o valor está na lista separada por vírgulas
+func containsValue(list, value string, ignoreCase bool) bool {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == value || (ignoreCase && strings.EqualFold(item, value)) {
+			return true
+		}
+	}
+	return false
+}
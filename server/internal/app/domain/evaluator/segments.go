@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+	"github.com/manorfm/totoogle/internal/app/domain/semver"
+)
+
+// matchSegment satisfaz a condição quando o contexto pertence a algum dos segmentos referenciados.
+// Segmentos ausentes do contexto de avaliação nunca são satisfeitos.
+func matchSegment(condition *Condition, seed string, ctx *Context, trace *ConditionTrace) bool {
+	for _, id := range rules.ParseSegmentIDs(condition.Value) {
+		segment, ok := ctx.Segments[id]
+		if ok && MatchSegment(segment, ctx) {
+			trace.explain("context belongs to segment %q", segment.Name)
+			return true
+		}
+	}
+	trace.explain("context belongs to none of the segments %s", condition.Value)
+	return false
+}
+
+// MatchSegment verifica se o contexto satisfaz todas as restrições do segmento
+func MatchSegment(segment *Segment, ctx *Context) bool {
+	if len(segment.Constraints) == 0 {
+		return false
+	}
+	for _, constraint := range segment.Constraints {
+		if !MatchConstraint(constraint, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchConstraint verifica se o atributo do contexto satisfaz a restrição.
+// Um atributo ausente só satisfaz o operador not_in.
+func MatchConstraint(constraint *Constraint, ctx *Context) bool {
+	value, ok := ctx.Attribute(constraint.Attribute)
+	if !ok {
+		return constraint.Operator == rules.SegmentOperatorNotIn
+	}
+
+	switch {
+	case constraint.Operator == rules.SegmentOperatorIn:
+		return containsItem(constraint.Values, value)
+	case constraint.Operator == rules.SegmentOperatorNotIn:
+		return !containsItem(constraint.Values, value)
+	case constraint.Operator == rules.SegmentOperatorCIDR:
+		return matchCIDR(constraint.Values, value)
+	case constraint.Operator.IsNumeric():
+		return matchNumber(constraint, value)
+	case constraint.Operator.IsSemver():
+		return matchSemver(constraint, value)
+	}
+	return false
+}
+
+// matchCIDR verifica se o IP pertence a algum dos blocos informados
+func matchCIDR(values []string, value string) bool {
+	return matchAddress(strings.Join(values, ","), value)
+}
+
+// matchNumber compara o atributo numericamente com o valor da restrição
+func matchNumber(constraint *Constraint, value string) bool {
+	actual, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return false
+	}
+	expected, err := strconv.ParseFloat(strings.TrimSpace(constraint.Values[0]), 64)
+	if err != nil {
+		return false
+	}
+
+	switch constraint.Operator {
+	case rules.SegmentOperatorEq:
+		return actual == expected
+	case rules.SegmentOperatorGt:
+		return actual > expected
+	case rules.SegmentOperatorGte:
+		return actual >= expected
+	case rules.SegmentOperatorLt:
+		return actual < expected
+	case rules.SegmentOperatorLte:
+		return actual <= expected
+	}
+	return false
+}
+
+// matchSemver compara o atributo como versão semântica com o valor da restrição
+func matchSemver(constraint *Constraint, value string) bool {
+	actual, err := semver.Parse(value)
+	if err != nil {
+		return false
+	}
+	expected, err := semver.Parse(constraint.Values[0])
+	if err != nil {
+		return false
+	}
+
+	comparison := actual.Compare(expected)
+	switch constraint.Operator {
+	case rules.SegmentOperatorSemverEq:
+		return comparison == 0
+	case rules.SegmentOperatorSemverGt:
+		return comparison > 0
+	case rules.SegmentOperatorSemverGte:
+		return comparison >= 0
+	case rules.SegmentOperatorSemverLt:
+		return comparison < 0
+	case rules.SegmentOperatorSemverLte:
+		return comparison <= 0
+	}
+	return false
+}
+
+// containsItem verifica se o valor está na lista, ignorando espaços nas extremidades dos itens
+func containsItem(values []string, value string) bool {
+	for _, item := range values {
+		if strings.TrimSpace(item) == value {
+			return true
+		}
+	}
+	return false
+}
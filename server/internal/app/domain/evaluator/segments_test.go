@@ -0,0 +1,67 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+func TestMatchConstraint(t *testing.T) {
+	ctx := &Context{
+		UserID: "u1",
+		IP:     "10.1.2.3",
+		Attributes: map[string]string{
+			"age":         "21",
+			"app_version": "2.1.0-rc.2",
+			"ipv6":        "2001:db8::1",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		constraint *Constraint
+		expected   bool
+	}{
+		{"in", &Constraint{Attribute: "user_id", Operator: rules.SegmentOperatorIn, Values: []string{"u0", "u1"}}, true},
+		{"in without match", &Constraint{Attribute: "user_id", Operator: rules.SegmentOperatorIn, Values: []string{"u2"}}, false},
+		{"not_in", &Constraint{Attribute: "user_id", Operator: rules.SegmentOperatorNotIn, Values: []string{"u2"}}, true},
+		{"not_in missing attribute", &Constraint{Attribute: "plan", Operator: rules.SegmentOperatorNotIn, Values: []string{"free"}}, true},
+		{"in missing attribute", &Constraint{Attribute: "plan", Operator: rules.SegmentOperatorIn, Values: []string{"free"}}, false},
+		{"cidr ipv4", &Constraint{Attribute: "ip", Operator: rules.SegmentOperatorCIDR, Values: []string{"192.168.0.0/16", "10.0.0.0/8"}}, true},
+		{"cidr ipv4 outside", &Constraint{Attribute: "ip", Operator: rules.SegmentOperatorCIDR, Values: []string{"10.2.0.0/16"}}, false},
+		{"cidr ipv6", &Constraint{Attribute: "ipv6", Operator: rules.SegmentOperatorCIDR, Values: []string{"2001:db8::/32"}}, true},
+		{"gte", &Constraint{Attribute: "age", Operator: rules.SegmentOperatorGte, Values: []string{"21"}}, true},
+		{"lt", &Constraint{Attribute: "age", Operator: rules.SegmentOperatorLt, Values: []string{"18"}}, false},
+		{"semver prerelease below release", &Constraint{Attribute: "app_version", Operator: rules.SegmentOperatorSemverLt, Values: []string{"2.1.0"}}, true},
+		{"semver prerelease ordering", &Constraint{Attribute: "app_version", Operator: rules.SegmentOperatorSemverGt, Values: []string{"2.1.0-rc.1"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchConstraint(tt.constraint, ctx); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEvaluate_SegmentRule(t *testing.T) {
+	beta := &Segment{ID: "beta", Name: "beta", Constraints: []*Constraint{
+		{Attribute: "user_id", Operator: rules.SegmentOperatorIn, Values: []string{"tester-1", "tester-2"}},
+	}}
+	toggle := &Toggle{Path: "checkout", Enabled: true}
+	toggle.Rules = []*Rule{{Conditions: []*Condition{{Type: rules.TypeSegment, Value: beta.ID}}}}
+
+	segments := map[string]*Segment{beta.ID: beta}
+
+	if result := Evaluate(toggle, &Context{UserID: "tester-2", Segments: segments}); !result.Enabled {
+		t.Errorf("Expected segment member to be enabled, got %+v", result)
+	}
+	if result := Evaluate(toggle, &Context{UserID: "someone", Segments: segments}); result.Enabled {
+		t.Errorf("Expected non-member to be disabled, got %+v", result)
+	}
+	// Um segmento que não foi carregado nunca é satisfeito
+	if result := Evaluate(toggle, &Context{UserID: "tester-2"}); result.Enabled {
+		t.Errorf("Expected unknown segment not to match, got %+v", result)
+	}
+}
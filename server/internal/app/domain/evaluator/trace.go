@@ -0,0 +1,139 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// Trace é o rastro completo de uma avaliação: o estado dos ancestrais, os pré-requisitos,
+// cada regra e condição avaliada e os buckets usados nas distribuições por porcentagem.
+// Ao contrário da avaliação normal, todas as regras e condições são avaliadas, mas o
+// resultado é sempre o mesmo retornado por Evaluate.
+type Trace struct {
+	Result        *Result              `json:"result"`
+	Context       *Context             `json:"context"`
+	EvaluatedAt   time.Time            `json:"evaluated_at"`
+	Enabled       bool                 `json:"enabled"`     // Estado do próprio toggle, sem considerar a hierarquia
+	KillSwitch    bool                 `json:"kill_switch"` // Kill switch da aplicação no momento da avaliação
+	Ancestors     []*AncestorTrace     `json:"ancestors"`
+	Prerequisites []*PrerequisiteTrace `json:"prerequisites"`
+	Rules         []*RuleTrace         `json:"rules"`
+	Variant       *VariantTrace        `json:"variant,omitempty"`
+}
+
+// AncestorTrace é o estado de um toggle ancestral, da raiz até o pai.
+// RuleMatched indica se alguma regra do ancestral foi satisfeita e é omitido quando ele não tem regras.
+type AncestorTrace struct {
+	Path        string `json:"path"`
+	Enabled     bool   `json:"enabled"`
+	RuleMatched *bool  `json:"rule_matched,omitempty"`
+}
+
+// PrerequisiteTrace é a avaliação de um pré-requisito do toggle ou de um dos seus ancestrais
+type PrerequisiteTrace struct {
+	DeclaredBy string `json:"declared_by"` // Caminho do toggle que declarou o pré-requisito
+	Path       string `json:"path,omitempty"`
+	Required   bool   `json:"required"`
+	Actual     bool   `json:"actual"`
+	Reason     Reason `json:"reason,omitempty"`
+	Met        bool   `json:"met"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// RuleTrace é a avaliação de uma regra; Selected indica a primeira regra satisfeita, que define o resultado
+type RuleTrace struct {
+	Position   int               `json:"position"`
+	Variant    string            `json:"variant,omitempty"`
+	Matched    bool              `json:"matched"`
+	Selected   bool              `json:"selected"`
+	Conditions []*ConditionTrace `json:"conditions"`
+}
+
+// ConditionTrace é a avaliação de uma condição e o motivo do resultado
+type ConditionTrace struct {
+	Type      rules.Type      `json:"type"`
+	Value     string          `json:"value"`
+	Config    json.RawMessage `json:"config,omitempty"`
+	Matched   bool            `json:"matched"`
+	Detail    string          `json:"detail,omitempty"`
+	Bucket    *int            `json:"bucket,omitempty"`    // Bucket do contexto nas condições por porcentagem
+	Threshold *float64        `json:"threshold,omitempty"` // Buckets abaixo deste valor satisfazem a condição
+}
+
+// VariantTrace é a distribuição usada para escolher a variante pelos pesos
+type VariantTrace struct {
+	Key    string `json:"key"`
+	Bucket *int   `json:"bucket,omitempty"` // Posição da chave entre 0 e o peso total das variantes
+	Detail string `json:"detail,omitempty"` // Motivo de nenhuma variante ter sido escolhida
+}
+
+// newConditionTrace cria o rastro vazio de uma condição
+func newConditionTrace(condition *Condition) *ConditionTrace {
+	return &ConditionTrace{Type: condition.Type, Value: condition.Value, Config: condition.Config}
+}
+
+// explain registra o motivo do resultado; não faz nada fora do explain
+func (t *ConditionTrace) explain(format string, args ...interface{}) {
+	if t != nil {
+		t.Detail = fmt.Sprintf(format, args...)
+	}
+}
+
+// bucket registra o bucket e o limite de uma condição por porcentagem
+func (t *ConditionTrace) bucket(bucket int, threshold float64, format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.Bucket = &bucket
+	t.Threshold = &threshold
+	subject := fmt.Sprintf(format, args...)
+	if float64(bucket) < threshold {
+		t.explain("%s is in bucket %d, below %g", subject, bucket, threshold)
+	} else {
+		t.explain("%s is in bucket %d, not below %g", subject, bucket, threshold)
+	}
+}
+
+// membership registra se o valor do atributo está na lista da condição e retorna o resultado
+func (t *ConditionTrace) membership(matched bool, attribute, value string) bool {
+	if matched {
+		t.explain("%s %q is in the list", attribute, value)
+	} else {
+		t.explain("%s %q is not in the list", attribute, value)
+	}
+	return matched
+}
+
+// Explain avalia o toggle como Evaluate e retorna o rastro completo da avaliação
+func Explain(toggle *Toggle, ctx *Context) *Trace {
+	if ctx == nil {
+		ctx = &Context{}
+	}
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+
+	trace := &Trace{
+		Context:       ctx,
+		EvaluatedAt:   ctx.Now,
+		Enabled:       toggle.Enabled,
+		KillSwitch:    ctx.KillSwitch,
+		Ancestors:     make([]*AncestorTrace, 0),
+		Prerequisites: make([]*PrerequisiteTrace, 0),
+		Rules:         make([]*RuleTrace, 0),
+	}
+	for parent := toggle.Parent; parent != nil; parent = parent.Parent {
+		ancestor := &AncestorTrace{Path: parent.Path, Enabled: parent.Enabled}
+		if len(parent.Rules) > 0 {
+			matched := matchingRule(parent, ctx, nil) != nil
+			ancestor.RuleMatched = &matched
+		}
+		trace.Ancestors = append([]*AncestorTrace{ancestor}, trace.Ancestors...)
+	}
+
+	trace.Result = evaluate(toggle, ctx, trace)
+	return trace
+}
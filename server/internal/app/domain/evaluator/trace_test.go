@@ -0,0 +1,151 @@
+package evaluator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+func TestExplain_MatchesEvaluate(t *testing.T) {
+	toggle := newVariantToggle()
+	setRules(toggle, []*Rule{
+		{Conditions: []*Condition{
+			{Type: rules.TypeCountry, Value: "BR"},
+			{Type: rules.TypePercentage, Value: "50"},
+		}},
+		{Conditions: []*Condition{
+			{Type: rules.TypeUserID, Value: "admin"},
+		}, Variant: "treatment"},
+	})
+
+	for i := 0; i < 200; i++ {
+		ctx := Context{UserID: fmt.Sprintf("user-%d", i), Country: []string{"BR", "US"}[i%2]}
+		if i%7 == 0 {
+			ctx.UserID = "admin"
+		}
+		explainCtx := ctx
+		trace := Explain(toggle, &explainCtx)
+		if expected := Evaluate(toggle, &ctx); !reflect.DeepEqual(trace.Result, expected) {
+			t.Fatalf("Expected explain to return %+v, got %+v", expected, trace.Result)
+		}
+	}
+}
+
+func TestExplain_Trace(t *testing.T) {
+	root := &Toggle{Path: "shop", Enabled: true}
+	checkout := &Toggle{Path: "shop.checkout", Enabled: true, Parent: root}
+	flag := &Toggle{Path: "flag", Enabled: true}
+	toggle := newVariantToggle()
+	toggle.Path = "shop.checkout.button"
+	toggle.Parent = checkout
+	toggle.Prerequisites = []*Prerequisite{
+		{ID: "flag", Enabled: true, Toggle: flag},
+	}
+	setRules(toggle, []*Rule{
+		{Conditions: []*Condition{
+			{Type: rules.TypeUserID, Value: "admin"},
+			{Type: rules.TypePercentage, Value: "100"},
+		}},
+		{Conditions: []*Condition{
+			{Type: rules.TypePercentage, Value: "100"},
+		}, Variant: "treatment"},
+		{Conditions: []*Condition{
+			{Type: rules.TypeCountry, Value: "BR"},
+		}},
+	})
+
+	trace := Explain(toggle, &Context{UserID: "guest", Country: "BR"})
+
+	if trace.Result.Reason != ReasonRuleMatch || trace.Result.Variant == nil || trace.Result.Variant.Name != "treatment" {
+		t.Fatalf("Unexpected result %+v", trace.Result)
+	}
+	if len(trace.Ancestors) != 2 || trace.Ancestors[0].Path != "shop" || trace.Ancestors[1].Path != "shop.checkout" {
+		t.Errorf("Expected ancestors ordered from the root, got %+v", trace.Ancestors)
+	}
+	if len(trace.Prerequisites) != 1 || !trace.Prerequisites[0].Met || trace.Prerequisites[0].Path != "flag" {
+		t.Errorf("Unexpected prerequisites %+v", trace.Prerequisites)
+	}
+
+	// Todas as regras são avaliadas, mas só a primeira satisfeita é selecionada
+	if len(trace.Rules) != 3 {
+		t.Fatalf("Expected every rule in the trace, got %d", len(trace.Rules))
+	}
+	first, second, third := trace.Rules[0], trace.Rules[1], trace.Rules[2]
+	if first.Matched || len(first.Conditions) != 2 || first.Conditions[0].Matched || !first.Conditions[1].Matched {
+		t.Errorf("Expected every condition of the first rule to be explained, got %+v", first.Conditions)
+	}
+	if first.Conditions[0].Detail != `user_id "guest" is not in the list` {
+		t.Errorf("Unexpected detail %q", first.Conditions[0].Detail)
+	}
+	if !second.Matched || !second.Selected || second.Variant != "treatment" {
+		t.Errorf("Expected the second rule to be selected, got %+v", second)
+	}
+	if !third.Matched || third.Selected {
+		t.Errorf("Expected the third rule to match without being selected, got %+v", third)
+	}
+
+	bucket := second.Conditions[0].Bucket
+	if bucket == nil || *bucket != Bucket(toggle.Path, "guest") || *second.Conditions[0].Threshold != BucketCount {
+		t.Errorf("Expected the percentage bucket in the trace, got %+v", second.Conditions[0])
+	}
+	if trace.Variant != nil {
+		t.Errorf("Expected no weighted distribution when the rule selects the variant, got %+v", trace.Variant)
+	}
+}
+
+func TestExplain_DisabledStillExplainsRules(t *testing.T) {
+	parent := &Toggle{Path: "shop", Enabled: false}
+	toggle := newVariantToggle()
+	toggle.Parent = parent
+	setRules(toggle, []*Rule{
+		{Conditions: []*Condition{{Type: rules.TypeUserID, Value: "u1"}}},
+	})
+
+	trace := Explain(toggle, &Context{UserID: "u1"})
+	if trace.Result.Enabled || trace.Result.Reason != ReasonParentDisabled {
+		t.Fatalf("Unexpected result %+v", trace.Result)
+	}
+	if !trace.Enabled || len(trace.Ancestors) != 1 || trace.Ancestors[0].Enabled {
+		t.Errorf("Expected the disabled ancestor in the trace, got %+v", trace.Ancestors)
+	}
+	if len(trace.Rules) != 1 || !trace.Rules[0].Matched {
+		t.Errorf("Expected the rules to be explained, got %+v", trace.Rules)
+	}
+}
+
+func TestExplain_AncestorRule(t *testing.T) {
+	parent := &Toggle{Path: "shop", Enabled: true}
+	setRules(parent, []*Rule{
+		{Conditions: []*Condition{{Type: rules.TypeCountry, Value: "PT"}}},
+	})
+	toggle := newVariantToggle()
+	toggle.Parent = parent
+
+	trace := Explain(toggle, &Context{UserID: "u1", Country: "BR"})
+	if trace.Result.Enabled || trace.Result.Reason != ReasonParentNoMatch {
+		t.Fatalf("Unexpected result %+v", trace.Result)
+	}
+	if len(trace.Ancestors) != 1 || trace.Ancestors[0].RuleMatched == nil || *trace.Ancestors[0].RuleMatched {
+		t.Errorf("Expected the ancestor's unmatched rule in the trace, got %+v", trace.Ancestors)
+	}
+
+	setRules(parent, nil)
+	if trace := Explain(toggle, &Context{UserID: "u1"}); trace.Ancestors[0].RuleMatched != nil {
+		t.Errorf("Expected no rule result for an ancestor without rules, got %+v", trace.Ancestors[0])
+	}
+}
+
+func TestExplain_VariantBucket(t *testing.T) {
+	toggle := newVariantToggle()
+
+	trace := Explain(toggle, &Context{Key: "user-1"})
+	expected := Bucket(toggle.Path+":variant", "user-1") * rules.VariantWeightTotal / BucketCount
+	if trace.Variant == nil || trace.Variant.Key != "user-1" || trace.Variant.Bucket == nil || *trace.Variant.Bucket != expected {
+		t.Fatalf("Unexpected variant trace %+v", trace.Variant)
+	}
+	if trace.Result.Variant.Name != toggle.Variants.Select(expected).Name {
+		t.Errorf("Expected the variant of the traced bucket, got %s", trace.Result.Variant.Name)
+	}
+}
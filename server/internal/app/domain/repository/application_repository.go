@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/manorfm/totoogle/internal/app/domain/entity"
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
 
 // ApplicationRepository define os contratos para operações com aplicações
 type ApplicationRepository interface {
@@ -8,7 +12,11 @@ type ApplicationRepository interface {
 	GetByID(id string) (*entity.Application, error)
 	GetAll() ([]*entity.Application, error)
 	GetAllWithToggleCounts() ([]*entity.ApplicationWithCounts, error)
+	ListWithToggleCounts(page *entity.PageRequest, ids []string) (*entity.Page[*entity.ApplicationWithCounts], error)
 	Update(app *entity.Application) error
 	Delete(id string) error
 	Exists(id string) (bool, error)
+	GetTrash() ([]*entity.Application, error)
+	Undelete(id string, toggleIDs []string) error
+	Purge(before time.Time) (int64, error)
 }
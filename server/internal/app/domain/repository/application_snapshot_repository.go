@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/manorfm/totoogle/internal/app/domain/entity"
+
+// ApplicationSnapshotRepository define os contratos para operações com snapshots de aplicações
+type ApplicationSnapshotRepository interface {
+	Create(snapshot *entity.ApplicationSnapshot) error
+	GetByID(id string) (*entity.ApplicationSnapshot, error)
+	GetByAppID(appID string) ([]*entity.ApplicationSnapshot, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/manorfm/totoogle/internal/app/domain/entity"
+
+// AuditEventRepository define os contratos para operações com eventos de auditoria.
+// Eventos são imutáveis: não há atualização nem remoção.
+type AuditEventRepository interface {
+	Create(event *entity.AuditEvent) error
+	GetByAppID(appID string) ([]*entity.AuditEvent, error)
+}
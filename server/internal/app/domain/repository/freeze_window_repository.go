@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// FreezeWindowRepository define os contratos para operações com janelas de congelamento
+type FreezeWindowRepository interface {
+	Create(window *entity.FreezeWindow) error
+	GetByID(id string) (*entity.FreezeWindow, error)
+	GetByAppID(appID string) ([]*entity.FreezeWindow, error)
+	GetGlobal() ([]*entity.FreezeWindow, error)
+	GetActive(appID string, at time.Time) ([]*entity.FreezeWindow, error)
+	Delete(id string) error
+}
@@ -10,4 +10,5 @@ type SecretKeyRepository interface {
 	GetAll() ([]*entity.SecretKey, error)
 	Update(secretKey *entity.SecretKey) error
 	Delete(id string) error
+	RecordUsage(usage *entity.SecretKeyUsage) error
 }
\ No newline at end of file
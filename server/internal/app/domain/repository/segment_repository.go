@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/manorfm/totoogle/internal/app/domain/entity"
+
+// SegmentRepository define os contratos para operações com segmentos
+type SegmentRepository interface {
+	Create(segment *entity.Segment) error
+	GetByID(id string) (*entity.Segment, error)
+	GetByIDs(ids []string) ([]*entity.Segment, error)
+	GetByName(appID *string, name string) (*entity.Segment, error)
+	GetByAppID(appID string) ([]*entity.Segment, error)
+	GetGlobal() ([]*entity.Segment, error)
+	GetAvailableForApp(appID string) ([]*entity.Segment, error)
+	Update(segment *entity.Segment) error
+	Delete(id string) error
+	GetReferencingToggles(id string) ([]*entity.Toggle, error)
+}
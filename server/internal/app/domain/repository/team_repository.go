@@ -16,6 +16,7 @@ type TeamRepository interface {
 	RemoveUserFromTeam(teamID, userID string) error
 	GetUsersByTeamID(teamID string) ([]*entity.User, error)
 	GetTeamsByUserID(userID string) ([]*entity.Team, error)
+	ListUsersByTeamID(teamID string, page *entity.PageRequest) (*entity.Page[*entity.User], error)
 
 	// Operações relacionadas a aplicações
 	AddApplicationToTeam(teamID, applicationID string, permission entity.TeamPermissionLevel) error
@@ -31,4 +32,5 @@ type TeamRepository interface {
 	// Consultas com contagem
 	GetTeamsWithCounts() ([]*entity.TeamWithCounts, error)
 	GetTeamWithCounts(id string) (*entity.TeamWithCounts, error)
+	ListTeamsWithCounts(page *entity.PageRequest) (*entity.Page[*entity.TeamWithCounts], error)
 }
\ No newline at end of file
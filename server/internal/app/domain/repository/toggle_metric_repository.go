@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// ToggleMetricRepository define os contratos para operações com métricas de avaliação
+type ToggleMetricRepository interface {
+	Increment(metrics []*entity.ToggleMetric) error
+	GetByToggleID(toggleID string, from, to time.Time) ([]*entity.ToggleMetric, error)
+	GetEvaluatedToggleIDs(appID string, since time.Time) ([]string, error)
+}
@@ -1,17 +1,35 @@
 package repository
 
-import "github.com/manorfm/totoogle/internal/app/domain/entity"
+import (
+	"time"
 
-// ToggleRepository define os contratos para operações com toggles
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// ToggleRepository define os contratos para operações com toggles.
+// As alterações recebem as revisões que as descrevem e as gravam na mesma transação,
+// para que nenhuma alteração fique sem revisão.
 type ToggleRepository interface {
-	Create(toggle *entity.Toggle) error
+	Create(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error
 	GetByID(id string) (*entity.Toggle, error)
 	GetByPath(path string, appID string) (*entity.Toggle, error)
 	GetByAppID(appID string) ([]*entity.Toggle, error)
 	GetHierarchyByAppID(appID string) ([]*entity.Toggle, error)
-	Update(toggle *entity.Toggle) error
-	Delete(id string) error
-	DeleteByPath(path string, appID string) error
+	GetByAppIDWithFilter(appID string, filter *entity.ToggleFilter) ([]*entity.Toggle, error)
+	ListByAppID(appID string, page *entity.PageRequest) (*entity.Page[*entity.Toggle], error)
+	Update(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error
+	UpdateWithRules(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error
+	UpdatePrerequisites(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error
+	Restore(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error
+	RestoreApplication(appID string, toggles []*entity.Toggle, revisions ...*entity.ToggleRevision) error
+	ApplyBatch(created []*entity.Toggle, updated []*entity.Toggle, deleted []*entity.Toggle, revisions ...*entity.ToggleRevision) error
+	GetDependents(toggleIDs []string) ([]*entity.Toggle, error)
+	Delete(id string, revisions ...*entity.ToggleRevision) error
+	DeleteByPath(path string, appID string, revisions ...*entity.ToggleRevision) error
 	Exists(path string, appID string) (bool, error)
 	GetChildren(parentID string) ([]*entity.Toggle, error)
+	GetTrashByAppID(appID string) ([]*entity.Toggle, error)
+	Undelete(ids []string, revisions ...*entity.ToggleRevision) error
+	Purge(before time.Time) (int64, error)
+	Search(query *entity.ToggleSearchQuery) ([]*entity.ToggleSearchResult, int64, error)
 }
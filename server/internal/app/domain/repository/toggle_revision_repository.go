@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/manorfm/totoogle/internal/app/domain/entity"
+
+// ToggleRevisionRepository define os contratos para operações com revisões de toggles.
+// Revisões são imutáveis: não há atualização nem remoção.
+type ToggleRevisionRepository interface {
+	Create(revisions ...*entity.ToggleRevision) error
+	GetByToggleID(toggleID string) ([]*entity.ToggleRevision, error)
+	GetRevision(toggleID string, revision int) (*entity.ToggleRevision, error)
+}
@@ -7,6 +7,7 @@ type UserRepository interface {
 	GetByID(id string) (*entity.User, error)
 	GetByUsername(username string) (*entity.User, error)
 	GetAll() ([]*entity.User, error)
+	List(page *entity.PageRequest) (*entity.Page[*entity.User], error)
 	Update(user *entity.User) error
 	Delete(id string) error
 	GetApplicationsByUserID(userID string) ([]*entity.Application, error)
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Padrões e limites das regras canary
+const (
+	DefaultCanaryAttribute = "instance_id"
+	MaxCanaryInstances     = 1000
+)
+
+// CanaryStrategy define como uma regra canary escolhe as instâncias
+type CanaryStrategy string
+
+const (
+	CanaryStrategyInstances  CanaryStrategy = "instances"  // O valor lista as instâncias canário
+	CanaryStrategyPercentage CanaryStrategy = "percentage" // O valor é a porcentagem de instâncias canário
+)
+
+// CanaryRuleConfig é a configuração opcional de uma regra canary. Sem configuração,
+// o valor da regra é a lista de instâncias comparada com o atributo instance_id.
+type CanaryRuleConfig struct {
+	Attribute string         `json:"attribute,omitempty"` // Atributo da instância no contexto: instance_id, host, deployment...
+	Strategy  CanaryStrategy `json:"strategy,omitempty"`
+}
+
+// CanaryRule é uma regra canary já validada. O alvo são instâncias (pods, hosts, deployments),
+// e não requisições: todas as requisições de uma instância canário recebem o mesmo resultado.
+type CanaryRule struct {
+	Attribute  string
+	Strategy   CanaryStrategy
+	Instances  []string
+	Percentage float64
+}
+
+// ParseCanaryRule lê e valida o valor e a configuração de uma regra canary
+func ParseCanaryRule(value string, config json.RawMessage) (*CanaryRule, error) {
+	cfg := CanaryRuleConfig{}
+	if len(bytes.TrimSpace(config)) > 0 && string(bytes.TrimSpace(config)) != "null" {
+		decoder := json.NewDecoder(bytes.NewReader(config))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("config inválido: %v", err)
+		}
+	}
+
+	rule := &CanaryRule{
+		Attribute: cfg.Attribute,
+		Strategy:  cfg.Strategy,
+	}
+	if rule.Attribute == "" {
+		rule.Attribute = DefaultCanaryAttribute
+	}
+	if rule.Strategy == "" {
+		rule.Strategy = CanaryStrategyInstances
+	}
+	if !IsAttributeName(rule.Attribute) {
+		return nil, fmt.Errorf("attribute só pode conter letras, números, hífens, sublinhados e pontos")
+	}
+
+	switch rule.Strategy {
+	case CanaryStrategyInstances:
+		for _, instance := range strings.Split(value, ",") {
+			if instance = strings.TrimSpace(instance); instance != "" {
+				rule.Instances = append(rule.Instances, instance)
+			}
+		}
+		if len(rule.Instances) == 0 {
+			return nil, fmt.Errorf("valor do canary é obrigatório")
+		}
+		if len(rule.Instances) > MaxCanaryInstances {
+			return nil, fmt.Errorf("a regra canary aceita no máximo %d instâncias", MaxCanaryInstances)
+		}
+	case CanaryStrategyPercentage:
+		percentage, err := ParseRuleNumber(value)
+		if err != nil || percentage < 0 || percentage > 100 {
+			return nil, fmt.Errorf("porcentagem de instâncias deve ser um número entre 0 e 100")
+		}
+		rule.Percentage = percentage
+	default:
+		return nil, fmt.Errorf("estratégia de canary inválida: %s (use instances ou percentage)", rule.Strategy)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseCanaryRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		config   string
+		expected CanaryRule
+		errorMsg string
+	}{
+		{
+			name:     "defaults to instance list",
+			value:    "pod-a, pod-b",
+			expected: CanaryRule{Attribute: "instance_id", Strategy: CanaryStrategyInstances, Instances: []string{"pod-a", "pod-b"}},
+		},
+		{
+			name:     "host list",
+			value:    "web-01",
+			config:   `{"attribute": "host"}`,
+			expected: CanaryRule{Attribute: "host", Strategy: CanaryStrategyInstances, Instances: []string{"web-01"}},
+		},
+		{
+			name:     "percentage of instances",
+			value:    "12.5",
+			config:   `{"attribute": "deployment", "strategy": "percentage"}`,
+			expected: CanaryRule{Attribute: "deployment", Strategy: CanaryStrategyPercentage, Percentage: 12.5},
+		},
+		{name: "empty list", value: " , ", errorMsg: "valor do canary é obrigatório"},
+		{name: "percentage above 100", value: "101", config: `{"strategy": "percentage"}`, errorMsg: "porcentagem de instâncias deve ser um número entre 0 e 100"},
+		{name: "percentage not a number", value: "v2", config: `{"strategy": "percentage"}`, errorMsg: "porcentagem de instâncias deve ser um número entre 0 e 100"},
+		{name: "unknown strategy", value: "pod-a", config: `{"strategy": "requests"}`, errorMsg: "estratégia de canary inválida: requests (use instances ou percentage)"},
+		{name: "invalid attribute", value: "pod-a", config: `{"attribute": "instance id"}`, errorMsg: "attribute só pode conter letras, números, hífens, sublinhados e pontos"},
+		{name: "unknown config field", value: "pod-a", config: `{"pods": 2}`, errorMsg: "config inválido: json: unknown field \"pods\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseCanaryRule(tt.value, json.RawMessage(tt.config))
+			if tt.errorMsg != "" {
+				if err == nil || err.Error() != tt.errorMsg {
+					t.Errorf("Expected error %q, got %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if rule.Attribute != tt.expected.Attribute || rule.Strategy != tt.expected.Strategy || rule.Percentage != tt.expected.Percentage {
+				t.Errorf("Expected %+v, got %+v", tt.expected, rule)
+			}
+			if len(rule.Instances) != len(tt.expected.Instances) {
+				t.Errorf("Expected instances %v, got %v", tt.expected.Instances, rule.Instances)
+			}
+		})
+	}
+}
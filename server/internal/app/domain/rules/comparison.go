@@ -0,0 +1,119 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/semver"
+)
+
+// ComparisonOperator define como as regras semver e number comparam o atributo do contexto
+type ComparisonOperator string
+
+const (
+	ComparisonOperatorEq      ComparisonOperator = "eq"
+	ComparisonOperatorGt      ComparisonOperator = "gt"
+	ComparisonOperatorGte     ComparisonOperator = "gte"
+	ComparisonOperatorLt      ComparisonOperator = "lt"
+	ComparisonOperatorLte     ComparisonOperator = "lte"
+	ComparisonOperatorRange   ComparisonOperator = "range"   // semver: min <= versão < max
+	ComparisonOperatorBetween ComparisonOperator = "between" // number: min <= número <= max
+)
+
+// ComparisonRuleConfig é a configuração das regras semver e number:
+// o atributo do contexto comparado e o operador. O valor da regra traz o operando,
+// ou "min,max" para range e between.
+type ComparisonRuleConfig struct {
+	Attribute string             `json:"attribute"`
+	Operator  ComparisonOperator `json:"operator"`
+}
+
+// ComparisonRule é uma regra semver ou number já validada
+type ComparisonRule struct {
+	Attribute string
+	Operator  ComparisonOperator
+	Operands  []string
+}
+
+// ParseComparisonRule lê e valida de forma estrita uma regra do tipo semver ou number
+func ParseComparisonRule(ruleType Type, value string, config json.RawMessage) (*ComparisonRule, error) {
+	if len(bytes.TrimSpace(config)) == 0 {
+		return nil, fmt.Errorf("config com attribute e operator é obrigatório")
+	}
+
+	var cfg ComparisonRuleConfig
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("config inválido: %v", err)
+	}
+	if !IsAttributeName(cfg.Attribute) {
+		return nil, fmt.Errorf("attribute é obrigatório e só pode conter letras, números, hífens, sublinhados e pontos")
+	}
+
+	var interval ComparisonOperator
+	switch ruleType {
+	case TypeSemver:
+		interval = ComparisonOperatorRange
+	case TypeNumber:
+		interval = ComparisonOperatorBetween
+	default:
+		return nil, fmt.Errorf("tipo de regra de comparação inválido: %s", ruleType)
+	}
+
+	switch cfg.Operator {
+	case ComparisonOperatorEq, ComparisonOperatorGt, ComparisonOperatorGte, ComparisonOperatorLt, ComparisonOperatorLte, interval:
+	default:
+		return nil, fmt.Errorf("operador inválido para %s: %s (use eq, gt, gte, lt, lte ou %s)", ruleType, cfg.Operator, interval)
+	}
+
+	operands := strings.Split(value, ",")
+	for i := range operands {
+		operands[i] = strings.TrimSpace(operands[i])
+	}
+	expected := 1
+	if cfg.Operator == interval {
+		expected = 2
+	}
+	if len(operands) != expected || operands[0] == "" {
+		if expected == 2 {
+			return nil, fmt.Errorf("o operador %s exige o valor no formato min,max", cfg.Operator)
+		}
+		return nil, fmt.Errorf("o operador %s exige um único valor", cfg.Operator)
+	}
+
+	switch ruleType {
+	case TypeSemver:
+		versions := make([]*semver.Version, len(operands))
+		for i, operand := range operands {
+			version, err := semver.Parse(operand)
+			if err != nil {
+				return nil, fmt.Errorf("versão semântica inválida '%s'", operand)
+			}
+			versions[i] = version
+		}
+		if len(versions) == 2 && versions[0].Compare(versions[1]) >= 0 {
+			return nil, fmt.Errorf("o mínimo do range precisa ser menor que o máximo")
+		}
+	case TypeNumber:
+		numbers := make([]float64, len(operands))
+		for i, operand := range operands {
+			number, err := ParseRuleNumber(operand)
+			if err != nil {
+				return nil, fmt.Errorf("número inválido '%s'", operand)
+			}
+			numbers[i] = number
+		}
+		if len(numbers) == 2 && numbers[0] > numbers[1] {
+			return nil, fmt.Errorf("o mínimo do between não pode ser maior que o máximo")
+		}
+	}
+
+	return &ComparisonRule{
+		Attribute: cfg.Attribute,
+		Operator:  cfg.Operator,
+		Operands:  operands,
+	}, nil
+}
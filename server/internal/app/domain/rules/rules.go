@@ -0,0 +1,76 @@
+// Package rules define os tipos de regra de ativação, os operadores dos segmentos e as
+// variantes, com a leitura e validação dos seus valores. Não depende do banco de dados,
+// para que o avaliador e o SDK possam usá-lo sem as entidades persistidas.
+package rules
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Type define os tipos de condição de uma regra de ativação
+type Type string
+
+const (
+	TypePercentage Type = "percentage"
+	TypeParameter  Type = "parameter"
+	TypeUserID     Type = "user_id"
+	TypeIP         Type = "ip"
+	TypeCountry    Type = "country"
+	TypeTime       Type = "time"
+	TypeCanary     Type = "canary"
+	TypeSegment    Type = "segment"
+	TypeSemver     Type = "semver"
+	TypeNumber     Type = "number"
+)
+
+var attributeNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-\.]{1,100}$`)
+
+// IsAttributeName verifica se o nome pode identificar um atributo do contexto de avaliação
+func IsAttributeName(name string) bool {
+	return attributeNameRegex.MatchString(name)
+}
+
+// ParseRuleNumber lê um número finito de uma regra ou do contexto de avaliação
+func ParseRuleNumber(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("number must be finite")
+	}
+	return number, nil
+}
+
+// ParsePrefix lê um bloco CIDR ou um endereço IP isolado, tratado como um bloco de um único endereço
+func ParsePrefix(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+// ParseSegmentIDs lê a lista de IDs de segmentos separados por vírgula de uma regra do tipo segment
+func ParseSegmentIDs(value string) []string {
+	ids := make([]string, 0)
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
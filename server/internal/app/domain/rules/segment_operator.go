@@ -0,0 +1,47 @@
+package rules
+
+// SegmentOperator define como um atributo do contexto é comparado com os valores de uma restrição
+type SegmentOperator string
+
+const (
+	SegmentOperatorIn        SegmentOperator = "in"     // O atributo está na lista de valores
+	SegmentOperatorNotIn     SegmentOperator = "not_in" // O atributo não está na lista de valores
+	SegmentOperatorCIDR      SegmentOperator = "cidr"   // O IP do atributo pertence a um dos blocos CIDR
+	SegmentOperatorEq        SegmentOperator = "eq"     // Comparações numéricas com um único valor
+	SegmentOperatorGt        SegmentOperator = "gt"
+	SegmentOperatorGte       SegmentOperator = "gte"
+	SegmentOperatorLt        SegmentOperator = "lt"
+	SegmentOperatorLte       SegmentOperator = "lte"
+	SegmentOperatorSemverEq  SegmentOperator = "semver_eq" // Comparações de versão semântica com um único valor
+	SegmentOperatorSemverGt  SegmentOperator = "semver_gt"
+	SegmentOperatorSemverGte SegmentOperator = "semver_gte"
+	SegmentOperatorSemverLt  SegmentOperator = "semver_lt"
+	SegmentOperatorSemverLte SegmentOperator = "semver_lte"
+)
+
+// IsNumeric indica se o operador compara números
+func (o SegmentOperator) IsNumeric() bool {
+	switch o {
+	case SegmentOperatorEq, SegmentOperatorGt, SegmentOperatorGte, SegmentOperatorLt, SegmentOperatorLte:
+		return true
+	}
+	return false
+}
+
+// IsSemver indica se o operador compara versões semânticas
+func (o SegmentOperator) IsSemver() bool {
+	switch o {
+	case SegmentOperatorSemverEq, SegmentOperatorSemverGt, SegmentOperatorSemverGte, SegmentOperatorSemverLt, SegmentOperatorSemverLte:
+		return true
+	}
+	return false
+}
+
+// IsValid verifica se o operador é conhecido
+func (o SegmentOperator) IsValid() bool {
+	switch o {
+	case SegmentOperatorIn, SegmentOperatorNotIn, SegmentOperatorCIDR:
+		return true
+	}
+	return o.IsNumeric() || o.IsSemver()
+}
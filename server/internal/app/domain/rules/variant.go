@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// VariantWeightTotal é a soma dos pesos das variantes de um toggle
+const VariantWeightTotal = 100
+
+// VariantPayloadType define o tipo do valor retornado por uma variante
+type VariantPayloadType string
+
+const (
+	VariantPayloadString VariantPayloadType = "string"
+	VariantPayloadNumber VariantPayloadType = "number"
+	VariantPayloadJSON   VariantPayloadType = "json"
+)
+
+// IsValid verifica se o tipo de payload é conhecido
+func (p VariantPayloadType) IsValid() bool {
+	switch p {
+	case VariantPayloadString, VariantPayloadNumber, VariantPayloadJSON:
+		return true
+	}
+	return false
+}
+
+// Variant representa uma variante nomeada de um toggle com o valor retornado e o peso na distribuição
+type Variant struct {
+	Name        string             `json:"name"`
+	PayloadType VariantPayloadType `json:"payload_type"`
+	Payload     json.RawMessage    `json:"payload,omitempty"`
+	Weight      int                `json:"weight"`
+}
+
+// Variants representa as variantes de um toggle, persistidas como um array JSON
+type Variants []*Variant
+
+// Value serializa as variantes para o banco de dados
+func (v Variants) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]*Variant(v))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan desserializa as variantes lidas do banco de dados
+func (v *Variants) Scan(value interface{}) error {
+	var data []byte
+	switch val := value.(type) {
+	case nil:
+		*v = Variants{}
+		return nil
+	case string:
+		data = []byte(val)
+	case []byte:
+		data = val
+	default:
+		return fmt.Errorf("unsupported type for toggle variants: %T", value)
+	}
+
+	if len(data) == 0 {
+		*v = Variants{}
+		return nil
+	}
+
+	var variants []*Variant
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return err
+	}
+	*v = variants
+	return nil
+}
+
+// Find retorna a variante com o nome informado
+func (v Variants) Find(name string) *Variant {
+	for _, variant := range v {
+		if variant.Name == name {
+			return variant
+		}
+	}
+	return nil
+}
+
+// Select escolhe a variante correspondente ao bucket informado, entre 0 e VariantWeightTotal-1,
+// percorrendo os pesos acumulados na ordem em que as variantes foram definidas
+func (v Variants) Select(bucket int) *Variant {
+	cumulative := 0
+	for _, variant := range v {
+		cumulative += variant.Weight
+		if bucket < cumulative {
+			return variant
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Package semver implementa a leitura e a comparação de versões no formato Semantic Versioning 2.0.0
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version representa uma versão semântica. Os metadados de build são ignorados na comparação.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      string
+}
+
+// Parse lê uma versão no formato MAJOR.MINOR.PATCH[-prerelease][+build], aceitando um prefixo "v" opcional
+func Parse(value string) (*Version, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if raw == "" {
+		return nil, fmt.Errorf("invalid semantic version %q", value)
+	}
+
+	version := &Version{}
+	if core, build, ok := strings.Cut(raw, "+"); ok {
+		if !validIdentifiers(build, false) {
+			return nil, fmt.Errorf("invalid build metadata in version %q", value)
+		}
+		raw, version.Build = core, build
+	}
+	if core, prerelease, ok := strings.Cut(raw, "-"); ok {
+		if !validIdentifiers(prerelease, true) {
+			return nil, fmt.Errorf("invalid prerelease in version %q", value)
+		}
+		raw, version.Prerelease = core, strings.Split(prerelease, ".")
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid semantic version %q: expected MAJOR.MINOR.PATCH", value)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		if !isNumeric(part) || (len(part) > 1 && part[0] == '0') {
+			return nil, fmt.Errorf("invalid semantic version %q", value)
+		}
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid semantic version %q", value)
+		}
+		numbers[i] = number
+	}
+	version.Major, version.Minor, version.Patch = numbers[0], numbers[1], numbers[2]
+	return version, nil
+}
+
+// String retorna a versão no formato canônico, sem o prefixo "v"
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare retorna -1, 0 ou 1 quando v é menor, igual ou maior que other.
+// Uma versão de prerelease é menor que a versão normal correspondente (1.0.0-rc.1 < 1.0.0).
+func (v *Version) Compare(other *Version) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(other.Prerelease)))
+}
+
+// compareIdentifier compara identificadores de prerelease: numéricos por valor e sempre menores que os alfanuméricos
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		if len(a) != len(b) {
+			return compareUint(uint64(len(a)), uint64(len(b)))
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// validIdentifiers verifica os identificadores separados por ponto de um prerelease ou build
+func validIdentifiers(value string, rejectLeadingZero bool) bool {
+	if value == "" {
+		return false
+	}
+	for _, identifier := range strings.Split(value, ".") {
+		if identifier == "" {
+			return false
+		}
+		for _, r := range identifier {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+		if rejectLeadingZero && isNumeric(identifier) && len(identifier) > 1 && identifier[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	valid := map[string]string{
+		"1.2.3":              "1.2.3",
+		"v1.2.3":             "1.2.3",
+		"0.0.0":              "0.0.0",
+		"1.0.0-alpha.1":      "1.0.0-alpha.1",
+		"1.0.0-rc.1+build.5": "1.0.0-rc.1+build.5",
+		"10.20.30+20250101":  "10.20.30+20250101",
+		"1.0.0-x-y-z.--":     "1.0.0-x-y-z.--",
+	}
+	for input, expected := range valid {
+		version, err := Parse(input)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error %v", input, err)
+			continue
+		}
+		if version.String() != expected {
+			t.Errorf("Parse(%q) = %s, expected %s", input, version, expected)
+		}
+	}
+
+	invalid := []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.02.3", "1.2.3-", "1.2.3-01", "1.2.3-a..b", "1.2.3+", "a.b.c", "1.2.3-beta!", "-1.2.3"}
+	for _, input := range invalid {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
+
+func TestCompare_PrereleaseOrdering(t *testing.T) {
+	// Ordem definida pela especificação SemVer 2.0.0
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := Parse(ordered[i])
+		b, _ := Parse(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := Parse("1.0.0+build.1")
+	b, _ := Parse("1.0.0+build.2")
+	if a.Compare(b) != 0 {
+		t.Errorf("Expected build metadata to be ignored")
+	}
+}
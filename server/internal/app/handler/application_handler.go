@@ -53,7 +53,7 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 		return
 	}
 
-	app, err := h.appUseCase.CreateApplication(req.Name)
+	app, err := h.appUseCase.CreateApplication(req.Name, currentUser(c))
 	if err != nil {
 		appErr, ok := err.(*entity.AppError)
 		if ok {
@@ -72,7 +72,7 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 	err = h.teamUseCase.AddApplicationToTeam(req.TeamID, app.ID, entity.PermissionAdmin)
 	if err != nil {
 		// Se falhar ao associar ao team, remover a aplicação criada
-		h.appUseCase.DeleteApplication(app.ID)
+		h.appUseCase.DeleteApplication(app.ID, currentUser(c))
 		c.JSON(http.StatusBadRequest, entity.NewAppError(entity.ErrCodeValidation, "failed to associate application with team"))
 		return
 	}
@@ -121,11 +121,13 @@ func (h *ApplicationHandler) GetApplication(c *gin.Context) {
 
 	// Create response with application and teams
 	response := gin.H{
-		"id":         app.ID,
-		"name":       app.Name,
-		"created_at": app.CreatedAt,
-		"updated_at": app.UpdatedAt,
-		"teams":      teams,
+		"id":             app.ID,
+		"name":           app.Name,
+		"created_at":     app.CreatedAt,
+		"updated_at":     app.UpdatedAt,
+		"kill_switch":    app.KillSwitch,
+		"kill_switch_at": app.KillSwitchAt,
+		"teams":          teams,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -146,8 +148,21 @@ func (h *ApplicationHandler) GetAllApplications(c *gin.Context) {
 		return
 	}
 
+	// Paginação opcional por cursor (?limit=&cursor=&sort=&order=&name=)
+	page, pageErr := parsePageRequest(c, entity.ApplicationListFields)
+	if pageErr != nil {
+		c.JSON(http.StatusBadRequest, pageErr)
+		return
+	}
+
 	// Se for root, retorna todas as aplicações
 	if user.Role == entity.UserRoleRoot {
+		if page != nil {
+			result, err := h.appUseCase.ListApplicationsWithCounts(page, nil)
+			respondPage(c, result, err)
+			return
+		}
+
 		apps, err := h.appUseCase.GetAllApplicationsWithCounts()
 		if err != nil {
 			appErr, ok := err.(*entity.AppError)
@@ -188,6 +203,15 @@ func (h *ApplicationHandler) GetAllApplications(c *gin.Context) {
 		ids = append(ids, id)
 	}
 
+	if page != nil {
+		if ids == nil {
+			ids = []string{}
+		}
+		result, err := h.appUseCase.ListApplicationsWithCounts(page, ids)
+		respondPage(c, result, err)
+		return
+	}
+
 	// Obter aplicações com contagem
 	filteredApps, err := h.appUseCase.GetApplicationsWithCountsByIDs(ids)
 	if err != nil {
@@ -221,13 +245,15 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 		return
 	}
 
-	app, err := h.appUseCase.UpdateApplication(id, req.Name)
+	app, err := h.appUseCase.UpdateApplication(id, req.Name, currentUser(c))
 	if err != nil {
 		appErr, ok := err.(*entity.AppError)
 		if ok {
 			status := http.StatusBadRequest
 			if appErr.Code == entity.ErrCodeNotFound {
 				status = http.StatusNotFound
+			} else if appErr.Code == entity.ErrCodeManaged {
+				status = http.StatusConflict
 			}
 			c.JSON(status, appErr)
 			return
@@ -266,13 +292,15 @@ func (h *ApplicationHandler) DeleteApplication(c *gin.Context) {
 		return
 	}
 
-	err := h.appUseCase.DeleteApplication(id)
+	err := h.appUseCase.DeleteApplication(id, currentUser(c))
 	if err != nil {
 		appErr, ok := err.(*entity.AppError)
 		if ok {
 			status := http.StatusBadRequest
 			if appErr.Code == entity.ErrCodeNotFound {
 				status = http.StatusNotFound
+			} else if appErr.Code == entity.ErrCodeManaged {
+				status = http.StatusConflict
 			}
 			c.JSON(status, appErr)
 			return
@@ -285,3 +313,42 @@ func (h *ApplicationHandler) DeleteApplication(c *gin.Context) {
 		"message": "application deleted successfully",
 	})
 }
+
+// KillSwitchRequest representa a requisição para ligar ou desligar o kill switch da aplicação
+type KillSwitchRequest struct {
+	Active *bool `json:"active" binding:"required"`
+}
+
+// SetKillSwitch liga ou desliga o kill switch da aplicação, forçando todos os toggles a desligados
+// POST /applications/:id/kill-switch
+func (h *ApplicationHandler) SetKillSwitch(c *gin.Context) {
+	var req KillSwitchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("active", "Active is required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	app, err := h.appUseCase.SetKillSwitch(c.Param("id"), *req.Active, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+// GetAuditEvents lista os eventos de auditoria da aplicação
+// GET /applications/:id/audit
+func (h *ApplicationHandler) GetAuditEvents(c *gin.Context) {
+	events, err := h.appUseCase.GetAuditEvents(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+	})
+}
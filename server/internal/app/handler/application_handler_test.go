@@ -10,6 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/usecase"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func setupTestRouter() *gin.Engine {
@@ -17,6 +19,13 @@ func setupTestRouter() *gin.Engine {
 	return gin.New()
 }
 
+// setupTestDB cria uma base de dados em memória com todas as tabelas usadas pelos handlers
+func setupTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.ToggleRule{}, &entity.TogglePrerequisite{}, &entity.ToggleRevision{}, &entity.ApplicationSnapshot{}, &entity.AuditEvent{}, &entity.FreezeWindow{}, &entity.Segment{}, &entity.User{}, &entity.SecretKey{}, &entity.Team{}, &entity.TeamUser{}, &entity.TeamApplication{}, &entity.ToggleMetric{})
+	return db
+}
+
 func TestApplicationHandler_CreateApplication(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -65,9 +74,9 @@ func TestApplicationHandler_CreateApplication(t *testing.T) {
 			// Setup
 			router := setupTestRouter()
 			mockRepo := usecase.NewMockApplicationRepository()
-			useCase := usecase.NewApplicationUseCase(mockRepo)
+			useCase := usecase.NewApplicationUseCase(mockRepo, usecase.NewMockAuditEventRepository())
 			toggleMock := usecase.NewMockToggleRepository()
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			teamMock := usecase.NewMockTeamRepository()
 			userMock := usecase.NewMockUserRepository()
 			teamUseCase := usecase.NewTeamUseCase(teamMock, userMock, mockRepo)
@@ -155,9 +164,9 @@ func TestApplicationHandler_GetApplication(t *testing.T) {
 			router := setupTestRouter()
 			mockRepo := usecase.NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
-			useCase := usecase.NewApplicationUseCase(mockRepo)
+			useCase := usecase.NewApplicationUseCase(mockRepo, usecase.NewMockAuditEventRepository())
 			toggleMock := usecase.NewMockToggleRepository()
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			teamMock := usecase.NewMockTeamRepository()
 			userMock := usecase.NewMockUserRepository()
 			teamUseCase := usecase.NewTeamUseCase(teamMock, userMock, mockRepo)
@@ -202,9 +211,9 @@ func TestApplicationHandler_GetAllApplications(t *testing.T) {
 		"app1": {ID: "app1", Name: "App 1"},
 		"app2": {ID: "app2", Name: "App 2"},
 	}
-	useCase := usecase.NewApplicationUseCase(mockRepo)
+	useCase := usecase.NewApplicationUseCase(mockRepo, usecase.NewMockAuditEventRepository())
 	toggleMock := usecase.NewMockToggleRepository()
-	toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo)
+	toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 	teamMock := usecase.NewMockTeamRepository()
 	userMock := usecase.NewMockUserRepository()
 	teamUseCase := usecase.NewTeamUseCase(teamMock, userMock, mockRepo)
@@ -295,9 +304,9 @@ func TestApplicationHandler_UpdateApplication(t *testing.T) {
 			router := setupTestRouter()
 			mockRepo := usecase.NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
-			useCase := usecase.NewApplicationUseCase(mockRepo)
+			useCase := usecase.NewApplicationUseCase(mockRepo, usecase.NewMockAuditEventRepository())
 			toggleMock := usecase.NewMockToggleRepository()
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			teamMock := usecase.NewMockTeamRepository()
 			userMock := usecase.NewMockUserRepository()
 			teamUseCase := usecase.NewTeamUseCase(teamMock, userMock, mockRepo)
@@ -372,9 +381,9 @@ func TestApplicationHandler_DeleteApplication(t *testing.T) {
 			router := setupTestRouter()
 			mockRepo := usecase.NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
-			useCase := usecase.NewApplicationUseCase(mockRepo)
+			useCase := usecase.NewApplicationUseCase(mockRepo, usecase.NewMockAuditEventRepository())
 			toggleMock := usecase.NewMockToggleRepository()
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, mockRepo, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			teamMock := usecase.NewMockTeamRepository()
 			userMock := usecase.NewMockUserRepository()
 			teamUseCase := usecase.NewTeamUseCase(teamMock, userMock, mockRepo)
@@ -418,3 +427,48 @@ func TestApplicationHandler_DeleteApplication(t *testing.T) {
 		})
 	}
 }
+
+func TestApplicationHandler_KillSwitch(t *testing.T) {
+	router := setupTestRouter()
+	mockRepo := usecase.NewMockApplicationRepository()
+	mockRepo.Applications["test123"] = &entity.Application{ID: "test123", Name: "Test App"}
+	auditMock := usecase.NewMockAuditEventRepository()
+	useCase := usecase.NewApplicationUseCase(mockRepo, auditMock)
+	toggleUseCase := usecase.NewToggleUseCase(usecase.NewMockToggleRepository(), mockRepo, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
+	teamUseCase := usecase.NewTeamUseCase(usecase.NewMockTeamRepository(), usecase.NewMockUserRepository(), mockRepo)
+	handler := NewApplicationHandler(useCase, toggleUseCase, teamUseCase)
+
+	router.POST("/applications/:id/kill-switch", handler.SetKillSwitch)
+	router.GET("/applications/:id/audit", handler.GetAuditEvents)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("POST", "/applications/test123/kill-switch", `{"active": true}`)
+	var app entity.Application
+	json.Unmarshal(w.Body.Bytes(), &app)
+	if w.Code != http.StatusOK || !app.KillSwitch || app.KillSwitchAt == nil {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = request("GET", "/applications/test123/audit", "")
+	var response struct {
+		Events []*entity.AuditEvent `json:"events"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.Events) != 1 || response.Events[0].Action != entity.AuditActionKillSwitchActivated {
+		t.Errorf("Unexpected audit %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := request("POST", "/applications/test123/kill-switch", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without active, got %d", w.Code)
+	}
+	if w := request("POST", "/applications/missing/kill-switch", `{"active": false}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown application, got %d", w.Code)
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// respondError responde com o status correspondente ao código do erro; erros que não são
+// AppError viram um erro interno
+func respondError(c *gin.Context, err error) {
+	appErr, ok := err.(*entity.AppError)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		return
+	}
+
+	c.JSON(errorStatus(appErr), appErr)
+}
+
+// errorStatus retorna o status HTTP correspondente ao código do erro
+func errorStatus(appErr *entity.AppError) int {
+	switch appErr.Code {
+	case entity.ErrCodeNotFound:
+		return http.StatusNotFound
+	case entity.ErrCodeAlreadyExists, entity.ErrCodeInUse, entity.ErrCodeManaged, entity.ErrCodeConflict:
+		return http.StatusConflict
+	case entity.ErrCodeFrozen:
+		return http.StatusLocked
+	case entity.ErrCodeDatabase:
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
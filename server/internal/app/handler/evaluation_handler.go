@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// EvaluationHandler gerencia as requisições HTTP de avaliação de toggles no servidor
+type EvaluationHandler struct {
+	evaluationUseCase *usecase.EvaluationUseCase
+	secretKeyUseCase  *usecase.SecretKeyUseCase
+}
+
+// NewEvaluationHandler cria uma nova instância de EvaluationHandler
+func NewEvaluationHandler(evaluationUseCase *usecase.EvaluationUseCase, secretKeyUseCase *usecase.SecretKeyUseCase) *EvaluationHandler {
+	return &EvaluationHandler{
+		evaluationUseCase: evaluationUseCase,
+		secretKeyUseCase:  secretKeyUseCase,
+	}
+}
+
+// EvaluateRequest representa a requisição de avaliação de um toggle
+type EvaluateRequest struct {
+	Path    string             `json:"path" binding:"required"`
+	Context *evaluator.Context `json:"context"`
+}
+
+// Evaluate avalia um toggle para o contexto informado e retorna o estado e a variante escolhida
+// POST /api/evaluate - Header: X-API-Key
+func (h *EvaluationHandler) Evaluate(c *gin.Context) {
+	secretKey := c.GetHeader("X-API-Key")
+	if secretKey == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "X-API-Key header is required",
+		})
+		return
+	}
+
+	key, err := h.secretKeyUseCase.ValidateSecretKey(secretKey, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Invalid or expired secret key",
+		})
+		return
+	}
+
+	var req EvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("path", "Toggle path is required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	// Sem IP no contexto, vale o IP de quem fez a requisição; X-Forwarded-For só é
+	// considerado quando a requisição passa por um proxy confiável
+	if req.Context == nil {
+		req.Context = &evaluator.Context{}
+	}
+	if req.Context.IP == "" {
+		req.Context.IP = c.ClientIP()
+	}
+
+	result, err := h.evaluationUseCase.Evaluate(key.ApplicationID, req.Path, req.Context)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Explain avalia um toggle da aplicação e retorna o rastro completo da avaliação, explicando o resultado
+// POST /applications/:id/explain
+func (h *EvaluationHandler) Explain(c *gin.Context) {
+	appID := c.Param("id")
+
+	var req EvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("path", "Toggle path is required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	trace, err := h.evaluationUseCase.Explain(appID, req.Path, req.Context)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trace)
+}
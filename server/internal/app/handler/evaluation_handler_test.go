@@ -0,0 +1,509 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+	"github.com/manorfm/totoogle/internal/app/infrastructure/geoip"
+	"gorm.io/gorm"
+)
+
+func setupEvaluationTestRouter() (*gin.Engine, string, *gorm.DB) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTestDB()
+
+	InitHandlers(db)
+
+	db.Create(&entity.Application{ID: "01JZNM42NKSANGHZ3G4KKXGCNW", Name: "Test App"})
+	toggle := entity.NewToggle("button", true, "button", 1, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	toggle.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "vip", Variant: "gold"})
+	toggle.Variants = entity.ToggleVariants{
+		{Name: "blue", PayloadType: entity.VariantPayloadJSON, Payload: json.RawMessage(`{"color":"blue"}`), Weight: 100},
+		{Name: "gold", PayloadType: entity.VariantPayloadJSON, Payload: json.RawMessage(`{"color":"gold"}`), Weight: 0},
+	}
+	db.Create(toggle)
+
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Test Secret",
+		ApplicationID: "01JZNM42NKSANGHZ3G4KKXGCNW",
+		CreatedBy:     "test-user-id",
+	}
+	plainKey, _ := secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	router := gin.New()
+	router.POST("/api/evaluate", Evaluate)
+	router.GET("/api/toggles", GetTogglesBySecret)
+	router.POST("/applications/:id/explain", Explain)
+
+	return router, plainKey, db
+}
+
+func TestEvaluate_ReturnsVariant(t *testing.T) {
+	router, plainKey, _ := setupEvaluationTestRouter()
+
+	evaluate := func(body string, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/evaluate", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := evaluate(`{"path": "button", "context": {"user_id": "vip"}}`, plainKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result evaluator.Result
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if !result.Enabled || result.Variant == nil || result.Variant.Name != "gold" || string(result.Variant.Payload) != `{"color":"gold"}` {
+		t.Errorf("Unexpected result %s", w.Body.String())
+	}
+
+	w = evaluate(`{"path": "button", "context": {"user_id": "regular"}}`, plainKey)
+	result = evaluator.Result{}
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if result.Enabled || result.Reason != evaluator.ReasonRuleNoMatch {
+		t.Errorf("Unexpected result %s", w.Body.String())
+	}
+
+	if w := evaluate(`{"path": "missing"}`, plainKey); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown toggle, got %d", w.Code)
+	}
+	if w := evaluate(`{}`, plainKey); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without path, got %d", w.Code)
+	}
+	if w := evaluate(`{"path": "button"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without key, got %d", w.Code)
+	}
+}
+
+func TestExplain_ReturnsTrace(t *testing.T) {
+	router, _, _ := setupEvaluationTestRouter()
+
+	explain := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/applications/01JZNM42NKSANGHZ3G4KKXGCNW/explain", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := explain(`{"path": "button", "context": {"user_id": "regular"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var trace evaluator.Trace
+	json.Unmarshal(w.Body.Bytes(), &trace)
+	if trace.Result == nil || trace.Result.Reason != evaluator.ReasonRuleNoMatch {
+		t.Fatalf("Unexpected trace %s", w.Body.String())
+	}
+	if len(trace.Rules) != 1 || len(trace.Rules[0].Conditions) != 1 || trace.Rules[0].Conditions[0].Detail != `user_id "regular" is not in the list` {
+		t.Errorf("Unexpected rules %s", w.Body.String())
+	}
+
+	if w := explain(`{"path": "missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown toggle, got %d", w.Code)
+	}
+	if w := explain(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without path, got %d", w.Code)
+	}
+}
+
+func TestEvaluate_IPRuleUsesClientIP(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+
+	office := entity.NewToggle("office", true, "office", 0, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	office.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeIP, Value: "10.0.0.0/8,2001:db8::/32"})
+	db.Create(office)
+
+	evaluate := func(body, forwardedFor string) evaluator.Result {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/evaluate", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", plainKey)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.RemoteAddr = "127.0.0.1:5000"
+		router.ServeHTTP(w, req)
+
+		var result evaluator.Result
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return result
+	}
+
+	// Sem IP no contexto, o IP encaminhado pelo proxy é usado
+	if result := evaluate(`{"path": "office"}`, "10.20.30.40"); !result.Enabled {
+		t.Errorf("Expected forwarded IP inside the block to enable the toggle, got %+v", result)
+	}
+	if result := evaluate(`{"path": "office", "context": {}}`, "2001:db8::7"); !result.Enabled {
+		t.Errorf("Expected forwarded IPv6 inside the block to enable the toggle, got %+v", result)
+	}
+	if result := evaluate(`{"path": "office"}`, "192.168.0.1"); result.Enabled {
+		t.Errorf("Expected forwarded IP outside the blocks to disable the toggle, got %+v", result)
+	}
+
+	// O IP informado no contexto tem precedência
+	if result := evaluate(`{"path": "office", "context": {"ip": "10.0.0.1"}}`, "192.168.0.1"); !result.Enabled {
+		t.Errorf("Expected context IP to be used, got %+v", result)
+	}
+}
+
+func TestEvaluate_CountryFromGeoIP(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+
+	resolver, err := geoip.NewResolver("../infrastructure/geoip/testdata/GeoIP2-Country-Test.mmdb")
+	if err != nil {
+		t.Fatalf("Failed to load GeoIP fixture: %v", err)
+	}
+	InitHandlersWithOptions(db, Options{CountryResolver: resolver})
+
+	promo := entity.NewToggle("promo", true, "promo", 0, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	promo.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeCountry, Value: "GB,SE"})
+	db.Create(promo)
+
+	evaluate := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/evaluate", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", plainKey)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{"country resolved from IP", `{"path": "promo", "context": {"ip": "81.2.69.160"}}`, true},
+		{"country outside the list", `{"path": "promo", "context": {"ip": "2001:db8::1"}}`, false},
+		{"explicit country", `{"path": "promo", "context": {"ip": "2001:db8::1", "country": "se"}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result evaluator.Result
+			json.Unmarshal(evaluate(tt.body).Body.Bytes(), &result)
+			if result.Enabled != tt.expected {
+				t.Errorf("Expected enabled %v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	if w := evaluate(`{"path": "promo", "context": {"country": "XX"}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid country, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetTogglesBySecret_IncludesVariants(t *testing.T) {
+	router, plainKey, _ := setupEvaluationTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Application struct {
+			Toggles []struct {
+				ActivationRule *entity.ActivationRule `json:"activation_rule"`
+				Variants       entity.ToggleVariants  `json:"variants"`
+				Rules          []*entity.ToggleRule   `json:"rules"`
+			} `json:"toggles"`
+		} `json:"application"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if len(response.Application.Toggles) != 1 {
+		t.Fatalf("Expected 1 toggle, got %s", w.Body.String())
+	}
+	toggle := response.Application.Toggles[0]
+	if len(toggle.Variants) != 2 || toggle.Variants[1].Name != "gold" {
+		t.Errorf("Expected variants in SDK payload, got %s", w.Body.String())
+	}
+	if toggle.ActivationRule == nil || toggle.ActivationRule.Variant != "gold" {
+		t.Errorf("Expected rule variant in SDK payload, got %s", w.Body.String())
+	}
+	if len(toggle.Rules) != 1 || len(toggle.Rules[0].Conditions) != 1 || toggle.Rules[0].Variant != "gold" {
+		t.Errorf("Expected rules in SDK payload, got %s", w.Body.String())
+	}
+}
+
+// Os campos has_activation_rule e activation_rule são lidos por SDKs que não conhecem as
+// regras compostas: sem regras activation_rule continua null, e com regras é sempre preenchido
+func TestGetTogglesBySecret_LegacyActivationRule(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+
+	plain := entity.NewToggle("plain", true, "plain", 1, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	db.Create(plain)
+	composite := entity.NewToggle("composite", true, "composite", 1, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	composite.SetRules([]*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}, {Type: entity.ActivationRuleTypePercentage, Value: "10"}}},
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: "vip"}}},
+	})
+	db.Create(composite)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Application struct {
+			Toggles []map[string]json.RawMessage `json:"toggles"`
+		} `json:"application"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	expected := map[string][2]string{
+		"plain":     {`false`, `null`},
+		"button":    {`true`, `{"type":"user_id","value":"vip","variant":"gold"}`},
+		"composite": {`true`, `{"type":"composite","value":"2"}`},
+	}
+	if len(response.Application.Toggles) != len(expected) {
+		t.Fatalf("Expected %d toggles, got %s", len(expected), w.Body.String())
+	}
+	for _, toggle := range response.Application.Toggles {
+		var path string
+		json.Unmarshal(toggle["path"], &path)
+		want := expected[path]
+		if string(toggle["has_activation_rule"]) != want[0] || string(toggle["activation_rule"]) != want[1] {
+			t.Errorf("Toggle %s: expected has_activation_rule %s and activation_rule %s, got %s and %s",
+				path, want[0], want[1], toggle["has_activation_rule"], toggle["activation_rule"])
+		}
+	}
+}
+
+// legacyClientToggle é o modelo de toggle dos SDKs anteriores às regras compostas, com os
+// campos enviados pelo servidor naquela versão
+type legacyClientToggle struct {
+	ID                string  `json:"id"`
+	Value             string  `json:"value"`
+	Enabled           bool    `json:"enabled"`
+	Path              string  `json:"path"`
+	Level             int     `json:"level"`
+	ParentID          *string `json:"parent_id"`
+	AppID             string  `json:"app_id"`
+	HasActivationRule bool    `json:"has_activation_rule"`
+	ActivationRule    *struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"activation_rule"`
+}
+
+// O payload atual precisa continuar sendo lido pelo modelo dos SDKs antigos
+func TestGetTogglesBySecret_LegacyClientModel(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+
+	plain := entity.NewToggle("plain", true, "plain", 1, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	db.Create(plain)
+	composite := entity.NewToggle("composite", true, "composite", 1, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+	composite.SetRules([]*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}, {Type: entity.ActivationRuleTypePercentage, Value: "10"}}},
+	})
+	db.Create(composite)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Application struct {
+			Toggles []legacyClientToggle `json:"toggles"`
+		} `json:"application"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected the legacy model to decode the payload, got %v", err)
+	}
+
+	toggles := make(map[string]legacyClientToggle)
+	for _, toggle := range response.Application.Toggles {
+		toggles[toggle.Path] = toggle
+	}
+	if toggle := toggles["plain"]; toggle.ID != plain.ID || toggle.AppID != plain.AppID || toggle.HasActivationRule || toggle.ActivationRule != nil {
+		t.Errorf("Expected plain toggle without activation rule, got %+v", toggle)
+	}
+	if toggle := toggles["button"]; !toggle.HasActivationRule || toggle.ActivationRule == nil || toggle.ActivationRule.Type != "user_id" || toggle.ActivationRule.Value != "vip" {
+		t.Errorf("Expected simple rule on button, got %+v", toggle)
+	}
+	if toggle := toggles["composite"]; !toggle.HasActivationRule || toggle.ActivationRule == nil || toggle.ActivationRule.Type != string(entity.LegacyCompositeRuleType) {
+		t.Errorf("Expected composite legacy rule, got %+v", toggle)
+	}
+}
+
+func TestGetTogglesBySecret_KillSwitch(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+	db.Model(&entity.Application{}).Where("id = ?", "01JZNM42NKSANGHZ3G4KKXGCNW").Update("kill_switch", true)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Application struct {
+			KillSwitch bool `json:"kill_switch"`
+			Toggles    []struct {
+				Enabled bool `json:"enabled"`
+			} `json:"toggles"`
+		} `json:"application"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if !response.Application.KillSwitch || len(response.Application.Toggles) != 1 || response.Application.Toggles[0].Enabled {
+		t.Errorf("Expected every toggle off with the kill switch, got %s", w.Body.String())
+	}
+
+	// O estado gravado do toggle não muda
+	var toggle entity.Toggle
+	db.Where("path = ?", "button").First(&toggle)
+	if !toggle.Enabled {
+		t.Error("Expected stored toggle to stay enabled")
+	}
+}
+
+func TestGetTogglesBySecret_ETag(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/toggles", nil)
+		req.Header.Set("X-API-Key", plainKey)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected 200 with an ETag, got %d %q", w.Code, etag)
+	}
+	if w := get(etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 for the same version, got %d", w.Code)
+	}
+
+	db.Model(&entity.Toggle{}).Where("path = ?", "button").Update("enabled", false)
+	if w := get(etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected a new version after a change, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+// sseEvent é um evento lido do stream de snapshots
+type sseEvent struct {
+	name string
+	id   string
+	data string
+}
+
+// readSSEEvent lê o próximo evento do stream, ignorando comentários
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (*sseEvent, error) {
+	t.Helper()
+	type result struct {
+		event *sseEvent
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		event := &sseEvent{}
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				done <- result{nil, err}
+				return
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "" && event.name != "":
+				done <- result{event, nil}
+				return
+			case strings.HasPrefix(line, "event: "):
+				event.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	select {
+	case r := <-done:
+		return r.event, r.err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a stream event")
+		return nil, nil
+	}
+}
+
+func TestStreamTogglesBySecret(t *testing.T) {
+	router, plainKey, db := setupEvaluationTestRouter()
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	secretKeyHandler.SetStreamInterval(20 * time.Millisecond)
+	router.GET("/api/toggles/stream", StreamTogglesBySecret)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	open := func(key, lastEventID string) *http.Response {
+		req, _ := http.NewRequest("GET", server.URL+"/api/toggles/stream", nil)
+		req.Header.Set("X-API-Key", key)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected the stream to open, got %v", err)
+		}
+		return resp
+	}
+
+	if resp := open("sk_invalid", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an invalid key, got %d", resp.StatusCode)
+	}
+
+	resp := open(plainKey, "")
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", resp.Header.Get("Content-Type"))
+	}
+	reader := bufio.NewReader(resp.Body)
+	first, err := readSSEEvent(t, reader)
+	if err != nil || first.name != "snapshot" || !strings.Contains(first.data, `"path":"button"`) {
+		t.Fatalf("Expected the current snapshot, got %+v %v", first, err)
+	}
+
+	// Reconectar com a versão atual não reenvia o snapshot; a próxima mudança é enviada
+	resumed := open(plainKey, first.id)
+	defer resumed.Body.Close()
+	resumedReader := bufio.NewReader(resumed.Body)
+
+	db.Model(&entity.Toggle{}).Where("path = ?", "button").Update("enabled", false)
+	changed, err := readSSEEvent(t, reader)
+	if err != nil || changed.id == first.id || !strings.Contains(changed.data, `"enabled":false`) {
+		t.Fatalf("Expected the changed snapshot, got %+v %v", changed, err)
+	}
+	if event, err := readSSEEvent(t, resumedReader); err != nil || event.id != changed.id {
+		t.Errorf("Expected the resumed stream to start at the change, got %+v %v", event, err)
+	}
+
+	// Remover a chave encerra o stream
+	db.Delete(&entity.SecretKey{}, "id = ?", "test-secret-id")
+	if _, err := readSSEEvent(t, reader); err == nil {
+		t.Error("Expected the stream to end after the key is deleted")
+	}
+}
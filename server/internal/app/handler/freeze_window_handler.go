@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// FreezeWindowHandler gerencia as requisições HTTP para janelas de congelamento.
+// As rotas em /applications/:id/freeze-windows operam sobre as janelas da aplicação
+// e as rotas em /freeze-windows sobre as janelas globais.
+type FreezeWindowHandler struct {
+	freezeWindowUseCase *usecase.FreezeWindowUseCase
+}
+
+// NewFreezeWindowHandler cria uma nova instância de FreezeWindowHandler
+func NewFreezeWindowHandler(freezeWindowUseCase *usecase.FreezeWindowUseCase) *FreezeWindowHandler {
+	return &FreezeWindowHandler{
+		freezeWindowUseCase: freezeWindowUseCase,
+	}
+}
+
+// FreezeWindowRequest representa a requisição para criar uma janela de congelamento
+type FreezeWindowRequest struct {
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required"`
+	Reason      string    `json:"reason" binding:"required"`
+	ExemptUsers []string  `json:"exempt_users"`
+}
+
+// CreateFreezeWindow cria uma janela de congelamento
+// POST /applications/:id/freeze-windows | POST /freeze-windows
+func (h *FreezeWindowHandler) CreateFreezeWindow(c *gin.Context) {
+	var req FreezeWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("request", "Start, end and reason are required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	window, err := h.freezeWindowUseCase.CreateFreezeWindow(segmentScope(c), req.StartsAt, req.EndsAt, req.Reason, req.ExemptUsers, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, window)
+}
+
+// GetFreezeWindows lista as janelas de congelamento do escopo
+// GET /applications/:id/freeze-windows | GET /freeze-windows
+func (h *FreezeWindowHandler) GetFreezeWindows(c *gin.Context) {
+	windows, err := h.freezeWindowUseCase.ListFreezeWindows(segmentScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"freeze_windows": windows,
+	})
+}
+
+// DeleteFreezeWindow remove uma janela de congelamento
+// DELETE /applications/:id/freeze-windows/:windowId | DELETE /freeze-windows/:windowId
+func (h *FreezeWindowHandler) DeleteFreezeWindow(c *gin.Context) {
+	if err := h.freezeWindowUseCase.DeleteFreezeWindow(c.Param("windowId"), segmentScope(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "freeze window deleted successfully",
+	})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+func TestFreezeWindowHandler_BlocksToggleChanges(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	freezeMock := usecase.NewMockFreezeWindowRepository()
+	auditMock := usecase.NewMockAuditEventRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Enabled: true}
+
+	user := &entity.User{ID: "admin1", Username: "admin", Role: entity.UserRoleAdmin}
+	router.Use(func(c *gin.Context) {
+		c.Set("user", user)
+		c.Next()
+	})
+
+	toggleHandler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), freezeMock, auditMock))
+	handler := NewFreezeWindowHandler(usecase.NewFreezeWindowUseCase(freezeMock, appMock))
+	router.PUT("/applications/:id/toggles/:toggleId", toggleHandler.UpdateToggle)
+	router.POST("/applications/:id/freeze-windows", handler.CreateFreezeWindow)
+	router.GET("/applications/:id/freeze-windows", handler.GetFreezeWindows)
+	router.DELETE("/applications/:id/freeze-windows/:windowId", handler.DeleteFreezeWindow)
+
+	request := func(method, url string, body interface{}, override string) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		if override != "" {
+			req.Header.Set("X-Freeze-Override", override)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	now := time.Now()
+	w := request("POST", "/applications/app123/freeze-windows", gin.H{
+		"starts_at": now.Add(-time.Hour),
+		"ends_at":   now.Add(time.Hour),
+		"reason":    "black friday",
+	}, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var window entity.FreezeWindow
+	json.Unmarshal(w.Body.Bytes(), &window)
+
+	update := gin.H{"enabled": false}
+	w = request("PUT", "/applications/app123/toggles/toggle1", update, "")
+	var appErr entity.AppError
+	json.Unmarshal(w.Body.Bytes(), &appErr)
+	if w.Code != http.StatusLocked || appErr.Code != entity.ErrCodeFrozen {
+		t.Fatalf("Expected status 423, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Apenas o root pode sobrepor o congelamento
+	if w := request("PUT", "/applications/app123/toggles/toggle1", update, "incident 42"); w.Code != http.StatusLocked {
+		t.Errorf("Expected admin override to be refused, got %d", w.Code)
+	}
+	user = &entity.User{ID: "root1", Username: "root", Role: entity.UserRoleRoot}
+	if w := request("PUT", "/applications/app123/toggles/toggle1", update, "incident 42"); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 with override, got %d: %s", w.Code, w.Body.String())
+	}
+	events, _ := auditMock.GetByAppID("app123")
+	if len(events) != 1 || events[0].Action != entity.AuditActionFreezeOverridden || events[0].Actor != "root" {
+		t.Errorf("Expected override to be audited, got %+v", events)
+	}
+
+	w = request("GET", "/applications/app123/freeze-windows", nil, "")
+	var response struct {
+		FreezeWindows []*entity.FreezeWindow `json:"freeze_windows"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.FreezeWindows) != 1 {
+		t.Fatalf("Unexpected freeze windows %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := request("DELETE", "/applications/other/freeze-windows/"+window.ID, nil, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown application, got %d", w.Code)
+	}
+	if w := request("DELETE", "/applications/app123/freeze-windows/"+window.ID, nil, ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := request("DELETE", "/applications/app123/freeze-windows/"+window.ID, nil, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a deleted window, got %d", w.Code)
+	}
+
+	if w := request("POST", "/applications/app123/freeze-windows", gin.H{"reason": "missing period"}, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without period, got %d", w.Code)
+	}
+}
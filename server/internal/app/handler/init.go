@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/auth"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
@@ -17,16 +19,58 @@ var (
 	userManagementHandler *UserManagementHandler
 	teamHandler           *TeamHandler
 	secretKeyHandler      *SecretKeyHandler
+	metricsHandler        *MetricsHandler
+	reportHandler         *ReportHandler
+	searchHandler         *SearchHandler
+	evaluationHandler     *EvaluationHandler
+	segmentHandler        *SegmentHandler
+	snapshotHandler       *SnapshotHandler
+	trashHandler          *TrashHandler
+	freezeWindowHandler   *FreezeWindowHandler
+	toggleBatchHandler    *ToggleBatchHandler
+	syncHandler           *SyncHandler
+
+	// stopBackground encerra as tarefas em segundo plano iniciadas com os handlers
+	stopBackground []func()
 )
 
+// Options configura dependências opcionais dos handlers
+type Options struct {
+	// CountryResolver obtém o país a partir do IP nas avaliações; nil desativa a resolução
+	CountryResolver entity.CountryResolver
+
+	// TrashRetention é o tempo que toggles e aplicações removidos ficam na lixeira antes do
+	// expurgo periódico; zero desativa o expurgo
+	TrashRetention time.Duration
+
+	// ManagedPolicy define se os usuários podem alterar manualmente os recursos gerenciados
+	// pela sincronização declarativa; vazio apenas avisa na interface
+	ManagedPolicy entity.ManagedPolicy
+
+	// StreamPollInterval é a frequência com que o stream de snapshots verifica mudanças;
+	// zero usa DefaultStreamPollInterval
+	StreamPollInterval time.Duration
+}
+
 // InitHandlers inicializa os handlers
 func InitHandlers(db *gorm.DB) {
+	InitHandlersWithOptions(db, Options{})
+}
+
+// InitHandlersWithOptions inicializa os handlers com as dependências opcionais informadas
+func InitHandlersWithOptions(db *gorm.DB, options Options) {
 	// Inicializa repositórios
 	appRepo := database.NewApplicationRepository(db)
 	toggleRepo := database.NewToggleRepository(db)
 	userRepo := database.NewUserRepository(db)
 	teamRepo := database.NewTeamRepository(db)
 	secretKeyRepo := database.NewSecretKeyRepository(db)
+	metricRepo := database.NewToggleMetricRepository(db)
+	segmentRepo := database.NewSegmentRepository(db)
+	revisionRepo := database.NewToggleRevisionRepository(db)
+	snapshotRepo := database.NewApplicationSnapshotRepository(db)
+	auditRepo := database.NewAuditEventRepository(db)
+	freezeRepo := database.NewFreezeWindowRepository(db)
 
 	// Inicializa sistema de autenticação
 	authManager := auth.NewAuthManager()
@@ -34,12 +78,29 @@ func InitHandlers(db *gorm.DB) {
 	authManager.RegisterStrategy("local", localStrategy)
 
 	// Inicializa use cases
-	appUseCase := usecase.NewApplicationUseCase(appRepo)
-	toggleUseCase := usecase.NewToggleUseCase(toggleRepo, appRepo)
+	appUseCase := usecase.NewApplicationUseCase(appRepo, auditRepo)
+	toggleUseCase := usecase.NewToggleUseCase(toggleRepo, appRepo, segmentRepo, revisionRepo, freezeRepo, auditRepo)
 	authUseCase := usecase.NewAuthUseCase(userRepo, authManager)
 	userUseCase := usecase.NewUserUseCase(userRepo)
 	teamUseCase := usecase.NewTeamUseCase(teamRepo, userRepo, appRepo)
 	secretKeyUseCase := usecase.NewSecretKeyUseCase(secretKeyRepo)
+	stopBackground = []func(){secretKeyUseCase.StartUsageTracking(usecase.DefaultUsageFlushInterval)}
+	metricsUseCase := usecase.NewMetricsUseCase(metricRepo, toggleRepo)
+	reportUseCase := usecase.NewReportUseCase(toggleRepo, appRepo, metricRepo)
+	searchUseCase := usecase.NewSearchUseCase(toggleRepo, teamRepo)
+	segmentUseCase := usecase.NewSegmentUseCase(segmentRepo, appRepo)
+	evaluationUseCase := usecase.NewEvaluationUseCase(toggleRepo, appRepo, segmentRepo, options.CountryResolver)
+	snapshotUseCase := usecase.NewSnapshotUseCase(toggleRepo, appRepo, snapshotRepo, toggleUseCase)
+	trashUseCase := usecase.NewTrashUseCase(toggleRepo, appRepo, toggleUseCase)
+	freezeWindowUseCase := usecase.NewFreezeWindowUseCase(freezeRepo, appRepo)
+	toggleBatchUseCase := usecase.NewToggleBatchUseCase(toggleRepo, appRepo, toggleUseCase)
+	syncUseCase := usecase.NewSyncUseCase(toggleRepo, appUseCase, segmentUseCase, toggleUseCase)
+	appUseCase.SetManagedPolicy(options.ManagedPolicy)
+	segmentUseCase.SetManagedPolicy(options.ManagedPolicy)
+	toggleUseCase.SetManagedPolicy(options.ManagedPolicy)
+	if options.TrashRetention > 0 {
+		stopBackground = append(stopBackground, trashUseCase.StartPurge(options.TrashRetention, usecase.DefaultTrashPurgeInterval))
+	}
 
 	// Inicializar usuário root padrão
 	authUseCase.InitializeRootUser()
@@ -51,7 +112,27 @@ func InitHandlers(db *gorm.DB) {
 	userHandler = NewUserHandler(userUseCase)
 	userManagementHandler = NewUserManagementHandler(userUseCase, teamUseCase)
 	teamHandler = NewTeamHandler(teamUseCase)
-	secretKeyHandler = NewSecretKeyHandler(secretKeyUseCase, toggleUseCase, appUseCase)
+	secretKeyHandler = NewSecretKeyHandler(secretKeyUseCase, toggleUseCase, appUseCase, segmentUseCase)
+	secretKeyHandler.SetStreamInterval(options.StreamPollInterval)
+	metricsHandler = NewMetricsHandler(metricsUseCase, secretKeyUseCase)
+	reportHandler = NewReportHandler(reportUseCase)
+	searchHandler = NewSearchHandler(searchUseCase)
+	evaluationHandler = NewEvaluationHandler(evaluationUseCase, secretKeyUseCase)
+	segmentHandler = NewSegmentHandler(segmentUseCase)
+	snapshotHandler = NewSnapshotHandler(snapshotUseCase)
+	trashHandler = NewTrashHandler(trashUseCase)
+	freezeWindowHandler = NewFreezeWindowHandler(freezeWindowUseCase)
+	toggleBatchHandler = NewToggleBatchHandler(toggleBatchUseCase)
+	syncHandler = NewSyncHandler(syncUseCase)
+}
+
+// Shutdown encerra as tarefas em segundo plano dos handlers, gravando o uso das secret keys
+// ainda não persistido. Deve ser chamado ao encerrar o servidor.
+func Shutdown() {
+	for _, stop := range stopBackground {
+		stop()
+	}
+	stopBackground = nil
 }
 
 // Funções globais para as rotas
@@ -75,6 +156,14 @@ func DeleteApplication(c *gin.Context) {
 	appHandler.DeleteApplication(c)
 }
 
+func SetKillSwitch(c *gin.Context) {
+	appHandler.SetKillSwitch(c)
+}
+
+func GetAuditEvents(c *gin.Context) {
+	appHandler.GetAuditEvents(c)
+}
+
 func CreateToggle(c *gin.Context) {
 	toggleHandler.CreateToggle(c)
 }
@@ -91,10 +180,77 @@ func UpdateToggle(c *gin.Context) {
 	toggleHandler.UpdateToggle(c)
 }
 
+func UpdateToggleMetadata(c *gin.Context) {
+	toggleHandler.UpdateToggleMetadata(c)
+}
+
+func UpdateTogglePrerequisites(c *gin.Context) {
+	toggleHandler.UpdateTogglePrerequisites(c)
+}
+
 func DeleteToggle(c *gin.Context) {
 	toggleHandler.DeleteToggle(c)
 }
 
+func GetToggleHistory(c *gin.Context) {
+	toggleHandler.GetToggleHistory(c)
+}
+
+func RollbackToggle(c *gin.Context) {
+	toggleHandler.RollbackToggle(c)
+}
+
+func ApplyToggleBatch(c *gin.Context) {
+	toggleBatchHandler.ApplyBatch(c)
+}
+
+// Funções da sincronização declarativa
+func Sync(c *gin.Context) {
+	syncHandler.Sync(c)
+}
+
+// Funções de snapshots de aplicações
+func CreateSnapshot(c *gin.Context) {
+	snapshotHandler.CreateSnapshot(c)
+}
+
+func GetSnapshots(c *gin.Context) {
+	snapshotHandler.GetSnapshots(c)
+}
+
+func RestoreSnapshot(c *gin.Context) {
+	snapshotHandler.RestoreSnapshot(c)
+}
+
+// Funções da lixeira
+func GetToggleTrash(c *gin.Context) {
+	trashHandler.GetToggleTrash(c)
+}
+
+func RestoreTrashedToggle(c *gin.Context) {
+	trashHandler.RestoreToggle(c)
+}
+
+func GetApplicationTrash(c *gin.Context) {
+	trashHandler.GetApplicationTrash(c)
+}
+
+func RestoreTrashedApplication(c *gin.Context) {
+	trashHandler.RestoreApplication(c)
+}
+
+func CreateFreezeWindow(c *gin.Context) {
+	freezeWindowHandler.CreateFreezeWindow(c)
+}
+
+func GetFreezeWindows(c *gin.Context) {
+	freezeWindowHandler.GetFreezeWindows(c)
+}
+
+func DeleteFreezeWindow(c *gin.Context) {
+	freezeWindowHandler.DeleteFreezeWindow(c)
+}
+
 func UpdateEnabled(c *gin.Context) {
 	toggleHandler.UpdateEnabled(c)
 }
@@ -149,6 +305,10 @@ func GetTogglesBySecret(c *gin.Context) {
 	secretKeyHandler.GetTogglesBySecret(c)
 }
 
+func StreamTogglesBySecret(c *gin.Context) {
+	secretKeyHandler.StreamTogglesBySecret(c)
+}
+
 func GetSecretKeys(c *gin.Context) {
 	secretKeyHandler.GetSecretKeys(c)
 }
@@ -157,6 +317,55 @@ func DeleteSecretKey(c *gin.Context) {
 	secretKeyHandler.DeleteSecretKey(c)
 }
 
+// Funções de métricas de avaliação
+func PostMetrics(c *gin.Context) {
+	metricsHandler.PostMetrics(c)
+}
+
+func GetToggleMetrics(c *gin.Context) {
+	metricsHandler.GetToggleMetrics(c)
+}
+
+// Funções de avaliação de toggles
+func Evaluate(c *gin.Context) {
+	evaluationHandler.Evaluate(c)
+}
+
+func Explain(c *gin.Context) {
+	evaluationHandler.Explain(c)
+}
+
+// Funções de segmentos
+func CreateSegment(c *gin.Context) {
+	segmentHandler.CreateSegment(c)
+}
+
+func GetAllSegments(c *gin.Context) {
+	segmentHandler.GetAllSegments(c)
+}
+
+func GetSegment(c *gin.Context) {
+	segmentHandler.GetSegment(c)
+}
+
+func UpdateSegment(c *gin.Context) {
+	segmentHandler.UpdateSegment(c)
+}
+
+func DeleteSegment(c *gin.Context) {
+	segmentHandler.DeleteSegment(c)
+}
+
+// Funções de relatórios
+func GetStaleReport(c *gin.Context) {
+	reportHandler.GetStaleReport(c)
+}
+
+// Funções de busca
+func Search(c *gin.Context) {
+	searchHandler.Search(c)
+}
+
 // Funções de gestão de usuários
 func CreateUser(c *gin.Context) {
 	userManagementHandler.CreateUser(c)
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// MetricsHandler gerencia as requisições HTTP para métricas de avaliação
+type MetricsHandler struct {
+	metricsUseCase   *usecase.MetricsUseCase
+	secretKeyUseCase *usecase.SecretKeyUseCase
+}
+
+// NewMetricsHandler cria uma nova instância de MetricsHandler
+func NewMetricsHandler(metricsUseCase *usecase.MetricsUseCase, secretKeyUseCase *usecase.SecretKeyUseCase) *MetricsHandler {
+	return &MetricsHandler{
+		metricsUseCase:   metricsUseCase,
+		secretKeyUseCase: secretKeyUseCase,
+	}
+}
+
+// PostMetricsRequest representa um lote de contagens de avaliação enviado pelo SDK
+type PostMetricsRequest struct {
+	Metrics []*entity.EvaluationCount `json:"metrics" binding:"required"`
+}
+
+// PostMetrics registra contagens de avaliação enviadas pelo SDK
+// POST /api/metrics - Header: X-API-Key
+func (h *MetricsHandler) PostMetrics(c *gin.Context) {
+	secretKey := c.GetHeader("X-API-Key")
+	if secretKey == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "X-API-Key header is required",
+		})
+		return
+	}
+
+	key, err := h.secretKeyUseCase.ValidateSecretKey(secretKey, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Invalid or expired secret key",
+		})
+		return
+	}
+
+	var req PostMetricsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("request", "Invalid request body")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	result, err := h.metricsUseCase.RecordEvaluations(key.ApplicationID, req.Metrics)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// GetToggleMetrics retorna as métricas de avaliação de um toggle
+// GET /applications/:id/toggles/:toggleId/metrics?from=RFC3339&to=RFC3339
+func (h *MetricsHandler) GetToggleMetrics(c *gin.Context) {
+	appID := c.Param("id")
+	toggleID := c.Param("toggleId")
+
+	appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		appErr.AddDetail("from", "from must be an RFC3339 timestamp")
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		appErr.AddDetail("to", "to must be an RFC3339 timestamp")
+	}
+	if len(appErr.Details) > 0 {
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	summary, err := h.metricsUseCase.GetToggleMetrics(toggleID, appID, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// parseTimeQuery lê um parâmetro de query no formato RFC3339, retornando zero se ausente
+func parseTimeQuery(c *gin.Context, name string) (time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
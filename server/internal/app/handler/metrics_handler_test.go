@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"gorm.io/gorm"
+)
+
+func setupMetricsTestRouter() (*gin.Engine, *gorm.DB, string) {
+	gin.SetMode(gin.TestMode)
+
+	// Cria base de dados em memória para testes
+	db := setupTestDB()
+
+	// Inicializa handlers com a base de dados de teste
+	InitHandlers(db)
+
+	db.Create(&entity.Application{ID: "01JZNM42NKSANGHZ3G4KKXGCNW", Name: "Test App"})
+	db.Create(&entity.Toggle{ID: "01JZNM42NKSANGHZ3G4KKXGCT1", Value: "checkout", Path: "checkout", Enabled: true, AppID: "01JZNM42NKSANGHZ3G4KKXGCNW"})
+
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Test Secret",
+		ApplicationID: "01JZNM42NKSANGHZ3G4KKXGCNW",
+		CreatedBy:     "test-user-id",
+	}
+	plainKey, _ := secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	router := gin.New()
+	router.POST("/api/metrics", PostMetrics)
+	router.GET("/applications/:id/toggles/:toggleId/metrics", GetToggleMetrics)
+
+	return router, db, plainKey
+}
+
+func TestPostMetrics_AggregatesIntoBuckets(t *testing.T) {
+	router, _, plainKey := setupMetricsTestRouter()
+
+	windowStart := time.Now().UTC().Add(-time.Hour).Truncate(time.Hour).Add(10 * time.Minute)
+	body, _ := json.Marshal(gin.H{
+		"metrics": []gin.H{
+			{"toggle": "checkout", "result": true, "count": 7, "window_start": windowStart, "window_end": windowStart.Add(time.Minute)},
+			{"toggle": "checkout", "result": false, "count": 3, "window_start": windowStart, "window_end": windowStart.Add(time.Minute)},
+			{"toggle": "checkout", "result": true, "count": 5, "window_start": windowStart.Add(20 * time.Minute)},
+			{"toggle": "unknown", "result": true, "count": 1, "window_start": windowStart},
+			{"toggle": "checkout", "result": true, "count": 0, "window_start": windowStart},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/metrics", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var postResponse map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &postResponse)
+
+	if postResponse["accepted"].(float64) != 3 {
+		t.Errorf("Expected 3 accepted metrics, got %v", postResponse["accepted"])
+	}
+	if len(postResponse["rejected"].([]interface{})) != 2 {
+		t.Errorf("Expected 2 rejected metrics, got %v", postResponse["rejected"])
+	}
+
+	// Um segundo envio no mesmo bucket deve somar aos contadores existentes
+	body, _ = json.Marshal(gin.H{
+		"metrics": []gin.H{
+			{"toggle": "checkout", "result": false, "count": 2, "window_start": windowStart},
+		},
+	})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/metrics", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/applications/01JZNM42NKSANGHZ3G4KKXGCNW/toggles/01JZNM42NKSANGHZ3G4KKXGCT1/metrics", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var summary entity.ToggleMetricsSummary
+	json.Unmarshal(w.Body.Bytes(), &summary)
+
+	if summary.TrueCount != 12 || summary.FalseCount != 5 || summary.Total != 17 {
+		t.Errorf("Expected 12 true / 5 false / 17 total, got %d / %d / %d", summary.TrueCount, summary.FalseCount, summary.Total)
+	}
+	if len(summary.Buckets) != 1 {
+		t.Fatalf("Expected 1 bucket, got %d", len(summary.Buckets))
+	}
+	if !summary.Buckets[0].BucketStart.Equal(windowStart.Truncate(time.Hour)) {
+		t.Errorf("Expected bucket start %v, got %v", windowStart.Truncate(time.Hour), summary.Buckets[0].BucketStart)
+	}
+}
+
+func TestPostMetrics_Authentication(t *testing.T) {
+	router, _, _ := setupMetricsTestRouter()
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		expectedStatus int
+	}{
+		{name: "missing_header", apiKey: "", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid_key", apiKey: "sk_invalid", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/metrics", bytes.NewBufferString(`{"metrics": []}`))
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestGetToggleMetrics_Validation(t *testing.T) {
+	router, _, _ := setupMetricsTestRouter()
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "invalid_from", url: "/applications/01JZNM42NKSANGHZ3G4KKXGCNW/toggles/01JZNM42NKSANGHZ3G4KKXGCT1/metrics?from=yesterday", expectedStatus: http.StatusBadRequest},
+		{name: "unknown_toggle", url: "/applications/01JZNM42NKSANGHZ3G4KKXGCNW/toggles/01JZNM42NKSANGHZ3G4KKXGCT9/metrics", expectedStatus: http.StatusNotFound},
+		{name: "empty_range", url: "/applications/01JZNM42NKSANGHZ3G4KKXGCNW/toggles/01JZNM42NKSANGHZ3G4KKXGCT1/metrics", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
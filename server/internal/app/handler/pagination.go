@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// parsePageRequest lê os parâmetros de paginação por cursor da query.
+// A paginação é opcional: sem `limit` nem `cursor` retorna nil e as listagens mantêm a resposta original.
+func parsePageRequest(c *gin.Context, fields entity.ListFields) (*entity.PageRequest, *entity.AppError) {
+	rawLimit := c.Query("limit")
+	cursor := c.Query("cursor")
+	if rawLimit == "" && cursor == "" {
+		return nil, nil
+	}
+
+	page := &entity.PageRequest{
+		Cursor:  cursor,
+		Sort:    c.Query("sort"),
+		Order:   entity.SortOrder(c.Query("order")),
+		Filters: make(map[string][]string),
+	}
+
+	if rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil {
+			appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+			appErr.AddDetail("limit", "limit must be an integer")
+			return nil, appErr
+		}
+		page.Limit = limit
+	}
+
+	for _, name := range fields.Filters {
+		if values := c.QueryArray(name); len(values) > 0 && values[0] != "" {
+			page.Filters[name] = values
+		}
+	}
+
+	return page, nil
+}
+
+// respondPage responde com o envelope paginado ou com o erro correspondente
+func respondPage[T any](c *gin.Context, page *entity.Page[T], err error) {
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
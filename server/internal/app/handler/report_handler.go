@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// ReportHandler gerencia as requisições HTTP para relatórios de toggles
+type ReportHandler struct {
+	reportUseCase *usecase.ReportUseCase
+}
+
+// NewReportHandler cria uma nova instância de ReportHandler
+func NewReportHandler(reportUseCase *usecase.ReportUseCase) *ReportHandler {
+	return &ReportHandler{
+		reportUseCase: reportUseCase,
+	}
+}
+
+// GetStaleReport retorna o relatório de toggles obsoletos de uma aplicação
+// GET /applications/:id/reports/stale?days=30
+func (h *ReportHandler) GetStaleReport(c *gin.Context) {
+	appID := c.Param("id")
+
+	days := entity.DefaultStaleDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+			appErr.AddDetail("days", "days must be a positive integer")
+			c.JSON(http.StatusBadRequest, appErr)
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.reportUseCase.GetStaleReport(appID, days)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
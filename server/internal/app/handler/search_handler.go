@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// SearchHandler gerencia as requisições HTTP para busca de toggles
+type SearchHandler struct {
+	searchUseCase *usecase.SearchUseCase
+}
+
+// NewSearchHandler cria uma nova instância de SearchHandler
+func NewSearchHandler(searchUseCase *usecase.SearchUseCase) *SearchHandler {
+	return &SearchHandler{
+		searchUseCase: searchUseCase,
+	}
+}
+
+// Search busca toggles em todas as aplicações acessíveis pelo usuário
+// GET /search?q=checkout&limit=20&offset=0
+func (h *SearchHandler) Search(c *gin.Context) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.NewAppError(entity.ErrCodeValidation, "user not authenticated"))
+		return
+	}
+
+	user, ok := userInterface.(*entity.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "invalid user context"))
+		return
+	}
+
+	appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+	limit, err := parseIntQuery(c, "limit", entity.DefaultSearchLimit)
+	if err != nil {
+		appErr.AddDetail("limit", "limit must be an integer")
+	}
+	offset, err := parseIntQuery(c, "offset", 0)
+	if err != nil {
+		appErr.AddDetail("offset", "offset must be an integer")
+	}
+	if len(appErr.Details) > 0 {
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	results, err := h.searchUseCase.SearchToggles(user, c.Query("q"), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// parseIntQuery lê um parâmetro de query inteiro, retornando o valor padrão se ausente
+func parseIntQuery(c *gin.Context, name string, defaultValue int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(raw)
+}
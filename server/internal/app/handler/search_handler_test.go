@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+func TestSearchHandler_Search(t *testing.T) {
+	toggleMock := usecase.NewMockToggleRepository()
+	toggleMock.Toggles["t1"] = &entity.Toggle{ID: "t1", AppID: "shop", Path: "new-checkout", Value: "new-checkout"}
+	toggleMock.Toggles["t2"] = &entity.Toggle{ID: "t2", AppID: "shop", Path: "payments.new-checkout", Value: "new-checkout"}
+
+	handler := NewSearchHandler(usecase.NewSearchUseCase(toggleMock, usecase.NewMockTeamRepository()))
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &entity.User{ID: "root", Role: entity.UserRoleRoot})
+		c.Next()
+	})
+	router.GET("/search", handler.Search)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+		expectedTotal  int64
+	}{
+		{"ranked results", "?q=new-checkout", http.StatusOK, 2, 2},
+		{"paginated results", "?q=new-checkout&limit=1&offset=1", http.StatusOK, 1, 2},
+		{"missing term", "", http.StatusBadRequest, 0, 0},
+		{"invalid limit", "?q=new-checkout&limit=abc", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/search"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response entity.ToggleSearchResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response.Items) != tt.expectedCount || response.Total != tt.expectedTotal {
+				t.Errorf("Expected %d items (total %d), got %d (total %d)", tt.expectedCount, tt.expectedTotal, len(response.Items), response.Total)
+			}
+		})
+	}
+}
+
+func TestSearchHandler_Search_Unauthenticated(t *testing.T) {
+	handler := NewSearchHandler(usecase.NewSearchUseCase(usecase.NewMockToggleRepository(), usecase.NewMockTeamRepository()))
+	router := setupTestRouter()
+	router.GET("/search", handler.Search)
+
+	req, _ := http.NewRequest("GET", "/search?q=checkout", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -1,24 +1,47 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/usecase"
 )
 
+// Intervalos padrão do stream de snapshots
+const (
+	DefaultStreamPollInterval = 2 * time.Second  // Frequência com que o stream verifica se o snapshot mudou
+	DefaultStreamHeartbeat    = 15 * time.Second // Comentário enviado para manter a conexão aberta em proxies
+)
+
 type SecretKeyHandler struct {
 	secretKeyUseCase    *usecase.SecretKeyUseCase
 	toggleUseCase       *usecase.ToggleUseCase
 	applicationUseCase  *usecase.ApplicationUseCase
+	segmentUseCase      *usecase.SegmentUseCase
+	streamInterval      time.Duration
 }
 
-func NewSecretKeyHandler(secretKeyUseCase *usecase.SecretKeyUseCase, toggleUseCase *usecase.ToggleUseCase, applicationUseCase *usecase.ApplicationUseCase) *SecretKeyHandler {
+func NewSecretKeyHandler(secretKeyUseCase *usecase.SecretKeyUseCase, toggleUseCase *usecase.ToggleUseCase, applicationUseCase *usecase.ApplicationUseCase, segmentUseCase *usecase.SegmentUseCase) *SecretKeyHandler {
 	return &SecretKeyHandler{
 		secretKeyUseCase:   secretKeyUseCase,
 		toggleUseCase:      toggleUseCase,
 		applicationUseCase: applicationUseCase,
+		segmentUseCase:     segmentUseCase,
+		streamInterval:     DefaultStreamPollInterval,
+	}
+}
+
+// SetStreamInterval define a frequência com que o stream verifica mudanças; zero mantém o padrão
+func (h *SecretKeyHandler) SetStreamInterval(interval time.Duration) {
+	if interval > 0 {
+		h.streamInterval = interval
 	}
 }
 
@@ -86,7 +109,7 @@ func (h *SecretKeyHandler) GetTogglesBySecret(c *gin.Context) {
 	}
 
 	// Validar a secret key
-	key, err := h.secretKeyUseCase.ValidateSecretKey(secretKey)
+	key, err := h.secretKeyUseCase.ValidateSecretKey(secretKey, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Invalid or expired secret key",
@@ -94,52 +117,179 @@ func (h *SecretKeyHandler) GetTogglesBySecret(c *gin.Context) {
 		return
 	}
 
-	// Buscar dados da aplicação
-	application, err := h.applicationUseCase.GetApplicationByID(key.ApplicationID)
+	snapshot, err := h.togglesSnapshot(key.ApplicationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve application: " + err.Error(),
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Buscar todos os toggles da aplicação
-	toggles, err := h.toggleUseCase.GetAllTogglesByApp(key.ApplicationID)
+	// A versão permite que os SDKs consultem periodicamente sem baixar o snapshot inalterado
+	c.Header("ETag", snapshot.etag())
+	if c.GetHeader("If-None-Match") == snapshot.etag() {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", snapshot.data)
+}
+
+// StreamTogglesBySecret envia o snapshot da aplicação por Server-Sent Events sempre que ele muda.
+// O primeiro evento é o snapshot atual, omitido quando Last-Event-ID já tem a mesma versão.
+// O stream termina quando o cliente desconecta ou a secret key é removida.
+// GET /api/toggles/stream - Header: X-API-Key
+func (h *SecretKeyHandler) StreamTogglesBySecret(c *gin.Context) {
+	secretKey := c.GetHeader("X-API-Key")
+	if secretKey == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "X-API-Key header is required",
+		})
+		return
+	}
+
+	key, err := h.secretKeyUseCase.ValidateSecretKey(secretKey, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Invalid or expired secret key",
+		})
+		return
+	}
+
+	snapshot, err := h.togglesSnapshot(key.ApplicationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve toggles: " + err.Error(),
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Simplificar toggles removendo children e parent
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	version := c.GetHeader("Last-Event-ID")
+	if version != snapshot.version {
+		writeSnapshotEvent(c, snapshot)
+		version = snapshot.version
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.streamInterval)
+	defer ticker.Stop()
+	lastWrite := time.Now()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		// A chave é verificada pelo ID para não contar cada verificação como um uso
+		if _, err := h.secretKeyUseCase.GetSecretKeyByID(key.ID); err != nil {
+			return
+		}
+
+		snapshot, err := h.togglesSnapshot(key.ApplicationID)
+		if err != nil {
+			// Erros temporários do banco mantêm o último snapshot enviado
+			continue
+		}
+		if snapshot.version != version {
+			writeSnapshotEvent(c, snapshot)
+			version = snapshot.version
+			lastWrite = time.Now()
+		} else if time.Since(lastWrite) >= DefaultStreamHeartbeat {
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			lastWrite = time.Now()
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeSnapshotEvent escreve o snapshot como um evento "snapshot" identificado pela versão
+func writeSnapshotEvent(c *gin.Context, snapshot *togglesSnapshot) {
+	fmt.Fprintf(c.Writer, "event: snapshot\nid: %s\ndata: %s\n\n", snapshot.version, snapshot.data)
+}
+
+// togglesSnapshot é o estado da aplicação enviado aos SDKs, já serializado, com a versão
+// calculada a partir do conteúdo
+type togglesSnapshot struct {
+	data    []byte
+	version string
+}
+
+// etag retorna a versão no formato do cabeçalho ETag
+func (s *togglesSnapshot) etag() string {
+	return `"` + s.version + `"`
+}
+
+// togglesSnapshot monta o snapshot com a aplicação, os toggles e os segmentos disponíveis
+func (h *SecretKeyHandler) togglesSnapshot(applicationID string) (*togglesSnapshot, error) {
+	// Buscar dados da aplicação
+	application, err := h.applicationUseCase.GetApplicationByID(applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve application: %w", err)
+	}
+
+	// Buscar todos os toggles da aplicação
+	toggles, err := h.toggleUseCase.GetAllTogglesByApp(applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve toggles: %w", err)
+	}
+
+	// Segmentos que as regras dos toggles podem referenciar, incluindo os globais
+	segments, err := h.segmentUseCase.GetAvailableSegments(applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve segments: %w", err)
+	}
+
+	// Simplificar toggles removendo children e parent. Com o kill switch ativo todos os
+	// toggles são enviados desligados, sem alterar o estado gravado de cada um.
+	// activation_rule é a projeção para SDKs que ainda não conhecem as regras compostas.
 	simplifiedToggles := make([]gin.H, 0, len(toggles))
 	for _, toggle := range toggles {
 		simplifiedToggle := gin.H{
 			"id":                toggle.ID,
 			"value":             toggle.Value,
-			"enabled":           toggle.Enabled,
+			"enabled":           toggle.Enabled && !application.KillSwitch,
 			"path":              toggle.Path,
 			"level":             toggle.Level,
 			"parent_id":         toggle.ParentID,
 			"app_id":            toggle.AppID,
 			"has_activation_rule": toggle.HasActivationRule,
-			"activation_rule":   toggle.ActivationRule,
+			"activation_rule":   toggle.LegacyActivationRule(),
+			"rules":             toggle.Rules,
+			"prerequisites":     toggle.Prerequisites,
+			"variants":          toggle.Variants,
+			"description":       toggle.Description,
+			"owner":             toggle.Owner,
+			"tags":              toggle.Tags,
+			"kind":              toggle.Kind,
+			"expires_at":        toggle.ExpiresAt,
 		}
 		simplifiedToggles = append(simplifiedToggles, simplifiedToggle)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	data, err := json.Marshal(gin.H{
 		"application": gin.H{
-			"id":      application.ID,
-			"name":    application.Name,
-			"toggles": simplifiedToggles,
+			"id":          application.ID,
+			"name":        application.Name,
+			"kill_switch": application.KillSwitch,
+			"toggles":     simplifiedToggles,
+			"segments":    segments,
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+	return &togglesSnapshot{data: data, version: hex.EncodeToString(hash[:16])}, nil
 }
 
-// GetSecretKeys retorna todas as secret keys de uma aplicação
-// GET /api/applications/{application_id}/secret-keys
+// GetSecretKeys retorna todas as secret keys de uma aplicação com a telemetria de uso
+// GET /api/applications/{application_id}/secret-keys?unused_days=30
 func (h *SecretKeyHandler) GetSecretKeys(c *gin.Context) {
 	applicationID := c.Param("id")
 	if applicationID == "" {
@@ -149,7 +299,19 @@ func (h *SecretKeyHandler) GetSecretKeys(c *gin.Context) {
 		return
 	}
 
-	secretKeys, err := h.secretKeyUseCase.GetSecretKeysByApplicationID(applicationID)
+	unusedDays := usecase.DefaultUnusedKeyDays
+	if raw := c.Query("unused_days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "unused_days must be a positive integer",
+			})
+			return
+		}
+		unusedDays = days
+	}
+
+	secretKeys, err := h.secretKeyUseCase.GetSecretKeysWithUsage(applicationID, unusedDays)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve secret keys: " + err.Error(),
@@ -160,6 +322,7 @@ func (h *SecretKeyHandler) GetSecretKeys(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success":     true,
 		"secret_keys": secretKeys,
+		"unused_days": unusedDays,
 	})
 }
 
@@ -6,10 +6,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -17,10 +17,7 @@ func setupSecretKeyTestRouter() (*gin.Engine, *gorm.DB) {
 	gin.SetMode(gin.TestMode)
 	
 	// Cria base de dados em memória para testes
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	
-	// Auto migrate tables
-	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.User{}, &entity.SecretKey{})
+	db := setupTestDB()
 	
 	// Inicializa handlers com a base de dados de teste
 	InitHandlers(db)
@@ -221,15 +218,68 @@ func TestGetTogglesBySecret_ValidSecret(t *testing.T) {
 	}
 }
 
+func TestGetTogglesBySecret_IncludesMetadata(t *testing.T) {
+	router, db := setupSecretKeyTestRouter()
+
+	db.Create(&entity.Application{ID: "test-app-id", Name: "Test App"})
+
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Test Secret",
+		ApplicationID: "test-app-id",
+		CreatedBy:     "test-user-id",
+	}
+	plainKey, _ := secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	toggle := entity.NewToggle("checkout", true, "checkout", 0, nil, "test-app-id")
+	toggle.SetMetadata(&entity.ToggleMetadata{
+		Description: "New checkout flow",
+		Owner:       "payments",
+		Tags:        []string{"q3"},
+		Kind:        entity.ToggleKindExperiment,
+		ExpiresAt:   &expiresAt,
+	})
+	db.Create(toggle)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Application struct {
+			Toggles []map[string]interface{} `json:"toggles"`
+		} `json:"application"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if len(response.Application.Toggles) != 1 {
+		t.Fatalf("Expected 1 toggle, got %d", len(response.Application.Toggles))
+	}
+	payload := response.Application.Toggles[0]
+	if payload["owner"] != "payments" || payload["kind"] != "experiment" || payload["description"] != "New checkout flow" {
+		t.Errorf("Expected metadata in SDK payload, got %v", payload)
+	}
+	if tags, ok := payload["tags"].([]interface{}); !ok || len(tags) != 1 || tags[0] != "q3" {
+		t.Errorf("Expected tags [q3], got %v", payload["tags"])
+	}
+	if payload["expires_at"] != "2030-01-01T00:00:00Z" {
+		t.Errorf("Expected expires_at 2030-01-01T00:00:00Z, got %v", payload["expires_at"])
+	}
+}
+
 func TestSecretKeyRegeneration(t *testing.T) {
 	// Create separate router for this test to avoid database conflicts
 	gin.SetMode(gin.TestMode)
 	
 	// Cria base de dados em memória para testes
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	
-	// Auto migrate tables
-	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.User{}, &entity.SecretKey{})
+	db := setupTestDB()
 	
 	// Inicializa handlers com a base de dados de teste
 	InitHandlers(db)
@@ -303,4 +353,158 @@ func TestSecretKeyRegeneration(t *testing.T) {
 	if w4.Code != http.StatusOK {
 		t.Errorf("Expected status 200 for new key, got %d", w4.Code)
 	}
-}
\ No newline at end of file
+}
+func TestGetSecretKeys_UsageTelemetry(t *testing.T) {
+	router, db := setupSecretKeyTestRouter()
+
+	app := &entity.Application{
+		ID:   "test-app-id",
+		Name: "Test App",
+	}
+	db.Create(app)
+
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Test Secret",
+		ApplicationID: "test-app-id",
+		CreatedBy:     "test-user-id",
+	}
+	plainKey, _ := secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	// Usa a chave duas vezes pelo caminho do SDK
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/toggles", nil)
+		req.Header.Set("X-API-Key", plainKey)
+		req.Header.Set("User-Agent", "totoggle-sdk/1.0")
+		req.RemoteAddr = "10.1.2.3:4567"
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/applications/test-app-id/secret-keys", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	keys := response["secret_keys"].([]interface{})
+	if len(keys) != 1 {
+		t.Fatalf("Expected 1 secret key, got %d", len(keys))
+	}
+
+	key := keys[0].(map[string]interface{})
+	if key["usage_count"].(float64) != 2 {
+		t.Errorf("Expected usage_count 2, got %v", key["usage_count"])
+	}
+	if key["last_used_ip"] != "10.1.2.3" {
+		t.Errorf("Expected last_used_ip 10.1.2.3, got %v", key["last_used_ip"])
+	}
+	if key["last_used_user_agent"] != "totoggle-sdk/1.0" {
+		t.Errorf("Expected last_used_user_agent totoggle-sdk/1.0, got %v", key["last_used_user_agent"])
+	}
+	if key["last_used_at"] == nil {
+		t.Error("Expected last_used_at to be present")
+	}
+	if key["unused"].(bool) {
+		t.Error("Expected recently used key not to be flagged as unused")
+	}
+}
+
+func TestGetSecretKeys_FlagsUnusedKeys(t *testing.T) {
+	router, db := setupSecretKeyTestRouter()
+
+	app := &entity.Application{
+		ID:   "test-app-id",
+		Name: "Test App",
+	}
+	db.Create(app)
+
+	lastUsed := time.Now().UTC().AddDate(0, 0, -10)
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Old Secret",
+		ApplicationID: "test-app-id",
+		CreatedBy:     "test-user-id",
+		LastUsedAt:    &lastUsed,
+	}
+	secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+		expectedUnused bool
+	}{
+		{query: "?unused_days=7", expectedStatus: http.StatusOK, expectedUnused: true},
+		{query: "", expectedStatus: http.StatusOK, expectedUnused: false},
+		{query: "?unused_days=abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/applications/test-app-id/secret-keys"+tt.query, nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.query, tt.expectedStatus, w.Code)
+			continue
+		}
+		if tt.expectedStatus != http.StatusOK {
+			continue
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		key := response["secret_keys"].([]interface{})[0].(map[string]interface{})
+		if key["unused"].(bool) != tt.expectedUnused {
+			t.Errorf("%s: expected unused %v, got %v", tt.query, tt.expectedUnused, key["unused"])
+		}
+	}
+}
+
+func TestShutdown_FlushesSecretKeyUsage(t *testing.T) {
+	router, db := setupSecretKeyTestRouter()
+
+	db.Create(&entity.Application{ID: "test-app-id", Name: "Test App"})
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Test Secret",
+		ApplicationID: "test-app-id",
+		CreatedBy:     "test-user-id",
+	}
+	plainKey, _ := secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	// O uso fica em memória até o próximo flush periódico
+	var stored entity.SecretKey
+	db.First(&stored, "id = ?", "test-secret-id")
+	if stored.UsageCount != 0 {
+		t.Fatalf("Expected usage to be pending before shutdown, got %d", stored.UsageCount)
+	}
+
+	Shutdown()
+
+	db.First(&stored, "id = ?", "test-secret-id")
+	if stored.UsageCount != 1 || stored.LastUsedAt == nil {
+		t.Errorf("Expected shutdown to flush the pending usage, got count %d", stored.UsageCount)
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// SegmentHandler gerencia as requisições HTTP para segmentos.
+// As rotas em /applications/:id/segments operam sobre os segmentos da aplicação
+// e as rotas em /segments sobre os segmentos globais.
+type SegmentHandler struct {
+	segmentUseCase *usecase.SegmentUseCase
+}
+
+// NewSegmentHandler cria uma nova instância de SegmentHandler
+func NewSegmentHandler(segmentUseCase *usecase.SegmentUseCase) *SegmentHandler {
+	return &SegmentHandler{
+		segmentUseCase: segmentUseCase,
+	}
+}
+
+// SegmentRequest representa a requisição para criar ou atualizar um segmento
+type SegmentRequest struct {
+	Name        string                    `json:"name" binding:"required"`
+	Description string                    `json:"description"`
+	Constraints entity.SegmentConstraints `json:"constraints"`
+}
+
+// CreateSegment cria um segmento
+// POST /applications/:id/segments | POST /segments
+func (h *SegmentHandler) CreateSegment(c *gin.Context) {
+	var req SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("request", "Invalid request body")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	segment, err := h.segmentUseCase.CreateSegment(segmentScope(c), req.Name, req.Description, req.Constraints, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, segment)
+}
+
+// GetAllSegments lista os segmentos do escopo
+// GET /applications/:id/segments | GET /segments
+func (h *SegmentHandler) GetAllSegments(c *gin.Context) {
+	segments, err := h.segmentUseCase.ListSegments(segmentScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"segments": segments,
+	})
+}
+
+// GetSegment busca um segmento por ID
+// GET /applications/:id/segments/:segmentId | GET /segments/:segmentId
+func (h *SegmentHandler) GetSegment(c *gin.Context) {
+	segment, err := h.segmentUseCase.GetSegment(c.Param("segmentId"), segmentScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// UpdateSegment atualiza um segmento
+// PUT /applications/:id/segments/:segmentId | PUT /segments/:segmentId
+func (h *SegmentHandler) UpdateSegment(c *gin.Context) {
+	var req SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("request", "Invalid request body")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	segment, err := h.segmentUseCase.UpdateSegment(c.Param("segmentId"), segmentScope(c), req.Name, req.Description, req.Constraints, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// DeleteSegment remove um segmento; falha com 409 enquanto alguma regra o referencia
+// DELETE /applications/:id/segments/:segmentId | DELETE /segments/:segmentId
+func (h *SegmentHandler) DeleteSegment(c *gin.Context) {
+	if err := h.segmentUseCase.DeleteSegment(c.Param("segmentId"), segmentScope(c), currentUser(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "segment deleted successfully",
+	})
+}
+
+// segmentScope retorna a aplicação da rota, ou nil nas rotas de segmentos globais
+func segmentScope(c *gin.Context) *string {
+	if appID := c.Param("id"); appID != "" {
+		return &appID
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+const segmentTestAppID = "01JZNM42NKSANGHZ3G4KKXGCNW"
+
+func setupSegmentTestRouter() (*gin.Engine, string) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTestDB()
+
+	InitHandlers(db)
+
+	db.Create(&entity.Application{ID: segmentTestAppID, Name: "Test App"})
+	db.Create(entity.NewToggle("button", true, "button", 1, nil, segmentTestAppID))
+
+	secretKey := &entity.SecretKey{
+		ID:            "test-secret-id",
+		Name:          "Test Secret",
+		ApplicationID: segmentTestAppID,
+		CreatedBy:     "test-user-id",
+	}
+	plainKey, _ := secretKey.SetSecretKey()
+	db.Create(secretKey)
+
+	router := gin.New()
+	router.POST("/applications/:id/segments", CreateSegment)
+	router.GET("/applications/:id/segments", GetAllSegments)
+	router.PUT("/applications/:id/segments/:segmentId", UpdateSegment)
+	router.DELETE("/applications/:id/segments/:segmentId", DeleteSegment)
+	router.POST("/segments", CreateSegment)
+	router.GET("/segments/:segmentId", GetSegment)
+	router.DELETE("/segments/:segmentId", DeleteSegment)
+	router.PUT("/applications/:id/toggles/:toggleId", UpdateToggle)
+	router.GET("/applications/:id/toggles", GetAllToggles)
+	router.GET("/api/toggles", GetTogglesBySecret)
+
+	return router, plainKey
+}
+
+func performSegmentRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSegmentHandler_CRUD(t *testing.T) {
+	router, _ := setupSegmentTestRouter()
+	appPath := "/applications/" + segmentTestAppID + "/segments"
+
+	body := `{"name": "Beta testers", "constraints": [{"attribute": "user_id", "operator": "in", "values": ["u1", "u2"]}]}`
+	w := performSegmentRequest(router, "POST", appPath, body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created entity.Segment
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.AppID == nil || *created.AppID != segmentTestAppID {
+		t.Errorf("Expected application segment, got %s", w.Body.String())
+	}
+
+	if w := performSegmentRequest(router, "POST", appPath, body); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicated name, got %d", w.Code)
+	}
+	if w := performSegmentRequest(router, "POST", appPath, `{"name": "Empty", "constraints": []}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without constraints, got %d", w.Code)
+	}
+
+	update := `{"name": "Beta testers", "constraints": [{"attribute": "app_version", "operator": "semver_gte", "values": ["2.0.0"]}]}`
+	if w := performSegmentRequest(router, "PUT", appPath+"/"+created.ID, update); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performSegmentRequest(router, "GET", appPath, "")
+	var list struct {
+		Segments []*entity.Segment `json:"segments"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Segments) != 1 || list.Segments[0].Constraints[0].Operator != entity.SegmentOperatorSemverGte {
+		t.Errorf("Expected updated segment in list, got %s", w.Body.String())
+	}
+
+	// Segmentos da aplicação não são acessíveis pelas rotas globais
+	if w := performSegmentRequest(router, "GET", "/segments/"+created.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 from global route, got %d", w.Code)
+	}
+
+	if w := performSegmentRequest(router, "DELETE", appPath+"/"+created.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestSegmentHandler_ReferencedSegment(t *testing.T) {
+	router, plainKey := setupSegmentTestRouter()
+
+	w := performSegmentRequest(router, "POST", "/segments", `{"name": "Internal", "constraints": [{"attribute": "ip", "operator": "cidr", "values": ["10.0.0.0/8"]}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var segment entity.Segment
+	json.Unmarshal(w.Body.Bytes(), &segment)
+
+	w = performSegmentRequest(router, "GET", "/applications/"+segmentTestAppID+"/toggles", "")
+	var toggles []*entity.Toggle
+	json.Unmarshal(w.Body.Bytes(), &toggles)
+	if len(toggles) != 1 {
+		t.Fatalf("Expected 1 toggle, got %s", w.Body.String())
+	}
+	togglePath := "/applications/" + segmentTestAppID + "/toggles/" + toggles[0].ID
+
+	rule := `{"enabled": true, "rules": [{"conditions": [{"type": "segment", "value": "` + segment.ID + `"}]}]}`
+	if w := performSegmentRequest(router, "PUT", togglePath, rule); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	unknown := `{"enabled": true, "rules": [{"conditions": [{"type": "segment", "value": "01JZNM42NKSANGHZ3G4KKXGCNX"}]}]}`
+	if w := performSegmentRequest(router, "PUT", togglePath, unknown); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unknown segment, got %d", w.Code)
+	}
+
+	w = performSegmentRequest(router, "DELETE", "/segments/"+segment.ID, "")
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 while referenced, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// O payload do SDK inclui os segmentos disponíveis para a aplicação
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/toggles", nil)
+	req.Header.Set("X-API-Key", plainKey)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Application struct {
+			Segments []*entity.Segment `json:"segments"`
+		} `json:"application"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Application.Segments) != 1 || response.Application.Segments[0].ID != segment.ID {
+		t.Errorf("Expected segments in SDK payload, got %s", w.Body.String())
+	}
+}
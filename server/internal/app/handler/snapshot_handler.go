@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// SnapshotHandler gerencia as requisições HTTP para snapshots de aplicações
+type SnapshotHandler struct {
+	snapshotUseCase *usecase.SnapshotUseCase
+}
+
+// NewSnapshotHandler cria uma nova instância de SnapshotHandler
+func NewSnapshotHandler(snapshotUseCase *usecase.SnapshotUseCase) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotUseCase: snapshotUseCase,
+	}
+}
+
+// CreateSnapshotRequest representa a requisição para criar um snapshot
+type CreateSnapshotRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateSnapshot grava o estado atual de todos os toggles da aplicação
+// POST /applications/:id/snapshots
+func (h *SnapshotHandler) CreateSnapshot(c *gin.Context) {
+	var req CreateSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("name", "Snapshot name is required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	snapshot, err := h.snapshotUseCase.CreateSnapshot(c.Param("id"), req.Name, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// GetSnapshots lista os snapshots da aplicação
+// GET /applications/:id/snapshots
+func (h *SnapshotHandler) GetSnapshots(c *gin.Context) {
+	snapshots, err := h.snapshotUseCase.ListSnapshots(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"snapshots": snapshots,
+	})
+}
+
+// RestoreSnapshot substitui os toggles da aplicação pelos do snapshot
+// POST /applications/:id/snapshots/:snapshotId/restore
+func (h *SnapshotHandler) RestoreSnapshot(c *gin.Context) {
+	if err := h.snapshotUseCase.RestoreSnapshot(c.Param("id"), c.Param("snapshotId"), currentUser(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "snapshot restored successfully",
+	})
+}
@@ -59,6 +59,13 @@ func isAPIRoute(path string) bool {
 		return true
 	}
 	
+	// Demais rotas globais da API
+	for _, prefix := range []string{"/search", "/segments", "/trash", "/freeze-windows", "/sync"} {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	
 	// Rota base de applications
 	if path == "/applications" {
 		return true
@@ -86,14 +93,9 @@ func isAPIRoute(path string) bool {
 				}
 			}
 			
-			// Se tem apenas o ID: /applications/{id} (assumindo que IDs não são palavras reservadas)
-			if len(parts) == 1 {
-				return true
-			}
-			// Se tem ID e "toggles": /applications/{id}/toggles
-			if len(parts) >= 2 && parts[1] == "toggles" {
-				return true
-			}
+			// /applications/{id} e todos os recursos da aplicação são da API,
+			// assumindo que IDs não são palavras reservadas
+			return true
 		}
 		// Caso contrário, assumir que é SPA route
 		return false
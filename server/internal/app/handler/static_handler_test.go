@@ -89,6 +89,21 @@ func TestIsAPIRoute(t *testing.T) {
 		{"/applications", true},
 		{"/applications/123", true},
 		{"/applications/123/toggles", true},
+		{"/applications/123/toggle/456", true},
+		{"/applications/123/reports/stale", true},
+		{"/search", true},
+		{"/applications/123/segments", true},
+		{"/segments/789", true},
+		{"/applications/123/explain", true},
+		{"/applications/123/snapshots", true},
+		{"/applications/123/trash", true},
+		{"/trash/applications", true},
+		{"/applications/123/kill-switch", true},
+		{"/applications/123/audit", true},
+		{"/applications/123/freeze-windows", true},
+		{"/freeze-windows", true},
+		{"/applications/123/toggles:batch", true},
+		{"/sync", true},
 		{"/api/test", true},
 		{"/health", true},
 		
@@ -101,6 +116,9 @@ func TestIsAPIRoute(t *testing.T) {
 		{"/auth/logout", false},
 		{"/dashboard", false},
 		{"/some-spa-route", false},
+		{"/applications/view", false},
+		{"/searching", false},
+		{"/synchronize", false},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// MaxSyncFileSize é o tamanho máximo do arquivo de flags aceito pela API
+const MaxSyncFileSize = 1 << 20
+
+// SyncHandler gerencia as requisições HTTP da sincronização declarativa
+type SyncHandler struct {
+	syncUseCase *usecase.SyncUseCase
+}
+
+// NewSyncHandler cria uma nova instância de SyncHandler
+func NewSyncHandler(syncUseCase *usecase.SyncUseCase) *SyncHandler {
+	return &SyncHandler{
+		syncUseCase: syncUseCase,
+	}
+}
+
+// Sync aplica o arquivo de flags enviado no corpo, em YAML ou JSON, e retorna o plano.
+// Com dry_run=true o plano é apenas calculado. Se uma alteração falha, o plano volta com o
+// status do erro dessa alteração e o resultado de cada uma.
+// POST /sync
+func (h *SyncHandler) Sync(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, MaxSyncFileSize+1))
+	if err != nil || len(body) > MaxSyncFileSize {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("file", "The flags file must be at most 1 MiB")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	file, err := entity.ParseSyncFile(body)
+	if err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("file", err.Error())
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	plan, err := h.syncUseCase.Sync(file, c.Query("dry_run") == "true", currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if failed := plan.Failed(); failed != nil {
+		status = errorStatus(failed.Error)
+	}
+	c.JSON(status, plan)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+const syncHandlerTestFile = `
+applications:
+  - name: Shop
+    segments:
+      - name: beta
+        constraints:
+          - attribute: country
+            operator: in
+            values: [BR]
+    toggles:
+      - path: checkout.new-flow
+        enabled: false
+        variants:
+          - name: json
+            payload_type: json
+            payload: {"color": "blue", "size": 2}
+            weight: 100
+        rules:
+          - conditions:
+              - type: segment
+                value: beta
+              - type: country
+                value: br, pt
+`
+
+func TestSyncHandler_Sync(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	InitHandlersWithOptions(db, Options{ManagedPolicy: entity.ManagedPolicyBlock})
+
+	user := &entity.User{ID: "root1", Username: "root", Role: entity.UserRoleRoot}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", user)
+		c.Next()
+	})
+	router.POST("/sync", Sync)
+	router.PUT("/applications/:id/toggles/:toggleId", UpdateToggle)
+
+	sync := func(url, body string) (*httptest.ResponseRecorder, *entity.SyncPlan) {
+		req, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var plan entity.SyncPlan
+		json.Unmarshal(w.Body.Bytes(), &plan)
+		return w, &plan
+	}
+
+	w, plan := sync("/sync?dry_run=true", syncHandlerTestFile)
+	if w.Code != http.StatusOK || plan.Applied || len(plan.Changes) != 3 {
+		t.Fatalf("Expected a dry run plan with 3 changes, got %d: %s", w.Code, w.Body.String())
+	}
+	var count int64
+	db.Model(&entity.Application{}).Count(&count)
+	if count != 0 {
+		t.Fatal("Expected dry run to leave the database untouched")
+	}
+
+	w, plan = sync("/sync", syncHandlerTestFile)
+	if w.Code != http.StatusOK || !plan.Applied || plan.Count(entity.SyncActionCreate) != 3 {
+		t.Fatalf("Expected the plan to be applied, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// As regras e variantes lidas do banco coincidem com o arquivo
+	w, plan = sync("/sync", syncHandlerTestFile)
+	if w.Code != http.StatusOK || len(plan.Changes) != 0 {
+		t.Fatalf("Expected an empty plan on the second sync, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var toggle entity.Toggle
+	db.Where("path = ?", "checkout.new-flow").First(&toggle)
+	if !toggle.Managed {
+		t.Fatalf("Expected the toggle to be managed, got %+v", toggle)
+	}
+	data, _ := json.Marshal(gin.H{"enabled": true})
+	req, _ := http.NewRequest("PUT", "/applications/"+toggle.AppID+"/toggles/"+toggle.ID, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var appErr entity.AppError
+	json.Unmarshal(w.Body.Bytes(), &appErr)
+	if w.Code != http.StatusConflict || appErr.Code != entity.ErrCodeManaged {
+		t.Errorf("Expected manual change of a managed toggle to be blocked, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Uma alteração bloqueada devolve o plano com o status do erro e o resultado de cada alteração
+	now := time.Now()
+	db.Create(entity.NewFreezeWindow(&toggle.AppID, now.Add(-time.Hour), now.Add(time.Hour), "launch", nil, nil))
+	w, plan = sync("/sync", strings.Replace(syncHandlerTestFile, "enabled: false", "enabled: true", 1))
+	if w.Code != http.StatusLocked || len(plan.Changes) != 1 || plan.Changes[0].Status != entity.SyncStatusFailed || plan.Applied {
+		t.Errorf("Expected status 423 with the failed change, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w, _ := sync("/sync", "applications:\n  - name: Shop\n    extra: true\n"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown field, got %d", w.Code)
+	}
+	if w, _ := sync("/sync", "applications:\n  - name: Shop\n    toggles:\n      - path: a\n      - path: a\n"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a duplicated toggle, got %d", w.Code)
+	}
+}
+
+// Um toggle declarado desligado e sem regras é criado desligado, e aplicar de novo não muda nada
+func TestSyncHandler_SyncDisabledToggle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	InitHandlersWithOptions(db, Options{ManagedPolicy: entity.ManagedPolicyBlock})
+
+	user := &entity.User{ID: "root1", Username: "root", Role: entity.UserRoleRoot}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", user)
+		c.Next()
+	})
+	router.POST("/sync", Sync)
+
+	file := "applications:\n  - name: Shop\n    toggles:\n      - path: checkout.legacy\n        enabled: false\n"
+	sync := func() *entity.SyncPlan {
+		req, _ := http.NewRequest("POST", "/sync", bytes.NewBufferString(file))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var plan entity.SyncPlan
+		json.Unmarshal(w.Body.Bytes(), &plan)
+		return &plan
+	}
+
+	if plan := sync(); plan.Count(entity.SyncActionCreate) != 2 {
+		t.Fatalf("Expected the application and the toggle to be created, got %+v", plan.Changes)
+	}
+	var toggle entity.Toggle
+	db.Where("path = ?", "checkout.legacy").First(&toggle)
+	if toggle.Enabled {
+		t.Error("Expected the toggle declared disabled to be created disabled")
+	}
+	if plan := sync(); len(plan.Changes) != 0 {
+		t.Errorf("Expected an empty plan on the second sync, got %+v", plan.Changes[0])
+	}
+}
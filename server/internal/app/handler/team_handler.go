@@ -90,6 +90,18 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 
 // GetAllTeams lista todos os times com contagens
 func (h *TeamHandler) GetAllTeams(c *gin.Context) {
+	// Paginação opcional por cursor (?limit=&cursor=&sort=&order=&name=)
+	page, pageErr := parsePageRequest(c, entity.TeamListFields)
+	if pageErr != nil {
+		c.JSON(http.StatusBadRequest, pageErr)
+		return
+	}
+	if page != nil {
+		result, err := h.teamUseCase.ListTeamsWithCounts(page)
+		respondPage(c, result, err)
+		return
+	}
+
 	teams, err := h.teamUseCase.GetAllTeamsWithCounts()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, TeamsResponse{
@@ -282,6 +294,18 @@ func (h *TeamHandler) GetTeamUsers(c *gin.Context) {
 		return
 	}
 
+	// Paginação opcional por cursor (?limit=&cursor=&sort=&order=&username=&role=)
+	page, pageErr := parsePageRequest(c, entity.UserListFields)
+	if pageErr != nil {
+		c.JSON(http.StatusBadRequest, pageErr)
+		return
+	}
+	if page != nil {
+		result, err := h.teamUseCase.ListTeamUsers(teamID, page)
+		respondPage(c, result, err)
+		return
+	}
+
 	users, err := h.teamUseCase.GetTeamUsers(teamID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
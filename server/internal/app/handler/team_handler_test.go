@@ -9,7 +9,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -17,11 +16,7 @@ func setupTeamTestRouter() (*gin.Engine, *gorm.DB) {
 	gin.SetMode(gin.TestMode)
 	
 	// Cria base de dados em memória para testes
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	
-	// Auto migrate tables
-	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.User{}, &entity.SecretKey{}, 
-		&entity.Team{}, &entity.TeamUser{}, &entity.TeamApplication{})
+	db := setupTestDB()
 	
 	// Inicializa handlers com a base de dados de teste
 	InitHandlers(db)
@@ -380,4 +375,71 @@ func TestAddApplicationToTeam_Success(t *testing.T) {
 	if teamApp.Permission != entity.PermissionWrite {
 		t.Errorf("Expected permission 'write', got %s", teamApp.Permission)
 	}
-}
\ No newline at end of file
+}
+func TestGetAllTeams_Pagination(t *testing.T) {
+	router, db := setupTeamTestRouter()
+
+	for _, name := range []string{"Alpha", "Beta", "Gamma"} {
+		db.Create(&entity.Team{Name: name})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/teams?limit=2&sort=name", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var page entity.Page[*entity.TeamWithCounts]
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if len(page.Items) != 2 || page.Total != 3 || page.NextCursor == "" {
+		t.Fatalf("Unexpected first page %s", w.Body.String())
+	}
+	if page.Items[0].Name != "Alpha" || page.Items[1].Name != "Beta" {
+		t.Errorf("Expected Alpha and Beta, got %s and %s", page.Items[0].Name, page.Items[1].Name)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/teams?limit=2&sort=name&cursor="+page.NextCursor, nil)
+	router.ServeHTTP(w, req)
+
+	page = entity.Page[*entity.TeamWithCounts]{}
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if len(page.Items) != 1 || page.Items[0].Name != "Gamma" || page.NextCursor != "" {
+		t.Errorf("Unexpected second page %s", w.Body.String())
+	}
+}
+
+func TestGetTeamUsers_Pagination(t *testing.T) {
+	router, db := setupTeamTestRouter()
+
+	team := &entity.Team{Name: "Core"}
+	db.Create(team)
+	for _, username := range []string{"ana", "bob", "carla"} {
+		user := &entity.User{Username: username, Password: "hash", Role: entity.UserRoleUser}
+		db.Create(user)
+		db.Create(&entity.TeamUser{TeamID: team.ID, UserID: user.ID})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/teams/"+team.ID+"/users?limit=2&username=a", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var page entity.Page[*entity.User]
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if page.Total != 2 || len(page.Items) != 2 || page.Items[0].Username != "ana" {
+		t.Errorf("Unexpected page %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/teams/missing/users?limit=2", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
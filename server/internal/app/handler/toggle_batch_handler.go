@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// ToggleBatchHandler gerencia as requisições HTTP de operações em lote sobre toggles
+type ToggleBatchHandler struct {
+	toggleBatchUseCase *usecase.ToggleBatchUseCase
+}
+
+// NewToggleBatchHandler cria uma nova instância de ToggleBatchHandler
+func NewToggleBatchHandler(toggleBatchUseCase *usecase.ToggleBatchUseCase) *ToggleBatchHandler {
+	return &ToggleBatchHandler{
+		toggleBatchUseCase: toggleBatchUseCase,
+	}
+}
+
+// ToggleBatchRequest representa a requisição de um lote; o modo padrão é atomic
+type ToggleBatchRequest struct {
+	Mode       string                   `json:"mode"`
+	Operations []*entity.BatchOperation `json:"operations" binding:"required"`
+}
+
+// ApplyBatch aplica um lote de operações sobre os toggles da aplicação.
+// O gin registra o sufixo ":batch" como parâmetro, então outros sufixos retornam 404.
+// POST /applications/:id/toggles:batch
+func (h *ToggleBatchHandler) ApplyBatch(c *gin.Context) {
+	if c.Param("batch") != ":batch" {
+		c.JSON(http.StatusNotFound, entity.NewAppError(entity.ErrCodeNotFound, "route not found"))
+		return
+	}
+
+	var req ToggleBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("operations", "Operations are required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	mode, err := entity.ParseBatchMode(req.Mode)
+	if err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("mode", err.Error())
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	result, err := h.toggleBatchUseCase.ApplyBatch(c.Param("id"), mode, req.Operations, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Um lote atômico com falhas não grava nada
+	status := http.StatusOK
+	if mode == entity.BatchModeAtomic && result.Failed() > 0 {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, result)
+}
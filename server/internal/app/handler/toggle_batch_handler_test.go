@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+func TestToggleBatchHandler_ApplyBatch(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Enabled: true}
+
+	toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
+	handler := NewToggleBatchHandler(usecase.NewToggleBatchUseCase(toggleMock, appMock, toggleUseCase))
+	router.POST("/applications/:id/toggles", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.POST("/applications/:id/toggles:batch", handler.ApplyBatch)
+
+	request := func(url string, body interface{}) (*httptest.ResponseRecorder, *entity.BatchResult) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", url, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var result entity.BatchResult
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return w, &result
+	}
+
+	operations := []gin.H{
+		{"op": "disable", "path": "checkout"},
+		{"op": "create", "path": "launch.banner"},
+		{"op": "enable", "path": "missing"},
+	}
+
+	w, result := request("/applications/app123/toggles:batch", gin.H{"operations": operations})
+	if w.Code != http.StatusBadRequest || result.Applied || len(result.Results) != 3 || result.Results[2].Error.Code != entity.ErrCodeNotFound {
+		t.Fatalf("Expected atomic batch to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+	if !toggleMock.Toggles["toggle1"].Enabled {
+		t.Error("Expected nothing to be applied")
+	}
+
+	w, result = request("/applications/app123/toggles:batch", gin.H{"mode": "best_effort", "operations": operations})
+	if w.Code != http.StatusOK || !result.Applied || result.Results[0].Status != entity.BatchStatusApplied || result.Results[2].Status != entity.BatchStatusFailed {
+		t.Fatalf("Expected best effort batch to be applied, got %d: %s", w.Code, w.Body.String())
+	}
+	if toggleMock.Toggles["toggle1"].Enabled || len(toggleMock.Toggles) != 3 {
+		t.Errorf("Expected checkout disabled and the banner created, got %d toggles", len(toggleMock.Toggles))
+	}
+
+	if w, _ := request("/applications/app123/toggles:batch", gin.H{"mode": "partial", "operations": operations}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown mode, got %d", w.Code)
+	}
+	if w, _ := request("/applications/app123/toggles:other", gin.H{"operations": operations}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for another suffix, got %d", w.Code)
+	}
+	if w, _ := request("/applications/missing/toggles:batch", gin.H{"operations": operations}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown application, got %d", w.Code)
+	}
+	if w, _ := request("/applications/app123/toggles", gin.H{"toggle": "other"}); w.Code != http.StatusCreated {
+		t.Errorf("Expected the create route to keep working, got %d", w.Code)
+	}
+}
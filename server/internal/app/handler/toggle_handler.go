@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
@@ -23,6 +24,7 @@ func NewToggleHandler(toggleUseCase *usecase.ToggleUseCase) *ToggleHandler {
 // CreateToggleRequest representa a requisição para criar um toggle
 type CreateToggleRequest struct {
 	Toggle string `json:"toggle" binding:"required"`
+	entity.ToggleMetadata
 }
 
 // UpdateToggleRequest representa a requisição para atualizar um toggle
@@ -30,6 +32,8 @@ type UpdateToggleRequest struct {
 	Enabled           bool                     `json:"enabled"`
 	HasActivationRule bool                     `json:"has_activation_rule"`
 	ActivationRule    *entity.ActivationRule   `json:"activation_rule,omitempty"`
+	Rules             []*entity.ToggleRule     `json:"rules,omitempty"`    // Regras compostas; substituem activation_rule quando informadas
+	Variants          entity.ToggleVariants    `json:"variants,omitempty"` // Ausente mantém as variantes atuais
 }
 
 // ToggleStatusResponse representa a resposta do status de um toggle
@@ -75,18 +79,9 @@ func (h *ToggleHandler) CreateToggle(c *gin.Context) {
 		return
 	}
 
-	err := h.toggleUseCase.CreateToggle(req.Toggle, true, true, appID)
+	err := h.toggleUseCase.CreateToggleWithMetadata(req.Toggle, true, true, appID, &req.ToggleMetadata, currentUser(c))
 	if err != nil {
-		appErr, ok := err.(*entity.AppError)
-		if ok {
-			status := http.StatusBadRequest
-			if appErr.Code == entity.ErrCodeNotFound {
-				status = http.StatusNotFound
-			}
-			c.JSON(status, appErr)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		respondError(c, err)
 		return
 	}
 
@@ -132,16 +127,7 @@ func (h *ToggleHandler) GetToggleStatus(c *gin.Context) {
 
 	toggle, err := h.toggleUseCase.GetToggleByID(toggleID, appID)
 	if err != nil {
-		appErr, ok := err.(*entity.AppError)
-		if ok {
-			status := http.StatusBadRequest
-			if appErr.Code == entity.ErrCodeNotFound {
-				status = http.StatusNotFound
-			}
-			c.JSON(status, appErr)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		respondError(c, err)
 		return
 	}
 
@@ -172,24 +158,87 @@ func (h *ToggleHandler) UpdateToggle(c *gin.Context) {
 		return
 	}
 
-	err := h.toggleUseCase.UpdateToggleWithRule(toggleID, req.Enabled, req.HasActivationRule, req.ActivationRule, appID)
+	err := h.toggleUseCase.UpdateToggleWithRule(toggleID, req.Enabled, req.HasActivationRule, req.ActivationRule, req.Rules, req.Variants, appID, currentUser(c))
 	if err != nil {
-		appErr, ok := err.(*entity.AppError)
-		if ok {
-			status := http.StatusBadRequest
-			if appErr.Code == entity.ErrCodeNotFound {
-				status = http.StatusNotFound
-			}
-			c.JSON(status, appErr)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "toggle updated successfully"})
 }
 
+// UpdateToggleMetadata atualiza os metadados de um toggle por ID
+// PUT /applications/:id/toggles/:toggleId/metadata
+func (h *ToggleHandler) UpdateToggleMetadata(c *gin.Context) {
+	appID := c.Param("id")
+	toggleID := c.Param("toggleId")
+	if appID == "" || toggleID == "" {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		if appID == "" {
+			appErr.AddDetail("appID", "Application ID is required")
+		}
+		if toggleID == "" {
+			appErr.AddDetail("toggleID", "Toggle ID is required")
+		}
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	var req entity.ToggleMetadata
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("request", "Invalid request body")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	err := h.toggleUseCase.UpdateToggleMetadata(toggleID, appID, &req, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "toggle metadata updated successfully"})
+}
+
+// UpdateTogglePrerequisitesRequest representa a requisição para substituir os pré-requisitos de um toggle
+type UpdateTogglePrerequisitesRequest struct {
+	Prerequisites []*entity.TogglePrerequisite `json:"prerequisites"`
+}
+
+// UpdateTogglePrerequisites substitui os pré-requisitos de um toggle
+func (h *ToggleHandler) UpdateTogglePrerequisites(c *gin.Context) {
+	appID := c.Param("id")
+	toggleID := c.Param("toggleId")
+	if appID == "" || toggleID == "" {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		if appID == "" {
+			appErr.AddDetail("appID", "Application ID is required")
+		}
+		if toggleID == "" {
+			appErr.AddDetail("toggleID", "Toggle ID is required")
+		}
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	var req UpdateTogglePrerequisitesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("request", "Invalid request body")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	err := h.toggleUseCase.UpdateTogglePrerequisites(toggleID, appID, req.Prerequisites, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "toggle prerequisites updated successfully"})
+}
+
 // DeleteToggle remove um toggle por ID
 func (h *ToggleHandler) DeleteToggle(c *gin.Context) {
 	appID := c.Param("id")
@@ -206,18 +255,9 @@ func (h *ToggleHandler) DeleteToggle(c *gin.Context) {
 		return
 	}
 
-	err := h.toggleUseCase.DeleteToggleByID(toggleID, appID)
+	err := h.toggleUseCase.DeleteToggleByID(toggleID, appID, currentUser(c))
 	if err != nil {
-		appErr, ok := err.(*entity.AppError)
-		if ok {
-			status := http.StatusBadRequest
-			if appErr.Code == entity.ErrCodeNotFound {
-				status = http.StatusNotFound
-			}
-			c.JSON(status, appErr)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		respondError(c, err)
 		return
 	}
 
@@ -237,22 +277,40 @@ func (h *ToggleHandler) GetAllToggles(c *gin.Context) {
 		return
 	}
 
+	// Paginação opcional por cursor (?limit=&cursor=&sort=&order=&path=&enabled=&owner=&kind=&tag=)
+	page, pageErr := parsePageRequest(c, entity.ToggleListFields)
+	if pageErr != nil {
+		c.JSON(http.StatusBadRequest, pageErr)
+		return
+	}
+	if page != nil {
+		result, err := h.toggleUseCase.ListToggles(appID, page)
+		respondPage(c, result, err)
+		return
+	}
+
+	// Filtros opcionais por metadados: ?tag=a&tag=b&owner=x&kind=release
+	filter := &entity.ToggleFilter{
+		Tags:  c.QueryArray("tag"),
+		Owner: c.Query("owner"),
+		Kind:  entity.ToggleKind(c.Query("kind")),
+	}
+
 	// Verifica se quer a hierarquia ou lista simples
 	hierarchy := c.Query("hierarchy") == "true"
 
 	if hierarchy {
+		// A hierarquia sempre traz a árvore completa, então não combina com os filtros
+		if !filter.IsEmpty() {
+			appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+			appErr.AddDetail("hierarchy", "hierarchy cannot be combined with the tag, owner or kind filters")
+			c.JSON(http.StatusBadRequest, appErr)
+			return
+		}
+
 		hierarchyArr, err := h.toggleUseCase.GetToggleHierarchy(appID)
 		if err != nil {
-			appErr, ok := err.(*entity.AppError)
-			if ok {
-				status := http.StatusBadRequest
-				if appErr.Code == entity.ErrCodeNotFound {
-					status = http.StatusNotFound
-				}
-				c.JSON(status, appErr)
-				return
-			}
-			c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+			respondError(c, err)
 			return
 		}
 
@@ -263,18 +321,9 @@ func (h *ToggleHandler) GetAllToggles(c *gin.Context) {
 		return
 	}
 
-	toggles, err := h.toggleUseCase.GetAllTogglesByApp(appID)
+	toggles, err := h.toggleUseCase.GetTogglesByFilter(appID, filter)
 	if err != nil {
-		appErr, ok := err.(*entity.AppError)
-		if ok {
-			status := http.StatusBadRequest
-			if appErr.Code == entity.ErrCodeNotFound {
-				status = http.StatusNotFound
-			}
-			c.JSON(status, appErr)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		respondError(c, err)
 		return
 	}
 
@@ -305,20 +354,62 @@ func (h *ToggleHandler) UpdateEnabled(c *gin.Context) {
 		return
 	}
 
-	err := h.toggleUseCase.UpdateEnabledRecursively(toggleID, req.Enabled, appID)
+	err := h.toggleUseCase.UpdateEnabledRecursively(toggleID, req.Enabled, appID, currentUser(c))
 	if err != nil {
-		appErr, ok := err.(*entity.AppError)
-		if ok {
-			status := http.StatusBadRequest
-			if appErr.Code == entity.ErrCodeNotFound {
-				status = http.StatusNotFound
-			}
-			c.JSON(status, appErr)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, entity.NewAppError(entity.ErrCodeInternal, "internal server error"))
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "toggle enabled updated successfully"})
 }
+
+// GetToggleHistory lista as revisões de um toggle, da mais recente para a mais antiga
+// GET /applications/:id/toggles/:toggleId/history
+func (h *ToggleHandler) GetToggleHistory(c *gin.Context) {
+	revisions, err := h.toggleUseCase.GetToggleHistory(c.Param("toggleId"), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+	})
+}
+
+// RollbackToggle restaura o estado de uma revisão do toggle, gerando uma nova revisão
+// POST /applications/:id/toggles/:toggleId/rollback?revision=N
+func (h *ToggleHandler) RollbackToggle(c *gin.Context) {
+	revision, err := strconv.Atoi(c.Query("revision"))
+	if err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("revision", "Revision must be a positive integer")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+
+	rolledBack, err := h.toggleUseCase.RollbackToggle(c.Param("toggleId"), c.Param("id"), revision, currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rolledBack)
+}
+
+// currentUser retorna o usuário autenticado da requisição, ou nil quando não há um.
+// A justificativa do header X-Freeze-Override acompanha uma cópia do usuário, para que os casos
+// de uso decidam se a alteração pode ignorar uma janela de congelamento.
+func currentUser(c *gin.Context) *entity.User {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		return nil
+	}
+	user, _ := userInterface.(*entity.User)
+	if override := c.GetHeader("X-Freeze-Override"); user != nil && override != "" {
+		actor := *user
+		actor.FreezeOverride = override
+		return &actor
+	}
+	return user
+}
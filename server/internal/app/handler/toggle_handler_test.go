@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
@@ -34,6 +36,20 @@ func TestToggleHandler_CreateToggle(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedError:  "",
 		},
+		{
+			name:  "duplicate_toggle",
+			appID: "01JZNM42NKSANGHZ3G4KKXGCNW",
+			body:  `{"toggle": "feature"}`,
+			setupMock: func(toggleMock *usecase.MockToggleRepository, appMock *usecase.MockApplicationRepository) {
+				appMock.Applications["01JZNM42NKSANGHZ3G4KKXGCNW"] = &entity.Application{
+					ID:   "01JZNM42NKSANGHZ3G4KKXGCNW",
+					Name: "Test App",
+				}
+				toggleMock.Toggles["t1"] = entity.NewToggle("feature", true, "feature", 0, nil, "01JZNM42NKSANGHZ3G4KKXGCNW")
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "toggle already exists",
+		},
 		{
 			name:           "missing_toggle",
 			appID:          "01JZNM42NKSANGHZ3G4KKXGCNW",
@@ -53,7 +69,7 @@ func TestToggleHandler_CreateToggle(t *testing.T) {
 
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.POST("/applications/:id/toggles", handler.CreateToggle)
@@ -164,7 +180,7 @@ func TestToggleHandler_GetToggleStatus(t *testing.T) {
 			appMock := usecase.NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.GET("/applications/:id/toggles/:toggleId", handler.GetToggleStatus)
@@ -243,7 +259,7 @@ func TestToggleHandler_UpdateToggle(t *testing.T) {
 			appMock := usecase.NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.PUT("/applications/:id/toggles/:toggleId", handler.UpdateToggle)
@@ -316,7 +332,7 @@ func TestToggleHandler_GetAllTogglesByApp(t *testing.T) {
 			appMock := usecase.NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.GET("/applications/:id/toggles", handler.GetAllToggles)
@@ -427,7 +443,7 @@ func TestToggleHandler_DeleteToggle(t *testing.T) {
 			appMock := usecase.NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.DELETE("/applications/:id/toggles/:toggleId", handler.DeleteToggle)
@@ -520,7 +536,7 @@ func TestToggleHandler_UpdateEnabled(t *testing.T) {
 			appMock := usecase.NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.PUT("/applications/:id/toggle/:toggleId", handler.UpdateEnabled)
@@ -628,7 +644,7 @@ func TestToggleHandler_GetToggleStatus_Validation(t *testing.T) {
 
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.GET("/applications/:id/toggles/:toggleId/status", handler.GetToggleStatus)
@@ -841,7 +857,7 @@ func TestToggleHandler_UpdateToggleWithActivationRules(t *testing.T) {
 
 			tt.setupMock(toggleMock, appMock)
 
-			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock)
+			toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
 			handler := NewToggleHandler(toggleUseCase)
 
 			router.PUT("/applications/:id/toggles/:toggleId", handler.UpdateToggle)
@@ -900,3 +916,279 @@ func TestToggleHandler_UpdateToggleWithActivationRules(t *testing.T) {
 		})
 	}
 }
+
+func TestToggleHandler_GetAllToggles_FilterByMetadata(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Owner: "payments", Tags: entity.ToggleTags{"q3", "web"}}
+	toggleMock.Toggles["toggle2"] = &entity.Toggle{ID: "toggle2", AppID: "app123", Path: "search", Owner: "search", Tags: entity.ToggleTags{"q3"}}
+
+	handler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository()))
+	router.GET("/applications/:id/toggles", handler.GetAllToggles)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCount int
+	}{
+		{"by tag", "?tag=q3", 2},
+		{"by multiple tags", "?tag=q3&tag=web", 1},
+		{"by owner and tag", "?tag=q3&owner=search", 1},
+		{"no match", "?owner=nobody", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/applications/app123/toggles"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response []entity.Toggle
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response) != tt.expectedCount {
+				t.Errorf("Expected %d toggles, got %d", tt.expectedCount, len(response))
+			}
+		})
+	}
+}
+
+func TestToggleHandler_GetAllToggles_HierarchyRejectsFilters(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Owner: "payments", Tags: entity.ToggleTags{"q3"}}
+
+	handler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository()))
+	router.GET("/applications/:id/toggles", handler.GetAllToggles)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"hierarchy only", "?hierarchy=true", http.StatusOK},
+		{"hierarchy with tag", "?hierarchy=true&tag=q3", http.StatusBadRequest},
+		{"hierarchy with owner", "?hierarchy=true&owner=payments", http.StatusBadRequest},
+		{"hierarchy with kind", "?hierarchy=true&kind=release", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/applications/app123/toggles"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusBadRequest && !strings.Contains(w.Body.String(), "hierarchy cannot be combined") {
+				t.Errorf("Expected the hierarchy detail, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestToggleHandler_UpdateToggleMetadata(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "successful update",
+			body:           `{"description": "New checkout", "owner": "payments", "tags": ["q3"], "kind": "experiment", "expires_at": "2999-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid kind",
+			body:           `{"kind": "temporary"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `{"tags": "q3"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			toggleMock := usecase.NewMockToggleRepository()
+			appMock := usecase.NewMockApplicationRepository()
+			toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout"}
+
+			handler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository()))
+			router.PUT("/applications/:id/toggles/:toggleId/metadata", handler.UpdateToggleMetadata)
+
+			req, _ := http.NewRequest("PUT", "/applications/app123/toggles/toggle1/metadata", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && toggleMock.Toggles["toggle1"].Kind != entity.ToggleKindExperiment {
+				t.Errorf("Expected kind to be updated, got %q", toggleMock.Toggles["toggle1"].Kind)
+			}
+		})
+	}
+}
+
+func TestToggleHandler_UpdateTogglePrerequisites(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	toggleMock.Toggles["payment"] = &entity.Toggle{ID: "payment", AppID: "app123", Path: "payment", Enabled: true}
+	toggleMock.Toggles["invoice"] = &entity.Toggle{ID: "invoice", AppID: "app123", Path: "invoice", Enabled: true}
+
+	handler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository()))
+	router.PUT("/applications/:id/toggles/:toggleId/prerequisites", handler.UpdateTogglePrerequisites)
+	router.DELETE("/applications/:id/toggles/:toggleId", handler.DeleteToggle)
+
+	put := func(toggleID, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/applications/app123/toggles/"+toggleID+"/prerequisites", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := put("payment", `{"prerequisites": [{"toggle_id": "invoice", "enabled": true}]}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(toggleMock.Toggles["payment"].Prerequisites) != 1 {
+		t.Errorf("Expected prerequisite to be saved, got %+v", toggleMock.Toggles["payment"].Prerequisites)
+	}
+
+	if w := put("invoice", `{"prerequisites": [{"toggle_id": "payment", "enabled": true}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a cycle, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := put("missing", `{"prerequisites": []}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req, _ := http.NewRequest("DELETE", "/applications/app123/toggles/invoice", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 when deleting a prerequisite, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToggleHandler_GetAllToggles_Pagination(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	for i, path := range []string{"alpha", "beta", "gamma"} {
+		id := fmt.Sprintf("toggle%d", i)
+		toggleMock.Toggles[id] = &entity.Toggle{ID: id, AppID: "app123", Path: path, Enabled: true}
+	}
+
+	handler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository()))
+	router.GET("/applications/:id/toggles", handler.GetAllToggles)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/applications/app123/toggles"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Sem limit/cursor a resposta continua sendo a lista simples
+	w := get("")
+	var legacy []entity.Toggle
+	if err := json.Unmarshal(w.Body.Bytes(), &legacy); err != nil || len(legacy) != 3 {
+		t.Fatalf("Expected legacy list with 3 toggles, got %s", w.Body.String())
+	}
+
+	w = get("?limit=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var page entity.Page[*entity.Toggle]
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if len(page.Items) != 2 || page.Total != 3 || page.NextCursor == "" {
+		t.Fatalf("Unexpected first page %s", w.Body.String())
+	}
+
+	w = get("?limit=2&cursor=" + page.NextCursor)
+	page = entity.Page[*entity.Toggle]{}
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if len(page.Items) != 1 || page.Items[0].Path != "gamma" || page.NextCursor != "" {
+		t.Fatalf("Unexpected second page %s", w.Body.String())
+	}
+
+	for _, query := range []string{"?limit=500", "?limit=abc", "?limit=2&sort=secret", "?limit=2&enabled=maybe", "?cursor=bogus"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/applications/missing/toggles?limit=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for unknown application, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestToggleHandler_HistoryAndRollback(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Enabled: true}
+	toggleMock.RevisionRepo = usecase.NewMockToggleRevisionRepository()
+
+	handler := NewToggleHandler(usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), toggleMock.RevisionRepo, usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository()))
+	router.PUT("/applications/:id/toggles/:toggleId", handler.UpdateToggle)
+	router.GET("/applications/:id/toggles/:toggleId/history", handler.GetToggleHistory)
+	router.POST("/applications/:id/toggles/:toggleId/rollback", handler.RollbackToggle)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, enabled := range []string{"false", "true"} {
+		if w := request("PUT", "/applications/app123/toggles/toggle1", `{"enabled": `+enabled+`}`); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := request("POST", "/applications/app123/toggles/toggle1/rollback?revision=1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if toggleMock.Toggles["toggle1"].Enabled {
+		t.Error("Expected revision 1 to disable the toggle")
+	}
+
+	w = request("GET", "/applications/app123/toggles/toggle1/history", "")
+	var response struct {
+		Revisions []*entity.ToggleRevision `json:"revisions"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.Revisions) != 3 || response.Revisions[0].Action != entity.RevisionActionRolledBack {
+		t.Errorf("Unexpected history %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := request("POST", "/applications/app123/toggles/toggle1/rollback?revision=abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid revision, got %d", w.Code)
+	}
+	if w := request("POST", "/applications/app123/toggles/toggle1/rollback?revision=9", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown revision, got %d", w.Code)
+	}
+}
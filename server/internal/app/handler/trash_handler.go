@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+// TrashHandler gerencia as requisições HTTP da lixeira de toggles e aplicações
+type TrashHandler struct {
+	trashUseCase *usecase.TrashUseCase
+}
+
+// NewTrashHandler cria uma nova instância de TrashHandler
+func NewTrashHandler(trashUseCase *usecase.TrashUseCase) *TrashHandler {
+	return &TrashHandler{
+		trashUseCase: trashUseCase,
+	}
+}
+
+// GetToggleTrash lista os toggles da aplicação que estão na lixeira
+// GET /applications/:id/trash
+func (h *TrashHandler) GetToggleTrash(c *gin.Context) {
+	toggles, err := h.trashUseCase.GetToggleTrash(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"toggles": toggles,
+	})
+}
+
+// RestoreToggle tira um toggle da lixeira com os ancestrais e descendentes necessários
+// POST /applications/:id/trash/:toggleId/restore
+func (h *TrashHandler) RestoreToggle(c *gin.Context) {
+	toggles, err := h.trashUseCase.RestoreToggle(c.Param("id"), c.Param("toggleId"), currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "toggle restored successfully",
+		"toggles": toggles,
+	})
+}
+
+// GetApplicationTrash lista as aplicações que estão na lixeira
+// GET /trash/applications
+func (h *TrashHandler) GetApplicationTrash(c *gin.Context) {
+	apps, err := h.trashUseCase.GetApplicationTrash()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applications": apps,
+	})
+}
+
+// RestoreApplication tira uma aplicação da lixeira com os toggles removidos junto com ela
+// POST /trash/applications/:id/restore
+func (h *TrashHandler) RestoreApplication(c *gin.Context) {
+	app, err := h.trashUseCase.RestoreApplication(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "application restored successfully",
+		"application": app,
+	})
+}
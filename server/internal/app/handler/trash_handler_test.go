@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/usecase"
+)
+
+func TestTrashHandler_ToggleTrash(t *testing.T) {
+	router := setupTestRouter()
+	toggleMock := usecase.NewMockToggleRepository()
+	appMock := usecase.NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Enabled: true}
+
+	toggleUseCase := usecase.NewToggleUseCase(toggleMock, appMock, usecase.NewMockSegmentRepository(), usecase.NewMockToggleRevisionRepository(), usecase.NewMockFreezeWindowRepository(), usecase.NewMockAuditEventRepository())
+	toggleHandler := NewToggleHandler(toggleUseCase)
+	handler := NewTrashHandler(usecase.NewTrashUseCase(toggleMock, appMock, toggleUseCase))
+	router.DELETE("/applications/:id/toggles/:toggleId", toggleHandler.DeleteToggle)
+	router.GET("/applications/:id/trash", handler.GetToggleTrash)
+	router.POST("/applications/:id/trash/:toggleId/restore", handler.RestoreToggle)
+
+	request := func(method, url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := request("DELETE", "/applications/app123/toggles/toggle1"); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := request("GET", "/applications/app123/trash")
+	var response struct {
+		Toggles []*entity.Toggle `json:"toggles"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.Toggles) != 1 || !response.Toggles[0].DeletedAt.Valid {
+		t.Fatalf("Unexpected trash %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := request("POST", "/applications/app123/trash/toggle1/restore"); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if toggleMock.Toggles["toggle1"] == nil {
+		t.Error("Expected toggle to be restored")
+	}
+
+	if w := request("POST", "/applications/app123/trash/toggle1/restore"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a toggle outside the trash, got %d", w.Code)
+	}
+	if w := request("GET", "/applications/missing/trash"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown application, got %d", w.Code)
+	}
+}
@@ -138,6 +138,18 @@ func (h *UserManagementHandler) CreateUser(c *gin.Context) {
 
 // ListUsers lista todos os usuários (apenas root pode listar usuários)
 func (h *UserManagementHandler) ListUsers(c *gin.Context) {
+	// Paginação opcional por cursor (?limit=&cursor=&sort=&order=&username=&role=)
+	page, pageErr := parsePageRequest(c, entity.UserListFields)
+	if pageErr != nil {
+		c.JSON(http.StatusBadRequest, pageErr)
+		return
+	}
+	if page != nil {
+		result, err := h.userUseCase.ListUsers(page)
+		respondPage(c, result, err)
+		return
+	}
+
 	users, err := h.userUseCase.GetAllUsers()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ListUsersResponse{
@@ -9,7 +9,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -17,10 +16,7 @@ func setupUserManagementTestRouter() (*gin.Engine, *gorm.DB) {
 	gin.SetMode(gin.TestMode)
 	
 	// Cria base de dados em memória para testes
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	
-	// Auto migrate tables
-	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.User{}, &entity.SecretKey{})
+	db := setupTestDB()
 	
 	// Inicializa handlers com a base de dados de teste
 	InitHandlers(db)
@@ -217,6 +213,40 @@ func TestListUsers(t *testing.T) {
 	}
 }
 
+func TestListUsers_Pagination(t *testing.T) {
+	router, db := setupUserManagementTestRouter()
+
+	for _, username := range []string{"alice", "bruno", "carol"} {
+		user := &entity.User{Username: username, Role: entity.UserRoleUser}
+		user.SetPassword("password123")
+		db.Create(user)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users?limit=2&role=user&sort=username&order=desc", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var page entity.Page[*entity.User]
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if page.Total != 3 || len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("Unexpected page %s", w.Body.String())
+	}
+	if page.Items[0].Username != "carol" || page.Items[1].Username != "bruno" {
+		t.Errorf("Expected carol and bruno, got %s and %s", page.Items[0].Username, page.Items[1].Username)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/users?limit=2&role=superuser", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid role filter, got %d", w.Code)
+	}
+}
+
 func TestDeleteUser_Success(t *testing.T) {
 	router, db := setupUserManagementTestRouter()
 
@@ -283,10 +313,7 @@ func TestChangePassword_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	
 	// Cria base de dados em memória para testes
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	
-	// Auto migrate tables
-	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.User{}, &entity.SecretKey{})
+	db := setupTestDB()
 	
 	// Inicializa handlers com a base de dados de teste
 	InitHandlers(db)
@@ -352,10 +379,7 @@ func TestChangePassword_WrongCurrentPassword(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	
 	// Cria base de dados em memória para testes
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	
-	// Auto migrate tables
-	db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.User{}, &entity.SecretKey{})
+	db := setupTestDB()
 	
 	// Inicializa handlers com a base de dados de teste
 	InitHandlers(db)
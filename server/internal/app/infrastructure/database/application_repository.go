@@ -1,6 +1,8 @@
 package database
 
 import (
+	"time"
+
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/domain/repository"
 	"gorm.io/gorm"
@@ -48,30 +50,61 @@ func (r *ApplicationRepositoryImpl) Update(app *entity.Application) error {
 	return r.db.Save(app).Error
 }
 
-// Delete remove uma aplicação e todas as suas toggles em cascata
+// Delete move a aplicação e os seus toggles para a lixeira no mesmo instante, para que voltem juntos.
+// Os toggles que já estavam na lixeira mantêm o instante original.
 func (r *ApplicationRepositoryImpl) Delete(id string) error {
-	// Inicia uma transação
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
+	now := r.db.NowFunc()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Toggle{}).Where("app_id = ?", id).UpdateColumn("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Application{}).Where("id = ?", id).UpdateColumn("deleted_at", now).Error
+	})
+}
 
-	// Deleta todas as toggles da aplicação primeiro
-	err := tx.Where("app_id = ?", id).Delete(&entity.Toggle{}).Error
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+// GetTrash busca as aplicações que estão na lixeira, das removidas mais recentemente
+func (r *ApplicationRepositoryImpl) GetTrash() ([]*entity.Application, error) {
+	apps := make([]*entity.Application, 0)
+	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, name").Find(&apps).Error
+	return apps, err
+}
 
-	// Deleta a aplicação
-	err = tx.Where("id = ?", id).Delete(&entity.Application{}).Error
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+// Undelete tira a aplicação e os toggles informados da lixeira na mesma transação
+func (r *ApplicationRepositoryImpl) Undelete(id string, toggleIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(toggleIDs) > 0 {
+			if err := tx.Unscoped().Model(&entity.Toggle{}).Where("id IN ?", toggleIDs).UpdateColumn("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Model(&entity.Application{}).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error
+	})
+}
 
-	// Commit da transação
-	return tx.Commit().Error
+// Purge remove definitivamente as aplicações que estão na lixeira desde antes do instante informado,
+// com todos os seus toggles e segmentos; os segmentos globais são mantidos
+func (r *ApplicationRepositoryImpl) Purge(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&entity.Application{}).Select("id").Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+		appToggles := tx.Unscoped().Model(&entity.Toggle{}).Select("id").Where("app_id IN (?)", expired)
+		if err := tx.Where("toggle_id IN (?)", appToggles).Delete(&entity.ToggleRule{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("toggle_id IN (?)", appToggles).Delete(&entity.TogglePrerequisite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("app_id IN (?)", expired).Delete(&entity.Toggle{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("app_id IN (?)", expired).Delete(&entity.Segment{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&entity.Application{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
 }
 
 // Exists verifica se uma aplicação existe
@@ -98,10 +131,46 @@ func (r *ApplicationRepositoryImpl) GetAllWithToggleCounts() ([]*entity.Applicat
 			SUM(CASE WHEN toggles.enabled = 1 THEN 1 ELSE 0 END) as enabled_toggles,
 			SUM(CASE WHEN toggles.enabled = 0 THEN 1 ELSE 0 END) as disabled_toggles
 		`).
-		Joins("LEFT JOIN toggles ON applications.id = toggles.app_id").
+		Joins("LEFT JOIN toggles ON applications.id = toggles.app_id AND toggles.deleted_at IS NULL").
+		Where("applications.deleted_at IS NULL").
 		Group("applications.id, applications.name, applications.created_at, applications.updated_at").
 		Order("applications.created_at DESC").
 		Find(&results).Error
 
 	return results, err
 }
+
+// applicationListSpec define a ordenação e os filtros da listagem paginada de aplicações
+var applicationListSpec = &listSpec[*entity.ApplicationWithCounts]{
+	sorts: map[string]sortColumn[*entity.ApplicationWithCounts]{
+		"name":       {column: "name", kind: sortString, value: func(a *entity.ApplicationWithCounts) interface{} { return a.Name }},
+		"created_at": {column: "created_at", kind: sortTime, value: func(a *entity.ApplicationWithCounts) interface{} { return a.CreatedAt }},
+		"updated_at": {column: "updated_at", kind: sortTime, value: func(a *entity.ApplicationWithCounts) interface{} { return a.UpdatedAt }},
+	},
+	filters: map[string]filterColumn{
+		"name": {column: "name", op: filterContains},
+	},
+	id: func(a *entity.ApplicationWithCounts) string { return a.ID },
+}
+
+// ListWithToggleCounts lista aplicações com contagem de toggles usando paginação por cursor.
+// Quando ids não é nil, apenas as aplicações informadas são consideradas.
+func (r *ApplicationRepositoryImpl) ListWithToggleCounts(page *entity.PageRequest, ids []string) (*entity.Page[*entity.ApplicationWithCounts], error) {
+	base := r.db.Table("applications").
+		Select(`
+			applications.id,
+			applications.name,
+			applications.created_at,
+			applications.updated_at,
+			COUNT(toggles.id) as total_toggles,
+			SUM(CASE WHEN toggles.enabled = 1 THEN 1 ELSE 0 END) as enabled_toggles,
+			SUM(CASE WHEN toggles.enabled = 0 THEN 1 ELSE 0 END) as disabled_toggles
+		`).
+		Joins("LEFT JOIN toggles ON applications.id = toggles.app_id AND toggles.deleted_at IS NULL").
+		Where("applications.deleted_at IS NULL").
+		Group("applications.id, applications.name, applications.created_at, applications.updated_at")
+	if ids != nil {
+		base = base.Where("applications.id IN ?", ids)
+	}
+	return paginate(r.db, base, page, applicationListSpec)
+}
@@ -1,7 +1,10 @@
 package database
 
 import (
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"gorm.io/driver/sqlite"
@@ -15,7 +18,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Auto migrate
-	err = db.AutoMigrate(&entity.Application{}, &entity.Toggle{})
+	err = db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.ToggleRule{}, &entity.TogglePrerequisite{}, &entity.ToggleRevision{}, &entity.ApplicationSnapshot{}, &entity.AuditEvent{}, &entity.FreezeWindow{}, &entity.Segment{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -23,6 +26,28 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// setupSearchIndex cria no banco de teste o índice da busca de toggles, que o AutoMigrate não cria.
+// A tabela FTS5 do índice exige a build tag sqlite_fts5; sem ela o teste é ignorado.
+func setupSearchIndex(tb testing.TB, db *gorm.DB) {
+	tb.Helper()
+	var fts5 bool
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil || !fts5 {
+		tb.Skip("the toggle search index requires the sqlite_fts5 build tag")
+	}
+
+	migration, err := os.ReadFile("../../../../db/migrations/20250915_add_toggle_search_index.sql")
+	if err != nil {
+		tb.Fatalf("Failed to read search index migration: %v", err)
+	}
+	up := strings.Split(string(migration), "-- +goose Down")[0]
+	for _, block := range strings.Split(up, "-- +goose StatementBegin")[1:] {
+		statement := strings.Split(block, "-- +goose StatementEnd")[0]
+		if err := db.Exec(statement).Error; err != nil {
+			tb.Fatalf("Failed to create search index: %v", err)
+		}
+	}
+}
+
 func TestApplicationRepository_Create(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewApplicationRepository(db)
@@ -240,3 +265,74 @@ func TestApplicationRepository_DeleteWithCascade(t *testing.T) {
 		t.Errorf("Expected 0 toggles after cascade deletion, got %d", len(toggles))
 	}
 }
+
+func TestApplicationRepository_TrashRestoreAndPurge(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewApplicationRepository(db)
+	toggleRepo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test Application")
+	if err := repo.Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	kept := entity.NewToggle("kept", true, "kept", 0, nil, app.ID)
+	earlier := entity.NewToggle("earlier", true, "earlier", 0, nil, app.ID)
+	for _, toggle := range []*entity.Toggle{kept, earlier} {
+		if err := toggleRepo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+	if err := toggleRepo.Delete(earlier.ID); err != nil {
+		t.Fatalf("Failed to delete test toggle: %v", err)
+	}
+
+	if err := repo.Delete(app.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if counts, _ := repo.GetAllWithToggleCounts(); len(counts) != 0 {
+		t.Errorf("Expected trashed application to be hidden from listings, got %+v", counts)
+	}
+	trash, err := repo.GetTrash()
+	if err != nil || len(trash) != 1 || !trash[0].DeletedAt.Valid {
+		t.Fatalf("Expected application in the trash, got %+v (%v)", trash, err)
+	}
+
+	// Os toggles removidos com a aplicação têm o mesmo instante de remoção
+	toggles, _ := toggleRepo.GetTrashByAppID(app.ID)
+	var restoring []string
+	for _, toggle := range toggles {
+		if toggle.DeletedAt.Time.Equal(trash[0].DeletedAt.Time) {
+			restoring = append(restoring, toggle.ID)
+		}
+	}
+	if len(restoring) != 1 || restoring[0] != kept.ID {
+		t.Fatalf("Expected only the toggle deleted with the application, got %v", restoring)
+	}
+
+	if err := repo.Undelete(app.ID, restoring); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(app.ID); err != nil {
+		t.Errorf("Expected application to be restored, got %v", err)
+	}
+	if live, _ := toggleRepo.GetByAppID(app.ID); len(live) != 1 || live[0].ID != kept.ID {
+		t.Errorf("Expected only the kept toggle to be restored, got %+v", live)
+	}
+
+	// O expurgo só remove o que está na lixeira desde antes do instante informado
+	if err := repo.Delete(app.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if purged, _ := repo.Purge(time.Now().Add(-time.Hour)); purged != 0 {
+		t.Errorf("Expected nothing to be purged, got %d", purged)
+	}
+	purged, err := repo.Purge(time.Now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("Expected application to be purged, got %d (%v)", purged, err)
+	}
+	var count int64
+	db.Unscoped().Model(&entity.Toggle{}).Where("app_id = ?", app.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected toggles to be purged with the application, got %d", count)
+	}
+}
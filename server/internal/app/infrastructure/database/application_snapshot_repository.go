@@ -0,0 +1,41 @@
+package database
+
+import (
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+)
+
+// ApplicationSnapshotRepositoryImpl implementa ApplicationSnapshotRepository
+type ApplicationSnapshotRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewApplicationSnapshotRepository cria uma nova instância de ApplicationSnapshotRepositoryImpl
+func NewApplicationSnapshotRepository(db *gorm.DB) repository.ApplicationSnapshotRepository {
+	return &ApplicationSnapshotRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create grava um snapshot
+func (r *ApplicationSnapshotRepositoryImpl) Create(snapshot *entity.ApplicationSnapshot) error {
+	return r.db.Create(snapshot).Error
+}
+
+// GetByID busca um snapshot por ID
+func (r *ApplicationSnapshotRepositoryImpl) GetByID(id string) (*entity.ApplicationSnapshot, error) {
+	var snapshot entity.ApplicationSnapshot
+	err := r.db.Where("id = ?", id).First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetByAppID busca os snapshots da aplicação, do mais recente para o mais antigo
+func (r *ApplicationSnapshotRepositoryImpl) GetByAppID(appID string) ([]*entity.ApplicationSnapshot, error) {
+	snapshots := make([]*entity.ApplicationSnapshot, 0)
+	err := r.db.Where("app_id = ?", appID).Order("created_at DESC, id DESC").Find(&snapshots).Error
+	return snapshots, err
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+)
+
+// AuditEventRepositoryImpl implementa AuditEventRepository
+type AuditEventRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAuditEventRepository cria uma nova instância de AuditEventRepositoryImpl
+func NewAuditEventRepository(db *gorm.DB) repository.AuditEventRepository {
+	return &AuditEventRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create grava o evento de auditoria
+func (r *AuditEventRepositoryImpl) Create(event *entity.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// GetByAppID busca os eventos da aplicação, do mais recente para o mais antigo
+func (r *AuditEventRepositoryImpl) GetByAppID(appID string) ([]*entity.AuditEvent, error) {
+	events := make([]*entity.AuditEvent, 0)
+	err := r.db.Where("app_id = ?", appID).Order("created_at DESC, id DESC").Find(&events).Error
+	return events, err
+}
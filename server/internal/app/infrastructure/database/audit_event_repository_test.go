@@ -0,0 +1,44 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestAuditEventRepository_CreateAndGetByAppID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAuditEventRepository(db)
+	appRepo := NewApplicationRepository(db)
+
+	app := entity.NewApplication("Test App")
+	other := entity.NewApplication("Other App")
+	appRepo.Create(app)
+	appRepo.Create(other)
+
+	// O kill switch é gravado junto com a aplicação
+	now := time.Now()
+	app.KillSwitch = true
+	app.KillSwitchAt = &now
+	if err := appRepo.Update(app); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	found, _ := appRepo.GetByID(app.ID)
+	if !found.KillSwitch || found.KillSwitchAt == nil {
+		t.Errorf("Expected kill switch to be stored, got %+v", found)
+	}
+
+	actor := &entity.User{ID: "user1", Username: "oncall"}
+	repo.Create(entity.NewAuditEvent(app.ID, entity.AuditActionKillSwitchActivated, "activated", actor))
+	repo.Create(entity.NewAuditEvent(other.ID, entity.AuditActionKillSwitchActivated, "activated", nil))
+	repo.Create(entity.NewAuditEvent(app.ID, entity.AuditActionKillSwitchDeactivated, "deactivated", nil))
+
+	events, err := repo.GetByAppID(app.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 2 || events[0].Action != entity.AuditActionKillSwitchDeactivated || events[1].Actor != "oncall" {
+		t.Errorf("Expected the application's events newest first, got %+v", events)
+	}
+}
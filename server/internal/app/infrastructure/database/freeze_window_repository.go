@@ -0,0 +1,65 @@
+package database
+
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+)
+
+// FreezeWindowRepositoryImpl implementa FreezeWindowRepository
+type FreezeWindowRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFreezeWindowRepository cria uma nova instância de FreezeWindowRepositoryImpl
+func NewFreezeWindowRepository(db *gorm.DB) repository.FreezeWindowRepository {
+	return &FreezeWindowRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create cria uma janela de congelamento
+func (r *FreezeWindowRepositoryImpl) Create(window *entity.FreezeWindow) error {
+	return r.db.Create(window).Error
+}
+
+// GetByID busca uma janela por ID
+func (r *FreezeWindowRepositoryImpl) GetByID(id string) (*entity.FreezeWindow, error) {
+	var window entity.FreezeWindow
+	err := r.db.Where("id = ?", id).First(&window).Error
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// GetByAppID busca as janelas da aplicação, das que começam mais tarde
+func (r *FreezeWindowRepositoryImpl) GetByAppID(appID string) ([]*entity.FreezeWindow, error) {
+	windows := make([]*entity.FreezeWindow, 0)
+	err := r.db.Where("app_id = ?", appID).Order("starts_at DESC").Find(&windows).Error
+	return windows, err
+}
+
+// GetGlobal busca as janelas globais, das que começam mais tarde
+func (r *FreezeWindowRepositoryImpl) GetGlobal() ([]*entity.FreezeWindow, error) {
+	windows := make([]*entity.FreezeWindow, 0)
+	err := r.db.Where("app_id IS NULL").Order("starts_at DESC").Find(&windows).Error
+	return windows, err
+}
+
+// GetActive busca as janelas da aplicação e as globais em vigor no instante informado
+func (r *FreezeWindowRepositoryImpl) GetActive(appID string, at time.Time) ([]*entity.FreezeWindow, error) {
+	windows := make([]*entity.FreezeWindow, 0)
+	err := r.db.
+		Where("(app_id = ? OR app_id IS NULL) AND starts_at <= ? AND ends_at > ?", appID, at, at).
+		Order("ends_at DESC").
+		Find(&windows).Error
+	return windows, err
+}
+
+// Delete remove uma janela
+func (r *FreezeWindowRepositoryImpl) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&entity.FreezeWindow{}).Error
+}
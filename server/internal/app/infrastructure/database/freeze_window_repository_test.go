@@ -0,0 +1,54 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestFreezeWindowRepository_GetActive(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFreezeWindowRepository(db)
+	appRepo := NewApplicationRepository(db)
+
+	app := entity.NewApplication("Test App")
+	other := entity.NewApplication("Other App")
+	appRepo.Create(app)
+	appRepo.Create(other)
+
+	now := time.Now()
+	current := entity.NewFreezeWindow(&app.ID, now.Add(-time.Hour), now.Add(time.Hour), "release", []string{"ops"}, nil)
+	global := entity.NewFreezeWindow(nil, now.Add(-time.Hour), now.Add(2*time.Hour), "black friday", nil, nil)
+	past := entity.NewFreezeWindow(&app.ID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "past", nil, nil)
+	foreign := entity.NewFreezeWindow(&other.ID, now.Add(-time.Hour), now.Add(time.Hour), "other", nil, nil)
+	for _, window := range []*entity.FreezeWindow{current, global, past, foreign} {
+		if err := repo.Create(window); err != nil {
+			t.Fatalf("Failed to create freeze window: %v", err)
+		}
+	}
+
+	active, err := repo.GetActive(app.ID, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(active) != 2 || active[0].ID != global.ID || active[1].ID != current.ID {
+		t.Fatalf("Expected the application and global windows, got %+v", active)
+	}
+	if len(active[1].ExemptUsers) != 1 || active[1].ExemptUsers[0] != "ops" {
+		t.Errorf("Expected exempt users to be stored, got %v", active[1].ExemptUsers)
+	}
+
+	windows, _ := repo.GetByAppID(app.ID)
+	globals, _ := repo.GetGlobal()
+	if len(windows) != 2 || windows[0].ID != current.ID || len(globals) != 1 {
+		t.Errorf("Expected windows split by scope, got %d and %d", len(windows), len(globals))
+	}
+
+	if err := repo.Delete(current.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(current.ID); err == nil {
+		t.Error("Expected window to be deleted")
+	}
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"gorm.io/gorm"
+)
+
+// sortKind define como o valor de uma coluna ordenada é serializado no cursor
+type sortKind int
+
+const (
+	sortString sortKind = iota
+	sortInt
+	sortTime
+)
+
+// sortColumn associa um campo de ordenação à coluna e ao valor do item correspondente
+type sortColumn[T any] struct {
+	column string
+	kind   sortKind
+	value  func(item T) interface{}
+}
+
+// filterOp define como um filtro é aplicado
+type filterOp int
+
+const (
+	filterEquals filterOp = iota
+	filterContains
+	filterBool
+	filterJSONArray // Array JSON de strings, todos os valores precisam estar presentes
+)
+
+// filterColumn associa um filtro à coluna e à operação
+type filterColumn struct {
+	column string
+	op     filterOp
+}
+
+// listSpec descreve como paginar uma listagem: colunas de ordenação, filtros e o ID de cada item
+type listSpec[T any] struct {
+	sorts   map[string]sortColumn[T]
+	filters map[string]filterColumn
+	id      func(item T) string
+	preload func(db *gorm.DB) *gorm.DB // Carregamento opcional de associações com condições
+}
+
+// paginate executa uma listagem paginada por cursor sobre a consulta base.
+// A consulta base é usada como subconsulta "src", de modo que ordenação, filtros e
+// cursor se aplicam às colunas projetadas. A ordenação usa sempre o ID como desempate.
+func paginate[T any](db *gorm.DB, base *gorm.DB, req *entity.PageRequest, spec *listSpec[T], preloads ...string) (*entity.Page[T], error) {
+	sort := spec.sorts[req.Sort]
+
+	filtered := func() *gorm.DB {
+		query := db.Table("(?) AS src", base)
+		for name, values := range req.Filters {
+			filter, ok := spec.filters[name]
+			if !ok || len(values) == 0 {
+				continue
+			}
+			switch filter.op {
+			case filterEquals:
+				query = query.Where("src."+filter.column+" = ?", values[0])
+			case filterContains:
+				query = query.Where("src."+filter.column+" LIKE ? ESCAPE '\\'", "%"+escapeLike(values[0])+"%")
+			case filterBool:
+				enabled, _ := strconv.ParseBool(values[0])
+				query = query.Where("src."+filter.column+" = ?", enabled)
+			case filterJSONArray:
+				for _, value := range values {
+					query = query.Where("src."+filter.column+" LIKE ? ESCAPE '\\'", "%\""+escapeLike(value)+"\"%")
+				}
+			}
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query := filtered()
+	if req.Cursor != "" {
+		cursor, err := entity.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeCursorValue(cursor.Value, sort.kind)
+		if err != nil {
+			return nil, entity.ErrInvalidCursor
+		}
+		op := ">"
+		if req.Order == entity.SortDesc {
+			op = "<"
+		}
+		query = query.Where("(src."+sort.column+" "+op+" ? OR (src."+sort.column+" = ? AND src.id "+op+" ?))", value, value, cursor.ID)
+	}
+
+	direction := " ASC"
+	if req.Order == entity.SortDesc {
+		direction = " DESC"
+	}
+	query = query.Order("src." + sort.column + direction).Order("src.id" + direction)
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	if spec.preload != nil {
+		query = spec.preload(query)
+	}
+
+	items := make([]T, 0, req.Limit+1)
+	if err := query.Limit(req.Limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	page := &entity.Page[T]{Items: items, Total: total}
+	if len(items) > req.Limit {
+		page.Items = items[:req.Limit]
+		last := page.Items[req.Limit-1]
+		page.NextCursor = entity.EncodeCursor(encodeCursorValue(sort.value(last)), spec.id(last))
+	}
+	return page, nil
+}
+
+// encodeCursorValue serializa o valor ordenado do último item para o cursor
+func encodeCursorValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return v
+	}
+	return ""
+}
+
+// decodeCursorValue converte o valor do cursor para o tipo da coluna ordenada
+func decodeCursorValue(value string, kind sortKind) (interface{}, error) {
+	switch kind {
+	case sortInt:
+		return strconv.Atoi(value)
+	case sortTime:
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, err
+		}
+		// O driver grava as datas no fuso local; a comparação precisa usar o mesmo formato
+		return parsed.In(time.Local), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,208 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// collectPages percorre todas as páginas seguindo o next_cursor
+func collectPages[T any](t *testing.T, req entity.PageRequest, list func(*entity.PageRequest) (*entity.Page[T], error)) ([]T, int64) {
+	t.Helper()
+
+	var items []T
+	var total int64
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("Pagination did not terminate")
+		}
+		page := req
+		page.Normalize(entity.ListFields{Sort: []string{req.Sort}, DefaultSort: req.Sort, DefaultOrder: req.Order})
+		result, err := list(&page)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		items = append(items, result.Items...)
+		total = result.Total
+		if result.NextCursor == "" {
+			return items, total
+		}
+		req.Cursor = result.NextCursor
+	}
+}
+
+func TestPaginate_Toggles(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+	appRepo := NewApplicationRepository(db)
+
+	app := entity.NewApplication("Paginated App")
+	if err := appRepo.Create(app); err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+	other := entity.NewApplication("Other App")
+	if err := appRepo.Create(other); err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		toggle := entity.NewToggle(fmt.Sprintf("f%d", i), i%2 == 0, fmt.Sprintf("feature.f%d", i), 1, nil, app.ID)
+		toggle.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if i < 3 {
+			toggle.Owner = "payments"
+			toggle.Tags = entity.ToggleTags{"q3"}
+		}
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create toggle: %v", err)
+		}
+		// default:true ignora o valor zero na criação
+		if i%2 != 0 {
+			if err := db.Model(toggle).Update("enabled", false).Error; err != nil {
+				t.Fatalf("Failed to disable toggle: %v", err)
+			}
+		}
+	}
+	if err := repo.Create(entity.NewToggle("x", true, "feature.x", 1, nil, other.ID)); err != nil {
+		t.Fatalf("Failed to create toggle: %v", err)
+	}
+
+	list := func(req *entity.PageRequest) (*entity.Page[*entity.Toggle], error) {
+		return repo.ListByAppID(app.ID, req)
+	}
+
+	t.Run("path ascending", func(t *testing.T) {
+		items, total := collectPages(t, entity.PageRequest{Limit: 3, Sort: "path", Order: entity.SortAsc}, list)
+		if total != 7 || len(items) != 7 {
+			t.Fatalf("Expected 7 toggles, got %d (total %d)", len(items), total)
+		}
+		for i, toggle := range items {
+			if toggle.Path != fmt.Sprintf("feature.f%d", i) {
+				t.Errorf("Expected feature.f%d at position %d, got %s", i, i, toggle.Path)
+			}
+		}
+	})
+
+	t.Run("created_at descending", func(t *testing.T) {
+		items, _ := collectPages(t, entity.PageRequest{Limit: 2, Sort: "created_at", Order: entity.SortDesc}, list)
+		if len(items) != 7 {
+			t.Fatalf("Expected 7 toggles, got %d", len(items))
+		}
+		for i, toggle := range items {
+			if toggle.Path != fmt.Sprintf("feature.f%d", 6-i) {
+				t.Errorf("Expected feature.f%d at position %d, got %s", 6-i, i, toggle.Path)
+			}
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		req := entity.PageRequest{Limit: 2, Sort: "path", Order: entity.SortAsc, Filters: map[string][]string{
+			"owner":   {"payments"},
+			"tag":     {"q3"},
+			"enabled": {"true"},
+		}}
+		items, total := collectPages(t, req, list)
+		if total != 2 || len(items) != 2 {
+			t.Fatalf("Expected 2 toggles, got %d (total %d)", len(items), total)
+		}
+		if items[0].Path != "feature.f0" || items[1].Path != "feature.f2" {
+			t.Errorf("Unexpected toggles %s, %s", items[0].Path, items[1].Path)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		req := &entity.PageRequest{Limit: 2, Sort: "path", Order: entity.SortAsc, Cursor: "invalid"}
+		if _, err := repo.ListByAppID(app.ID, req); err == nil {
+			t.Error("Expected error for invalid cursor")
+		}
+	})
+}
+
+func TestPaginate_ApplicationsUsersAndTeams(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&entity.User{}, &entity.Team{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	appRepo := NewApplicationRepository(db)
+	userRepo := NewUserRepository(db)
+	teamRepo := NewTeamRepository(db)
+
+	var appIDs []string
+	for i := 0; i < 5; i++ {
+		app := entity.NewApplication(fmt.Sprintf("app-%d", i))
+		if err := appRepo.Create(app); err != nil {
+			t.Fatalf("Failed to create application: %v", err)
+		}
+		appIDs = append(appIDs, app.ID)
+	}
+
+	team := &entity.Team{Name: "core"}
+	if err := teamRepo.Create(team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	if err := teamRepo.Create(&entity.Team{Name: "platform"}); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		role := entity.UserRoleUser
+		if i == 0 {
+			role = entity.UserRoleAdmin
+		}
+		user := &entity.User{Username: fmt.Sprintf("user-%d", i), Password: "hash", Role: role}
+		if err := userRepo.Create(user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		if i < 3 {
+			if err := teamRepo.AddUserToTeam(team.ID, user.ID); err != nil {
+				t.Fatalf("Failed to add user to team: %v", err)
+			}
+		}
+	}
+
+	t.Run("applications restricted to ids", func(t *testing.T) {
+		items, total := collectPages(t, entity.PageRequest{Limit: 2, Sort: "name", Order: entity.SortAsc},
+			func(req *entity.PageRequest) (*entity.Page[*entity.ApplicationWithCounts], error) {
+				return appRepo.ListWithToggleCounts(req, appIDs[1:4])
+			})
+		if total != 3 || len(items) != 3 {
+			t.Fatalf("Expected 3 applications, got %d (total %d)", len(items), total)
+		}
+		if items[0].Name != "app-1" || items[2].Name != "app-3" {
+			t.Errorf("Unexpected order %s..%s", items[0].Name, items[2].Name)
+		}
+	})
+
+	t.Run("users filtered by role", func(t *testing.T) {
+		items, total := collectPages(t, entity.PageRequest{Limit: 2, Sort: "username", Order: entity.SortDesc, Filters: map[string][]string{"role": {"user"}}},
+			userRepo.List)
+		if total != 4 || len(items) != 4 {
+			t.Fatalf("Expected 4 users, got %d (total %d)", len(items), total)
+		}
+		if items[0].Username != "user-4" || items[3].Username != "user-1" {
+			t.Errorf("Unexpected order %s..%s", items[0].Username, items[3].Username)
+		}
+	})
+
+	t.Run("team users", func(t *testing.T) {
+		items, total := collectPages(t, entity.PageRequest{Limit: 2, Sort: "username", Order: entity.SortAsc},
+			func(req *entity.PageRequest) (*entity.Page[*entity.User], error) {
+				return teamRepo.ListUsersByTeamID(team.ID, req)
+			})
+		if total != 3 || len(items) != 3 {
+			t.Fatalf("Expected 3 team users, got %d (total %d)", len(items), total)
+		}
+	})
+
+	t.Run("teams with counts", func(t *testing.T) {
+		items, total := collectPages(t, entity.PageRequest{Limit: 1, Sort: "name", Order: entity.SortAsc}, teamRepo.ListTeamsWithCounts)
+		if total != 2 || len(items) != 2 {
+			t.Fatalf("Expected 2 teams, got %d (total %d)", len(items), total)
+		}
+		if items[0].Name != "core" || items[0].UserCount != 3 {
+			t.Errorf("Expected core with 3 users, got %s with %d", items[0].Name, items[0].UserCount)
+		}
+	})
+}
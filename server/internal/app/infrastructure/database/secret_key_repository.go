@@ -56,4 +56,16 @@ func (r *secretKeyRepository) Update(secretKey *entity.SecretKey) error {
 
 func (r *secretKeyRepository) Delete(id string) error {
 	return r.db.Delete(&entity.SecretKey{}, "id = ?", id).Error
-}
\ No newline at end of file
+}
+
+// RecordUsage acumula o contador de uso e atualiza os dados do último acesso
+func (r *secretKeyRepository) RecordUsage(usage *entity.SecretKeyUsage) error {
+	return r.db.Model(&entity.SecretKey{}).
+		Where("id = ?", usage.SecretKeyID).
+		UpdateColumns(map[string]interface{}{
+			"usage_count":     gorm.Expr("usage_count + ?", usage.Count),
+			"last_used_at":    usage.LastUsedAt,
+			"last_used_ip":    usage.LastIP,
+			"last_used_agent": usage.LastAgent,
+		}).Error
+}
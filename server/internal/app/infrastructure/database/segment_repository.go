@@ -0,0 +1,118 @@
+package database
+
+import (
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+)
+
+// SegmentRepositoryImpl implementa SegmentRepository
+type SegmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSegmentRepository cria uma nova instância de SegmentRepositoryImpl
+func NewSegmentRepository(db *gorm.DB) repository.SegmentRepository {
+	return &SegmentRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create cria um novo segmento
+func (r *SegmentRepositoryImpl) Create(segment *entity.Segment) error {
+	return r.db.Create(segment).Error
+}
+
+// GetByID busca um segmento por ID
+func (r *SegmentRepositoryImpl) GetByID(id string) (*entity.Segment, error) {
+	var segment entity.Segment
+	err := r.db.Where("id = ?", id).First(&segment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &segment, nil
+}
+
+// GetByIDs busca os segmentos com os IDs informados, ignorando os inexistentes
+func (r *SegmentRepositoryImpl) GetByIDs(ids []string) ([]*entity.Segment, error) {
+	segments := make([]*entity.Segment, 0)
+	if len(ids) == 0 {
+		return segments, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&segments).Error
+	return segments, err
+}
+
+// GetByName busca um segmento pelo nome dentro do escopo da aplicação, ou entre os globais quando appID é nil
+func (r *SegmentRepositoryImpl) GetByName(appID *string, name string) (*entity.Segment, error) {
+	var segment entity.Segment
+	err := scopeByApp(r.db, appID).Where("name = ?", name).First(&segment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &segment, nil
+}
+
+// GetByAppID busca os segmentos da aplicação, sem incluir os globais
+func (r *SegmentRepositoryImpl) GetByAppID(appID string) ([]*entity.Segment, error) {
+	segments := make([]*entity.Segment, 0)
+	err := r.db.Where("app_id = ?", appID).Order("name ASC").Find(&segments).Error
+	return segments, err
+}
+
+// GetGlobal busca os segmentos globais
+func (r *SegmentRepositoryImpl) GetGlobal() ([]*entity.Segment, error) {
+	segments := make([]*entity.Segment, 0)
+	err := r.db.Where("app_id IS NULL").Order("name ASC").Find(&segments).Error
+	return segments, err
+}
+
+// GetAvailableForApp busca os segmentos que os toggles da aplicação podem referenciar: os da aplicação e os globais
+func (r *SegmentRepositoryImpl) GetAvailableForApp(appID string) ([]*entity.Segment, error) {
+	segments := make([]*entity.Segment, 0)
+	err := r.db.Where("app_id = ? OR app_id IS NULL", appID).Order("name ASC").Find(&segments).Error
+	return segments, err
+}
+
+// Update atualiza um segmento
+func (r *SegmentRepositoryImpl) Update(segment *entity.Segment) error {
+	return r.db.Save(segment).Error
+}
+
+// Delete remove um segmento
+func (r *SegmentRepositoryImpl) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&entity.Segment{}).Error
+}
+
+// GetReferencingToggles busca os toggles com alguma regra que referencia o segmento
+func (r *SegmentRepositoryImpl) GetReferencingToggles(id string) ([]*entity.Toggle, error) {
+	// O filtro por texto seleciona os candidatos; a verificação exata é feita sobre as regras carregadas
+	candidates := r.db.Model(&entity.ToggleRule{}).
+		Select("toggle_id").
+		Where("conditions LIKE ? AND conditions LIKE ?", `%"type":"segment"%`, "%"+id+"%")
+
+	var toggles []*entity.Toggle
+	err := preloadAssociations(r.db).Where("id IN (?)", candidates).Order("path ASC").Find(&toggles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	referencing := make([]*entity.Toggle, 0, len(toggles))
+	for _, toggle := range toggles {
+		for _, segmentID := range toggle.SegmentIDs() {
+			if segmentID == id {
+				referencing = append(referencing, toggle)
+				break
+			}
+		}
+	}
+	return referencing, nil
+}
+
+// scopeByApp restringe a consulta aos registros da aplicação, ou aos globais quando appID é nil
+func scopeByApp(db *gorm.DB, appID *string) *gorm.DB {
+	if appID == nil {
+		return db.Where("app_id IS NULL")
+	}
+	return db.Where("app_id = ?", *appID)
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestSegmentRepository_Scopes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSegmentRepository(db)
+
+	app1 := entity.NewApplication("App One")
+	app2 := entity.NewApplication("App Two")
+	appRepo := NewApplicationRepository(db)
+	appRepo.Create(app1)
+	appRepo.Create(app2)
+
+	constraints := entity.SegmentConstraints{{Attribute: "user_id", Operator: entity.SegmentOperatorIn, Values: []string{"u1"}}}
+	global := entity.NewSegment(nil, "Global", "", constraints)
+	first := entity.NewSegment(&app1.ID, "First", "", constraints)
+	second := entity.NewSegment(&app2.ID, "Second", "", constraints)
+	for _, segment := range []*entity.Segment{global, first, second} {
+		if err := repo.Create(segment); err != nil {
+			t.Fatalf("Failed to create segment: %v", err)
+		}
+	}
+
+	loaded, err := repo.GetByID(first.ID)
+	if err != nil || len(loaded.Constraints) != 1 || loaded.Constraints[0].Values[0] != "u1" {
+		t.Fatalf("Expected constraints to be persisted, got %+v, %v", loaded, err)
+	}
+
+	available, _ := repo.GetAvailableForApp(app1.ID)
+	if len(available) != 2 || available[0].Name != "First" || available[1].Name != "Global" {
+		t.Errorf("Expected app and global segments, got %+v", available)
+	}
+	globals, _ := repo.GetGlobal()
+	if len(globals) != 1 || globals[0].ID != global.ID {
+		t.Errorf("Expected only the global segment, got %+v", globals)
+	}
+	if _, err := repo.GetByName(nil, "First"); err == nil {
+		t.Errorf("Expected application segment not to be found among global segments")
+	}
+	if found, err := repo.GetByName(&app2.ID, "Second"); err != nil || found.ID != second.ID {
+		t.Errorf("Expected segment to be found by name, got %+v, %v", found, err)
+	}
+
+	// Expurgar a aplicação da lixeira remove os seus segmentos, mas mantém os globais
+	if err := appRepo.Delete(app1.ID); err != nil {
+		t.Fatalf("Failed to delete application: %v", err)
+	}
+	if _, err := appRepo.Purge(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to purge application: %v", err)
+	}
+	remaining, _ := repo.GetByIDs([]string{global.ID, first.ID, second.ID})
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 remaining segments, got %d", len(remaining))
+	}
+}
+
+func TestSegmentRepository_GetReferencingToggles(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSegmentRepository(db)
+	toggleRepo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	NewApplicationRepository(db).Create(app)
+
+	constraints := entity.SegmentConstraints{{Attribute: "user_id", Operator: entity.SegmentOperatorIn, Values: []string{"u1"}}}
+	used := entity.NewSegment(nil, "Used", "", constraints)
+	unused := entity.NewSegment(nil, "Unused", "", constraints)
+	repo.Create(used)
+	repo.Create(unused)
+
+	toggle := entity.NewToggle("checkout", true, "checkout", 1, nil, app.ID)
+	toggleRepo.Create(toggle)
+	toggle.SetRules([]*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}}},
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeSegment, Value: "other," + used.ID}}},
+	})
+	if err := toggleRepo.UpdateWithRules(toggle); err != nil {
+		t.Fatalf("Failed to save rules: %v", err)
+	}
+
+	// Um toggle que apenas cita o ID em outro tipo de regra não é uma referência
+	other := entity.NewToggle("other", true, "other", 1, nil, app.ID)
+	toggleRepo.Create(other)
+	other.SetRules([]*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: unused.ID}}}})
+	toggleRepo.UpdateWithRules(other)
+
+	toggles, err := repo.GetReferencingToggles(used.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggles) != 1 || toggles[0].Path != "checkout" {
+		t.Errorf("Expected checkout to reference the segment, got %+v", toggles)
+	}
+
+	toggles, _ = repo.GetReferencingToggles(unused.ID)
+	if len(toggles) != 0 {
+		t.Errorf("Expected no references, got %d", len(toggles))
+	}
+}
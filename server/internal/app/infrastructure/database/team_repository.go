@@ -257,4 +257,50 @@ func (r *teamRepository) GetTeamWithCounts(id string) (*entity.TeamWithCounts, e
 		return nil, err
 	}
 	return &team, nil
-}
\ No newline at end of file
+}
+
+// teamListSpec define a ordenação e os filtros da listagem paginada de times
+var teamListSpec = &listSpec[*entity.TeamWithCounts]{
+	sorts: map[string]sortColumn[*entity.TeamWithCounts]{
+		"name":       {column: "name", kind: sortString, value: func(t *entity.TeamWithCounts) interface{} { return t.Name }},
+		"created_at": {column: "created_at", kind: sortTime, value: func(t *entity.TeamWithCounts) interface{} { return t.CreatedAt }},
+	},
+	filters: map[string]filterColumn{
+		"name": {column: "name", op: filterContains},
+	},
+	id: func(t *entity.TeamWithCounts) string { return t.ID },
+}
+
+// ListTeamsWithCounts lista os times com contagem de usuários e aplicações usando paginação por cursor
+func (r *teamRepository) ListTeamsWithCounts(page *entity.PageRequest) (*entity.Page[*entity.TeamWithCounts], error) {
+	base := r.db.Table("teams t").
+		Select(`
+			t.id,
+			t.name,
+			t.description,
+			t.created_at,
+			t.updated_at,
+			COALESCE(user_counts.user_count, 0) as user_count,
+			COALESCE(app_counts.application_count, 0) as application_count
+		`).
+		Joins(`LEFT JOIN (
+			SELECT team_id, COUNT(*) as user_count
+			FROM team_users
+			GROUP BY team_id
+		) user_counts ON t.id = user_counts.team_id`).
+		Joins(`LEFT JOIN (
+			SELECT team_id, COUNT(*) as application_count
+			FROM team_applications
+			GROUP BY team_id
+		) app_counts ON t.id = app_counts.team_id`)
+	return paginate(r.db, base, page, teamListSpec)
+}
+
+// ListUsersByTeamID lista os usuários de um time usando paginação por cursor
+func (r *teamRepository) ListUsersByTeamID(teamID string, page *entity.PageRequest) (*entity.Page[*entity.User], error) {
+	base := r.db.Model(&entity.User{}).
+		Select("users.*").
+		Joins("JOIN team_users ON team_users.user_id = users.id").
+		Where("team_users.team_id = ?", teamID)
+	return paginate(r.db, base, page, userListSpec)
+}
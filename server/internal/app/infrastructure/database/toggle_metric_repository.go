@@ -0,0 +1,67 @@
+package database
+
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ToggleMetricRepositoryImpl implementa ToggleMetricRepository
+type ToggleMetricRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewToggleMetricRepository cria uma nova instância de ToggleMetricRepositoryImpl
+func NewToggleMetricRepository(db *gorm.DB) repository.ToggleMetricRepository {
+	return &ToggleMetricRepositoryImpl{
+		db: db,
+	}
+}
+
+// Increment soma os contadores aos buckets existentes, criando os que ainda não existem
+func (r *ToggleMetricRepositoryImpl) Increment(metrics []*entity.ToggleMetric) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, metric := range metrics {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "toggle_id"}, {Name: "bucket_start"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"true_count":  gorm.Expr("toggle_metrics.true_count + ?", metric.TrueCount),
+					"false_count": gorm.Expr("toggle_metrics.false_count + ?", metric.FalseCount),
+					"updated_at":  time.Now().UTC(),
+				}),
+			}).Create(metric).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByToggleID busca os buckets de métricas de um toggle no intervalo [from, to)
+func (r *ToggleMetricRepositoryImpl) GetByToggleID(toggleID string, from, to time.Time) ([]*entity.ToggleMetric, error) {
+	var metrics []*entity.ToggleMetric
+	err := r.db.Where("toggle_id = ? AND bucket_start >= ? AND bucket_start < ?", toggleID, from, to).
+		Order("bucket_start").
+		Find(&metrics).Error
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// GetEvaluatedToggleIDs busca os IDs dos toggles da aplicação avaliados desde o instante informado
+func (r *ToggleMetricRepositoryImpl) GetEvaluatedToggleIDs(appID string, since time.Time) ([]string, error) {
+	var toggleIDs []string
+	err := r.db.Model(&entity.ToggleMetric{}).
+		Where("app_id = ? AND bucket_start >= ?", appID, since).
+		Distinct().
+		Pluck("toggle_id", &toggleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return toggleIDs, nil
+}
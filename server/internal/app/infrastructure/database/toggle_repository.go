@@ -1,6 +1,13 @@
 package database
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/domain/repository"
 	"gorm.io/gorm"
@@ -18,15 +25,23 @@ func NewToggleRepository(db *gorm.DB) repository.ToggleRepository {
 	}
 }
 
-// Create cria um novo toggle
-func (r *ToggleRepositoryImpl) Create(toggle *entity.Toggle) error {
-	return r.db.Create(toggle).Error
+// Create cria um novo toggle com as suas regras, pré-requisitos e revisões
+func (r *ToggleRepositoryImpl) Create(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createToggle(tx, toggle); err != nil {
+			return err
+		}
+		if err := restoreAssociations(tx, toggle); err != nil {
+			return err
+		}
+		return createRevisions(tx, revisions)
+	})
 }
 
 // GetByID busca um toggle por ID
 func (r *ToggleRepositoryImpl) GetByID(id string) (*entity.Toggle, error) {
 	var toggle entity.Toggle
-	err := r.db.Preload("Parent").Preload("Children").Where("id = ?", id).First(&toggle).Error
+	err := preloadAssociations(r.db).Preload("Parent").Preload("Children").Where("id = ?", id).First(&toggle).Error
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +51,7 @@ func (r *ToggleRepositoryImpl) GetByID(id string) (*entity.Toggle, error) {
 // GetByPath busca um toggle por caminho e appID
 func (r *ToggleRepositoryImpl) GetByPath(path string, appID string) (*entity.Toggle, error) {
 	var toggle entity.Toggle
-	err := r.db.Preload("Parent").Preload("Children").Where("path = ? AND app_id = ?", path, appID).First(&toggle).Error
+	err := preloadAssociations(r.db).Preload("Parent").Preload("Children").Where("path = ? AND app_id = ?", path, appID).First(&toggle).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +61,7 @@ func (r *ToggleRepositoryImpl) GetByPath(path string, appID string) (*entity.Tog
 // GetByAppID busca todos os toggles de uma aplicação
 func (r *ToggleRepositoryImpl) GetByAppID(appID string) ([]*entity.Toggle, error) {
 	var toggles []*entity.Toggle
-	err := r.db.Where("app_id = ?", appID).Find(&toggles).Error
+	err := preloadAssociations(r.db).Where("app_id = ?", appID).Find(&toggles).Error
 	if err != nil {
 		return nil, err
 	}
@@ -56,39 +71,364 @@ func (r *ToggleRepositoryImpl) GetByAppID(appID string) ([]*entity.Toggle, error
 // GetHierarchyByAppID busca todos os toggles de uma aplicação com hierarquia
 func (r *ToggleRepositoryImpl) GetHierarchyByAppID(appID string) ([]*entity.Toggle, error) {
 	var toggles []*entity.Toggle
-	err := r.db.Preload("Parent").Preload("Children").Where("app_id = ?", appID).Order("level, value").Find(&toggles).Error
+	err := preloadAssociations(r.db).Preload("Parent").Preload("Children").Where("app_id = ?", appID).Order("level, value").Find(&toggles).Error
 	if err != nil {
 		return nil, err
 	}
 	return toggles, nil
 }
 
-// Update atualiza um toggle
-func (r *ToggleRepositoryImpl) Update(toggle *entity.Toggle) error {
-	return r.db.Save(toggle).Error
+// GetByAppIDWithFilter busca os toggles de uma aplicação que atendem aos filtros de metadados
+func (r *ToggleRepositoryImpl) GetByAppIDWithFilter(appID string, filter *entity.ToggleFilter) ([]*entity.Toggle, error) {
+	query := preloadAssociations(r.db).Where("app_id = ?", appID)
+	if filter != nil {
+		if filter.Owner != "" {
+			query = query.Where("owner = ?", filter.Owner)
+		}
+		if filter.Kind != "" {
+			query = query.Where("kind = ?", filter.Kind)
+		}
+		// As tags são persistidas como array JSON; o LIKE pré-filtra e a verificação exata é feita abaixo
+		for _, tag := range filter.Tags {
+			query = query.Where("tags LIKE ?", "%\""+tag+"\"%")
+		}
+	}
+
+	var candidates []*entity.Toggle
+	if err := query.Order("level, value").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	toggles := make([]*entity.Toggle, 0, len(candidates))
+	for _, toggle := range candidates {
+		if filter.Matches(toggle) {
+			toggles = append(toggles, toggle)
+		}
+	}
+	return toggles, nil
+}
+
+// Update atualiza um toggle sem alterar as suas regras e pré-requisitos
+func (r *ToggleRepositoryImpl) Update(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rules", "Prerequisites").Save(toggle).Error; err != nil {
+			return err
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// UpdateWithRules atualiza um toggle e substitui as suas regras na mesma transação
+func (r *ToggleRepositoryImpl) UpdateWithRules(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rules", "Prerequisites").Save(toggle).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("toggle_id = ?", toggle.ID).Delete(&entity.ToggleRule{}).Error; err != nil {
+			return err
+		}
+		if len(toggle.Rules) > 0 {
+			if err := tx.Create(&toggle.Rules).Error; err != nil {
+				return err
+			}
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// UpdatePrerequisites substitui os pré-requisitos de um toggle na mesma transação
+func (r *ToggleRepositoryImpl) UpdatePrerequisites(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("toggle_id = ?", toggle.ID).Delete(&entity.TogglePrerequisite{}).Error; err != nil {
+			return err
+		}
+		if len(toggle.Prerequisites) > 0 {
+			if err := tx.Create(&toggle.Prerequisites).Error; err != nil {
+				return err
+			}
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// Restore grava o toggle com as suas regras e pré-requisitos em uma única transação
+func (r *ToggleRepositoryImpl) Restore(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := restoreToggle(tx, toggle); err != nil {
+			return err
+		}
+		return createRevisions(tx, revisions)
+	})
 }
 
-// Delete remove um toggle por ID e seus filhos em cascata
-func (r *ToggleRepositoryImpl) Delete(id string) error {
-	// Primeiro, deleta todos os filhos recursivamente
-	children, err := r.GetChildren(id)
+// RestoreApplication substitui todos os toggles da aplicação pelos informados em uma única transação.
+// Toggles que não estão na lista vão para a lixeira; os demais mantêm o ID, inclusive os que estavam
+// na lixeira, e são gravados pais antes dos filhos. Os pré-requisitos de toggles fora da lista que
+// apontam para os regravados são removidos antes deles e gravados de novo no fim.
+func (r *ToggleRepositoryImpl) RestoreApplication(appID string, toggles []*entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	ordered := append([]*entity.Toggle{}, toggles...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Level < ordered[j].Level })
+	ids := make([]string, 0, len(ordered))
+	for _, toggle := range ordered {
+		ids = append(ids, toggle.ID)
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		dependents := make([]*entity.TogglePrerequisite, 0)
+		if len(ids) > 0 {
+			if err := tx.Where("toggle_id IN ?", ids).Delete(&entity.ToggleRule{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("toggle_id IN ?", ids).Delete(&entity.TogglePrerequisite{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("prerequisite_id IN ?", ids).Find(&dependents).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("prerequisite_id IN ?", ids).Delete(&entity.TogglePrerequisite{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("id IN ?", ids).Delete(&entity.Toggle{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("app_id = ?", appID).Delete(&entity.Toggle{}).Error; err != nil {
+			return err
+		}
+
+		for _, toggle := range ordered {
+			if err := createToggle(tx, toggle); err != nil {
+				return err
+			}
+		}
+		for _, toggle := range ordered {
+			if err := restoreAssociations(tx, toggle); err != nil {
+				return err
+			}
+		}
+		if len(dependents) > 0 {
+			if err := tx.Create(&dependents).Error; err != nil {
+				return err
+			}
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// ApplyBatch grava o resultado de um lote em uma única transação: cria os toggles novos, pais antes
+// dos filhos, grava o enabled, as variantes e as regras dos alterados e move os removidos para a
+// lixeira no mesmo instante, para que voltem juntos. Os toggles alterados e removidos foram lidos
+// antes da transação; se algum mudou desde então o lote é desfeito com ErrToggleChanged.
+func (r *ToggleRepositoryImpl) ApplyBatch(created []*entity.Toggle, updated []*entity.Toggle, deleted []*entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	ordered := append([]*entity.Toggle{}, created...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Level < ordered[j].Level })
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, toggle := range ordered {
+			if err := createToggle(tx, toggle); err != nil {
+				return err
+			}
+			if err := restoreAssociations(tx, toggle); err != nil {
+				return err
+			}
+		}
+		for _, toggle := range updated {
+			if err := checkUnchanged(tx, toggle); err != nil {
+				return err
+			}
+			if err := updateBatchFields(tx, toggle); err != nil {
+				return err
+			}
+		}
+		ids := make([]string, 0, len(deleted))
+		for _, toggle := range deleted {
+			if err := checkUnchanged(tx, toggle); err != nil {
+				return err
+			}
+			ids = append(ids, toggle.ID)
+		}
+		if len(ids) > 0 {
+			if err := tx.Where("id IN ?", ids).Delete(&entity.Toggle{}).Error; err != nil {
+				return err
+			}
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// checkUnchanged verifica, dentro da transação, se o toggle continua como foi lido
+func checkUnchanged(tx *gorm.DB, toggle *entity.Toggle) error {
+	var current entity.Toggle
+	err := tx.Select("id", "updated_at").Where("id = ?", toggle.ID).First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ErrToggleChanged
+	}
 	if err != nil {
 		return err
 	}
+	if !current.UpdatedAt.Equal(toggle.UpdatedAt) {
+		return entity.ErrToggleChanged
+	}
+	return nil
+}
 
-	for _, child := range children {
-		err = r.Delete(child.ID)
+// updateBatchFields grava apenas os campos que um lote altera, sem tocar nos metadados e nos pré-requisitos
+func updateBatchFields(tx *gorm.DB, toggle *entity.Toggle) error {
+	err := tx.Model(toggle).
+		Select("enabled", "variants", "has_activation_rule", "rule_type", "rule_value", "rule_config", "rule_variant").
+		Updates(toggle).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("toggle_id = ?", toggle.ID).Delete(&entity.ToggleRule{}).Error; err != nil {
+		return err
+	}
+	if len(toggle.Rules) == 0 {
+		return nil
+	}
+	return tx.Create(&toggle.Rules).Error
+}
+
+// createToggle cria o toggle sem as suas regras e pré-requisitos, que são gravados à parte.
+// O default da coluna enabled substitui o false na criação, então ele é gravado depois; a
+// atualização usa um modelo vazio para não gravar as associações do toggle.
+func createToggle(tx *gorm.DB, toggle *entity.Toggle) error {
+	enabled := toggle.Enabled
+	if err := tx.Omit("Rules", "Prerequisites", "Parent", "Children").Create(toggle).Error; err != nil {
+		return err
+	}
+	if enabled {
+		return nil
+	}
+	toggle.Enabled = false
+	return tx.Model(&entity.Toggle{}).Where("id = ?", toggle.ID).UpdateColumn("enabled", false).Error
+}
+
+// restoreToggle grava o toggle e substitui as suas regras e pré-requisitos
+func restoreToggle(tx *gorm.DB, toggle *entity.Toggle) error {
+	if err := tx.Omit("Rules", "Prerequisites", "Parent", "Children").Save(toggle).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("toggle_id = ?", toggle.ID).Delete(&entity.ToggleRule{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("toggle_id = ?", toggle.ID).Delete(&entity.TogglePrerequisite{}).Error; err != nil {
+		return err
+	}
+	return restoreAssociations(tx, toggle)
+}
+
+// restoreAssociations grava as regras e os pré-requisitos do toggle
+func restoreAssociations(tx *gorm.DB, toggle *entity.Toggle) error {
+	if len(toggle.Rules) > 0 {
+		if err := tx.Create(&toggle.Rules).Error; err != nil {
+			return err
+		}
+	}
+	if len(toggle.Prerequisites) > 0 {
+		if err := tx.Create(&toggle.Prerequisites).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDependents busca os toggles que declaram algum dos toggles informados como pré-requisito
+func (r *ToggleRepositoryImpl) GetDependents(toggleIDs []string) ([]*entity.Toggle, error) {
+	toggles := make([]*entity.Toggle, 0)
+	if len(toggleIDs) == 0 {
+		return toggles, nil
+	}
+	dependents := r.db.Model(&entity.TogglePrerequisite{}).Select("toggle_id").Where("prerequisite_id IN ?", toggleIDs)
+	err := preloadAssociations(r.db).Where("id IN (?)", dependents).Order("path").Find(&toggles).Error
+	return toggles, err
+}
+
+// preloadAssociations carrega as regras dos toggles na ordem de avaliação e os seus pré-requisitos
+func preloadAssociations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Rules", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("position")
+	}).Preload("Prerequisites", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("created_at, id")
+	})
+}
+
+// Delete move um toggle e seus filhos para a lixeira no mesmo instante, para que voltem juntos.
+// As regras e os pré-requisitos são mantidos até o expurgo.
+func (r *ToggleRepositoryImpl) Delete(id string, revisions ...*entity.ToggleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids, err := subtreeIDs(tx, id)
 		if err != nil {
 			return err
 		}
+		if err := tx.Where("id IN ?", ids).Delete(&entity.Toggle{}).Error; err != nil {
+			return err
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// subtreeIDs retorna o ID do toggle e de todos os seus descendentes
+func subtreeIDs(tx *gorm.DB, id string) ([]string, error) {
+	ids := []string{id}
+	var children []string
+	if err := tx.Model(&entity.Toggle{}).Where("parent_id = ?", id).Pluck("id", &children).Error; err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		descendants, err := subtreeIDs(tx, child)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, descendants...)
 	}
+	return ids, nil
+}
 
-	// Depois deleta o toggle pai
-	return r.db.Where("id = ?", id).Delete(&entity.Toggle{}).Error
+// GetTrashByAppID busca os toggles da aplicação que estão na lixeira, dos removidos mais recentemente
+func (r *ToggleRepositoryImpl) GetTrashByAppID(appID string) ([]*entity.Toggle, error) {
+	toggles := make([]*entity.Toggle, 0)
+	err := preloadAssociations(r.db.Unscoped()).
+		Where("app_id = ? AND deleted_at IS NOT NULL", appID).
+		Order("deleted_at DESC, path").
+		Find(&toggles).Error
+	return toggles, err
+}
+
+// Undelete tira os toggles informados da lixeira
+func (r *ToggleRepositoryImpl) Undelete(ids []string, revisions ...*entity.ToggleRevision) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&entity.Toggle{}).Where("id IN ?", ids).UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return createRevisions(tx, revisions)
+	})
+}
+
+// Purge remove definitivamente os toggles que estão na lixeira desde antes do instante informado,
+// com as suas regras, os seus pré-requisitos e os pré-requisitos que apontam para eles
+func (r *ToggleRepositoryImpl) Purge(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&entity.Toggle{}).Select("id").Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+		if err := tx.Where("toggle_id IN (?)", expired).Delete(&entity.ToggleRule{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("toggle_id IN (?) OR prerequisite_id IN (?)", expired, expired).Delete(&entity.TogglePrerequisite{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&entity.Toggle{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
 }
 
 // DeleteByPath remove um toggle e seus filhos por caminho
-func (r *ToggleRepositoryImpl) DeleteByPath(path string, appID string) error {
+func (r *ToggleRepositoryImpl) DeleteByPath(path string, appID string, revisions ...*entity.ToggleRevision) error {
 	// Busca o toggle
 	toggle, err := r.GetByPath(path, appID)
 	if err != nil {
@@ -96,7 +436,7 @@ func (r *ToggleRepositoryImpl) DeleteByPath(path string, appID string) error {
 	}
 
 	// Remove o toggle e seus filhos
-	return r.Delete(toggle.ID)
+	return r.Delete(toggle.ID, revisions...)
 }
 
 // Exists verifica se um toggle existe
@@ -112,9 +452,143 @@ func (r *ToggleRepositoryImpl) Exists(path string, appID string) (bool, error) {
 // GetChildren busca os filhos de um toggle
 func (r *ToggleRepositoryImpl) GetChildren(parentID string) ([]*entity.Toggle, error) {
 	var children []*entity.Toggle
-	err := r.db.Where("parent_id = ?", parentID).Find(&children).Error
+	err := preloadAssociations(r.db).Where("parent_id = ?", parentID).Find(&children).Error
 	if err != nil {
 		return nil, err
 	}
 	return children, nil
 }
+
+// searchRuleExpr verifica se o termo aparece no valor da regra simples ou no valor de alguma
+// condição das regras compostas, gravadas como JSON em toggle_rules.conditions
+const searchRuleExpr = `(toggles.rule_value LIKE ? ESCAPE '\' OR EXISTS (
+		SELECT 1 FROM toggle_rules, json_each(toggle_rules.conditions) AS condition
+		WHERE toggle_rules.toggle_id = toggles.id AND json_extract(condition.value, '$.value') LIKE ? ESCAPE '\'))`
+
+// searchScoreExpr calcula a pontuação de cada toggle na busca; é a única fonte da ordenação por
+// relevância. No SQLite o LIKE já ignora maiúsculas e minúsculas, evitando LOWER() em cada linha.
+var searchScoreExpr = fmt.Sprintf(`CASE
+	WHEN toggles.path = ? COLLATE NOCASE THEN %d
+	WHEN toggles.value = ? COLLATE NOCASE THEN %d
+	WHEN toggles.path LIKE ? ESCAPE '\' THEN %d
+	WHEN toggles.value LIKE ? ESCAPE '\' THEN %d
+	WHEN toggles.path LIKE ? ESCAPE '\' THEN %d
+	WHEN toggles.description LIKE ? ESCAPE '\' THEN %d
+	WHEN %s THEN %d
+	ELSE 0 END`,
+	entity.SearchScoreExactPath, entity.SearchScoreExactValue, entity.SearchScorePathPrefix, entity.SearchScoreValuePrefix,
+	entity.SearchScorePathContains, entity.SearchScoreDescription, searchRuleExpr, entity.SearchScoreRuleValue)
+
+// searchRow representa uma linha da busca com o total de resultados calculado pelo banco
+type searchRow struct {
+	entity.ToggleSearchResult
+	TotalCount int64
+}
+
+// Search busca toggles por caminho, nome, descrição e valores das regras, ordenados por relevância.
+// Os candidatos vêm do índice toggle_search (migração 20250915) e apenas eles são conferidos com
+// LIKE; o total é calculado na mesma consulta com COUNT(*) OVER().
+func (r *ToggleRepositoryImpl) Search(query *entity.ToggleSearchQuery) ([]*entity.ToggleSearchResult, int64, error) {
+	results := []*entity.ToggleSearchResult{}
+	if !query.AllApplications && len(query.AppIDs) == 0 {
+		return results, 0, nil
+	}
+
+	var rows []*searchRow
+	if err := searchQuery(r.db, query).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Página além do último resultado: o total precisa de uma consulta própria
+	if len(rows) == 0 {
+		var total int64
+		if query.Offset > 0 {
+			if err := searchFilter(r.db, query).Count(&total).Error; err != nil {
+				return nil, 0, err
+			}
+		}
+		return results, total, nil
+	}
+
+	for _, row := range rows {
+		result := row.ToggleSearchResult
+		result.MatchedField = entity.SearchMatchFieldForScore(result.Score)
+		results = append(results, &result)
+	}
+	return results, rows[0].TotalCount, nil
+}
+
+// searchFilter seleciona os toggles das aplicações acessíveis em que o termo aparece. O CROSS JOIN
+// fixa a ordem das tabelas no SQLite: os candidatos do índice guiam a consulta e cada toggle é lido
+// pela chave primária, em vez de o planejador percorrer os toggles pelo índice de app_id.
+func searchFilter(db *gorm.DB, query *entity.ToggleSearchQuery) *gorm.DB {
+	contains := "%" + escapeLike(query.Term) + "%"
+	condition, value := searchCandidates(query.Term)
+	db = db.Table("(SELECT toggle_id FROM toggle_search WHERE "+condition+") AS candidates", value).
+		Joins("CROSS JOIN toggles ON toggles.id = candidates.toggle_id").
+		Joins("JOIN applications ON applications.id = toggles.app_id").
+		Where("toggles.deleted_at IS NULL AND applications.deleted_at IS NULL").
+		Where(`(toggles.path LIKE ? ESCAPE '\' OR toggles.description LIKE ? ESCAPE '\' OR `+searchRuleExpr+`)`, contains, contains, contains, contains)
+	if !query.AllApplications {
+		db = db.Where("toggles.app_id IN ?", query.AppIDs)
+	}
+	return db
+}
+
+// searchQuery seleciona a página de resultados com a pontuação e o total de cada toggle
+func searchQuery(db *gorm.DB, query *entity.ToggleSearchQuery) *gorm.DB {
+	term := query.Term
+	escaped := escapeLike(term)
+	prefix := escaped + "%"
+	contains := "%" + escaped + "%"
+
+	return searchFilter(db, query).
+		Select(`toggles.id AS toggle_id, toggles.app_id, applications.name AS app_name, toggles.path, toggles.value,
+			COALESCE(toggles.description, '') AS description, toggles.enabled, COALESCE(toggles.rule_value, '') AS rule_value,
+			`+searchScoreExpr+` AS score, COUNT(*) OVER() AS total_count`,
+			term, term, prefix, prefix, contains, contains, contains, contains).
+		Order("score DESC, toggles.path, applications.name").
+		Limit(query.Limit).
+		Offset(query.Offset)
+}
+
+// searchCandidates monta a condição do índice toggle_search para o termo. O tokenizador trigram
+// atende a frase entre aspas como substring; termos com menos de três caracteres não formam um
+// trigrama e são comparados com LIKE sobre o próprio índice.
+func searchCandidates(term string) (string, string) {
+	if utf8.RuneCountInString(term) < 3 {
+		return "document LIKE ?", "%" + term + "%"
+	}
+	return "document MATCH ?", `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+}
+
+// escapeLike escapa os caracteres especiais do LIKE para que o termo seja buscado literalmente
+func escapeLike(term string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(term)
+}
+
+// toggleListSpec define a ordenação e os filtros da listagem paginada de toggles
+var toggleListSpec = &listSpec[*entity.Toggle]{
+	sorts: map[string]sortColumn[*entity.Toggle]{
+		"path":       {column: "path", kind: sortString, value: func(t *entity.Toggle) interface{} { return t.Path }},
+		"value":      {column: "value", kind: sortString, value: func(t *entity.Toggle) interface{} { return t.Value }},
+		"level":      {column: "level", kind: sortInt, value: func(t *entity.Toggle) interface{} { return t.Level }},
+		"created_at": {column: "created_at", kind: sortTime, value: func(t *entity.Toggle) interface{} { return t.CreatedAt }},
+		"updated_at": {column: "updated_at", kind: sortTime, value: func(t *entity.Toggle) interface{} { return t.UpdatedAt }},
+	},
+	filters: map[string]filterColumn{
+		"path":    {column: "path", op: filterContains},
+		"enabled": {column: "enabled", op: filterBool},
+		"owner":   {column: "owner", op: filterEquals},
+		"kind":    {column: "kind", op: filterEquals},
+		"tag":     {column: "tags", op: filterJSONArray},
+	},
+	id:      func(t *entity.Toggle) string { return t.ID },
+	preload: preloadAssociations,
+}
+
+// ListByAppID lista os toggles de uma aplicação com paginação por cursor, ordenação e filtros
+func (r *ToggleRepositoryImpl) ListByAppID(appID string, page *entity.PageRequest) (*entity.Page[*entity.Toggle], error) {
+	base := r.db.Model(&entity.Toggle{}).Where("app_id = ?", appID)
+	return paginate(r.db, base, page, toggleListSpec)
+}
@@ -1,9 +1,17 @@
 package database
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func TestToggleRepository_Create(t *testing.T) {
@@ -28,6 +36,19 @@ func TestToggleRepository_Create(t *testing.T) {
 	if toggle.ID == "" {
 		t.Error("Expected ID to be generated")
 	}
+
+	// O default da coluna enabled não pode substituir um toggle criado desligado
+	disabled := entity.NewToggle("off", false, "test.off", 1, nil, app.ID)
+	if err := repo.Create(disabled); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	loaded, err := repo.GetByID(disabled.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loaded.Enabled || disabled.Enabled {
+		t.Error("Expected the toggle to be created disabled")
+	}
 }
 
 func TestToggleRepository_GetByPath(t *testing.T) {
@@ -508,3 +529,698 @@ func TestToggleRepository_Delete_Cascade(t *testing.T) {
 		t.Error("Expected grandchild toggle to be deleted")
 	}
 }
+
+func TestToggleRepository_GetByAppIDWithFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+
+	appRepo := NewApplicationRepository(db)
+	app := entity.NewApplication("Test App")
+	if err := appRepo.Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+
+	checkout := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	checkout.SetMetadata(&entity.ToggleMetadata{Owner: "payments", Tags: []string{"q3", "web"}, Kind: entity.ToggleKindRelease})
+	search := entity.NewToggle("search", true, "search", 0, nil, app.ID)
+	search.SetMetadata(&entity.ToggleMetadata{Owner: "search", Tags: []string{"q3"}, Kind: entity.ToggleKindExperiment})
+	// "q_" não pode casar com "q3" apesar do curinga do LIKE
+	wildcard := entity.NewToggle("wildcard", true, "wildcard", 0, nil, app.ID)
+	wildcard.SetMetadata(&entity.ToggleMetadata{Tags: []string{"q_"}, Kind: entity.ToggleKindOps})
+
+	for _, toggle := range []*entity.Toggle{checkout, search, wildcard} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   *entity.ToggleFilter
+		expected []string
+	}{
+		{"by tag", &entity.ToggleFilter{Tags: []string{"q3"}}, []string{"checkout", "search"}},
+		{"by multiple tags", &entity.ToggleFilter{Tags: []string{"q3", "web"}}, []string{"checkout"}},
+		{"by owner", &entity.ToggleFilter{Owner: "search"}, []string{"search"}},
+		{"by kind", &entity.ToggleFilter{Kind: entity.ToggleKindOps}, []string{"wildcard"}},
+		{"underscore is not a wildcard", &entity.ToggleFilter{Tags: []string{"q_"}}, []string{"wildcard"}},
+		{"no match", &entity.ToggleFilter{Owner: "nobody"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toggles, err := repo.GetByAppIDWithFilter(app.ID, tt.filter)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(toggles) != len(tt.expected) {
+				t.Fatalf("Expected %d toggles, got %d", len(tt.expected), len(toggles))
+			}
+			for i, toggle := range toggles {
+				if toggle.Path != tt.expected[i] {
+					t.Errorf("Expected toggle %s at position %d, got %s", tt.expected[i], i, toggle.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestToggleRepository_UpdateWithRules(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	toggle := entity.NewToggle("checkout", true, "checkout", 1, nil, app.ID)
+	if err := repo.Create(toggle); err != nil {
+		t.Fatalf("Failed to create test toggle: %v", err)
+	}
+
+	toggle.SetRules([]*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: "u1"}}},
+		{Conditions: entity.RuleConditions{
+			{Type: entity.ActivationRuleTypeCountry, Value: "BR"},
+			{Type: entity.ActivationRuleTypePercentage, Value: "10"},
+		}},
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeIP, Value: "10.0.0.1"}}},
+	})
+	if err := repo.UpdateWithRules(toggle); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	loaded, err := repo.GetByID(toggle.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(loaded.Rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %d", len(loaded.Rules))
+	}
+	for i, rule := range loaded.Rules {
+		if rule.Position != i {
+			t.Errorf("Expected rule %d at position %d, got %d", i, i, rule.Position)
+		}
+	}
+	if len(loaded.Rules[1].Conditions) != 2 || loaded.Rules[1].Conditions[0].Value != "BR" {
+		t.Errorf("Unexpected conditions %+v", loaded.Rules[1].Conditions)
+	}
+
+	// Update simples não altera as regras
+	loaded.Enabled = false
+	if err := repo.Update(loaded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	toggles, _ := repo.GetByAppID(app.ID)
+	if len(toggles) != 1 || len(toggles[0].Rules) != 3 {
+		t.Fatalf("Expected rules to be kept by Update")
+	}
+
+	// Substituir por uma única regra remove as demais
+	loaded.SetRules([]*entity.ToggleRule{loaded.Rules[2]})
+	if err := repo.UpdateWithRules(loaded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	reloaded, _ := repo.GetByID(toggle.ID)
+	if len(reloaded.Rules) != 1 || reloaded.Rules[0].Conditions[0].Type != entity.ActivationRuleTypeIP {
+		t.Errorf("Expected only the ip rule, got %+v", reloaded.Rules)
+	}
+	if reloaded.ActivationRule == nil || reloaded.ActivationRule.Value != "10.0.0.1" {
+		t.Errorf("Expected legacy rule mirror to be persisted, got %+v", reloaded.ActivationRule)
+	}
+
+	// As regras ficam na lixeira com o toggle e são removidas no expurgo
+	if err := repo.Delete(toggle.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var count int64
+	db.Model(&entity.ToggleRule{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected rules to be kept in the trash, got %d", count)
+	}
+	if _, err := repo.Purge(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	db.Model(&entity.ToggleRule{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected rules to be purged with the toggle, got %d", count)
+	}
+}
+
+func TestToggleRepository_UpdatePrerequisites(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	payment := entity.NewToggle("payment", true, "payment", 0, nil, app.ID)
+	invoice := entity.NewToggle("invoice", true, "invoice", 0, nil, app.ID)
+	legacy := entity.NewToggle("legacy", true, "legacy", 0, nil, app.ID)
+	for _, toggle := range []*entity.Toggle{payment, invoice, legacy} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	payment.SetPrerequisites([]*entity.TogglePrerequisite{
+		{PrerequisiteID: invoice.ID, Enabled: true},
+		{PrerequisiteID: legacy.ID, Enabled: false},
+	})
+	if err := repo.UpdatePrerequisites(payment); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	loaded, err := repo.GetByID(payment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(loaded.Prerequisites) != 2 {
+		t.Fatalf("Expected 2 prerequisites, got %d", len(loaded.Prerequisites))
+	}
+	for _, prerequisite := range loaded.Prerequisites {
+		if prerequisite.Enabled != (prerequisite.PrerequisiteID == invoice.ID) {
+			t.Errorf("Unexpected required state %+v", prerequisite)
+		}
+	}
+
+	dependents, err := repo.GetDependents([]string{legacy.ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].ID != payment.ID {
+		t.Errorf("Expected payment as dependent, got %+v", dependents)
+	}
+
+	// Substituir os pré-requisitos remove os anteriores
+	loaded.SetPrerequisites([]*entity.TogglePrerequisite{{PrerequisiteID: invoice.ID, Enabled: true}})
+	if err := repo.UpdatePrerequisites(loaded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if dependents, _ := repo.GetDependents([]string{legacy.ID}); len(dependents) != 0 {
+		t.Errorf("Expected no dependents, got %+v", dependents)
+	}
+
+	// Expurgar o toggle da lixeira remove os seus pré-requisitos
+	if err := repo.Delete(payment.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.Purge(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var count int64
+	db.Model(&entity.TogglePrerequisite{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected prerequisites to be purged with the toggle, got %d", count)
+	}
+}
+
+func TestToggleRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+	setupSearchIndex(t, db)
+	repo := NewToggleRepository(db)
+	appRepo := NewApplicationRepository(db)
+
+	shop := entity.NewApplication("shop")
+	admin := entity.NewApplication("admin")
+	for _, app := range []*entity.Application{shop, admin} {
+		if err := appRepo.Create(app); err != nil {
+			t.Fatalf("Failed to create test application: %v", err)
+		}
+	}
+
+	exact := entity.NewToggle("new-checkout", true, "new-checkout", 0, nil, shop.ID)
+	nested := entity.NewToggle("new-checkout", true, "payments.new-checkout", 1, nil, shop.ID)
+	described := entity.NewToggle("flow", true, "flow", 0, nil, admin.ID)
+	described.Description = "Gradual rollout of the NEW-CHECKOUT page"
+	withRule := entity.NewToggle("beta", true, "beta", 0, nil, admin.ID)
+	withRule.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeParameter, Value: "new-checkout-beta"})
+	literal := entity.NewToggle("new_checkout_v2", true, "new_checkout_v2", 0, nil, admin.ID)
+	composite := entity.NewToggle("rollout", true, "rollout", 0, nil, admin.ID)
+	composite.SetRules([]*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}, {Type: entity.ActivationRuleTypePercentage, Value: "10"}}},
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: "new-checkout-qa"}}},
+	})
+
+	for _, toggle := range []*entity.Toggle{exact, nested, described, withRule, literal, composite} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	results, total, err := repo.Search(&entity.ToggleSearchQuery{Term: "new-checkout", AllApplications: true, Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		path  string
+		field entity.SearchMatchField
+		score int
+	}{
+		{"new-checkout", entity.SearchMatchPath, entity.SearchScoreExactPath},
+		{"payments.new-checkout", entity.SearchMatchValue, entity.SearchScoreExactValue},
+		{"flow", entity.SearchMatchDescription, entity.SearchScoreDescription},
+		{"beta", entity.SearchMatchRuleValue, entity.SearchScoreRuleValue},
+		{"rollout", entity.SearchMatchRuleValue, entity.SearchScoreRuleValue},
+	}
+	if total != int64(len(expected)) || len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d (total %d)", len(expected), len(results), total)
+	}
+	for i, want := range expected {
+		if results[i].Path != want.path || results[i].MatchedField != want.field || results[i].Score != want.score {
+			t.Errorf("Result %d: expected %s/%s/%d, got %s/%s/%d", i, want.path, want.field, want.score,
+				results[i].Path, results[i].MatchedField, results[i].Score)
+		}
+	}
+	if results[0].AppName != "shop" {
+		t.Errorf("Expected application name 'shop', got %q", results[0].AppName)
+	}
+
+	// Os nomes das chaves do JSON das condições não são pesquisáveis
+	if results, _, _ := repo.Search(&entity.ToggleSearchQuery{Term: "type", AllApplications: true, Limit: 10}); len(results) != 0 {
+		t.Errorf("Expected condition keys not to match, got %d results", len(results))
+	}
+
+	// Underscore é buscado literalmente
+	results, _, _ = repo.Search(&entity.ToggleSearchQuery{Term: "new_", AllApplications: true, Limit: 10})
+	if len(results) != 1 || results[0].Path != "new_checkout_v2" {
+		t.Errorf("Expected only new_checkout_v2 for literal underscore, got %d results", len(results))
+	}
+
+	// Restrito às aplicações acessíveis e paginado
+	results, total, _ = repo.Search(&entity.ToggleSearchQuery{Term: "new-checkout", AppIDs: []string{shop.ID}, Limit: 1, Offset: 1})
+	if total != 2 || len(results) != 1 || results[0].Path != "payments.new-checkout" {
+		t.Errorf("Expected second shop result, got %d results (total %d)", len(results), total)
+	}
+
+	// Sem aplicações acessíveis não há resultados
+	results, total, _ = repo.Search(&entity.ToggleSearchQuery{Term: "new-checkout", AppIDs: []string{}, Limit: 10})
+	if total != 0 || len(results) != 0 {
+		t.Errorf("Expected no results without accessible applications, got %d", len(results))
+	}
+
+	// Termos de dois caracteres não formam um trigrama e também são encontrados
+	results, _, _ = repo.Search(&entity.ToggleSearchQuery{Term: "br", AllApplications: true, Limit: 10})
+	if len(results) != 1 || results[0].Path != "rollout" {
+		t.Errorf("Expected only rollout for a two-character term, got %d results", len(results))
+	}
+
+	// Toggles na lixeira ficam fora da busca
+	if err := repo.Delete(nested.ID); err != nil {
+		t.Fatalf("Failed to delete test toggle: %v", err)
+	}
+	results, total, _ = repo.Search(&entity.ToggleSearchQuery{Term: "new-checkout", AppIDs: []string{shop.ID}, Limit: 10})
+	if total != 1 || len(results) != 1 || results[0].Path != "new-checkout" {
+		t.Errorf("Expected trashed toggles to be hidden from search, got %d results (total %d)", len(results), total)
+	}
+}
+
+// O índice de busca acompanha as alterações dos toggles e das regras pelos triggers
+func TestToggleRepository_SearchIndexSync(t *testing.T) {
+	db := setupTestDB(t)
+	setupSearchIndex(t, db)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("shop")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	toggle := entity.NewToggle("banner", true, "home.banner", 1, nil, app.ID)
+	toggle.SetRules([]*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: "black-friday"}}}})
+	if err := repo.Create(toggle); err != nil {
+		t.Fatalf("Failed to create test toggle: %v", err)
+	}
+
+	found := func(term string) bool {
+		t.Helper()
+		results, _, err := repo.Search(&entity.ToggleSearchQuery{Term: term, AllApplications: true, Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return len(results) == 1
+	}
+
+	if !found("ck-fri") || !found("BANNER") || found("summer") {
+		t.Error("Expected the new toggle to be indexed by path and rule value")
+	}
+
+	toggle.Description = "Summer campaign"
+	toggle.SetRules([]*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}, {Type: entity.ActivationRuleTypeUserID, Value: "cyber-monday"}}}})
+	if err := repo.UpdateWithRules(toggle); err != nil {
+		t.Fatalf("Failed to update test toggle: %v", err)
+	}
+	if !found("summer") || !found("cyber") || found("black-friday") {
+		t.Error("Expected the index to follow the description and the replaced rules")
+	}
+
+	var indexed int64
+	db.Table("toggle_search").Count(&indexed)
+	if indexed != 1 {
+		t.Errorf("Expected one index row per toggle, got %d", indexed)
+	}
+
+	if err := db.Unscoped().Where("toggle_id = ?", toggle.ID).Delete(&entity.ToggleRule{}).Error; err != nil {
+		t.Fatalf("Failed to delete rules: %v", err)
+	}
+	if err := db.Unscoped().Delete(&entity.Toggle{}, "id = ?", toggle.ID).Error; err != nil {
+		t.Fatalf("Failed to delete test toggle: %v", err)
+	}
+	db.Table("toggle_search").Count(&indexed)
+	if indexed != 0 {
+		t.Errorf("Expected deleted toggle to leave the index, got %d rows", indexed)
+	}
+}
+
+// A busca não percorre a tabela de toggles: os candidatos vêm do índice de trigramas e
+// cada toggle é lido pela chave primária
+func TestToggleRepository_SearchQueryPlan(t *testing.T) {
+	db := setupTestDB(t)
+	setupSearchIndex(t, db)
+
+	for _, query := range []*entity.ToggleSearchQuery{
+		{Term: "new-checkout", AllApplications: true, Limit: 20},
+		{Term: "ab", AppIDs: []string{"app1", "app2"}, Limit: 20, Offset: 20},
+	} {
+		statement := db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)}).ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var rows []*searchRow
+			return searchQuery(tx, query).Scan(&rows)
+		})
+
+		rows, err := db.Raw("EXPLAIN QUERY PLAN " + statement).Rows()
+		if err != nil {
+			t.Fatalf("Failed to explain search query: %v", err)
+		}
+		var plan []string
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			rows.Scan(&id, &parent, &unused, &detail)
+			plan = append(plan, detail)
+		}
+		rows.Close()
+
+		usesIndex := false
+		for _, detail := range plan {
+			if strings.HasPrefix(detail, "SCAN toggle_search VIRTUAL TABLE INDEX") {
+				usesIndex = true
+			}
+			// Os toggles só podem ser lidos pela chave primária, a partir dos candidatos do índice
+			if (strings.HasPrefix(detail, "SCAN toggles") || strings.HasPrefix(detail, "SEARCH toggles ")) &&
+				!strings.HasPrefix(detail, "SEARCH toggles USING INDEX sqlite_autoindex_toggles_1 (id=?)") {
+				t.Errorf("Expected toggles to be read by primary key for %q, got plan %v", query.Term, plan)
+			}
+		}
+		if !usesIndex {
+			t.Errorf("Expected the search index to be used for %q, got plan %v", query.Term, plan)
+		}
+	}
+}
+
+// BenchmarkToggleRepository_Search mede a busca com 100 mil toggles. Os candidatos vêm do
+// índice de trigramas; apenas eles são conferidos com LIKE.
+func BenchmarkToggleRepository_Search(b *testing.B) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		b.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.ToggleRule{}, &entity.TogglePrerequisite{}, &entity.Segment{}); err != nil {
+		b.Fatalf("Failed to migrate test database: %v", err)
+	}
+	setupSearchIndex(b, db)
+
+	app := entity.NewApplication("bench")
+	db.Create(app)
+
+	toggles := make([]*entity.Toggle, 0, 100000)
+	for i := 0; i < 100000; i++ {
+		value := fmt.Sprintf("feature-%d", i)
+		toggles = append(toggles, entity.NewToggle(value, true, "module.feature."+value, 2, nil, app.ID))
+	}
+	if err := db.CreateInBatches(toggles, 500).Error; err != nil {
+		b.Fatalf("Failed to create toggles: %v", err)
+	}
+
+	repo := NewToggleRepository(db)
+	query := &entity.ToggleSearchQuery{Term: "feature-4242", AppIDs: []string{app.ID}, Limit: entity.DefaultSearchLimit}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := repo.Search(query); err != nil {
+			b.Fatalf("Search failed: %v", err)
+		}
+	}
+}
+
+func TestToggleRepository_RestoreApplication(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	checkout := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	payment := entity.NewToggle("payment", true, "checkout.payment", 1, &checkout.ID, app.ID)
+	beta := entity.NewToggle("beta", true, "beta", 0, nil, app.ID)
+	for _, toggle := range []*entity.Toggle{checkout, payment, beta} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	// O filho vem antes do pai para verificar a ordem de criação
+	payment.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1"})
+	payment.SetPrerequisites([]*entity.TogglePrerequisite{{PrerequisiteID: checkout.ID, Enabled: true}})
+	checkout.Enabled = false
+	checkout.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypePercentage, Value: "50"})
+	if err := repo.RestoreApplication(app.ID, []*entity.Toggle{payment, checkout}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	toggles, err := repo.GetByAppID(app.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggles) != 2 {
+		t.Fatalf("Expected 2 toggles, got %d", len(toggles))
+	}
+	loaded, err := repo.GetByID(payment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(loaded.Rules) != 1 || len(loaded.Prerequisites) != 1 {
+		t.Errorf("Expected rules and prerequisites to be restored, got %+v", loaded)
+	}
+	if loaded, _ := repo.GetByID(checkout.ID); loaded == nil || loaded.Enabled || len(loaded.Rules) != 1 {
+		t.Errorf("Expected checkout to be restored disabled with its rule, got %+v", loaded)
+	}
+	if _, err := repo.GetByID(beta.ID); err == nil {
+		t.Error("Expected beta to be removed")
+	}
+}
+
+// setupForeignKeysTestDB cria o banco com as chaves estrangeiras ativas e a tabela de
+// pré-requisitos criada pela migration, que declara as restrições de remoção
+func setupForeignKeysTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=on"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.ToggleRule{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	migration, err := os.ReadFile("../../../../db/migrations/20250909_add_toggle_prerequisites.sql")
+	if err != nil {
+		t.Fatalf("Failed to read migration: %v", err)
+	}
+	up := strings.Split(string(migration), "-- +goose Down")[0]
+	for _, statement := range strings.Split(up, ";") {
+		if strings.Contains(statement, "CREATE") {
+			if err := db.Exec(statement).Error; err != nil {
+				t.Fatalf("Failed to apply migration: %v", err)
+			}
+		}
+	}
+	return db
+}
+
+func TestToggleRepository_PrerequisiteForeignKeys(t *testing.T) {
+	db := setupForeignKeysTestDB(t)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	checkout := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	beta := entity.NewToggle("beta", true, "beta", 0, nil, app.ID)
+	beta.SetPrerequisites([]*entity.TogglePrerequisite{{PrerequisiteID: checkout.ID, Enabled: true}})
+	for _, toggle := range []*entity.Toggle{checkout, beta} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	// Um toggle exigido não é removido definitivamente junto com o pré-requisito de outro
+	if err := db.Unscoped().Where("id = ?", checkout.ID).Delete(&entity.Toggle{}).Error; err == nil {
+		t.Fatal("Expected hard delete of a required toggle to fail")
+	}
+
+	// A restauração regrava o toggle exigido e mantém o pré-requisito do toggle que vai para a lixeira
+	checkout.Enabled = false
+	if err := repo.RestoreApplication(app.ID, []*entity.Toggle{checkout}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var count int64
+	db.Model(&entity.TogglePrerequisite{}).Where("toggle_id = ? AND prerequisite_id = ?", beta.ID, checkout.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected trashed toggle to keep its prerequisite, got %d", count)
+	}
+
+	// A limpeza da lixeira remove os pré-requisitos que apontam para os toggles removidos
+	if err := repo.Delete(checkout.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.Purge(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected purge to succeed, got %v", err)
+	}
+	db.Model(&entity.TogglePrerequisite{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected prerequisites to be purged, got %d", count)
+	}
+}
+
+func TestToggleRepository_TrashAndUndelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	checkout := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	payment := entity.NewToggle("payment", true, "checkout.payment", 1, &checkout.ID, app.ID)
+	payment.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1"})
+	for _, toggle := range []*entity.Toggle{checkout, payment} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	if err := repo.Delete(checkout.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(payment.ID); err == nil {
+		t.Error("Expected trashed child to be hidden")
+	}
+	if exists, _ := repo.Exists("checkout", app.ID); exists {
+		t.Error("Expected trashed toggle not to exist")
+	}
+
+	trash, err := repo.GetTrashByAppID(app.ID)
+	if err != nil || len(trash) != 2 {
+		t.Fatalf("Expected 2 toggles in the trash, got %d (%v)", len(trash), err)
+	}
+	if !trash[0].DeletedAt.Time.Equal(trash[1].DeletedAt.Time) {
+		t.Errorf("Expected toggles deleted together to share the deletion time, got %v and %v", trash[0].DeletedAt, trash[1].DeletedAt)
+	}
+
+	if err := repo.Undelete([]string{checkout.ID, payment.ID}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	loaded, err := repo.GetByID(payment.ID)
+	if err != nil || len(loaded.Rules) != 1 {
+		t.Errorf("Expected toggle to be restored with its rules, got %+v (%v)", loaded, err)
+	}
+	if trash, _ := repo.GetTrashByAppID(app.ID); len(trash) != 0 {
+		t.Errorf("Expected empty trash, got %d", len(trash))
+	}
+}
+
+func TestToggleRepository_ApplyBatch(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	checkout := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	payment := entity.NewToggle("payment", true, "checkout.payment", 1, &checkout.ID, app.ID)
+	promo := entity.NewToggle("promo", true, "promo", 0, nil, app.ID)
+	for _, toggle := range []*entity.Toggle{checkout, payment, promo} {
+		if err := repo.Create(toggle); err != nil {
+			t.Fatalf("Failed to create test toggle: %v", err)
+		}
+	}
+
+	// O filho vem antes do pai para verificar a ordem de criação
+	launch := entity.NewToggle("launch", true, "launch", 0, nil, app.ID)
+	banner := entity.NewToggle("banner", false, "launch.banner", 1, &launch.ID, app.ID)
+	banner.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1"})
+	promo.Enabled = false
+	promo.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypePercentage, Value: "10"})
+	if err := repo.ApplyBatch([]*entity.Toggle{banner, launch}, []*entity.Toggle{promo}, []*entity.Toggle{checkout, payment}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	loaded, err := repo.GetByID(banner.ID)
+	if err != nil || loaded.Enabled || len(loaded.Rules) != 1 {
+		t.Errorf("Expected banner created disabled with its rule, got %+v (%v)", loaded, err)
+	}
+	if loaded, err := repo.GetByID(promo.ID); err != nil || loaded.Enabled || len(loaded.Rules) != 1 {
+		t.Errorf("Expected promo updated with its rule, got %+v (%v)", loaded, err)
+	}
+	trash, err := repo.GetTrashByAppID(app.ID)
+	if err != nil || len(trash) != 2 || !trash[0].DeletedAt.Time.Equal(trash[1].DeletedAt.Time) {
+		t.Errorf("Expected checkout and payment in the trash together, got %+v (%v)", trash, err)
+	}
+
+	// Uma falha desfaz todo o lote
+	duplicate := entity.NewToggle("other", true, "other", 0, nil, app.ID)
+	duplicate.ID = launch.ID
+	promo.Enabled = true
+	if err := repo.ApplyBatch([]*entity.Toggle{duplicate}, []*entity.Toggle{promo}, nil); err == nil {
+		t.Fatal("Expected error for a duplicated ID")
+	}
+	if loaded, _ := repo.GetByID(promo.ID); loaded.Enabled {
+		t.Error("Expected the batch to be rolled back")
+	}
+	// Um toggle alterado por outra operação depois de lido não é sobrescrito
+	stale, err := repo.GetByID(promo.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	concurrent, _ := repo.GetByID(promo.ID)
+	concurrent.Description = "changed concurrently"
+	time.Sleep(time.Millisecond)
+	if err := repo.Update(concurrent); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stale.Enabled = true
+	if err := repo.ApplyBatch(nil, []*entity.Toggle{stale}, nil); !errors.Is(err, entity.ErrToggleChanged) {
+		t.Fatalf("Expected ErrToggleChanged, got %v", err)
+	}
+	if loaded, _ := repo.GetByID(promo.ID); loaded.Enabled || loaded.Description != "changed concurrently" {
+		t.Errorf("Expected the concurrent change to be kept, got %+v", loaded)
+	}
+	if err := repo.ApplyBatch(nil, nil, []*entity.Toggle{checkout}); !errors.Is(err, entity.ErrToggleChanged) {
+		t.Errorf("Expected ErrToggleChanged for a toggle already in the trash, got %v", err)
+	}
+
+	// Só os campos do lote são gravados: os metadados continuam como estão
+	fresh, _ := repo.GetByID(promo.ID)
+	fresh.Enabled = true
+	fresh.Description = "ignored"
+	if err := repo.ApplyBatch(nil, []*entity.Toggle{fresh}, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loaded, _ := repo.GetByID(promo.ID); !loaded.Enabled || loaded.Description != "changed concurrently" || len(loaded.Rules) != 1 {
+		t.Errorf("Expected only the batch fields to be written, got %+v", loaded)
+	}
+}
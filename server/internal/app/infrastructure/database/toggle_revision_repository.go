@@ -0,0 +1,66 @@
+package database
+
+import (
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+)
+
+// ToggleRevisionRepositoryImpl implementa ToggleRevisionRepository
+type ToggleRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewToggleRevisionRepository cria uma nova instância de ToggleRevisionRepositoryImpl
+func NewToggleRevisionRepository(db *gorm.DB) repository.ToggleRevisionRepository {
+	return &ToggleRevisionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create grava as revisões, numerando cada uma a partir da última revisão do seu toggle
+func (r *ToggleRevisionRepositoryImpl) Create(revisions ...*entity.ToggleRevision) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createRevisions(tx, revisions)
+	})
+}
+
+// createRevisions grava as revisões dentro da transação de quem as chama, seja a do próprio
+// repositório de revisões ou a da alteração do toggle que elas descrevem
+func createRevisions(tx *gorm.DB, revisions []*entity.ToggleRevision) error {
+	for _, revision := range revisions {
+		var last int
+		err := tx.Model(&entity.ToggleRevision{}).
+			Where("toggle_id = ?", revision.ToggleID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		revision.Revision = last + 1
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByToggleID busca as revisões do toggle, da mais recente para a mais antiga
+func (r *ToggleRevisionRepositoryImpl) GetByToggleID(toggleID string) ([]*entity.ToggleRevision, error) {
+	revisions := make([]*entity.ToggleRevision, 0)
+	err := r.db.Where("toggle_id = ?", toggleID).Order("revision DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision busca uma revisão do toggle pelo número
+func (r *ToggleRevisionRepositoryImpl) GetRevision(toggleID string, revision int) (*entity.ToggleRevision, error) {
+	var found entity.ToggleRevision
+	err := r.db.Where("toggle_id = ? AND revision = ?", toggleID, revision).First(&found).Error
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestToggleRevisionRepository_CreateNumbersPerToggle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewToggleRevisionRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	checkout := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	search := entity.NewToggle("search", true, "search", 0, nil, app.ID)
+
+	if err := repo.Create(
+		entity.NewToggleRevision(checkout, entity.RevisionActionCreated, nil),
+		entity.NewToggleRevision(search, entity.RevisionActionCreated, nil),
+	); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	checkout.Enabled = false
+	checkout.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1"})
+	if err := repo.Create(entity.NewToggleRevision(checkout, entity.RevisionActionUpdated, &entity.User{ID: "user1", Username: "admin"})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	revisions, err := repo.GetByToggleID(checkout.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 2 || revisions[1].Revision != 1 {
+		t.Fatalf("Expected 2 revisions newest first, got %+v", revisions)
+	}
+	if revisions[0].State.Enabled || len(revisions[0].State.Rules) != 1 || revisions[0].Actor != "admin" {
+		t.Errorf("Expected state and actor to be stored, got %+v", revisions[0])
+	}
+
+	revision, err := repo.GetRevision(search.ID, 1)
+	if err != nil || revision.Action != entity.RevisionActionCreated {
+		t.Errorf("Expected first revision of search, got %+v (%v)", revision, err)
+	}
+	if _, err := repo.GetRevision(search.ID, 2); err == nil {
+		t.Error("Expected error for unknown revision")
+	}
+}
+
+func TestToggleRepository_RevisionsShareTheMutationTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	toggleRepo := NewToggleRepository(db)
+	revisionRepo := NewToggleRevisionRepository(db)
+
+	app := entity.NewApplication("Test App")
+	if err := NewApplicationRepository(db).Create(app); err != nil {
+		t.Fatalf("Failed to create test application: %v", err)
+	}
+	toggle := entity.NewToggle("checkout", true, "checkout", 0, nil, app.ID)
+	created := entity.NewToggleRevision(toggle, entity.RevisionActionCreated, nil)
+	if err := toggleRepo.Create(toggle, created); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.Revision != 1 {
+		t.Errorf("Expected the revision to be numbered on create, got %d", created.Revision)
+	}
+
+	// Uma revisão que não pode ser gravada desfaz a alteração do toggle
+	toggle.Enabled = false
+	duplicated := entity.NewToggleRevision(toggle, entity.RevisionActionUpdated, nil)
+	duplicated.ID = created.ID
+	if err := toggleRepo.Update(toggle, duplicated); err == nil {
+		t.Fatal("Expected error for duplicated revision")
+	}
+	stored, err := toggleRepo.GetByID(toggle.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !stored.Enabled {
+		t.Error("Expected the update to be rolled back with its revision")
+	}
+
+	if err := toggleRepo.Delete(toggle.ID, entity.NewToggleRevision(toggle, entity.RevisionActionDeleted, nil)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	revisions, err := revisionRepo.GetByToggleID(toggle.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Action != entity.RevisionActionDeleted {
+		t.Errorf("Expected created and deleted revisions, got %+v", revisions)
+	}
+}
@@ -91,4 +91,24 @@ func (r *userRepository) GetUsersByApplicationID(applicationID string) ([]*entit
 		Where("user_applications.application_id = ?", applicationID).
 		Find(&users).Error
 	return users, err
-}
\ No newline at end of file
+}
+
+// userListSpec define a ordenação e os filtros da listagem paginada de usuários
+var userListSpec = &listSpec[*entity.User]{
+	sorts: map[string]sortColumn[*entity.User]{
+		"username":   {column: "username", kind: sortString, value: func(u *entity.User) interface{} { return u.Username }},
+		"role":       {column: "role", kind: sortString, value: func(u *entity.User) interface{} { return string(u.Role) }},
+		"created_at": {column: "created_at", kind: sortTime, value: func(u *entity.User) interface{} { return u.CreatedAt }},
+	},
+	filters: map[string]filterColumn{
+		"username": {column: "username", op: filterContains},
+		"role":     {column: "role", op: filterEquals},
+	},
+	id: func(u *entity.User) string { return u.ID },
+}
+
+// List lista os usuários com paginação por cursor, ordenação e filtros
+func (r *userRepository) List(page *entity.PageRequest) (*entity.Page[*entity.User], error) {
+	base := r.db.Model(&entity.User{})
+	return paginate(r.db, base, page, userListSpec, "Applications", "Teams")
+}
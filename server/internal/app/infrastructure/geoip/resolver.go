@@ -0,0 +1,154 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/config"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// DefaultReloadInterval é o intervalo padrão entre as verificações de alteração do arquivo
+const DefaultReloadInterval = time.Minute
+
+// Resolver resolve o país de um endereço IP usando uma base MaxMind DB local
+// (GeoLite2/GeoIP2 Country ou City). O arquivo é recarregado quando muda em disco.
+type Resolver struct {
+	path string
+
+	mu      sync.RWMutex
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// countryRecord são os campos de país de um registro GeoIP2/GeoLite2 Country ou City
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// NewResolver carrega a base do caminho informado
+func NewResolver(path string) (*Resolver, error) {
+	resolver := &Resolver{path: path, stop: make(chan struct{})}
+	if err := resolver.load(); err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+// Country retorna o código ISO 3166-1 alfa-2 do país do endereço.
+// Usa o país de localização e, na ausência dele, o país de registro do bloco.
+func (r *Resolver) Country(addr netip.Addr) (string, bool) {
+	r.mu.RLock()
+	reader := r.reader
+	r.mu.RUnlock()
+
+	var record countryRecord
+	if err := reader.Lookup(net.IP(addr.Unmap().AsSlice()), &record); err != nil {
+		return "", false
+	}
+	for _, code := range []string{record.Country.ISOCode, record.RegisteredCountry.ISOCode} {
+		if code != "" {
+			return strings.ToUpper(code), true
+		}
+	}
+	return "", false
+}
+
+// Reload recarrega a base quando a data de modificação ou o tamanho do arquivo mudaram.
+// Em caso de erro a base anterior continua em uso.
+func (r *Resolver) Reload() (bool, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false, fmt.Errorf("error reading GeoIP database: %w", err)
+	}
+
+	r.mu.RLock()
+	changed := !info.ModTime().Equal(r.modTime) || info.Size() != r.size
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	if err := r.load(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Watch verifica periodicamente se o arquivo mudou, até que Close seja chamado
+func (r *Resolver) Watch(interval time.Duration) {
+	logger := config.GetLogger("geoip")
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if reloaded, err := r.Reload(); err != nil {
+					logger.Warnf("reloading database: %v", err)
+				} else if reloaded {
+					logger.Infof("reloaded %s", r.path)
+				}
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close encerra a verificação periódica do arquivo
+func (r *Resolver) Close() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+}
+
+// load lê e interpreta o arquivo, substituindo a base em uso
+func (r *Resolver) load() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("error reading GeoIP database: %w", err)
+	}
+	buffer, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("error reading GeoIP database: %w", err)
+	}
+	reader, err := openDatabase(buffer)
+	if err != nil {
+		return fmt.Errorf("error loading GeoIP database %s: %w", r.path, err)
+	}
+
+	r.mu.Lock()
+	r.reader = reader
+	r.modTime = info.ModTime()
+	r.size = info.Size()
+	r.mu.Unlock()
+	return nil
+}
+
+// openDatabase interpreta o conteúdo de um arquivo mmdb e verifica a árvore de busca e a seção
+// de dados, para que um arquivo corrompido seja rejeitado na carga e não nas consultas
+func openDatabase(buffer []byte) (*maxminddb.Reader, error) {
+	reader, err := maxminddb.FromBytes(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if err := reader.Verify(); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
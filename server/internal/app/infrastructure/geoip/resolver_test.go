@@ -0,0 +1,113 @@
+package geoip
+
+import (
+	"bytes"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const fixture = "testdata/GeoIP2-Country-Test.mmdb"
+
+func TestOpenDatabase(t *testing.T) {
+	buffer, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	reader, err := openDatabase(buffer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reader.Metadata.DatabaseType != "GeoIP2-Country-Test" || reader.Metadata.IPVersion != 6 || reader.Metadata.RecordSize != 24 {
+		t.Errorf("Unexpected metadata %+v", reader.Metadata)
+	}
+
+	// A seção de dados truncada passa pela leitura dos metadados, mas não pela verificação
+	truncated := append([]byte(nil), buffer...)
+	marker := bytes.LastIndex(truncated, []byte("\xAB\xCD\xEFMaxMind.com"))
+	truncated = append(truncated[:marker-16], truncated[marker:]...)
+	for name, buffer := range map[string][]byte{"not a database": []byte("not a database"), "truncated data": truncated} {
+		if _, err := openDatabase(buffer); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestResolver_Country(t *testing.T) {
+	resolver, err := NewResolver(fixture)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		country string
+		found   bool
+	}{
+		{"81.2.69.160", "GB", true},
+		{"89.160.20.1", "SE", true},
+		{"202.196.239.255", "PH", true},
+		{"202.196.240.0", "", false},
+		{"175.16.199.10", "CN", true}, // Apenas o país de registro
+		{"::ffff:81.2.69.1", "GB", true},
+		{"2001:db8:1234::1", "BR", true},
+		{"2001:db9::1", "", false},
+		{"8.8.8.8", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			country, found := resolver.Country(netip.MustParseAddr(tt.ip))
+			if country != tt.country || found != tt.found {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.country, tt.found, country, found)
+			}
+		})
+	}
+}
+
+func TestResolver_Reload(t *testing.T) {
+	buffer, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	if err := os.WriteFile(path, buffer, 0o644); err != nil {
+		t.Fatalf("Failed to write database: %v", err)
+	}
+
+	resolver, err := NewResolver(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if reloaded, err := resolver.Reload(); err != nil || reloaded {
+		t.Errorf("Expected unchanged file not to be reloaded, got %v, %v", reloaded, err)
+	}
+
+	// Um arquivo inválido mantém a base anterior em uso
+	if err := os.WriteFile(path, []byte("corrupted"), 0o644); err != nil {
+		t.Fatalf("Failed to write database: %v", err)
+	}
+	if _, err := resolver.Reload(); err == nil {
+		t.Error("Expected reload of an invalid file to fail")
+	}
+	if country, _ := resolver.Country(netip.MustParseAddr("81.2.69.1")); country != "GB" {
+		t.Errorf("Expected previous database to stay loaded, got %q", country)
+	}
+
+	// Restaurar o arquivo recarrega a base
+	if err := os.WriteFile(path, buffer, 0o644); err != nil {
+		t.Fatalf("Failed to write database: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+	if reloaded, err := resolver.Reload(); err != nil || !reloaded {
+		t.Errorf("Expected changed file to be reloaded, got %v, %v", reloaded, err)
+	}
+
+	if _, err := NewResolver(filepath.Join(t.TempDir(), "missing.mmdb")); err == nil {
+		t.Error("Expected missing database to fail")
+	}
+}
@@ -0,0 +1,187 @@
+//go:build ignore
+
+// Gera a base mmdb usada nos testes: go run testdata/generate.go
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"net/netip"
+	"os"
+	"sort"
+	"time"
+)
+
+// networks são os blocos da base de teste e o país de cada um
+var networks = []struct {
+	prefix  string
+	field   string
+	country string
+}{
+	{"81.2.69.0/24", "country", "GB"},
+	{"89.160.20.0/24", "country", "SE"},
+	{"202.196.224.0/20", "country", "PH"},
+	{"175.16.199.0/24", "registered_country", "CN"},
+	{"2001:db8::/32", "country", "BR"},
+}
+
+type node struct {
+	children [2]*node
+	data     [2]int
+}
+
+func newNode() *node {
+	return &node{data: [2]int{-1, -1}}
+}
+
+func main() {
+	var data bytes.Buffer
+	root := newNode()
+
+	for _, network := range networks {
+		prefix := netip.MustParsePrefix(network.prefix)
+		// Blocos IPv4 ficam na subárvore ::/96 de uma base IPv6
+		addr, bits := prefix.Addr().As16(), prefix.Bits()
+		if prefix.Addr().Is4() {
+			addr = [16]byte{}
+			v4 := prefix.Addr().As4()
+			copy(addr[12:], v4[:])
+			bits += 96
+		}
+
+		offset := data.Len()
+		writeMap(&data, map[string]interface{}{
+			network.field: map[string]interface{}{"iso_code": network.country},
+		})
+
+		current := root
+		for i := 0; i < bits; i++ {
+			bit := addr[i/8] >> (7 - i%8) & 1
+			if i == bits-1 {
+				current.data[bit] = offset
+				break
+			}
+			if current.children[bit] == nil {
+				current.children[bit] = newNode()
+			}
+			current = current.children[bit]
+		}
+	}
+
+	// Numera os nós em largura
+	nodes := []*node{root}
+	index := map[*node]int{root: 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			if child != nil {
+				index[child] = len(nodes)
+				nodes = append(nodes, child)
+			}
+		}
+	}
+
+	nodeCount := len(nodes)
+	var tree bytes.Buffer
+	for _, n := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			record := nodeCount
+			switch {
+			case n.children[bit] != nil:
+				record = index[n.children[bit]]
+			case n.data[bit] >= 0:
+				record = nodeCount + 16 + n.data[bit]
+			}
+			tree.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+
+	var file bytes.Buffer
+	file.Write(tree.Bytes())
+	file.Write(make([]byte, 16))
+	file.Write(data.Bytes())
+	file.WriteString("\xAB\xCD\xEFMaxMind.com")
+	writeMap(&file, map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC).Unix()),
+		"database_type":               "GeoIP2-Country-Test",
+		"description":                 map[string]interface{}{"en": "ToToogle test fixture"},
+		"ip_version":                  uint16(6),
+		"languages":                   []interface{}{"en"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+	})
+
+	if err := os.WriteFile("testdata/GeoIP2-Country-Test.mmdb", file.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeControl(w *bytes.Buffer, kind int, size int) {
+	ctrl := byte(0)
+	if kind <= 7 {
+		ctrl = byte(kind << 5)
+	}
+	var extra []byte
+	switch {
+	case size < 29:
+		ctrl |= byte(size)
+	case size < 285:
+		ctrl |= 29
+		extra = []byte{byte(size - 29)}
+	default:
+		ctrl |= 30
+		extra = []byte{byte((size - 285) >> 8), byte(size - 285)}
+	}
+	w.WriteByte(ctrl)
+	if kind > 7 {
+		w.WriteByte(byte(kind - 7))
+	}
+	w.Write(extra)
+}
+
+func writeValue(w *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		writeControl(w, 2, len(v))
+		w.WriteString(v)
+	case uint16:
+		writeUint(w, 5, uint64(v))
+	case uint32:
+		writeUint(w, 6, uint64(v))
+	case uint64:
+		writeUint(w, 9, v)
+	case map[string]interface{}:
+		writeMap(w, v)
+	case []interface{}:
+		writeControl(w, 11, len(v))
+		for _, item := range v {
+			writeValue(w, item)
+		}
+	default:
+		log.Fatalf("unsupported value %T", value)
+	}
+}
+
+func writeUint(w *bytes.Buffer, kind int, value uint64) {
+	var raw [8]byte
+	binary.BigEndian.PutUint64(raw[:], value)
+	trimmed := bytes.TrimLeft(raw[:], "\x00")
+	writeControl(w, kind, len(trimmed))
+	w.Write(trimmed)
+}
+
+func writeMap(w *bytes.Buffer, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeControl(w, 7, len(keys))
+	for _, key := range keys {
+		writeValue(w, key)
+		writeValue(w, m[key])
+	}
+}
@@ -0,0 +1,186 @@
+package relay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/pkg/client"
+)
+
+// Register adiciona as rotas do relay: a API pública atendida da memória, o health check
+// e o estado de sincronização. As métricas dos SDKs são encaminhadas ao upstream.
+func (r *Relay) Register(router gin.IRouter) {
+	router.GET("/health", r.health)
+	router.GET("/relay/status", r.status)
+
+	api := router.Group("/api")
+	api.GET("/toggles", r.getToggles)
+	api.GET("/toggles/stream", r.streamToggles)
+	api.POST("/evaluate", r.evaluate)
+	api.POST("/metrics", gin.WrapH(r.proxy))
+}
+
+// health informa que o processo está no ar, como o health check do servidor
+// GET /health
+func (r *Relay) health(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "healthy",
+		"service": "totoogle-relay",
+	})
+}
+
+// status retorna o estado de sincronização de cada chave; responde 503 enquanto alguma
+// chave não pode ser atendida, para servir de readiness probe
+// GET /relay/status
+func (r *Relay) status(c *gin.Context) {
+	status := r.Status()
+	code := http.StatusOK
+	if !status.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, status)
+}
+
+// snapshot valida a secret key e retorna o snapshot em memória, respondendo com o mesmo
+// erro do servidor quando a chave não é atendida
+func (r *Relay) snapshot(c *gin.Context) (*source, *client.Snapshot, bool) {
+	secretKey := c.GetHeader("X-API-Key")
+	if secretKey == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "X-API-Key header is required",
+		})
+		return nil, nil, false
+	}
+
+	src, ok := r.lookup(secretKey)
+	if !ok || src.revoked() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Invalid or expired secret key",
+		})
+		return nil, nil, false
+	}
+
+	snapshot := src.client.Snapshot()
+	if snapshot == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Toggles not synchronized with the upstream server yet",
+		})
+		return nil, nil, false
+	}
+	return src, snapshot, true
+}
+
+// getToggles retorna o snapshot exatamente como recebido do upstream, com o mesmo ETag
+// GET /api/toggles - Header: X-API-Key
+func (r *Relay) getToggles(c *gin.Context) {
+	_, snapshot, ok := r.snapshot(c)
+	if !ok {
+		return
+	}
+
+	etag := `"` + snapshot.Version + `"`
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", snapshot.Data())
+}
+
+// streamToggles envia o snapshot por Server-Sent Events sempre que o relay recebe um novo,
+// no mesmo formato do servidor. O stream termina quando o upstream recusa a chave.
+// GET /api/toggles/stream - Header: X-API-Key
+func (r *Relay) streamToggles(c *gin.Context) {
+	src, snapshot, ok := r.snapshot(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	version := c.GetHeader("Last-Event-ID")
+	if version != snapshot.Version {
+		writeSnapshotEvent(c, snapshot)
+		version = snapshot.Version
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(r.options.StreamInterval)
+	defer ticker.Stop()
+	lastWrite := time.Now()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		if src.revoked() {
+			return
+		}
+		if snapshot := src.client.Snapshot(); snapshot.Version != version {
+			writeSnapshotEvent(c, snapshot)
+			version = snapshot.Version
+			lastWrite = time.Now()
+		} else if time.Since(lastWrite) >= DefaultStreamHeartbeat {
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			lastWrite = time.Now()
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeSnapshotEvent escreve o snapshot como um evento "snapshot" identificado pela versão
+func writeSnapshotEvent(c *gin.Context, snapshot *client.Snapshot) {
+	fmt.Fprintf(c.Writer, "event: snapshot\nid: %s\ndata: %s\n\n", snapshot.Version, snapshot.Data())
+}
+
+// evaluateRequest é a mesma requisição de avaliação do servidor
+type evaluateRequest struct {
+	Path    string          `json:"path" binding:"required"`
+	Context *client.Context `json:"context"`
+}
+
+// evaluate avalia um toggle com o snapshot em memória, com as mesmas regras e respostas do servidor
+// POST /api/evaluate - Header: X-API-Key
+func (r *Relay) evaluate(c *gin.Context) {
+	src, _, ok := r.snapshot(c)
+	if !ok {
+		return
+	}
+
+	var req evaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("path", "Toggle path is required")
+		c.JSON(http.StatusBadRequest, appErr)
+		return
+	}
+	if req.Context == nil {
+		req.Context = &client.Context{}
+	}
+	if req.Context.IP == "" {
+		req.Context.IP = c.ClientIP()
+	}
+	country, err := entity.ResolveCountry(req.Context.Country, req.Context.IP, r.options.CountryResolver)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	req.Context.Country = country
+
+	result, err := src.client.Evaluate(strings.TrimSpace(req.Path), req.Context)
+	if err != nil {
+		c.JSON(http.StatusNotFound, entity.NewAppError(entity.ErrCodeNotFound, "toggle not found"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,220 @@
+// Package relay implementa o modo relay do binário: um proxy que mantém em memória os
+// snapshots das secret keys configuradas, obtidos de um servidor upstream, e atende a
+// API pública (/api/toggles e /api/evaluate) localmente.
+package relay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/pkg/client"
+)
+
+// Valores padrão do relay
+const (
+	DefaultRefreshInterval = 30 * time.Second
+	DefaultStreamInterval  = time.Second      // Frequência com que os streams locais verificam se o snapshot mudou
+	DefaultStreamHeartbeat = 15 * time.Second // Comentário enviado para manter a conexão aberta em proxies
+)
+
+// Options configura o relay
+type Options struct {
+	Upstream        string        // Endereço do servidor ToToogle de origem
+	SecretKeys      []string      // Secret keys atendidas pelo relay
+	DataDir         string        // Diretório onde o último snapshot de cada chave é gravado
+	RefreshInterval time.Duration // Intervalo de atualização; com streaming é o intervalo entre reconexões
+	Streaming       bool          // Recebe as mudanças por /api/toggles/stream em vez de consultar periodicamente
+	StreamInterval  time.Duration // Frequência com que os streams locais verificam se o snapshot mudou
+
+	// CountryResolver obtém o país a partir do IP nas avaliações; opcional
+	CountryResolver entity.CountryResolver
+
+	Logger *log.Logger
+}
+
+// Relay mantém um cliente do SDK por secret key
+type Relay struct {
+	options Options
+	sources map[string]*source
+	order   []*source
+	proxy   *httputil.ReverseProxy // Encaminha ao upstream as rotas que não são atendidas da memória
+
+	cancel   context.CancelFunc // Interrompe as primeiras sincronizações ainda em andamento
+	starting sync.WaitGroup     // Primeiras sincronizações iniciadas por Start
+}
+
+// source é uma secret key atendida pelo relay com o cliente que a mantém atualizada
+type source struct {
+	id     string // Identificador da chave nos logs, no status e no nome do arquivo, derivado do hash
+	client *client.Client
+}
+
+// New cria o relay; os clientes só começam a sincronizar em Start
+func New(options Options) (*Relay, error) {
+	options.Upstream = strings.TrimRight(strings.TrimSpace(options.Upstream), "/")
+	if options.Upstream == "" {
+		return nil, fmt.Errorf("upstream server URL is required")
+	}
+	upstream, err := url.Parse(options.Upstream)
+	if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" {
+		return nil, fmt.Errorf("invalid upstream server URL %q", options.Upstream)
+	}
+	if len(options.SecretKeys) == 0 {
+		return nil, fmt.Errorf("at least one secret key is required")
+	}
+	if options.DataDir == "" {
+		return nil, fmt.Errorf("data directory is required")
+	}
+	if options.RefreshInterval <= 0 {
+		options.RefreshInterval = DefaultRefreshInterval
+	}
+	if options.StreamInterval <= 0 {
+		options.StreamInterval = DefaultStreamInterval
+	}
+	if options.Logger == nil {
+		options.Logger = log.Default()
+	}
+
+	r := &Relay{options: options, sources: make(map[string]*source, len(options.SecretKeys))}
+	r.proxy = &httputil.ReverseProxy{
+		Rewrite: func(req *httputil.ProxyRequest) {
+			req.SetURL(upstream)
+			req.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			options.Logger.Printf("relay: forwarding %s to the upstream failed: %v", req.URL.Path, err)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, `{"error":"Upstream server unavailable"}`)
+		},
+	}
+	for _, secretKey := range options.SecretKeys {
+		secretKey = strings.TrimSpace(secretKey)
+		if secretKey == "" || r.sources[secretKey] != nil {
+			continue
+		}
+
+		hash := sha256.Sum256([]byte(secretKey))
+		id := hex.EncodeToString(hash[:6])
+		c, err := client.New(client.Options{
+			ServerURL:       options.Upstream,
+			SecretKey:       secretKey,
+			RefreshInterval: options.RefreshInterval,
+			Streaming:       options.Streaming,
+			BootstrapFile:   filepath.Join(options.DataDir, id+".json"),
+			Logger:          log.New(options.Logger.Writer(), options.Logger.Prefix()+"relay "+id+": ", options.Logger.Flags()),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		src := &source{id: id, client: c}
+		r.sources[secretKey] = src
+		r.order = append(r.order, src)
+	}
+	return r, nil
+}
+
+// Start carrega os snapshots gravados e sincroniza as chaves com o upstream em segundo plano,
+// todas ao mesmo tempo, sem esperar a resposta: um upstream lento ou fora do ar não impede o
+// relay de servir os snapshots gravados. As chaves sem snapshot ficam indisponíveis até o
+// upstream responder, o que /relay/status informa.
+func (r *Relay) Start(ctx context.Context) {
+	for _, src := range r.order {
+		src.client.LoadBootstrap()
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	for _, src := range r.order {
+		src := src
+		r.starting.Add(1)
+		go func() {
+			defer r.starting.Done()
+			if err := src.client.Start(ctx); err != nil && ctx.Err() == nil {
+				r.options.Logger.Printf("relay %s: no snapshot available yet: %v", src.id, err)
+			}
+		}()
+	}
+}
+
+// Close interrompe a sincronização
+func (r *Relay) Close() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.starting.Wait()
+	for _, src := range r.order {
+		src.client.Close()
+	}
+}
+
+// lookup retorna a chave configurada correspondente à secret key
+func (r *Relay) lookup(secretKey string) (*source, bool) {
+	src, ok := r.sources[secretKey]
+	return src, ok
+}
+
+// revoked informa se o upstream recusou a chave na última sincronização. Uma chave removida
+// ou expirada no servidor deixa de ser atendida, mesmo com um snapshot em memória.
+func (s *source) revoked() bool {
+	var responseErr *client.ResponseError
+	if !errors.As(s.client.LastError(), &responseErr) {
+		return false
+	}
+	return responseErr.Status == http.StatusUnauthorized || responseErr.Status == http.StatusNotFound
+}
+
+// Status é o estado de sincronização do relay
+type Status struct {
+	Upstream  string       `json:"upstream"`
+	Streaming bool         `json:"streaming"`
+	Ready     bool         `json:"ready"` // Todas as chaves têm um snapshot e nenhuma foi recusada
+	Keys      []*KeyStatus `json:"keys"`
+}
+
+// KeyStatus é o estado de sincronização de uma secret key
+type KeyStatus struct {
+	Key             string     `json:"key"` // Prefixo do hash da chave; a chave nunca é exposta
+	ApplicationID   string     `json:"application_id,omitempty"`
+	ApplicationName string     `json:"application_name,omitempty"`
+	Version         string     `json:"version,omitempty"`
+	Ready           bool       `json:"ready"`
+	Revoked         bool       `json:"revoked"`
+	LastSync        *time.Time `json:"last_sync,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// Status retorna o estado de sincronização de cada chave
+func (r *Relay) Status() *Status {
+	status := &Status{Upstream: r.options.Upstream, Streaming: r.options.Streaming, Ready: true, Keys: make([]*KeyStatus, 0, len(r.order))}
+	for _, src := range r.order {
+		key := &KeyStatus{Key: src.id, Revoked: src.revoked()}
+		if snapshot := src.client.Snapshot(); snapshot != nil {
+			key.ApplicationID = snapshot.ApplicationID
+			key.ApplicationName = snapshot.ApplicationName
+			key.Version = snapshot.Version
+			key.Ready = !key.Revoked
+		}
+		if lastSync := src.client.LastSync(); !lastSync.IsZero() {
+			key.LastSync = &lastSync
+		}
+		if err := src.client.LastError(); err != nil {
+			key.LastError = err.Error()
+		}
+		status.Ready = status.Ready && key.Ready
+		status.Keys = append(status.Keys, key)
+	}
+	return status
+}
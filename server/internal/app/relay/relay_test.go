@@ -0,0 +1,380 @@
+package relay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/manorfm/totoogle/internal/app/apitest"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/handler"
+)
+
+const testAppID = "01JZNM42NKSANGHZ3G4KKXGCNW"
+
+// testUpstream sobe o servidor completo com uma aplicação, seus toggles e uma secret key.
+// Enquanto down estiver ligado o servidor responde 503.
+func testUpstream(t *testing.T) (server *httptest.Server, secretKey string, db *gorm.DB, down *atomic.Bool) {
+	t.Helper()
+	api := apitest.New(t, handler.Options{StreamPollInterval: 20 * time.Millisecond})
+	db = api.DB
+
+	db.Create(&entity.Application{ID: testAppID, Name: "Shop"})
+	create := func(path string, enabled bool, configure func(*entity.Toggle)) {
+		toggle := entity.NewToggle(path, enabled, path, 1, nil, testAppID)
+		if configure != nil {
+			configure(toggle)
+		}
+		api.CreateToggle(t, toggle)
+	}
+	create("static", true, nil)
+	create("off", false, nil)
+	create("brazil", true, func(toggle *entity.Toggle) {
+		toggle.Rules = []*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}}}}
+	})
+	create("rollout", true, func(toggle *entity.Toggle) {
+		toggle.Rules = []*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypePercentage, Value: "50"}}}}
+	})
+	secretKey = api.CreateSecretKey(t, testAppID, "Relay")
+
+	return api.Server, secretKey, db, &api.Down
+}
+
+// startRelay cria e inicia um relay para o upstream, servindo-o por HTTP
+func startRelay(t *testing.T, upstream, dataDir string, streaming bool, secretKeys ...string) (*Relay, *httptest.Server) {
+	t.Helper()
+	r, err := New(Options{
+		Upstream:        upstream,
+		SecretKeys:      secretKeys,
+		DataDir:         dataDir,
+		RefreshInterval: time.Hour,
+		Streaming:       streaming,
+		StreamInterval:  20 * time.Millisecond,
+		Logger:          log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("Expected the relay to be created, got %v", err)
+	}
+	r.Start(context.Background())
+	t.Cleanup(r.Close)
+	r.starting.Wait()
+
+	engine := gin.New()
+	r.Register(engine)
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+// do envia uma requisição com a secret key e retorna o status, o corpo e os cabeçalhos
+func do(t *testing.T, method, url, secretKey string, body []byte, header map[string]string) (int, []byte, http.Header) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
+	if secretKey != "" {
+		req.Header.Set("X-API-Key", secretKey)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request to %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, resp.Header
+}
+
+// relayStatus retorna o estado de sincronização exposto pelo relay
+func relayStatus(t *testing.T, server *httptest.Server) (int, *Status) {
+	t.Helper()
+	code, body, _ := do(t, http.MethodGet, server.URL+"/relay/status", "", nil, nil)
+	var status Status
+	if err := json.Unmarshal(body, &status); err != nil {
+		t.Fatalf("Failed to decode the status %s: %v", body, err)
+	}
+	return code, &status
+}
+
+func TestNew_ValidatesOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"missing upstream", Options{SecretKeys: []string{"key"}, DataDir: "data"}},
+		{"invalid upstream", Options{Upstream: "ftp://example.com", SecretKeys: []string{"key"}, DataDir: "data"}},
+		{"missing keys", Options{Upstream: "http://example.com", DataDir: "data"}},
+		{"missing data dir", Options{Upstream: "http://example.com", SecretKeys: []string{"key"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.options); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestRelay_ServesTheSameAPI(t *testing.T) {
+	upstream, secretKey, _, _ := testUpstream(t)
+	_, relayServer := startRelay(t, upstream.URL, t.TempDir(), false, secretKey)
+
+	upstreamCode, upstreamBody, upstreamHeader := do(t, http.MethodGet, upstream.URL+"/api/toggles", secretKey, nil, nil)
+	code, body, header := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, nil)
+	if code != upstreamCode || !bytes.Equal(body, upstreamBody) {
+		t.Fatalf("Expected the relay to serve the upstream snapshot, got %d %s", code, body)
+	}
+	etag := header.Get("ETag")
+	if etag == "" || etag != upstreamHeader.Get("ETag") {
+		t.Errorf("Expected the upstream ETag %s, got %s", upstreamHeader.Get("ETag"), etag)
+	}
+	if code, _, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, map[string]string{"If-None-Match": etag}); code != http.StatusNotModified {
+		t.Errorf("Expected 304 for the current ETag, got %d", code)
+	}
+
+	requests := []string{
+		`{"path":"static"}`,
+		`{"path":"off"}`,
+		`{"path":"brazil","context":{"country":"br"}}`,
+		`{"path":"brazil","context":{"country":"US"}}`,
+		`{"path":"rollout","context":{"user_id":"alice"}}`,
+		`{"path":"rollout","context":{"user_id":"bob"}}`,
+		`{"path":"brazil","context":{"country":"Brazil"}}`,
+		`{"path":"missing"}`,
+		`{}`,
+	}
+	for _, request := range requests {
+		upstreamCode, upstreamBody, _ := do(t, http.MethodPost, upstream.URL+"/api/evaluate", secretKey, []byte(request), nil)
+		code, body, _ := do(t, http.MethodPost, relayServer.URL+"/api/evaluate", secretKey, []byte(request), nil)
+		if code != upstreamCode || !bytes.Equal(body, upstreamBody) {
+			t.Errorf("Evaluating %s: expected %d %s, got %d %s", request, upstreamCode, upstreamBody, code, body)
+		}
+	}
+
+	for _, key := range []string{"", "unknown-key"} {
+		upstreamCode, upstreamBody, _ := do(t, http.MethodGet, upstream.URL+"/api/toggles", key, nil, nil)
+		code, body, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", key, nil, nil)
+		if code != upstreamCode || !bytes.Equal(body, upstreamBody) {
+			t.Errorf("Key %q: expected %d %s, got %d %s", key, upstreamCode, upstreamBody, code, body)
+		}
+	}
+}
+
+func TestRelay_ForwardsMetrics(t *testing.T) {
+	upstream, secretKey, db, down := testUpstream(t)
+	_, relayServer := startRelay(t, upstream.URL, t.TempDir(), false, secretKey)
+
+	now := time.Now().UTC()
+	body, _ := json.Marshal(map[string]any{"metrics": []*entity.EvaluationCount{
+		{Toggle: "static", Result: true, Count: 3, WindowStart: now.Add(-time.Minute), WindowEnd: now},
+	}})
+	code, data, _ := do(t, http.MethodPost, relayServer.URL+"/api/metrics", secretKey, body, nil)
+	if code != http.StatusAccepted || !strings.Contains(string(data), `"accepted":1`) {
+		t.Fatalf("Expected the metrics to be forwarded, got %d %s", code, data)
+	}
+	var count int64
+	db.Model(&entity.ToggleMetric{}).Count(&count)
+	if count == 0 {
+		t.Error("Expected the upstream to record the metrics")
+	}
+
+	down.Store(true)
+	if code, _, _ := do(t, http.MethodPost, relayServer.URL+"/api/metrics", secretKey, body, nil); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the upstream response to be forwarded, got %d", code)
+	}
+	upstream.Close()
+	if code, data, _ := do(t, http.MethodPost, relayServer.URL+"/api/metrics", secretKey, body, nil); code != http.StatusBadGateway {
+		t.Errorf("Expected 502 with the upstream unreachable, got %d %s", code, data)
+	}
+}
+
+func TestRelay_KeepsServingWhenUpstreamIsDown(t *testing.T) {
+	upstream, secretKey, _, down := testUpstream(t)
+	dataDir := t.TempDir()
+	r, relayServer := startRelay(t, upstream.URL, dataDir, false, secretKey)
+
+	code, status := relayStatus(t, relayServer)
+	if code != http.StatusOK || !status.Ready || len(status.Keys) != 1 {
+		t.Fatalf("Expected the relay to be ready, got %d %+v", code, status)
+	}
+	key := status.Keys[0]
+	if key.ApplicationID != testAppID || key.ApplicationName != "Shop" || key.Version == "" || key.LastSync == nil || key.LastError != "" {
+		t.Errorf("Unexpected key status %+v", key)
+	}
+	if strings.Contains(key.Key, secretKey) || len(key.Key) != 12 {
+		t.Errorf("Expected the key to be identified by a hash prefix, got %s", key.Key)
+	}
+	_, expected, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, nil)
+
+	down.Store(true)
+	r.sources[secretKey].client.Refresh(context.Background())
+
+	code, body, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, nil)
+	if code != http.StatusOK || !bytes.Equal(body, expected) {
+		t.Errorf("Expected the relay to keep serving the last snapshot, got %d %s", code, body)
+	}
+	code, status = relayStatus(t, relayServer)
+	if code != http.StatusOK || !status.Ready || !strings.Contains(status.Keys[0].LastError, "503") {
+		t.Errorf("Expected the relay to stay ready and report the upstream error, got %d %+v", code, status.Keys[0])
+	}
+
+	// Um relay novo, com o upstream fora, parte do snapshot gravado em disco
+	_, restarted := startRelay(t, upstream.URL, dataDir, false, secretKey)
+	code, body, _ = do(t, http.MethodGet, restarted.URL+"/api/toggles", secretKey, nil, nil)
+	if code != http.StatusOK || !bytes.Equal(body, expected) {
+		t.Errorf("Expected the restarted relay to serve the persisted snapshot, got %d %s", code, body)
+	}
+	if code, status := relayStatus(t, restarted); code != http.StatusOK || status.Keys[0].LastSync != nil {
+		t.Errorf("Expected the persisted snapshot to be ready but never synchronized, got %d %+v", code, status.Keys[0])
+	}
+}
+
+// Um upstream que aceita a conexão e não responde não atrasa o relay, que serve o snapshot gravado
+func TestRelay_StartsWithoutWaitingForTheUpstream(t *testing.T) {
+	upstream, secretKey, _, _ := testUpstream(t)
+	dataDir := t.TempDir()
+	startRelay(t, upstream.URL, dataDir, false, secretKey)
+
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(hanging.Close)
+
+	r, err := New(Options{Upstream: hanging.URL, SecretKeys: []string{secretKey, "other-key"}, DataDir: dataDir, Logger: log.New(io.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("Expected the relay to be created, got %v", err)
+	}
+	started := time.Now()
+	r.Start(context.Background())
+	t.Cleanup(r.Close)
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("Expected start not to wait for the upstream, took %v", elapsed)
+	}
+
+	engine := gin.New()
+	r.Register(engine)
+	relayServer := httptest.NewServer(engine)
+	t.Cleanup(relayServer.Close)
+
+	if code, body, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, nil); code != http.StatusOK {
+		t.Errorf("Expected the persisted snapshot to be served, got %d %s", code, body)
+	}
+	code, status := relayStatus(t, relayServer)
+	if code != http.StatusServiceUnavailable || !status.Keys[0].Ready || status.Keys[1].Ready {
+		t.Errorf("Expected only the key with a persisted snapshot to be ready, got %d %+v %+v", code, status.Keys[0], status.Keys[1])
+	}
+}
+
+func TestRelay_NotSynchronized(t *testing.T) {
+	upstream, secretKey, _, down := testUpstream(t)
+	down.Store(true)
+	_, relayServer := startRelay(t, upstream.URL, t.TempDir(), false, secretKey)
+
+	if code, body, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, nil); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before the first synchronization, got %d %s", code, body)
+	}
+	if code, status := relayStatus(t, relayServer); code != http.StatusServiceUnavailable || status.Ready || status.Keys[0].Ready {
+		t.Errorf("Expected the relay not to be ready, got %d %+v", code, status)
+	}
+}
+
+func TestRelay_RevokedKey(t *testing.T) {
+	upstream, secretKey, db, _ := testUpstream(t)
+	r, relayServer := startRelay(t, upstream.URL, t.TempDir(), false, secretKey)
+
+	db.Delete(&entity.SecretKey{}, "id = ?", "test-secret-id")
+	r.sources[secretKey].client.Refresh(context.Background())
+
+	code, body, _ := do(t, http.MethodGet, relayServer.URL+"/api/toggles", secretKey, nil, nil)
+	if code != http.StatusNotFound || !strings.Contains(string(body), "Invalid or expired secret key") {
+		t.Errorf("Expected a revoked key to be refused, got %d %s", code, body)
+	}
+	if code, status := relayStatus(t, relayServer); code != http.StatusServiceUnavailable || !status.Keys[0].Revoked || status.Keys[0].Ready {
+		t.Errorf("Expected the key to be reported as revoked, got %d %+v", code, status.Keys[0])
+	}
+}
+
+func TestRelay_Stream(t *testing.T) {
+	upstream, secretKey, db, _ := testUpstream(t)
+	r, relayServer := startRelay(t, upstream.URL, t.TempDir(), true, secretKey)
+
+	req, _ := http.NewRequest(http.MethodGet, relayServer.URL+"/api/toggles/stream", nil)
+	req.Header.Set("X-API-Key", secretKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	first := readEvent(t, reader)
+	if first.id != r.sources[secretKey].client.Snapshot().Version {
+		t.Errorf("Expected the current snapshot first, got %s", first.id)
+	}
+
+	db.Model(&entity.Toggle{}).Where("path = ?", "off").Update("enabled", true)
+	second := readEvent(t, reader)
+	if second.id == first.id || !strings.Contains(second.data, `"off"`) {
+		t.Errorf("Expected the changed snapshot to be streamed, got %+v", second)
+	}
+}
+
+// event é um evento "snapshot" lido do stream
+type event struct {
+	id   string
+	data string
+}
+
+// readEvent lê o próximo evento do stream, ignorando os heartbeats
+func readEvent(t *testing.T, reader *bufio.Reader) event {
+	t.Helper()
+	result := make(chan event, 1)
+	go func() {
+		var e event
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(result)
+				return
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "" && e.id != "":
+				result <- e
+				return
+			case strings.HasPrefix(line, "id: "):
+				e.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				e.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	select {
+	case e, ok := <-result:
+		if !ok {
+			t.Fatal("Stream closed before an event")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a stream event")
+		return event{}
+	}
+}
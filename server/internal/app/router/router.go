@@ -1,18 +1,133 @@
 package router
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/manorfm/totoogle/internal/app/config"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/handler"
+	"github.com/manorfm/totoogle/internal/app/infrastructure/geoip"
+	"gorm.io/gorm"
 )
 
-func Initialize() {
-	router := gin.Default()
+// shutdownTimeout é o tempo máximo de espera pelas requisições em andamento ao encerrar
+const shutdownTimeout = 10 * time.Second
+
+// Options configura o servidor HTTP
+type Options struct {
+	// TrustedProxies são os IPs ou blocos CIDR dos proxies cujos cabeçalhos X-Forwarded-For
+	// e X-Real-IP são usados para obter o IP do cliente; vazio ignora esses cabeçalhos
+	TrustedProxies []string
+
+	// GeoIPDatabase é o caminho de uma base MaxMind DB (mmdb) usada para obter o país
+	// a partir do IP nas avaliações; vazio desativa a resolução
+	GeoIPDatabase string
+
+	// TrashRetention é o tempo que toggles e aplicações removidos ficam na lixeira antes
+	// de serem expurgados; zero mantém os itens na lixeira indefinidamente
+	TrashRetention time.Duration
+
+	// ManagedPolicy define se os usuários podem alterar manualmente os recursos gerenciados
+	// pela sincronização declarativa
+	ManagedPolicy entity.ManagedPolicy
+}
+
+func Initialize(options Options) error {
+	router, shutdown, err := setup(config.GetDatabase(), options)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
+
+	return run(router)
+}
+
+// setup cria o engine com os handlers inicializados sobre db e todas as rotas do servidor.
+// shutdown encerra as tarefas em segundo plano dos handlers e da base de países.
+func setup(db *gorm.DB, options Options) (*gin.Engine, func(), error) {
+	router, err := newEngine(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handlerOptions := handler.Options{TrashRetention: options.TrashRetention, ManagedPolicy: options.ManagedPolicy}
+	var resolver *geoip.Resolver
+	if options.GeoIPDatabase != "" {
+		resolver, err = geoip.NewResolver(options.GeoIPDatabase)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolver.Watch(geoip.DefaultReloadInterval)
+		handlerOptions.CountryResolver = resolver
+	}
 
 	// Inicializa os handlers
-	handler.InitHandlers(config.GetDatabase())
+	handler.InitHandlersWithOptions(db, handlerOptions)
 
 	Init(router)
 
-	router.Run(":3056")
+	shutdown := func() {
+		handler.Shutdown()
+		if resolver != nil {
+			resolver.Close()
+		}
+	}
+	return router, shutdown, nil
+}
+
+// InitializeRelay inicia o servidor HTTP do modo relay, que atende apenas as rotas
+// adicionadas por register
+func InitializeRelay(register func(gin.IRouter), options Options) error {
+	router, err := newEngine(options)
+	if err != nil {
+		return err
+	}
+
+	register(router)
+
+	return run(router)
+}
+
+// run atende as requisições até o processo receber SIGINT ou SIGTERM. As requisições em
+// andamento, incluindo os streams, são encerradas antes de retornar.
+func run(router *gin.Engine) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:        ":3056",
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// newEngine cria o engine do Gin com os proxies confiáveis configurados
+func newEngine(options Options) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(options.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	return router, nil
 }
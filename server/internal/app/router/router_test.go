@@ -1,27 +1,40 @@
 package router
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/manorfm/totoogle/internal/app/config"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/handler"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
+// setupTestDB cria uma base de dados em um diretório temporário com todas as tabelas usadas pelos handlers
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "toggles.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	err = db.AutoMigrate(&entity.Application{}, &entity.Toggle{}, &entity.ToggleRule{}, &entity.TogglePrerequisite{}, &entity.ToggleRevision{}, &entity.ApplicationSnapshot{}, &entity.AuditEvent{}, &entity.FreezeWindow{}, &entity.Segment{}, &entity.User{}, &entity.SecretKey{}, &entity.Team{}, &entity.TeamUser{}, &entity.TeamApplication{}, &entity.ToggleMetric{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	return db
+}
+
 func TestInit(t *testing.T) {
 	// Configura o modo de teste do Gin
 	gin.SetMode(gin.TestMode)
 
-	// Inicializa a configuração
-	err := config.Init()
-	if err != nil {
-		t.Fatalf("Failed to init config: %v", err)
-	}
-
 	// Inicializa os handlers
-	handler.InitHandlers(config.GetDatabase())
+	handler.InitHandlers(setupTestDB(t))
 
 	// Cria o router
 	router := gin.New()
@@ -37,14 +50,8 @@ func TestRoutes(t *testing.T) {
 	// Configura o modo de teste do Gin
 	gin.SetMode(gin.TestMode)
 
-	// Inicializa a configuração
-	err := config.Init()
-	if err != nil {
-		t.Fatalf("Failed to init config: %v", err)
-	}
-
 	// Inicializa os handlers
-	handler.InitHandlers(config.GetDatabase())
+	handler.InitHandlers(setupTestDB(t))
 
 	// Cria o router
 	router := gin.New()
@@ -85,14 +92,8 @@ func TestStaticRoutes(t *testing.T) {
 	// Configura o modo de teste do Gin
 	gin.SetMode(gin.TestMode)
 
-	// Inicializa a configuração
-	err := config.Init()
-	if err != nil {
-		t.Fatalf("Failed to init config: %v", err)
-	}
-
 	// Inicializa os handlers
-	handler.InitHandlers(config.GetDatabase())
+	handler.InitHandlers(setupTestDB(t))
 
 	// Cria o router
 	router := gin.New()
@@ -108,3 +109,103 @@ func TestStaticRoutes(t *testing.T) {
 		t.Errorf("Expected status 404 for non-existent static file, got %d", w.Code)
 	}
 }
+
+func TestNewEngine_TrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(options Options, remoteAddr string) string {
+		engine, err := newEngine(options)
+		if err != nil {
+			t.Fatalf("Failed to create engine: %v", err)
+		}
+		engine.GET("/ip", func(c *gin.Context) {
+			c.String(http.StatusOK, c.ClientIP())
+		})
+
+		req, _ := http.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	// Sem proxies confiáveis o cabeçalho é ignorado
+	if ip := clientIP(Options{}, "10.0.0.1:4000"); ip != "10.0.0.1" {
+		t.Errorf("Expected remote address, got %s", ip)
+	}
+
+	// Os proxies confiáveis são removidos da direita para a esquerda
+	if ip := clientIP(Options{TrustedProxies: []string{"10.0.0.0/8"}}, "10.0.0.1:4000"); ip != "203.0.113.7" {
+		t.Errorf("Expected forwarded client address, got %s", ip)
+	}
+
+	// Um proxy não confiável não pode falsificar o IP
+	if ip := clientIP(Options{TrustedProxies: []string{"10.0.0.0/8"}}, "198.51.100.1:4000"); ip != "198.51.100.1" {
+		t.Errorf("Expected untrusted remote address, got %s", ip)
+	}
+
+	if _, err := newEngine(Options{TrustedProxies: []string{"not-an-ip"}}); err == nil {
+		t.Error("Expected invalid trusted proxies to fail")
+	}
+}
+
+// TestSetup_APIRoutes envia as rotas da API pelo engine completo do servidor: sem autenticação
+// cada uma precisa responder apenas o JSON do middleware de autenticação, sem o frontend
+// servido por ServeStatic antes dele
+func TestSetup_APIRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, shutdown, err := setup(setupTestDB(t), Options{})
+	if err != nil {
+		t.Fatalf("Failed to set up router: %v", err)
+	}
+	defer shutdown()
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/applications/app1"},
+		{"GET", "/applications/app1/reports/stale"},
+		{"GET", "/search"},
+		{"GET", "/segments"},
+		{"POST", "/segments"},
+		{"GET", "/segments/seg1"},
+		{"GET", "/applications/app1/segments"},
+		{"PUT", "/applications/app1/segments/seg1"},
+		{"POST", "/applications/app1/explain"},
+		{"GET", "/applications/app1/snapshots"},
+		{"POST", "/applications/app1/snapshots/snap1/restore"},
+		{"GET", "/applications/app1/trash"},
+		{"POST", "/applications/app1/trash/toggle1/restore"},
+		{"GET", "/trash/applications"},
+		{"POST", "/trash/applications/app1/restore"},
+		{"POST", "/applications/app1/kill-switch"},
+		{"GET", "/applications/app1/audit"},
+		{"GET", "/freeze-windows"},
+		{"DELETE", "/freeze-windows/window1"},
+		{"GET", "/applications/app1/freeze-windows"},
+		{"POST", "/applications/app1/toggles:batch"},
+		{"PUT", "/applications/app1/toggles/toggle1/metadata"},
+		{"PUT", "/applications/app1/toggles/toggle1/prerequisites"},
+		{"GET", "/applications/app1/toggles/toggle1/history"},
+		{"POST", "/applications/app1/toggles/toggle1/rollback"},
+		{"POST", "/sync"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(route.method, route.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d: %s", w.Code, w.Body.String())
+			}
+			if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") || !json.Valid(w.Body.Bytes()) {
+				t.Errorf("Expected only a JSON body, got %q", w.Body.String())
+			}
+		})
+	}
+}
@@ -36,6 +36,9 @@ func Init(router *gin.Engine) {
 	api := router.Group("/api")
 	{
 		api.GET("/toggles", handler.GetTogglesBySecret)
+		api.GET("/toggles/stream", handler.StreamTogglesBySecret)
+		api.POST("/metrics", handler.PostMetrics)
+		api.POST("/evaluate", handler.Evaluate)
 	}
 
 	// Rotas protegidas que requerem autenticação
@@ -50,6 +53,8 @@ func Init(router *gin.Engine) {
 			applications.GET("/:id", handler.GetApplication)
 			applications.PUT("/:id", handler.RequireAdmin(), handler.UpdateApplication)
 			applications.DELETE("/:id", handler.RequireRoot(), handler.DeleteApplication)
+			applications.POST("/:id/kill-switch", handler.RequireAdmin(), handler.SetKillSwitch)
+			applications.GET("/:id/audit", handler.GetAuditEvents)
 			
 			// Rotas de secret keys para aplicações (apenas admin/root)
 			applications.POST("/:id/generate-secret", handler.RequireAdmin(), handler.GenerateSecretKey)
@@ -62,13 +67,89 @@ func Init(router *gin.Engine) {
 			toggles.POST("", handler.RequireAdmin(), handler.CreateToggle)
 			toggles.GET("", handler.GetAllToggles) // Filtrado por permissão internamente
 		}
+		// Operações em lote; o gin trata ":batch" como parâmetro e o handler confere o sufixo
+		protected.POST("/applications/:id/toggles:batch", handler.RequireAdmin(), handler.ApplyToggleBatch)
 		toggleById := protected.Group("/applications/:id/toggles/:toggleId")
 		{
 			toggleById.GET("", handler.GetToggleStatus)
 			toggleById.PUT("", handler.RequireAdmin(), handler.UpdateToggle)
+			toggleById.PUT("/metadata", handler.RequireAdmin(), handler.UpdateToggleMetadata)
+			toggleById.PUT("/prerequisites", handler.RequireAdmin(), handler.UpdateTogglePrerequisites)
 			toggleById.DELETE("", handler.RequireAdmin(), handler.DeleteToggle)
+			toggleById.GET("/metrics", handler.GetToggleMetrics)
+			toggleById.GET("/history", handler.GetToggleHistory)
+			toggleById.POST("/rollback", handler.RequireAdmin(), handler.RollbackToggle)
 		}
 
+		// Rotas de snapshots da aplicação
+		snapshots := protected.Group("/applications/:id/snapshots")
+		{
+			snapshots.POST("", handler.RequireAdmin(), handler.CreateSnapshot)
+			snapshots.GET("", handler.GetSnapshots)
+			snapshots.POST("/:snapshotId/restore", handler.RequireAdmin(), handler.RestoreSnapshot)
+		}
+
+		// Rotas da lixeira: toggles por aplicação e aplicações removidas (apenas root)
+		toggleTrash := protected.Group("/applications/:id/trash")
+		{
+			toggleTrash.GET("", handler.GetToggleTrash)
+			toggleTrash.POST("/:toggleId/restore", handler.RequireAdmin(), handler.RestoreTrashedToggle)
+		}
+		applicationTrash := protected.Group("/trash/applications")
+		applicationTrash.Use(handler.RequireRoot())
+		{
+			applicationTrash.GET("", handler.GetApplicationTrash)
+			applicationTrash.POST("/:id/restore", handler.RestoreTrashedApplication)
+		}
+
+		// Rotas de segmentos da aplicação
+		appSegments := protected.Group("/applications/:id/segments")
+		{
+			appSegments.POST("", handler.RequireAdmin(), handler.CreateSegment)
+			appSegments.GET("", handler.GetAllSegments)
+			appSegments.GET("/:segmentId", handler.GetSegment)
+			appSegments.PUT("/:segmentId", handler.RequireAdmin(), handler.UpdateSegment)
+			appSegments.DELETE("/:segmentId", handler.RequireAdmin(), handler.DeleteSegment)
+		}
+
+		// Rotas de segmentos globais, disponíveis para todas as aplicações (alteração apenas root)
+		globalSegments := protected.Group("/segments")
+		{
+			globalSegments.POST("", handler.RequireRoot(), handler.CreateSegment)
+			globalSegments.GET("", handler.GetAllSegments)
+			globalSegments.GET("/:segmentId", handler.GetSegment)
+			globalSegments.PUT("/:segmentId", handler.RequireRoot(), handler.UpdateSegment)
+			globalSegments.DELETE("/:segmentId", handler.RequireRoot(), handler.DeleteSegment)
+		}
+
+		// Janelas de congelamento da aplicação, em que os toggles não podem ser alterados
+		appFreezeWindows := protected.Group("/applications/:id/freeze-windows")
+		{
+			appFreezeWindows.POST("", handler.RequireAdmin(), handler.CreateFreezeWindow)
+			appFreezeWindows.GET("", handler.GetFreezeWindows)
+			appFreezeWindows.DELETE("/:windowId", handler.RequireAdmin(), handler.DeleteFreezeWindow)
+		}
+
+		// Janelas de congelamento globais, válidas para todas as aplicações (alteração apenas root)
+		globalFreezeWindows := protected.Group("/freeze-windows")
+		{
+			globalFreezeWindows.POST("", handler.RequireRoot(), handler.CreateFreezeWindow)
+			globalFreezeWindows.GET("", handler.GetFreezeWindows)
+			globalFreezeWindows.DELETE("/:windowId", handler.RequireRoot(), handler.DeleteFreezeWindow)
+		}
+
+		// Busca de toggles em todas as aplicações acessíveis (filtrada por permissão internamente)
+		protected.GET("/search", handler.Search)
+
+		// Relatório de toggles obsoletos
+		protected.GET("/applications/:id/reports/stale", handler.GetStaleReport)
+
+		// Explicação da avaliação de um toggle para um contexto
+		protected.POST("/applications/:id/explain", handler.Explain)
+
+		// Sincronização declarativa a partir de um arquivo de flags (apenas root)
+		protected.POST("/sync", handler.RequireRoot(), handler.Sync)
+
 		// Rota para atualizar enabled recursivamente (apenas admin/root)
 		protected.PUT("/applications/:id/toggle/:toggleId", handler.RequireAdmin(), handler.UpdateEnabled)
 
@@ -1,29 +1,41 @@
 package usecase
 
 import (
+	"time"
+
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/domain/repository"
 )
 
 // ApplicationUseCase define os casos de uso para aplicações
 type ApplicationUseCase struct {
-	appRepo repository.ApplicationRepository
+	appRepo   repository.ApplicationRepository
+	auditRepo repository.AuditEventRepository
+
+	managedPolicy entity.ManagedPolicy
 }
 
 // NewApplicationUseCase cria uma nova instância de ApplicationUseCase
-func NewApplicationUseCase(appRepo repository.ApplicationRepository) *ApplicationUseCase {
+func NewApplicationUseCase(appRepo repository.ApplicationRepository, auditRepo repository.AuditEventRepository) *ApplicationUseCase {
 	return &ApplicationUseCase{
-		appRepo: appRepo,
+		appRepo:   appRepo,
+		auditRepo: auditRepo,
 	}
 }
 
-// CreateApplication cria uma nova aplicação
-func (uc *ApplicationUseCase) CreateApplication(name string) (*entity.Application, error) {
+// SetManagedPolicy define se os usuários podem renomear ou remover as aplicações criadas pela sincronização
+func (uc *ApplicationUseCase) SetManagedPolicy(policy entity.ManagedPolicy) {
+	uc.managedPolicy = policy
+}
+
+// CreateApplication cria uma nova aplicação; as criadas pela sincronização são gerenciadas
+func (uc *ApplicationUseCase) CreateApplication(name string, actor *entity.User) (*entity.Application, error) {
 	if name == "" {
 		return nil, entity.NewAppError(entity.ErrCodeValidation, "application name is required")
 	}
 
 	app := entity.NewApplication(name)
+	app.Managed = actor.IsSync()
 
 	// Verifica se já existe uma aplicação com o mesmo nome
 	exists, err := uc.appRepo.Exists(app.ID)
@@ -77,8 +89,28 @@ func (uc *ApplicationUseCase) GetAllApplicationsWithCounts() ([]*entity.Applicat
 	return apps, nil
 }
 
+// ListApplicationsWithCounts lista aplicações com contagem de toggles usando paginação por cursor.
+// Quando ids não é nil, apenas as aplicações informadas são consideradas.
+func (uc *ApplicationUseCase) ListApplicationsWithCounts(page *entity.PageRequest, ids []string) (*entity.Page[*entity.ApplicationWithCounts], error) {
+	validation := page.Normalize(entity.ApplicationListFields)
+	if !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	if ids != nil && len(ids) == 0 {
+		return &entity.Page[*entity.ApplicationWithCounts]{Items: []*entity.ApplicationWithCounts{}}, nil
+	}
+
+	result, err := uc.appRepo.ListWithToggleCounts(page, ids)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching applications with counts")
+	}
+
+	return result, nil
+}
+
 // UpdateApplication atualiza uma aplicação
-func (uc *ApplicationUseCase) UpdateApplication(id, name string) (*entity.Application, error) {
+func (uc *ApplicationUseCase) UpdateApplication(id, name string, actor *entity.User) (*entity.Application, error) {
 	if id == "" {
 		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
 	}
@@ -92,7 +124,15 @@ func (uc *ApplicationUseCase) UpdateApplication(id, name string) (*entity.Applic
 		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
 	}
 
+	// A sincronização identifica as aplicações pelo nome
+	if err := uc.managedPolicy.Check(app.Managed && app.Name != name, actor, "application "+app.Name); err != nil {
+		return nil, err
+	}
+
 	app.Name = name
+	if actor.IsSync() {
+		app.Managed = true
+	}
 
 	err = uc.appRepo.Update(app)
 	if err != nil {
@@ -102,19 +142,19 @@ func (uc *ApplicationUseCase) UpdateApplication(id, name string) (*entity.Applic
 	return app, nil
 }
 
-// DeleteApplication remove uma aplicação
-func (uc *ApplicationUseCase) DeleteApplication(id string) error {
+// DeleteApplication move uma aplicação e seus toggles para a lixeira
+func (uc *ApplicationUseCase) DeleteApplication(id string, actor *entity.User) error {
 	if id == "" {
 		return entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
 	}
 
-	exists, err := uc.appRepo.Exists(id)
+	app, err := uc.appRepo.GetByID(id)
 	if err != nil {
-		return entity.NewAppError(entity.ErrCodeDatabase, "error checking application existence")
+		return entity.NewAppError(entity.ErrCodeNotFound, "application not found")
 	}
 
-	if !exists {
-		return entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	if err := uc.managedPolicy.Check(app.Managed, actor, "application "+app.Name); err != nil {
+		return err
 	}
 
 	err = uc.appRepo.Delete(id)
@@ -125,6 +165,60 @@ func (uc *ApplicationUseCase) DeleteApplication(id string) error {
 	return nil
 }
 
+// SetKillSwitch liga ou desliga o kill switch da aplicação e registra a ação na auditoria.
+// O estado de cada toggle não é alterado, então desligar o kill switch restaura exatamente o estado anterior.
+func (uc *ApplicationUseCase) SetKillSwitch(id string, active bool, actor *entity.User) (*entity.Application, error) {
+	if id == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+
+	app, err := uc.appRepo.GetByID(id)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	if app.KillSwitch == active {
+		return app, nil
+	}
+
+	action := entity.AuditActionKillSwitchDeactivated
+	detail := "kill switch deactivated"
+	app.KillSwitch = active
+	app.KillSwitchAt = nil
+	if active {
+		now := time.Now()
+		app.KillSwitchAt = &now
+		action = entity.AuditActionKillSwitchActivated
+		detail = "kill switch activated: every toggle is off for SDKs and evaluation"
+	}
+
+	if err := uc.appRepo.Update(app); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error updating application")
+	}
+	if err := uc.auditRepo.Create(entity.NewAuditEvent(app.ID, action, detail, actor)); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error recording audit event")
+	}
+
+	return app, nil
+}
+
+// GetAuditEvents lista os eventos de auditoria da aplicação, do mais recente para o mais antigo
+func (uc *ApplicationUseCase) GetAuditEvents(id string) ([]*entity.AuditEvent, error) {
+	exists, err := uc.appRepo.Exists(id)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error checking application existence")
+	}
+	if !exists {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	events, err := uc.auditRepo.GetByAppID(id)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching audit events")
+	}
+	return events, nil
+}
+
 // GetApplicationsWithCountsByIDs busca aplicações específicas com contagem de toggles
 func (uc *ApplicationUseCase) GetApplicationsWithCountsByIDs(ids []string) ([]*entity.ApplicationWithCounts, error) {
 	if len(ids) == 0 {
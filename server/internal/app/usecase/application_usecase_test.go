@@ -45,8 +45,8 @@ func TestApplicationUseCase_CreateApplication(t *testing.T) {
 			mockRepo := NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
 
-			useCase := NewApplicationUseCase(mockRepo)
-			app, err := useCase.CreateApplication(tt.appName)
+			useCase := NewApplicationUseCase(mockRepo, NewMockAuditEventRepository())
+			app, err := useCase.CreateApplication(tt.appName, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -113,7 +113,7 @@ func TestApplicationUseCase_GetApplicationByID(t *testing.T) {
 			mockRepo := NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
 
-			useCase := NewApplicationUseCase(mockRepo)
+			useCase := NewApplicationUseCase(mockRepo, NewMockAuditEventRepository())
 			app, err := useCase.GetApplicationByID(tt.appID)
 
 			if tt.expectedError != "" {
@@ -147,7 +147,7 @@ func TestApplicationUseCase_GetAllApplications(t *testing.T) {
 	mockRepo.Applications["app1"] = &entity.Application{ID: "app1", Name: "App 1"}
 	mockRepo.Applications["app2"] = &entity.Application{ID: "app2", Name: "App 2"}
 
-	useCase := NewApplicationUseCase(mockRepo)
+	useCase := NewApplicationUseCase(mockRepo, NewMockAuditEventRepository())
 	apps, err := useCase.GetAllApplications()
 
 	if err != nil {
@@ -201,8 +201,8 @@ func TestApplicationUseCase_UpdateApplication(t *testing.T) {
 			mockRepo := NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
 
-			useCase := NewApplicationUseCase(mockRepo)
-			app, err := useCase.UpdateApplication(tt.appID, tt.newName)
+			useCase := NewApplicationUseCase(mockRepo, NewMockAuditEventRepository())
+			app, err := useCase.UpdateApplication(tt.appID, tt.newName, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -258,8 +258,8 @@ func TestApplicationUseCase_DeleteApplication(t *testing.T) {
 			mockRepo := NewMockApplicationRepository()
 			tt.setupMock(mockRepo)
 
-			useCase := NewApplicationUseCase(mockRepo)
-			err := useCase.DeleteApplication(tt.appID)
+			useCase := NewApplicationUseCase(mockRepo, NewMockAuditEventRepository())
+			err := useCase.DeleteApplication(tt.appID, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -282,3 +282,69 @@ func TestApplicationUseCase_DeleteApplication(t *testing.T) {
 		})
 	}
 }
+
+func TestApplicationUseCase_SetKillSwitch(t *testing.T) {
+	mockRepo := NewMockApplicationRepository()
+	mockRepo.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	auditMock := NewMockAuditEventRepository()
+	useCase := NewApplicationUseCase(mockRepo, auditMock)
+	actor := &entity.User{ID: "user1", Username: "oncall"}
+
+	app, err := useCase.SetKillSwitch("app123", true, actor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !app.KillSwitch || app.KillSwitchAt == nil {
+		t.Errorf("Expected kill switch to be active, got %+v", app)
+	}
+
+	// Ativar de novo não gera outro evento
+	useCase.SetKillSwitch("app123", true, actor)
+	if len(auditMock.Events) != 1 {
+		t.Fatalf("Expected 1 audit event, got %d", len(auditMock.Events))
+	}
+
+	app, _ = useCase.SetKillSwitch("app123", false, nil)
+	if app.KillSwitch || app.KillSwitchAt != nil {
+		t.Errorf("Expected kill switch to be inactive, got %+v", app)
+	}
+
+	events, err := useCase.GetAuditEvents("app123")
+	if err != nil || len(events) != 2 {
+		t.Fatalf("Expected 2 audit events, got %d, %v", len(events), err)
+	}
+	if events[0].Action != entity.AuditActionKillSwitchDeactivated || events[0].Actor != "system" {
+		t.Errorf("Unexpected latest event %+v", events[0])
+	}
+	if events[1].Action != entity.AuditActionKillSwitchActivated || events[1].ActorID != "user1" || events[1].Actor != "oncall" {
+		t.Errorf("Unexpected first event %+v", events[1])
+	}
+
+	_, err = useCase.SetKillSwitch("unknown", true, actor)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeNotFound {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestApplicationUseCase_ManagedPolicy(t *testing.T) {
+	mockRepo := NewMockApplicationRepository()
+	useCase := NewApplicationUseCase(mockRepo, NewMockAuditEventRepository())
+	useCase.SetManagedPolicy(entity.ManagedPolicyBlock)
+	admin := &entity.User{ID: "admin1", Username: "admin", Role: entity.UserRoleAdmin}
+
+	app, err := useCase.CreateApplication("Shop", entity.NewSyncActor(admin))
+	if err != nil || !app.Managed {
+		t.Fatalf("Expected a managed application, got %+v %v", app, err)
+	}
+
+	// A sincronização identifica a aplicação pelo nome, então apenas a renomeação é bloqueada
+	if _, err := useCase.UpdateApplication(app.ID, "Shop", admin); err != nil {
+		t.Errorf("Expected update keeping the name to be accepted, got %v", err)
+	}
+	if _, err := useCase.UpdateApplication(app.ID, "Store", admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error on rename, got %v", err)
+	}
+	if err := useCase.DeleteApplication(app.ID, admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error on delete, got %v", err)
+	}
+}
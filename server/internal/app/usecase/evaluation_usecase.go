@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// EvaluationUseCase define os casos de uso para avaliação de toggles no servidor
+type EvaluationUseCase struct {
+	toggleRepo      repository.ToggleRepository
+	appRepo         repository.ApplicationRepository
+	segmentRepo     repository.SegmentRepository
+	countryResolver entity.CountryResolver
+}
+
+// NewEvaluationUseCase cria uma nova instância de EvaluationUseCase.
+// countryResolver é opcional; sem ele o país só vem do contexto.
+func NewEvaluationUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository, segmentRepo repository.SegmentRepository, countryResolver entity.CountryResolver) *EvaluationUseCase {
+	return &EvaluationUseCase{
+		toggleRepo:      toggleRepo,
+		appRepo:         appRepo,
+		segmentRepo:     segmentRepo,
+		countryResolver: countryResolver,
+	}
+}
+
+// Evaluate avalia um toggle da aplicação para o contexto informado, retornando o estado e a variante escolhida
+func (uc *EvaluationUseCase) Evaluate(appID string, path string, ctx *evaluator.Context) (*evaluator.Result, error) {
+	toggle, ctx, err := uc.prepare(appID, path, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return evaluator.Evaluate(toggle, ctx), nil
+}
+
+// Explain avalia um toggle da aplicação como Evaluate e retorna o rastro completo da avaliação
+func (uc *EvaluationUseCase) Explain(appID string, path string, ctx *evaluator.Context) (*evaluator.Trace, error) {
+	toggle, ctx, err := uc.prepare(appID, path, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return evaluator.Explain(toggle, ctx), nil
+}
+
+// prepare carrega o toggle com a sua hierarquia e completa o contexto com o país, os segmentos
+// referenciados e o kill switch da aplicação
+func (uc *EvaluationUseCase) prepare(appID string, path string, ctx *evaluator.Context) (*evaluator.Toggle, *evaluator.Context, error) {
+	path = strings.TrimSpace(path)
+	if appID == "" || path == "" {
+		return nil, nil, entity.NewAppError(entity.ErrCodeValidation, "application ID and toggle path are required")
+	}
+
+	app, err := uc.appRepo.GetByID(appID)
+	if err != nil {
+		return nil, nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	toggles, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+
+	toggle := linkParents(toggles)[path]
+	if toggle == nil {
+		return nil, nil, entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
+	}
+
+	if ctx == nil {
+		ctx = &evaluator.Context{}
+	}
+	country, err := entity.ResolveCountry(ctx.Country, ctx.IP, uc.countryResolver)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx.Country = country
+	segments, err := uc.referencedSegments(toggle, appID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx.Segments = make(map[string]*evaluator.Segment, len(segments))
+	for id, segment := range segments {
+		ctx.Segments[id] = evaluationSegment(segment)
+	}
+	ctx.KillSwitch = app.KillSwitch
+
+	return evaluationToggle(toggle, make(map[*entity.Toggle]*evaluator.Toggle)), ctx, nil
+}
+
+// referencedSegments carrega os segmentos disponíveis para a aplicação referenciados pelas regras do toggle
+// e dos toggles dos quais ele depende (ancestrais e pré-requisitos)
+func (uc *EvaluationUseCase) referencedSegments(toggle *entity.Toggle, appID string) (map[string]*entity.Segment, error) {
+	segments := make(map[string]*entity.Segment)
+	rules := make([]*entity.ToggleRule, 0)
+	for _, dependency := range dependencyClosure(toggle) {
+		rules = append(rules, dependency.Rules...)
+	}
+	ids := entity.RuleSegmentIDs(rules)
+	if len(ids) == 0 {
+		return segments, nil
+	}
+
+	found, err := uc.segmentRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching segments")
+	}
+	for _, segment := range found {
+		if segment.AvailableTo(appID) {
+			segments[segment.ID] = segment
+		}
+	}
+	return segments, nil
+}
+
+// dependencyClosure retorna o toggle e todos os toggles dos quais a sua avaliação depende
+func dependencyClosure(toggle *entity.Toggle) []*entity.Toggle {
+	seen := make(map[*entity.Toggle]bool)
+	closure := make([]*entity.Toggle, 0)
+	pending := []*entity.Toggle{toggle}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == nil || seen[current] {
+			continue
+		}
+		seen[current] = true
+		closure = append(closure, current)
+		pending = append(pending, current.Parent)
+		for _, prerequisite := range current.Prerequisites {
+			pending = append(pending, prerequisite.Prerequisite)
+		}
+	}
+	return closure
+}
+
+// linkParents liga cada toggle ao seu pai e aos seus pré-requisitos e retorna os toggles indexados pelo caminho
+func linkParents(toggles []*entity.Toggle) map[string]*entity.Toggle {
+	byID := make(map[string]*entity.Toggle, len(toggles))
+	for _, toggle := range toggles {
+		byID[toggle.ID] = toggle
+	}
+
+	byPath := make(map[string]*entity.Toggle, len(toggles))
+	for _, toggle := range toggles {
+		if toggle.ParentID != nil {
+			toggle.Parent = byID[*toggle.ParentID]
+		}
+		for _, prerequisite := range toggle.Prerequisites {
+			prerequisite.Prerequisite = byID[prerequisite.PrerequisiteID]
+		}
+		byPath[toggle.Path] = toggle
+	}
+	return byPath
+}
+
+// evaluationToggle converte o toggle, a sua hierarquia e os seus pré-requisitos para o modelo do avaliador.
+// converted guarda os toggles já convertidos, para que cada um seja convertido uma única vez.
+func evaluationToggle(toggle *entity.Toggle, converted map[*entity.Toggle]*evaluator.Toggle) *evaluator.Toggle {
+	if toggle == nil {
+		return nil
+	}
+	if model, ok := converted[toggle]; ok {
+		return model
+	}
+
+	model := &evaluator.Toggle{Path: toggle.Path, Enabled: toggle.Enabled, Variants: toggle.Variants}
+	converted[toggle] = model
+	model.Parent = evaluationToggle(toggle.Parent, converted)
+	for _, rule := range toggle.Rules {
+		conditions := make([]*evaluator.Condition, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, &evaluator.Condition{Type: condition.Type, Value: condition.Value, Config: condition.Config})
+		}
+		model.Rules = append(model.Rules, &evaluator.Rule{Position: rule.Position, Variant: rule.Variant, Conditions: conditions})
+	}
+	for _, prerequisite := range toggle.Prerequisites {
+		model.Prerequisites = append(model.Prerequisites, &evaluator.Prerequisite{
+			ID:      prerequisite.PrerequisiteID,
+			Enabled: prerequisite.Enabled,
+			Toggle:  evaluationToggle(prerequisite.Prerequisite, converted),
+		})
+	}
+	return model
+}
+
+// evaluationSegment converte o segmento para o modelo do avaliador
+func evaluationSegment(segment *entity.Segment) *evaluator.Segment {
+	model := &evaluator.Segment{ID: segment.ID, Name: segment.Name}
+	for _, constraint := range segment.Constraints {
+		model.Constraints = append(model.Constraints, &evaluator.Constraint{Attribute: constraint.Attribute, Operator: constraint.Operator, Values: constraint.Values})
+	}
+	return model
+}
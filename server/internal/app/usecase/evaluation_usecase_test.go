@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+)
+
+// newEvaluationTestAppRepository cria o mock de aplicações com a aplicação usada nos testes de avaliação
+func newEvaluationTestAppRepository() *MockApplicationRepository {
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	return appMock
+}
+
+func TestEvaluationUseCase_Evaluate(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	parentID := "parent"
+	toggleMock.Toggles["parent"] = &entity.Toggle{ID: "parent", AppID: "app123", Path: "shop", Enabled: true}
+	toggleMock.Toggles["child"] = &entity.Toggle{
+		ID:       "child",
+		AppID:    "app123",
+		Path:     "shop.checkout",
+		Enabled:  true,
+		ParentID: &parentID,
+		Variants: entity.ToggleVariants{
+			{Name: "v2", PayloadType: entity.VariantPayloadNumber, Payload: json.RawMessage(`2`), Weight: 100},
+		},
+	}
+	useCase := NewEvaluationUseCase(toggleMock, newEvaluationTestAppRepository(), NewMockSegmentRepository(), nil)
+
+	result, err := useCase.Evaluate("app123", "shop.checkout", &evaluator.Context{UserID: "u1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Enabled || result.Variant == nil || result.Variant.Name != "v2" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	// Desligar o pai desliga o filho
+	toggleMock.Toggles["parent"].Enabled = false
+	result, _ = useCase.Evaluate("app123", "shop.checkout", nil)
+	if result.Enabled || result.Reason != evaluator.ReasonParentDisabled {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	_, err = useCase.Evaluate("app123", "unknown", nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeNotFound {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	_, err = useCase.Evaluate("app123", " ", nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	_, err = useCase.Evaluate("unknown", "shop", nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeNotFound {
+		t.Errorf("Expected not found error for an unknown application, got %v", err)
+	}
+}
+
+func TestEvaluationUseCase_Evaluate_KillSwitch(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	toggleMock.Toggles["promo"] = &entity.Toggle{ID: "promo", AppID: "app123", Path: "promo", Enabled: true}
+	appMock := newEvaluationTestAppRepository()
+	useCase := NewEvaluationUseCase(toggleMock, appMock, NewMockSegmentRepository(), nil)
+
+	appMock.Applications["app123"].KillSwitch = true
+	result, err := useCase.Evaluate("app123", "promo", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Enabled || result.Reason != evaluator.ReasonKillSwitch {
+		t.Errorf("Expected kill switch to disable the toggle, got %+v", result)
+	}
+
+	trace, _ := useCase.Explain("app123", "promo", nil)
+	if !trace.KillSwitch || trace.Result.Reason != evaluator.ReasonKillSwitch {
+		t.Errorf("Expected kill switch in the trace, got %+v", trace)
+	}
+
+	appMock.Applications["app123"].KillSwitch = false
+	if result, _ := useCase.Evaluate("app123", "promo", nil); !result.Enabled {
+		t.Errorf("Expected toggle enabled again, got %+v", result)
+	}
+}
+
+func TestEvaluationUseCase_Evaluate_Country(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	toggle := &entity.Toggle{ID: "promo", AppID: "app123", Path: "promo", Enabled: true}
+	toggle.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeCountry, Value: "br, pt"})
+	toggleMock.Toggles["promo"] = toggle
+
+	resolver := NewMockCountryResolver()
+	resolver.Countries["200.160.2.3"] = "BR"
+	resolver.Countries["8.8.8.8"] = "US"
+	useCase := NewEvaluationUseCase(toggleMock, newEvaluationTestAppRepository(), NewMockSegmentRepository(), resolver)
+
+	tests := []struct {
+		name     string
+		ctx      *evaluator.Context
+		expected bool
+	}{
+		{"country from IP", &evaluator.Context{IP: "200.160.2.3"}, true},
+		{"country from IP outside the list", &evaluator.Context{IP: "8.8.8.8"}, false},
+		{"explicit country wins over IP", &evaluator.Context{IP: "8.8.8.8", Country: "pt"}, true},
+		{"unknown IP", &evaluator.Context{IP: "10.0.0.1"}, false},
+		{"invalid IP", &evaluator.Context{IP: "unknown"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := useCase.Evaluate("app123", "promo", tt.ctx)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Enabled != tt.expected {
+				t.Errorf("Expected enabled %v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	_, err := useCase.Evaluate("app123", "promo", &evaluator.Context{Country: "Brazil"})
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Errorf("Expected validation error for an invalid country, got %v", err)
+	}
+
+	// Sem base GeoIP o país só vem do contexto
+	withoutGeoIP := NewEvaluationUseCase(toggleMock, newEvaluationTestAppRepository(), NewMockSegmentRepository(), nil)
+	if result, _ := withoutGeoIP.Evaluate("app123", "promo", &evaluator.Context{IP: "200.160.2.3"}); result.Enabled {
+		t.Errorf("Expected country to stay unknown without a resolver, got %+v", result)
+	}
+}
+
+func TestEvaluationUseCase_Explain(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	toggle := &entity.Toggle{ID: "promo", AppID: "app123", Path: "promo", Enabled: true}
+	toggle.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeCountry, Value: "BR"})
+	toggleMock.Toggles["promo"] = toggle
+
+	resolver := NewMockCountryResolver()
+	resolver.Countries["200.160.2.3"] = "BR"
+	useCase := NewEvaluationUseCase(toggleMock, newEvaluationTestAppRepository(), NewMockSegmentRepository(), resolver)
+
+	trace, err := useCase.Explain("app123", "promo", &evaluator.Context{IP: "200.160.2.3"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !trace.Result.Enabled || trace.Context.Country != "BR" {
+		t.Errorf("Expected the country resolved from the IP, got %+v", trace.Result)
+	}
+	if len(trace.Rules) != 1 || len(trace.Rules[0].Conditions) != 1 || !trace.Rules[0].Conditions[0].Matched {
+		t.Errorf("Unexpected rules %+v", trace.Rules)
+	}
+
+	_, err = useCase.Explain("app123", "unknown", nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeNotFound {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// FreezeWindowUseCase define os casos de uso para janelas de congelamento.
+// Os métodos recebem appID nil para operar sobre as janelas globais.
+type FreezeWindowUseCase struct {
+	freezeRepo repository.FreezeWindowRepository
+	appRepo    repository.ApplicationRepository
+}
+
+// NewFreezeWindowUseCase cria uma nova instância de FreezeWindowUseCase
+func NewFreezeWindowUseCase(freezeRepo repository.FreezeWindowRepository, appRepo repository.ApplicationRepository) *FreezeWindowUseCase {
+	return &FreezeWindowUseCase{
+		freezeRepo: freezeRepo,
+		appRepo:    appRepo,
+	}
+}
+
+// CreateFreezeWindow cria uma janela na aplicação ou, com appID nil, uma janela global
+func (uc *FreezeWindowUseCase) CreateFreezeWindow(appID *string, startsAt, endsAt time.Time, reason string, exemptUsers []string, actor *entity.User) (*entity.FreezeWindow, error) {
+	if err := uc.checkApplication(appID); err != nil {
+		return nil, err
+	}
+
+	window := entity.NewFreezeWindow(appID, startsAt, endsAt, reason, exemptUsers, actor)
+	if validation := window.Validate(); !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	if err := uc.freezeRepo.Create(window); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error creating freeze window")
+	}
+
+	return window, nil
+}
+
+// ListFreezeWindows lista as janelas da aplicação ou, com appID nil, as janelas globais
+func (uc *FreezeWindowUseCase) ListFreezeWindows(appID *string) ([]*entity.FreezeWindow, error) {
+	if err := uc.checkApplication(appID); err != nil {
+		return nil, err
+	}
+
+	var windows []*entity.FreezeWindow
+	var err error
+	if appID == nil {
+		windows, err = uc.freezeRepo.GetGlobal()
+	} else {
+		windows, err = uc.freezeRepo.GetByAppID(*appID)
+	}
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching freeze windows")
+	}
+
+	return windows, nil
+}
+
+// DeleteFreezeWindow remove uma janela do escopo informado, encerrando o congelamento se ela estiver em vigor
+func (uc *FreezeWindowUseCase) DeleteFreezeWindow(id string, appID *string) error {
+	if id == "" {
+		return entity.NewAppError(entity.ErrCodeValidation, "freeze window ID is required")
+	}
+
+	window, err := uc.freezeRepo.GetByID(id)
+	if err != nil || !sameScope(window.AppID, appID) {
+		return entity.NewAppError(entity.ErrCodeNotFound, "freeze window not found")
+	}
+
+	if err := uc.freezeRepo.Delete(id); err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error deleting freeze window")
+	}
+
+	return nil
+}
+
+// checkApplication verifica se a aplicação existe; janelas globais não têm aplicação
+func (uc *FreezeWindowUseCase) checkApplication(appID *string) error {
+	if appID == nil {
+		return nil
+	}
+	if *appID == "" {
+		return entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+	if _, err := uc.appRepo.GetByID(*appID); err != nil {
+		return entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestFreezeWindowUseCase_CreateListDelete(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	freezeMock := NewMockFreezeWindowRepository()
+	useCase := NewFreezeWindowUseCase(freezeMock, appMock)
+
+	appID := "app123"
+	start := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	actor := &entity.User{ID: "user1", Username: "release-manager"}
+
+	window, err := useCase.CreateFreezeWindow(&appID, start, start.Add(72*time.Hour), " black friday ", []string{" ops ", ""}, actor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if window.Reason != "black friday" || len(window.ExemptUsers) != 1 || window.ExemptUsers[0] != "ops" || window.Actor != "release-manager" {
+		t.Errorf("Unexpected window %+v", window)
+	}
+	if _, err := useCase.CreateFreezeWindow(nil, start, start.Add(time.Hour), "global", nil, actor); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = useCase.CreateFreezeWindow(&appID, start, start, "empty", nil, actor)
+	if !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error for an end before the start, got %v", err)
+	}
+	unknown := "unknown"
+	_, err = useCase.CreateFreezeWindow(&unknown, start, start.Add(time.Hour), "reason", nil, actor)
+	if !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	windows, _ := useCase.ListFreezeWindows(&appID)
+	global, _ := useCase.ListFreezeWindows(nil)
+	if len(windows) != 1 || len(global) != 1 || global[0].AppID != nil {
+		t.Fatalf("Expected windows split by scope, got %d and %d", len(windows), len(global))
+	}
+
+	// Uma janela só é removida no seu escopo
+	if err := useCase.DeleteFreezeWindow(window.ID, nil); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error outside the scope, got %v", err)
+	}
+	if err := useCase.DeleteFreezeWindow(window.ID, &appID); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if windows, _ := useCase.ListFreezeWindows(&appID); len(windows) != 0 {
+		t.Errorf("Expected window to be deleted, got %d", len(windows))
+	}
+}
+
+func TestFreezeWindows_BlockSnapshotAndTrashRestore(t *testing.T) {
+	trashUseCase, toggleUseCase, toggleMock, appMock := newTrashTestUseCase()
+	snapshotUseCase := NewSnapshotUseCase(toggleMock, appMock, NewMockApplicationSnapshotRepository(), toggleUseCase)
+
+	snapshot, err := snapshotUseCase.CreateSnapshot("app123", "before freeze", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := toggleUseCase.DeleteToggleByID("pix", "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	freezeMock := toggleUseCase.freezeRepo.(*MockFreezeWindowRepository)
+	freezeMock.Create(entity.NewFreezeWindow(nil, time.Now().Add(-time.Minute), time.Now().Add(time.Hour), "release", nil, nil))
+
+	if err := snapshotUseCase.RestoreSnapshot("app123", snapshot.ID, nil); !isAppError(err, entity.ErrCodeFrozen) {
+		t.Errorf("Expected frozen error restoring a snapshot, got %v", err)
+	}
+	if _, err := trashUseCase.RestoreToggle("app123", "pix", nil); !isAppError(err, entity.ErrCodeFrozen) {
+		t.Errorf("Expected frozen error restoring from the trash, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// MaxEvaluationBatchSize limita a quantidade de registros aceitos em um único envio
+const MaxEvaluationBatchSize = 1000
+
+// DefaultMetricsRange é o intervalo padrão consultado quando nenhum é informado
+const DefaultMetricsRange = 7 * 24 * time.Hour
+
+// MetricsUseCase define os casos de uso para métricas de avaliação de toggles
+type MetricsUseCase struct {
+	metricRepo repository.ToggleMetricRepository
+	toggleRepo repository.ToggleRepository
+}
+
+// NewMetricsUseCase cria uma nova instância de MetricsUseCase
+func NewMetricsUseCase(metricRepo repository.ToggleMetricRepository, toggleRepo repository.ToggleRepository) *MetricsUseCase {
+	return &MetricsUseCase{
+		metricRepo: metricRepo,
+		toggleRepo: toggleRepo,
+	}
+}
+
+// RecordEvaluationsResult representa o resultado do registro de um lote de avaliações
+type RecordEvaluationsResult struct {
+	Accepted int                   `json:"accepted"`
+	Rejected []*entity.ErrorDetail `json:"rejected,omitempty"`
+}
+
+// RecordEvaluations agrega as avaliações reportadas pelo SDK em buckets por toggle.
+// Registros inválidos ou de toggles desconhecidos são rejeitados individualmente.
+func (uc *MetricsUseCase) RecordEvaluations(appID string, counts []*entity.EvaluationCount) (*RecordEvaluationsResult, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+
+	if len(counts) == 0 {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "at least one metric is required")
+	}
+
+	if len(counts) > MaxEvaluationBatchSize {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, fmt.Sprintf("at most %d metrics are accepted per request", MaxEvaluationBatchSize))
+	}
+
+	result := &RecordEvaluationsResult{}
+	now := time.Now().UTC()
+	toggleIDs := make(map[string]string)
+	buckets := make(map[string]*entity.ToggleMetric)
+	var ordered []*entity.ToggleMetric
+
+	for i, count := range counts {
+		field := fmt.Sprintf("metrics[%d]", i)
+
+		if err := count.Validate(now); err != nil {
+			result.Rejected = append(result.Rejected, &entity.ErrorDetail{Field: field, Message: err.Error()})
+			continue
+		}
+
+		toggleID, known := toggleIDs[count.Toggle]
+		if !known {
+			toggle, err := uc.toggleRepo.GetByPath(count.Toggle, appID)
+			if err != nil {
+				result.Rejected = append(result.Rejected, &entity.ErrorDetail{Field: field, Message: "toggle not found"})
+				continue
+			}
+			toggleID = toggle.ID
+			toggleIDs[count.Toggle] = toggleID
+		}
+
+		bucketStart := entity.MetricsBucketStart(count.WindowStart)
+		key := toggleID + "|" + bucketStart.Format(time.RFC3339)
+		bucket, exists := buckets[key]
+		if !exists {
+			bucket = entity.NewToggleMetric(appID, toggleID, bucketStart)
+			buckets[key] = bucket
+			ordered = append(ordered, bucket)
+		}
+
+		bucket.Add(count.Result, count.Count)
+		result.Accepted++
+	}
+
+	if len(ordered) > 0 {
+		if err := uc.metricRepo.Increment(ordered); err != nil {
+			return nil, entity.NewAppError(entity.ErrCodeDatabase, "error recording metrics")
+		}
+	}
+
+	return result, nil
+}
+
+// GetToggleMetrics retorna as métricas de um toggle agregadas no intervalo [from, to)
+func (uc *MetricsUseCase) GetToggleMetrics(toggleID, appID string, from, to time.Time) (*entity.ToggleMetricsSummary, error) {
+	if toggleID == "" || appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
+	}
+
+	toggle, err := uc.toggleRepo.GetByID(toggleID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
+	}
+
+	if toggle.AppID != appID {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
+	}
+
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-DefaultMetricsRange)
+	}
+	if !from.Before(to) {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "from must be before to")
+	}
+
+	metrics, err := uc.metricRepo.GetByToggleID(toggleID, entity.MetricsBucketStart(from), to)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching metrics")
+	}
+
+	summary := &entity.ToggleMetricsSummary{
+		ToggleID:   toggle.ID,
+		Path:       toggle.Path,
+		From:       from,
+		To:         to,
+		BucketSize: entity.MetricsBucketSize.String(),
+		Buckets:    metrics,
+	}
+	if summary.Buckets == nil {
+		summary.Buckets = []*entity.ToggleMetric{}
+	}
+
+	for _, metric := range metrics {
+		summary.TrueCount += metric.TrueCount
+		summary.FalseCount += metric.FalseCount
+	}
+	summary.Total = summary.TrueCount + summary.FalseCount
+
+	return summary, nil
+}
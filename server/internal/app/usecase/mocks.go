@@ -2,12 +2,19 @@ package usecase
 
 import (
 	"errors"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"gorm.io/gorm"
 )
 
 type MockApplicationRepository struct {
 	Applications map[string]*entity.Application
+	Trash        map[string]*entity.Application
 	CreateError  error
 	GetByIDError error
 	ExistsError  error
@@ -18,6 +25,7 @@ type MockApplicationRepository struct {
 func NewMockApplicationRepository() *MockApplicationRepository {
 	return &MockApplicationRepository{
 		Applications: make(map[string]*entity.Application),
+		Trash:        make(map[string]*entity.Application),
 	}
 }
 
@@ -64,6 +72,18 @@ func (m *MockApplicationRepository) GetAllWithToggleCounts() ([]*entity.Applicat
 	return apps, nil
 }
 
+func (m *MockApplicationRepository) ListWithToggleCounts(page *entity.PageRequest, ids []string) (*entity.Page[*entity.ApplicationWithCounts], error) {
+	all, _ := m.GetAllWithToggleCounts()
+	apps := []*entity.ApplicationWithCounts{}
+	for _, app := range all {
+		if ids == nil || containsID(ids, app.ID) {
+			apps = append(apps, app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+	return mockPage(apps, page, func(a *entity.ApplicationWithCounts) string { return a.ID }), nil
+}
+
 func (m *MockApplicationRepository) Update(app *entity.Application) error {
 	if m.UpdateError != nil {
 		return m.UpdateError
@@ -76,10 +96,48 @@ func (m *MockApplicationRepository) Delete(id string) error {
 	if m.DeleteError != nil {
 		return m.DeleteError
 	}
+	if app, exists := m.Applications[id]; exists {
+		app.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		m.Trash[id] = app
+	}
 	delete(m.Applications, id)
 	return nil
 }
 
+func (m *MockApplicationRepository) GetTrash() ([]*entity.Application, error) {
+	apps := make([]*entity.Application, 0, len(m.Trash))
+	for _, app := range m.Trash {
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].DeletedAt.Time.After(apps[j].DeletedAt.Time) })
+	return apps, nil
+}
+
+func (m *MockApplicationRepository) Undelete(id string, toggleIDs []string) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	app, exists := m.Trash[id]
+	if !exists {
+		return errors.New("application not found")
+	}
+	app.DeletedAt = gorm.DeletedAt{}
+	m.Applications[id] = app
+	delete(m.Trash, id)
+	return nil
+}
+
+func (m *MockApplicationRepository) Purge(before time.Time) (int64, error) {
+	var purged int64
+	for id, app := range m.Trash {
+		if app.DeletedAt.Time.Before(before) {
+			delete(m.Trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func (m *MockApplicationRepository) Exists(id string) (bool, error) {
 	if m.ExistsError != nil {
 		return false, m.ExistsError
@@ -90,26 +148,37 @@ func (m *MockApplicationRepository) Exists(id string) (bool, error) {
 
 type MockToggleRepository struct {
 	Toggles        map[string]*entity.Toggle
+	Trash          map[string]*entity.Toggle
 	CreateError    error
 	GetByIDError   error
 	GetByPathError error
 	UpdateError    error
 	DeleteError    error
 	ExistsError    error
+	RevisionRepo   *MockToggleRevisionRepository // Recebe as revisões gravadas junto com as alterações
 }
 
 func NewMockToggleRepository() *MockToggleRepository {
 	return &MockToggleRepository{
 		Toggles: make(map[string]*entity.Toggle),
+		Trash:   make(map[string]*entity.Toggle),
 	}
 }
 
-func (m *MockToggleRepository) Create(toggle *entity.Toggle) error {
+func (m *MockToggleRepository) Create(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
 	if m.CreateError != nil {
 		return m.CreateError
 	}
 	m.Toggles[toggle.ID] = toggle
-	return nil
+	return m.recordRevisions(revisions)
+}
+
+// recordRevisions grava as revisões no repositório de revisões ligado ao mock, quando houver
+func (m *MockToggleRepository) recordRevisions(revisions []*entity.ToggleRevision) error {
+	if m.RevisionRepo == nil {
+		return nil
+	}
+	return m.RevisionRepo.Create(revisions...)
 }
 
 func (m *MockToggleRepository) GetByID(id string) (*entity.Toggle, error) {
@@ -149,26 +218,153 @@ func (m *MockToggleRepository) GetHierarchyByAppID(appID string) ([]*entity.Togg
 	return m.GetByAppID(appID)
 }
 
-func (m *MockToggleRepository) Update(toggle *entity.Toggle) error {
+func (m *MockToggleRepository) GetByAppIDWithFilter(appID string, filter *entity.ToggleFilter) ([]*entity.Toggle, error) {
+	var toggles []*entity.Toggle
+	for _, toggle := range m.Toggles {
+		if toggle.AppID == appID && filter.Matches(toggle) {
+			toggles = append(toggles, toggle)
+		}
+	}
+	return toggles, nil
+}
+
+func (m *MockToggleRepository) ListByAppID(appID string, page *entity.PageRequest) (*entity.Page[*entity.Toggle], error) {
+	toggles, _ := m.GetByAppID(appID)
+	sort.Slice(toggles, func(i, j int) bool { return toggles[i].Path < toggles[j].Path })
+	return mockPage(toggles, page, func(t *entity.Toggle) string { return t.ID }), nil
+}
+
+func (m *MockToggleRepository) Update(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
 	if m.UpdateError != nil {
 		return m.UpdateError
 	}
 	m.Toggles[toggle.ID] = toggle
-	return nil
+	return m.recordRevisions(revisions)
+}
+
+func (m *MockToggleRepository) UpdateWithRules(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return m.Update(toggle, revisions...)
+}
+
+func (m *MockToggleRepository) UpdatePrerequisites(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return m.Update(toggle, revisions...)
+}
+
+func (m *MockToggleRepository) Restore(toggle *entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	return m.Update(toggle, revisions...)
+}
+
+func (m *MockToggleRepository) RestoreApplication(appID string, toggles []*entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	for id, toggle := range m.Toggles {
+		if toggle.AppID == appID {
+			delete(m.Toggles, id)
+		}
+	}
+	for _, toggle := range toggles {
+		m.Toggles[toggle.ID] = toggle
+	}
+	return m.recordRevisions(revisions)
+}
+
+func (m *MockToggleRepository) ApplyBatch(created []*entity.Toggle, updated []*entity.Toggle, deleted []*entity.Toggle, revisions ...*entity.ToggleRevision) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	for _, toggle := range created {
+		m.Toggles[toggle.ID] = toggle
+	}
+	for _, toggle := range updated {
+		m.Toggles[toggle.ID] = toggle
+	}
+	deletedAt := time.Now()
+	for _, removed := range deleted {
+		if toggle, exists := m.Toggles[removed.ID]; exists {
+			toggle.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+			m.Trash[removed.ID] = toggle
+		}
+		delete(m.Toggles, removed.ID)
+	}
+	return m.recordRevisions(revisions)
+}
+
+func (m *MockToggleRepository) GetDependents(toggleIDs []string) ([]*entity.Toggle, error) {
+	toggles := make([]*entity.Toggle, 0)
+	for _, toggle := range m.Toggles {
+		for _, prerequisite := range toggle.Prerequisites {
+			if containsID(toggleIDs, prerequisite.PrerequisiteID) {
+				toggles = append(toggles, toggle)
+				break
+			}
+		}
+	}
+	sort.Slice(toggles, func(i, j int) bool { return toggles[i].Path < toggles[j].Path })
+	return toggles, nil
 }
 
-func (m *MockToggleRepository) Delete(id string) error {
+func (m *MockToggleRepository) Delete(id string, revisions ...*entity.ToggleRevision) error {
 	if m.DeleteError != nil {
 		return m.DeleteError
 	}
+	m.trash(id, time.Now())
+	return m.recordRevisions(revisions)
+}
+
+// trash move o toggle e seus descendentes para a lixeira com o mesmo instante de remoção
+func (m *MockToggleRepository) trash(id string, deletedAt time.Time) {
+	children, _ := m.GetChildren(id)
+	for _, child := range children {
+		m.trash(child.ID, deletedAt)
+	}
+	if toggle, exists := m.Toggles[id]; exists {
+		toggle.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+		m.Trash[id] = toggle
+	}
 	delete(m.Toggles, id)
-	return nil
 }
 
-func (m *MockToggleRepository) DeleteByPath(path string, appID string) error {
+func (m *MockToggleRepository) GetTrashByAppID(appID string) ([]*entity.Toggle, error) {
+	toggles := make([]*entity.Toggle, 0)
+	for _, toggle := range m.Trash {
+		if toggle.AppID == appID {
+			toggles = append(toggles, toggle)
+		}
+	}
+	sort.Slice(toggles, func(i, j int) bool { return toggles[i].Path < toggles[j].Path })
+	return toggles, nil
+}
+
+func (m *MockToggleRepository) Undelete(ids []string, revisions ...*entity.ToggleRevision) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	for _, id := range ids {
+		if toggle, exists := m.Trash[id]; exists {
+			toggle.DeletedAt = gorm.DeletedAt{}
+			m.Toggles[id] = toggle
+			delete(m.Trash, id)
+		}
+	}
+	return m.recordRevisions(revisions)
+}
+
+func (m *MockToggleRepository) Purge(before time.Time) (int64, error) {
+	var purged int64
+	for id, toggle := range m.Trash {
+		if toggle.DeletedAt.Time.Before(before) {
+			delete(m.Trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *MockToggleRepository) DeleteByPath(path string, appID string, revisions ...*entity.ToggleRevision) error {
 	for _, toggle := range m.Toggles {
 		if toggle.Path == path && toggle.AppID == appID {
-			return m.Delete(toggle.ID)
+			return m.Delete(toggle.ID, revisions...)
 		}
 	}
 	return errors.New("toggle not found")
@@ -196,6 +392,48 @@ func (m *MockToggleRepository) GetChildren(parentID string) ([]*entity.Toggle, e
 	return children, nil
 }
 
+func (m *MockToggleRepository) Search(query *entity.ToggleSearchQuery) ([]*entity.ToggleSearchResult, int64, error) {
+	allowed := make(map[string]bool, len(query.AppIDs))
+	for _, appID := range query.AppIDs {
+		allowed[appID] = true
+	}
+
+	results := []*entity.ToggleSearchResult{}
+	for _, toggle := range m.Toggles {
+		if !query.AllApplications && !allowed[toggle.AppID] {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(toggle.Path), query.Term) &&
+			!strings.Contains(strings.ToLower(toggle.Value), query.Term) &&
+			!strings.Contains(strings.ToLower(toggle.Description), query.Term) {
+			continue
+		}
+		results = append(results, &entity.ToggleSearchResult{
+			ToggleID:    toggle.ID,
+			AppID:       toggle.AppID,
+			Path:        toggle.Path,
+			Value:       toggle.Value,
+			Description: toggle.Description,
+			Enabled:     toggle.Enabled,
+		})
+	}
+
+	// A pontuação é calculada pelo banco; o mock apenas ordena pelo caminho
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+
+	total := int64(len(results))
+	if query.Offset >= len(results) {
+		return []*entity.ToggleSearchResult{}, total, nil
+	}
+	end := query.Offset + query.Limit
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[query.Offset:end], total, nil
+}
+
 // MockUserRepository represents a mock implementation of UserRepository
 type MockUserRepository struct {
 	Users       map[string]*entity.User
@@ -247,6 +485,12 @@ func (m *MockUserRepository) GetAll() ([]*entity.User, error) {
 	return users, nil
 }
 
+func (m *MockUserRepository) List(page *entity.PageRequest) (*entity.Page[*entity.User], error) {
+	users, _ := m.GetAll()
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return mockPage(users, page, func(u *entity.User) string { return u.ID }), nil
+}
+
 func (m *MockUserRepository) Update(user *entity.User) error {
 	if m.UpdateError != nil {
 		return m.UpdateError
@@ -359,7 +603,21 @@ func (m *MockTeamRepository) GetUsersByTeamID(teamID string) ([]*entity.User, er
 }
 
 func (m *MockTeamRepository) GetTeamsByUserID(userID string) ([]*entity.Team, error) {
-	return []*entity.Team{}, nil
+	teams := []*entity.Team{}
+	for _, team := range m.Teams {
+		if team.HasUser(userID) {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+func (m *MockTeamRepository) ListUsersByTeamID(teamID string, page *entity.PageRequest) (*entity.Page[*entity.User], error) {
+	users := []*entity.User{}
+	if team, exists := m.Teams[teamID]; exists {
+		users = append(users, team.Users...)
+	}
+	return mockPage(users, page, func(u *entity.User) string { return u.ID }), nil
 }
 
 func (m *MockTeamRepository) AddApplicationToTeam(teamID, applicationID string, permission entity.TeamPermissionLevel) error {
@@ -375,7 +633,11 @@ func (m *MockTeamRepository) UpdateApplicationPermission(teamID, applicationID s
 }
 
 func (m *MockTeamRepository) GetApplicationsByTeamID(teamID string) ([]*entity.Application, error) {
-	return []*entity.Application{}, nil
+	team, exists := m.Teams[teamID]
+	if !exists || team.Applications == nil {
+		return []*entity.Application{}, nil
+	}
+	return team.Applications, nil
 }
 
 func (m *MockTeamRepository) GetTeamsByApplicationID(applicationID string) ([]*entity.Team, error) {
@@ -397,3 +659,420 @@ func (m *MockTeamRepository) GetTeamsWithCounts() ([]*entity.TeamWithCounts, err
 func (m *MockTeamRepository) GetTeamWithCounts(id string) (*entity.TeamWithCounts, error) {
 	return &entity.TeamWithCounts{}, nil
 }
+
+func (m *MockTeamRepository) ListTeamsWithCounts(page *entity.PageRequest) (*entity.Page[*entity.TeamWithCounts], error) {
+	teams := []*entity.TeamWithCounts{}
+	for _, team := range m.Teams {
+		teams = append(teams, &entity.TeamWithCounts{
+			ID:               team.ID,
+			Name:             team.Name,
+			Description:      team.Description,
+			CreatedAt:        team.CreatedAt,
+			UpdatedAt:        team.UpdatedAt,
+			UserCount:        len(team.Users),
+			ApplicationCount: len(team.Applications),
+		})
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+	return mockPage(teams, page, func(t *entity.TeamWithCounts) string { return t.ID }), nil
+}
+
+// MockToggleMetricRepository represents a mock implementation of ToggleMetricRepository
+type MockToggleMetricRepository struct {
+	Metrics        []*entity.ToggleMetric
+	IncrementError error
+}
+
+func NewMockToggleMetricRepository() *MockToggleMetricRepository {
+	return &MockToggleMetricRepository{}
+}
+
+func (m *MockToggleMetricRepository) Increment(metrics []*entity.ToggleMetric) error {
+	if m.IncrementError != nil {
+		return m.IncrementError
+	}
+	for _, metric := range metrics {
+		merged := false
+		for _, existing := range m.Metrics {
+			if existing.ToggleID == metric.ToggleID && existing.BucketStart.Equal(metric.BucketStart) {
+				existing.TrueCount += metric.TrueCount
+				existing.FalseCount += metric.FalseCount
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			m.Metrics = append(m.Metrics, metric)
+		}
+	}
+	return nil
+}
+
+func (m *MockToggleMetricRepository) GetByToggleID(toggleID string, from, to time.Time) ([]*entity.ToggleMetric, error) {
+	var metrics []*entity.ToggleMetric
+	for _, metric := range m.Metrics {
+		if metric.ToggleID == toggleID && !metric.BucketStart.Before(from) && metric.BucketStart.Before(to) {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics, nil
+}
+
+func (m *MockToggleMetricRepository) GetEvaluatedToggleIDs(appID string, since time.Time) ([]string, error) {
+	seen := make(map[string]bool)
+	var toggleIDs []string
+	for _, metric := range m.Metrics {
+		if metric.AppID == appID && !metric.BucketStart.Before(since) && !seen[metric.ToggleID] {
+			seen[metric.ToggleID] = true
+			toggleIDs = append(toggleIDs, metric.ToggleID)
+		}
+	}
+	return toggleIDs, nil
+}
+
+// MockSegmentRepository represents a mock implementation of SegmentRepository
+type MockSegmentRepository struct {
+	Segments    map[string]*entity.Segment
+	ToggleRepo  *MockToggleRepository // Toggles consultados para encontrar referências aos segmentos
+	CreateError error
+	UpdateError error
+	DeleteError error
+}
+
+func NewMockSegmentRepository() *MockSegmentRepository {
+	return &MockSegmentRepository{
+		Segments: make(map[string]*entity.Segment),
+	}
+}
+
+func (m *MockSegmentRepository) Create(segment *entity.Segment) error {
+	if m.CreateError != nil {
+		return m.CreateError
+	}
+	m.Segments[segment.ID] = segment
+	return nil
+}
+
+func (m *MockSegmentRepository) GetByID(id string) (*entity.Segment, error) {
+	segment, exists := m.Segments[id]
+	if !exists {
+		return nil, errors.New("segment not found")
+	}
+	return segment, nil
+}
+
+func (m *MockSegmentRepository) GetByIDs(ids []string) ([]*entity.Segment, error) {
+	segments := make([]*entity.Segment, 0)
+	for _, id := range ids {
+		if segment, exists := m.Segments[id]; exists {
+			segments = append(segments, segment)
+		}
+	}
+	return segments, nil
+}
+
+func (m *MockSegmentRepository) GetByName(appID *string, name string) (*entity.Segment, error) {
+	for _, segment := range m.Segments {
+		if segment.Name == name && segment.IsGlobal() == (appID == nil) && (appID == nil || *segment.AppID == *appID) {
+			return segment, nil
+		}
+	}
+	return nil, errors.New("segment not found")
+}
+
+func (m *MockSegmentRepository) GetByAppID(appID string) ([]*entity.Segment, error) {
+	return m.filter(func(s *entity.Segment) bool { return !s.IsGlobal() && *s.AppID == appID }), nil
+}
+
+func (m *MockSegmentRepository) GetGlobal() ([]*entity.Segment, error) {
+	return m.filter(func(s *entity.Segment) bool { return s.IsGlobal() }), nil
+}
+
+func (m *MockSegmentRepository) GetAvailableForApp(appID string) ([]*entity.Segment, error) {
+	return m.filter(func(s *entity.Segment) bool { return s.AvailableTo(appID) }), nil
+}
+
+func (m *MockSegmentRepository) Update(segment *entity.Segment) error {
+	if m.UpdateError != nil {
+		return m.UpdateError
+	}
+	m.Segments[segment.ID] = segment
+	return nil
+}
+
+func (m *MockSegmentRepository) Delete(id string) error {
+	if m.DeleteError != nil {
+		return m.DeleteError
+	}
+	delete(m.Segments, id)
+	return nil
+}
+
+func (m *MockSegmentRepository) GetReferencingToggles(id string) ([]*entity.Toggle, error) {
+	toggles := make([]*entity.Toggle, 0)
+	if m.ToggleRepo == nil {
+		return toggles, nil
+	}
+	for _, toggle := range m.ToggleRepo.Toggles {
+		if containsID(toggle.SegmentIDs(), id) {
+			toggles = append(toggles, toggle)
+		}
+	}
+	sort.Slice(toggles, func(i, j int) bool { return toggles[i].Path < toggles[j].Path })
+	return toggles, nil
+}
+
+// filter retorna os segmentos que satisfazem o predicado, ordenados pelo nome
+func (m *MockSegmentRepository) filter(keep func(*entity.Segment) bool) []*entity.Segment {
+	segments := make([]*entity.Segment, 0)
+	for _, segment := range m.Segments {
+		if keep(segment) {
+			segments = append(segments, segment)
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].Name < segments[j].Name })
+	return segments
+}
+
+// MockToggleRevisionRepository represents a mock implementation of ToggleRevisionRepository
+type MockToggleRevisionRepository struct {
+	Revisions   []*entity.ToggleRevision
+	CreateError error
+}
+
+func NewMockToggleRevisionRepository() *MockToggleRevisionRepository {
+	return &MockToggleRevisionRepository{
+		Revisions: make([]*entity.ToggleRevision, 0),
+	}
+}
+
+func (m *MockToggleRevisionRepository) Create(revisions ...*entity.ToggleRevision) error {
+	if m.CreateError != nil {
+		return m.CreateError
+	}
+	for _, revision := range revisions {
+		revision.Revision = len(m.filter(revision.ToggleID)) + 1
+		revision.CreatedAt = time.Now()
+		m.Revisions = append(m.Revisions, revision)
+	}
+	return nil
+}
+
+func (m *MockToggleRevisionRepository) GetByToggleID(toggleID string) ([]*entity.ToggleRevision, error) {
+	revisions := m.filter(toggleID)
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision > revisions[j].Revision })
+	return revisions, nil
+}
+
+func (m *MockToggleRevisionRepository) GetRevision(toggleID string, revision int) (*entity.ToggleRevision, error) {
+	for _, found := range m.filter(toggleID) {
+		if found.Revision == revision {
+			return found, nil
+		}
+	}
+	return nil, errors.New("revision not found")
+}
+
+// filter retorna as revisões do toggle
+func (m *MockToggleRevisionRepository) filter(toggleID string) []*entity.ToggleRevision {
+	revisions := make([]*entity.ToggleRevision, 0)
+	for _, revision := range m.Revisions {
+		if revision.ToggleID == toggleID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// MockApplicationSnapshotRepository represents a mock implementation of ApplicationSnapshotRepository
+type MockApplicationSnapshotRepository struct {
+	Snapshots   map[string]*entity.ApplicationSnapshot
+	CreateError error
+}
+
+func NewMockApplicationSnapshotRepository() *MockApplicationSnapshotRepository {
+	return &MockApplicationSnapshotRepository{
+		Snapshots: make(map[string]*entity.ApplicationSnapshot),
+	}
+}
+
+func (m *MockApplicationSnapshotRepository) Create(snapshot *entity.ApplicationSnapshot) error {
+	if m.CreateError != nil {
+		return m.CreateError
+	}
+	if snapshot.ID == "" {
+		snapshot.ID = "snapshot-" + strconv.Itoa(len(m.Snapshots)+1)
+	}
+	snapshot.CreatedAt = time.Now()
+	m.Snapshots[snapshot.ID] = snapshot
+	return nil
+}
+
+func (m *MockApplicationSnapshotRepository) GetByID(id string) (*entity.ApplicationSnapshot, error) {
+	snapshot, exists := m.Snapshots[id]
+	if !exists {
+		return nil, errors.New("snapshot not found")
+	}
+	return snapshot, nil
+}
+
+func (m *MockApplicationSnapshotRepository) GetByAppID(appID string) ([]*entity.ApplicationSnapshot, error) {
+	snapshots := make([]*entity.ApplicationSnapshot, 0)
+	for _, snapshot := range m.Snapshots {
+		if snapshot.AppID == appID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].ID > snapshots[j].ID })
+	return snapshots, nil
+}
+
+// mockPage pagina uma lista em memória usando o ID do último item como cursor
+func mockPage[T any](items []T, page *entity.PageRequest, id func(T) string) *entity.Page[T] {
+	start := 0
+	if page.Cursor != "" {
+		if cursor, err := entity.DecodeCursor(page.Cursor); err == nil {
+			for i, item := range items {
+				if id(item) == cursor.ID {
+					start = i + 1
+					break
+				}
+			}
+		}
+	}
+
+	end := start + page.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	result := &entity.Page[T]{Items: append([]T{}, items[start:end]...), Total: int64(len(items))}
+	if end < len(items) {
+		result.NextCursor = entity.EncodeCursor("", id(items[end-1]))
+	}
+	return result
+}
+
+// MockCountryResolver resolve países a partir de um mapa de IPs
+type MockCountryResolver struct {
+	Countries map[string]string
+}
+
+func NewMockCountryResolver() *MockCountryResolver {
+	return &MockCountryResolver{
+		Countries: make(map[string]string),
+	}
+}
+
+func (m *MockCountryResolver) Country(addr netip.Addr) (string, bool) {
+	country, ok := m.Countries[addr.String()]
+	return country, ok
+}
+
+// containsID verifica se o ID está presente na lista
+func containsID(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+// MockAuditEventRepository represents a mock implementation of AuditEventRepository
+type MockAuditEventRepository struct {
+	Events      []*entity.AuditEvent
+	CreateError error
+}
+
+func NewMockAuditEventRepository() *MockAuditEventRepository {
+	return &MockAuditEventRepository{
+		Events: make([]*entity.AuditEvent, 0),
+	}
+}
+
+func (m *MockAuditEventRepository) Create(event *entity.AuditEvent) error {
+	if m.CreateError != nil {
+		return m.CreateError
+	}
+	event.CreatedAt = time.Now()
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+func (m *MockAuditEventRepository) GetByAppID(appID string) ([]*entity.AuditEvent, error) {
+	events := make([]*entity.AuditEvent, 0)
+	for i := len(m.Events) - 1; i >= 0; i-- {
+		if m.Events[i].AppID == appID {
+			events = append(events, m.Events[i])
+		}
+	}
+	return events, nil
+}
+
+// MockFreezeWindowRepository represents a mock implementation of FreezeWindowRepository
+type MockFreezeWindowRepository struct {
+	Windows     map[string]*entity.FreezeWindow
+	CreateError error
+}
+
+func NewMockFreezeWindowRepository() *MockFreezeWindowRepository {
+	return &MockFreezeWindowRepository{
+		Windows: make(map[string]*entity.FreezeWindow),
+	}
+}
+
+func (m *MockFreezeWindowRepository) Create(window *entity.FreezeWindow) error {
+	if m.CreateError != nil {
+		return m.CreateError
+	}
+	if window.ID == "" {
+		window.ID = "window-" + strconv.Itoa(len(m.Windows)+1)
+	}
+	window.CreatedAt = time.Now()
+	m.Windows[window.ID] = window
+	return nil
+}
+
+func (m *MockFreezeWindowRepository) GetByID(id string) (*entity.FreezeWindow, error) {
+	window, exists := m.Windows[id]
+	if !exists {
+		return nil, errors.New("freeze window not found")
+	}
+	return window, nil
+}
+
+func (m *MockFreezeWindowRepository) GetByAppID(appID string) ([]*entity.FreezeWindow, error) {
+	return m.filter(func(window *entity.FreezeWindow) bool {
+		return window.AppID != nil && *window.AppID == appID
+	}), nil
+}
+
+func (m *MockFreezeWindowRepository) GetGlobal() ([]*entity.FreezeWindow, error) {
+	return m.filter(func(window *entity.FreezeWindow) bool {
+		return window.AppID == nil
+	}), nil
+}
+
+func (m *MockFreezeWindowRepository) GetActive(appID string, at time.Time) ([]*entity.FreezeWindow, error) {
+	return m.filter(func(window *entity.FreezeWindow) bool {
+		return (window.AppID == nil || *window.AppID == appID) && window.IsActive(at)
+	}), nil
+}
+
+func (m *MockFreezeWindowRepository) Delete(id string) error {
+	delete(m.Windows, id)
+	return nil
+}
+
+// filter retorna as janelas que satisfazem keep, das que começam mais tarde
+func (m *MockFreezeWindowRepository) filter(keep func(*entity.FreezeWindow) bool) []*entity.FreezeWindow {
+	windows := make([]*entity.FreezeWindow, 0)
+	for _, window := range m.Windows {
+		if keep(window) {
+			windows = append(windows, window)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].StartsAt.After(windows[j].StartsAt) })
+	return windows
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// ReportUseCase define os casos de uso para relatórios sobre os toggles de uma aplicação
+type ReportUseCase struct {
+	toggleRepo repository.ToggleRepository
+	appRepo    repository.ApplicationRepository
+	metricRepo repository.ToggleMetricRepository
+}
+
+// NewReportUseCase cria uma nova instância de ReportUseCase
+func NewReportUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository, metricRepo repository.ToggleMetricRepository) *ReportUseCase {
+	return &ReportUseCase{
+		toggleRepo: toggleRepo,
+		appRepo:    appRepo,
+		metricRepo: metricRepo,
+	}
+}
+
+// GetStaleReport lista os toggles folha da aplicação que são candidatos à remoção:
+// totalmente ligados ou desligados sem alterações há staleDays dias, sem avaliações
+// no período ou com regras que, pelas porcentagens em 100% ou 0%, valem para todos ou para ninguém
+func (uc *ReportUseCase) GetStaleReport(appID string, staleDays int) (*entity.StaleReport, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+
+	if staleDays <= 0 {
+		staleDays = entity.DefaultStaleDays
+	}
+
+	app, err := uc.appRepo.GetByID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	toggles, err := uc.toggleRepo.GetHierarchyByAppID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggle hierarchy")
+	}
+
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, -staleDays)
+
+	evaluatedIDs, err := uc.metricRepo.GetEvaluatedToggleIDs(appID, since)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggle metrics")
+	}
+	evaluated := make(map[string]bool, len(evaluatedIDs))
+	for _, id := range evaluatedIDs {
+		evaluated[id] = true
+	}
+
+	byID := make(map[string]*entity.Toggle, len(toggles))
+	hasChildren := make(map[string]bool)
+	for _, toggle := range toggles {
+		byID[toggle.ID] = toggle
+		if toggle.ParentID != nil {
+			hasChildren[*toggle.ParentID] = true
+		}
+	}
+
+	report := &entity.StaleReport{
+		AppID:       app.ID,
+		AppName:     app.Name,
+		StaleDays:   staleDays,
+		GeneratedAt: now,
+		Toggles:     []*entity.StaleToggle{},
+	}
+
+	for _, toggle := range toggles {
+		// Toggles intermediários apenas agrupam os filhos
+		if hasChildren[toggle.ID] {
+			continue
+		}
+
+		enabled, lastChanged := effectiveState(toggle, byID)
+		unchanged := lastChanged.Before(since)
+
+		var reasons []entity.StaleReason
+		switch {
+		case !enabled && unchanged:
+			reasons = append(reasons, entity.StaleReasonFullyOff)
+		case enabled && !toggle.HasActivationRule && unchanged:
+			reasons = append(reasons, entity.StaleReasonFullyOn)
+		case enabled && toggle.HasActivationRule:
+			if percentage, ok := toggle.PercentageRollout(); ok {
+				if percentage >= 100 {
+					reasons = append(reasons, entity.StaleReasonFullRollout)
+				} else if percentage <= 0 {
+					reasons = append(reasons, entity.StaleReasonNoRollout)
+				}
+			}
+		}
+
+		// Toggles recém-criados ainda não tiveram tempo de serem avaliados
+		if !evaluated[toggle.ID] && toggle.CreatedAt.Before(since) {
+			reasons = append(reasons, entity.StaleReasonNotEvaluated)
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		staleToggle := &entity.StaleToggle{
+			ToggleID:  toggle.ID,
+			Path:      toggle.Path,
+			Enabled:   enabled,
+			HasRule:   toggle.HasActivationRule,
+			UpdatedAt: lastChanged,
+			Reasons:   reasons,
+		}
+		staleToggle.SuggestedAction = staleToggle.SuggestAction()
+		report.Toggles = append(report.Toggles, staleToggle)
+	}
+
+	return report, nil
+}
+
+// effectiveState retorna o estado efetivo do toggle considerando os ancestrais
+// e a data da última alteração que afeta esse estado
+func effectiveState(toggle *entity.Toggle, byID map[string]*entity.Toggle) (bool, time.Time) {
+	enabled := true
+	lastChanged := toggle.UpdatedAt
+
+	for current := toggle; current != nil; {
+		if !current.Enabled {
+			enabled = false
+		}
+		if current.UpdatedAt.After(lastChanged) {
+			lastChanged = current.UpdatedAt
+		}
+		if current.ParentID == nil {
+			break
+		}
+		current = byID[*current.ParentID]
+	}
+
+	return enabled, lastChanged
+}
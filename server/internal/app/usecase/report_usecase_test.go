@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestReportUseCase_GetStaleReport(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	metricMock := NewMockToggleMetricRepository()
+
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+
+	old := time.Now().UTC().AddDate(0, 0, -90)
+	recent := time.Now().UTC().AddDate(0, 0, -1)
+	rootID := "root"
+	disabledID := "disabled-parent"
+
+	toggles := []*entity.Toggle{
+		{ID: rootID, Value: "checkout", Path: "checkout", Enabled: true, AppID: "app123", CreatedAt: old, UpdatedAt: old},
+		{ID: "fully-on", Value: "new-flow", Path: "checkout.new-flow", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: old},
+		{ID: "recently-changed", Value: "apple-pay", Path: "checkout.apple-pay", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: recent},
+		{ID: "full-rollout", Value: "banner", Path: "checkout.banner", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: recent,
+			HasActivationRule: true, ActivationRule: &entity.ActivationRule{Type: entity.ActivationRuleTypePercentage, Value: "100"}},
+		{ID: "partial-rollout", Value: "search", Path: "checkout.search", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: old,
+			HasActivationRule: true, ActivationRule: &entity.ActivationRule{Type: entity.ActivationRuleTypePercentage, Value: "50"}},
+		{ID: "composite-full", Value: "wallet", Path: "checkout.wallet", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: recent,
+			HasActivationRule: true, Rules: []*entity.ToggleRule{
+				{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}}},
+				{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypePercentage, Value: "100"}}},
+			}},
+		{ID: "composite-off", Value: "coupon", Path: "checkout.coupon", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: recent,
+			HasActivationRule: true, Rules: []*entity.ToggleRule{
+				{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}, {Type: entity.ActivationRuleTypePercentage, Value: "0"}}},
+				{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypePercentage, Value: "0"}}},
+			}},
+		{ID: "composite-partial", Value: "gift", Path: "checkout.gift", Enabled: true, ParentID: &rootID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: recent,
+			HasActivationRule: true, Rules: []*entity.ToggleRule{
+				{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}, {Type: entity.ActivationRuleTypePercentage, Value: "100"}}},
+				{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypePercentage, Value: "0"}}},
+			}},
+		{ID: disabledID, Value: "legacy", Path: "legacy", Enabled: false, AppID: "app123", CreatedAt: old, UpdatedAt: old},
+		{ID: "fully-off", Value: "report", Path: "legacy.report", Enabled: true, ParentID: &disabledID, Level: 1, AppID: "app123", CreatedAt: old, UpdatedAt: old},
+		{ID: "brand-new", Value: "fresh", Path: "fresh", Enabled: true, AppID: "app123", CreatedAt: recent, UpdatedAt: recent},
+	}
+	for _, toggle := range toggles {
+		toggleMock.Toggles[toggle.ID] = toggle
+	}
+
+	// Toggles avaliados recentemente não são sinalizados como not_evaluated
+	bucket := entity.MetricsBucketStart(recent)
+	for _, id := range []string{"fully-on", "recently-changed", "full-rollout", "partial-rollout", "composite-full", "composite-off", "composite-partial", "fully-off"} {
+		metric := entity.NewToggleMetric("app123", id, bucket)
+		metric.Add(true, 1)
+		metricMock.Metrics = append(metricMock.Metrics, metric)
+	}
+
+	useCase := NewReportUseCase(toggleMock, appMock, metricMock)
+	report, err := useCase.GetStaleReport("app123", 30)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]struct {
+		reason entity.StaleReason
+		action string
+	}{
+		"checkout.new-flow": {entity.StaleReasonFullyOn, entity.StaleActionRemoveKeepCode},
+		"checkout.banner":   {entity.StaleReasonFullRollout, entity.StaleActionRemoveKeepCode},
+		"checkout.wallet":   {entity.StaleReasonFullRollout, entity.StaleActionRemoveKeepCode},
+		"checkout.coupon":   {entity.StaleReasonNoRollout, entity.StaleActionRemoveDeadCode},
+		"legacy.report":     {entity.StaleReasonFullyOff, entity.StaleActionRemoveDeadCode},
+	}
+
+	if len(report.Toggles) != len(expected) {
+		t.Fatalf("Expected %d stale toggles, got %d", len(expected), len(report.Toggles))
+	}
+
+	for _, stale := range report.Toggles {
+		want, ok := expected[stale.Path]
+		if !ok {
+			t.Errorf("Unexpected stale toggle %s", stale.Path)
+			continue
+		}
+		if len(stale.Reasons) != 1 || stale.Reasons[0] != want.reason {
+			t.Errorf("%s: expected reasons [%s], got %v", stale.Path, want.reason, stale.Reasons)
+		}
+		if stale.SuggestedAction != want.action {
+			t.Errorf("%s: expected action %q, got %q", stale.Path, want.action, stale.SuggestedAction)
+		}
+	}
+}
+
+func TestReportUseCase_GetStaleReport_NotEvaluated(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	metricMock := NewMockToggleMetricRepository()
+
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+
+	old := time.Now().UTC().AddDate(0, 0, -90)
+	recent := time.Now().UTC().AddDate(0, 0, -1)
+	toggleMock.Toggles["t1"] = &entity.Toggle{ID: "t1", Value: "search", Path: "search", Enabled: true, AppID: "app123", CreatedAt: old, UpdatedAt: recent,
+		HasActivationRule: true, ActivationRule: &entity.ActivationRule{Type: entity.ActivationRuleTypePercentage, Value: "0"}}
+
+	useCase := NewReportUseCase(toggleMock, appMock, metricMock)
+	report, err := useCase.GetStaleReport("app123", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.StaleDays != entity.DefaultStaleDays {
+		t.Errorf("Expected default stale days %d, got %d", entity.DefaultStaleDays, report.StaleDays)
+	}
+	if len(report.Toggles) != 1 {
+		t.Fatalf("Expected 1 stale toggle, got %d", len(report.Toggles))
+	}
+
+	reasons := report.Toggles[0].Reasons
+	if len(reasons) != 2 || reasons[0] != entity.StaleReasonNoRollout || reasons[1] != entity.StaleReasonNotEvaluated {
+		t.Errorf("Expected [no_rollout not_evaluated], got %v", reasons)
+	}
+	if report.Toggles[0].SuggestedAction != entity.StaleActionRemoveDeadCode {
+		t.Errorf("Expected action %q, got %q", entity.StaleActionRemoveDeadCode, report.Toggles[0].SuggestedAction)
+	}
+}
+
+func TestReportUseCase_GetStaleReport_ApplicationNotFound(t *testing.T) {
+	useCase := NewReportUseCase(NewMockToggleRepository(), NewMockApplicationRepository(), NewMockToggleMetricRepository())
+
+	_, err := useCase.GetStaleReport("missing", 30)
+	appErr, ok := err.(*entity.AppError)
+	if !ok || appErr.Code != entity.ErrCodeNotFound {
+		t.Errorf("Expected not found AppError, got %v", err)
+	}
+}
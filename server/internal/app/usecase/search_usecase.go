@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// SearchUseCase define os casos de uso para busca de toggles entre aplicações
+type SearchUseCase struct {
+	toggleRepo repository.ToggleRepository
+	teamRepo   repository.TeamRepository
+}
+
+// NewSearchUseCase cria uma nova instância de SearchUseCase
+func NewSearchUseCase(toggleRepo repository.ToggleRepository, teamRepo repository.TeamRepository) *SearchUseCase {
+	return &SearchUseCase{
+		toggleRepo: toggleRepo,
+		teamRepo:   teamRepo,
+	}
+}
+
+// SearchToggles busca toggles em todas as aplicações que o usuário pode acessar.
+// Usuários root enxergam todas as aplicações; os demais apenas as aplicações dos seus times.
+func (uc *SearchUseCase) SearchToggles(user *entity.User, term string, limit, offset int) (*entity.ToggleSearchResponse, error) {
+	if user == nil {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "user is required")
+	}
+
+	term = entity.NormalizeSearchTerm(term)
+	if limit == 0 {
+		limit = entity.DefaultSearchLimit
+	}
+
+	validation := entity.ValidateSearchQuery(term, limit, offset)
+	if !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	query := &entity.ToggleSearchQuery{
+		Term:            term,
+		AllApplications: user.IsRoot(),
+		Limit:           limit,
+		Offset:          offset,
+	}
+
+	if !query.AllApplications {
+		appIDs, err := uc.accessibleApplicationIDs(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		query.AppIDs = appIDs
+	}
+
+	results, total, err := uc.toggleRepo.Search(query)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error searching toggles")
+	}
+
+	return &entity.ToggleSearchResponse{
+		Query:  term,
+		Items:  results,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// accessibleApplicationIDs retorna os IDs das aplicações associadas aos times do usuário
+func (uc *SearchUseCase) accessibleApplicationIDs(userID string) ([]string, error) {
+	teams, err := uc.teamRepo.GetTeamsByUserID(userID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching user teams")
+	}
+
+	seen := make(map[string]bool)
+	appIDs := []string{}
+	for _, team := range teams {
+		apps, err := uc.teamRepo.GetApplicationsByTeamID(team.ID)
+		if err != nil {
+			return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching team applications")
+		}
+		for _, app := range apps {
+			if !seen[app.ID] {
+				seen[app.ID] = true
+				appIDs = append(appIDs, app.ID)
+			}
+		}
+	}
+	return appIDs, nil
+}
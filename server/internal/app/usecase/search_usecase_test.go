@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func setupSearchUseCase() *SearchUseCase {
+	toggleMock := NewMockToggleRepository()
+	teamMock := NewMockTeamRepository()
+
+	toggleMock.Toggles["t1"] = &entity.Toggle{ID: "t1", AppID: "shop", Path: "new-checkout", Value: "new-checkout"}
+	toggleMock.Toggles["t2"] = &entity.Toggle{ID: "t2", AppID: "shop", Path: "payments.new-checkout", Value: "new-checkout"}
+	toggleMock.Toggles["t3"] = &entity.Toggle{ID: "t3", AppID: "admin", Path: "flow", Value: "flow", Description: "new-checkout rollout"}
+
+	teamMock.Teams["team1"] = &entity.Team{
+		ID:           "team1",
+		Name:         "shop-team",
+		Users:        []*entity.User{{ID: "member"}},
+		Applications: []*entity.Application{{ID: "shop"}},
+	}
+
+	return NewSearchUseCase(toggleMock, teamMock)
+}
+
+func TestSearchUseCase_SearchToggles_Permissions(t *testing.T) {
+	useCase := setupSearchUseCase()
+
+	tests := []struct {
+		name          string
+		user          *entity.User
+		expectedPaths []string
+	}{
+		{"root sees all applications", &entity.User{ID: "root", Role: entity.UserRoleRoot}, []string{"flow", "new-checkout", "payments.new-checkout"}},
+		{"member sees team applications", &entity.User{ID: "member", Role: entity.UserRoleUser}, []string{"new-checkout", "payments.new-checkout"}},
+		{"user without teams sees nothing", &entity.User{ID: "outsider", Role: entity.UserRoleAdmin}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := useCase.SearchToggles(tt.user, "  New-Checkout ", 0, 0)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if response.Query != "new-checkout" || response.Limit != entity.DefaultSearchLimit {
+				t.Errorf("Expected normalized query and default limit, got %q/%d", response.Query, response.Limit)
+			}
+			if response.Total != int64(len(tt.expectedPaths)) || len(response.Items) != len(tt.expectedPaths) {
+				t.Fatalf("Expected %d results, got %d (total %d)", len(tt.expectedPaths), len(response.Items), response.Total)
+			}
+			for i, path := range tt.expectedPaths {
+				if response.Items[i].Path != path {
+					t.Errorf("Expected %s at position %d, got %s", path, i, response.Items[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchUseCase_SearchToggles_Validation(t *testing.T) {
+	useCase := setupSearchUseCase()
+	root := &entity.User{ID: "root", Role: entity.UserRoleRoot}
+
+	tests := []struct {
+		name   string
+		term   string
+		limit  int
+		offset int
+		field  string
+	}{
+		{"term too short", "n", 10, 0, "q"},
+		{"limit too large", "new", 500, 0, "limit"},
+		{"negative offset", "new", 10, -1, "offset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := useCase.SearchToggles(root, tt.term, tt.limit, tt.offset)
+			appErr, ok := err.(*entity.AppError)
+			if !ok || appErr.Code != entity.ErrCodeValidation {
+				t.Fatalf("Expected validation error, got %v", err)
+			}
+			if appErr.Details[0].Field != tt.field {
+				t.Errorf("Expected error on field %s, got %s", tt.field, appErr.Details[0].Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/manorfm/totoogle/internal/app/config"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// DefaultUsageFlushInterval é o intervalo padrão de gravação em lote do uso das secret keys
+const DefaultUsageFlushInterval = 30 * time.Second
+
+// SecretKeyUsageTracker acumula o uso das secret keys em memória e grava em lote,
+// mantendo o caminho do SDK livre de escritas no banco
+type SecretKeyUsageTracker struct {
+	secretKeyRepo repository.SecretKeyRepository
+	mu            sync.Mutex
+	pending       map[string]*entity.SecretKeyUsage
+}
+
+// NewSecretKeyUsageTracker cria uma nova instância de SecretKeyUsageTracker
+func NewSecretKeyUsageTracker(secretKeyRepo repository.SecretKeyRepository) *SecretKeyUsageTracker {
+	return &SecretKeyUsageTracker{
+		secretKeyRepo: secretKeyRepo,
+		pending:       make(map[string]*entity.SecretKeyUsage),
+	}
+}
+
+// Track registra um uso da secret key sem acessar o banco
+func (t *SecretKeyUsageTracker) Track(secretKeyID, clientIP, userAgent string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage, exists := t.pending[secretKeyID]
+	if !exists {
+		usage = &entity.SecretKeyUsage{SecretKeyID: secretKeyID}
+		t.pending[secretKeyID] = usage
+	}
+
+	usage.Count++
+	usage.LastUsedAt = time.Now().UTC()
+	usage.LastIP = truncate(clientIP, 45)
+	usage.LastAgent = truncate(userAgent, 255)
+}
+
+// Flush grava todo o uso acumulado. Usos que falharem voltam para a fila.
+func (t *SecretKeyUsageTracker) Flush() error {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = make(map[string]*entity.SecretKeyUsage)
+	t.mu.Unlock()
+
+	var firstErr error
+	for _, usage := range batch {
+		if err := t.secretKeyRepo.RecordUsage(usage); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			t.requeue(usage)
+		}
+	}
+
+	return firstErr
+}
+
+// Start inicia a gravação periódica em background e retorna a função para pará-la
+func (t *SecretKeyUsageTracker) Start(interval time.Duration) func() {
+	logger := config.GetLogger("secret-key-usage")
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := t.Flush(); err != nil {
+					logger.Warnf("flushing secret key usage: %v", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			if err := t.Flush(); err != nil {
+				logger.Warnf("flushing secret key usage: %v", err)
+			}
+		})
+	}
+}
+
+// requeue devolve um uso não gravado para a fila, somando com usos mais recentes
+func (t *SecretKeyUsageTracker) requeue(usage *entity.SecretKeyUsage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current, exists := t.pending[usage.SecretKeyID]
+	if !exists {
+		t.pending[usage.SecretKeyID] = usage
+		return
+	}
+
+	current.Count += usage.Count
+}
+
+// truncate limita o tamanho em bytes de uma string para caber na coluna do banco,
+// sem cortar um caractere UTF-8 ao meio
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		value    string
+		max      int
+		expected string
+	}{
+		{"curl/8.0", 255, "curl/8.0"},
+		{"curl/8.0", 4, "curl"},
+		{"agente-ção", 9, "agente-ç"}, // O "ç" ocupa dois bytes e não é cortado
+		{"agente-ção", 8, "agente-"},
+		{"日本", 2, ""},
+	}
+
+	for _, tt := range tests {
+		got := truncate(tt.value, tt.max)
+		if got != tt.expected || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d): expected %q, got %q", tt.value, tt.max, tt.expected, got)
+		}
+	}
+
+	if got := truncate(strings.Repeat("é", 200), 255); len(got) != 254 || !utf8.ValidString(got) {
+		t.Errorf("Expected a valid user agent within the column size, got %d bytes", len(got))
+	}
+}
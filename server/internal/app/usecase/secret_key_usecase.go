@@ -3,21 +3,33 @@ package usecase
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"time"
 
+	"github.com/manorfm/totoogle/internal/app/config"
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/domain/repository"
 )
 
+// DefaultUnusedKeyDays é o número padrão de dias sem uso para uma chave ser sinalizada
+const DefaultUnusedKeyDays = 30
+
 type SecretKeyUseCase struct {
 	secretKeyRepo repository.SecretKeyRepository
+	usageTracker  *SecretKeyUsageTracker
 }
 
 func NewSecretKeyUseCase(secretKeyRepo repository.SecretKeyRepository) *SecretKeyUseCase {
 	return &SecretKeyUseCase{
 		secretKeyRepo: secretKeyRepo,
+		usageTracker:  NewSecretKeyUsageTracker(secretKeyRepo),
 	}
 }
 
+// StartUsageTracking inicia a gravação periódica da telemetria de uso das chaves
+func (uc *SecretKeyUseCase) StartUsageTracking(interval time.Duration) func() {
+	return uc.usageTracker.Start(interval)
+}
+
 // CreateSecretKeyResponse representa a resposta da criação de uma secret key
 type CreateSecretKeyResponse struct {
 	SecretKey    *entity.SecretKey `json:"secret_key"`
@@ -59,6 +71,32 @@ func (uc *SecretKeyUseCase) GetSecretKeysByApplicationID(applicationID string) (
 	return uc.secretKeyRepo.GetByApplicationID(applicationID)
 }
 
+// GetSecretKeysWithUsage retorna as secret keys de uma aplicação com a telemetria de uso,
+// sinalizando as chaves sem uso há mais de unusedDays dias
+func (uc *SecretKeyUseCase) GetSecretKeysWithUsage(applicationID string, unusedDays int) ([]*entity.SecretKey, error) {
+	if unusedDays <= 0 {
+		unusedDays = DefaultUnusedKeyDays
+	}
+
+	// Grava o uso pendente para que a listagem reflita os acessos mais recentes; usos que não
+	// puderam ser gravados continuam na fila e a listagem segue com o que já está no banco
+	if err := uc.usageTracker.Flush(); err != nil {
+		config.GetLogger("secret-key-usage").Warnf("flushing secret key usage: %v", err)
+	}
+
+	secretKeys, err := uc.secretKeyRepo.GetByApplicationID(applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -unusedDays)
+	for _, key := range secretKeys {
+		key.Unused = key.IsUnusedSince(since)
+	}
+
+	return secretKeys, nil
+}
+
 // GetAllSecretKeys retorna todas as secret keys
 func (uc *SecretKeyUseCase) GetAllSecretKeys() ([]*entity.SecretKey, error) {
 	return uc.secretKeyRepo.GetAll()
@@ -74,14 +112,22 @@ func (uc *SecretKeyUseCase) DeleteSecretKey(id string) error {
 	return uc.secretKeyRepo.Delete(id)
 }
 
-// ValidateSecretKey valida uma secret key fornecida
-func (uc *SecretKeyUseCase) ValidateSecretKey(secretKey string) (*entity.SecretKey, error) {
+// ValidateSecretKey valida uma secret key fornecida e registra o uso pelo cliente
+func (uc *SecretKeyUseCase) ValidateSecretKey(secretKey, clientIP, userAgent string) (*entity.SecretKey, error) {
 	// Gerar hash da chave fornecida
 	hash := sha256.Sum256([]byte(secretKey))
 	keyHash := hex.EncodeToString(hash[:])
 
 	// Buscar pela hash no banco
-	return uc.secretKeyRepo.GetByHash(keyHash)
+	key, err := uc.secretKeyRepo.GetByHash(keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// O uso é acumulado em memória e gravado em lote
+	uc.usageTracker.Track(key.ID, clientIP, userAgent)
+
+	return key, nil
 }
 
 // RegenerateSecretKey regenera uma secret key existente, invalidando a anterior
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// SegmentUseCase define os casos de uso para segmentos.
+// Os métodos recebem appID nil para operar sobre os segmentos globais.
+type SegmentUseCase struct {
+	segmentRepo repository.SegmentRepository
+	appRepo     repository.ApplicationRepository
+
+	managedPolicy entity.ManagedPolicy
+}
+
+// NewSegmentUseCase cria uma nova instância de SegmentUseCase
+func NewSegmentUseCase(segmentRepo repository.SegmentRepository, appRepo repository.ApplicationRepository) *SegmentUseCase {
+	return &SegmentUseCase{
+		segmentRepo: segmentRepo,
+		appRepo:     appRepo,
+	}
+}
+
+// SetManagedPolicy define se os usuários podem alterar manualmente os segmentos gerenciados pela sincronização
+func (uc *SegmentUseCase) SetManagedPolicy(policy entity.ManagedPolicy) {
+	uc.managedPolicy = policy
+}
+
+// CreateSegment cria um segmento na aplicação ou, com appID nil, um segmento global
+func (uc *SegmentUseCase) CreateSegment(appID *string, name, description string, constraints entity.SegmentConstraints, actor *entity.User) (*entity.Segment, error) {
+	if err := uc.checkApplication(appID); err != nil {
+		return nil, err
+	}
+
+	segment := entity.NewSegment(appID, name, description, constraints)
+	segment.Managed = actor.IsSync()
+	if err := uc.validate(segment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.segmentRepo.Create(segment); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error creating segment")
+	}
+
+	return segment, nil
+}
+
+// GetSegment busca um segmento por ID dentro do escopo informado
+func (uc *SegmentUseCase) GetSegment(id string, appID *string) (*entity.Segment, error) {
+	if id == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "segment ID is required")
+	}
+
+	segment, err := uc.segmentRepo.GetByID(id)
+	if err != nil || !sameScope(segment.AppID, appID) {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "segment not found")
+	}
+
+	return segment, nil
+}
+
+// ListSegments lista os segmentos da aplicação ou, com appID nil, os segmentos globais
+func (uc *SegmentUseCase) ListSegments(appID *string) ([]*entity.Segment, error) {
+	if err := uc.checkApplication(appID); err != nil {
+		return nil, err
+	}
+
+	var segments []*entity.Segment
+	var err error
+	if appID == nil {
+		segments, err = uc.segmentRepo.GetGlobal()
+	} else {
+		segments, err = uc.segmentRepo.GetByAppID(*appID)
+	}
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching segments")
+	}
+
+	return segments, nil
+}
+
+// GetAvailableSegments lista os segmentos que os toggles da aplicação podem referenciar, incluindo os globais
+func (uc *SegmentUseCase) GetAvailableSegments(appID string) ([]*entity.Segment, error) {
+	segments, err := uc.segmentRepo.GetAvailableForApp(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching segments")
+	}
+	return segments, nil
+}
+
+// UpdateSegment atualiza o nome, a descrição e as restrições de um segmento.
+// Os toggles que referenciam o segmento passam a usar as novas restrições imediatamente.
+func (uc *SegmentUseCase) UpdateSegment(id string, appID *string, name, description string, constraints entity.SegmentConstraints, actor *entity.User) (*entity.Segment, error) {
+	segment, err := uc.GetSegment(id, appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.managedPolicy.Check(segment.Managed, actor, "segment "+segment.Name); err != nil {
+		return nil, err
+	}
+
+	if constraints == nil {
+		constraints = entity.SegmentConstraints{}
+	}
+	segment.Name = strings.TrimSpace(name)
+	segment.Description = strings.TrimSpace(description)
+	segment.Constraints = constraints
+	if actor.IsSync() {
+		segment.Managed = true
+	}
+
+	if err := uc.validate(segment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.segmentRepo.Update(segment); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error updating segment")
+	}
+
+	return segment, nil
+}
+
+// DeleteSegment remove um segmento que não é referenciado por nenhuma regra de toggle
+func (uc *SegmentUseCase) DeleteSegment(id string, appID *string, actor *entity.User) error {
+	segment, err := uc.GetSegment(id, appID)
+	if err != nil {
+		return err
+	}
+	if err := uc.managedPolicy.Check(segment.Managed, actor, "segment "+segment.Name); err != nil {
+		return err
+	}
+
+	toggles, err := uc.segmentRepo.GetReferencingToggles(segment.ID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error checking segment references")
+	}
+	if len(toggles) > 0 {
+		appErr := entity.NewAppError(entity.ErrCodeInUse, "segment is referenced by toggle rules")
+		for _, toggle := range toggles {
+			appErr.AddDetail("toggles", toggle.AppID+":"+toggle.Path)
+		}
+		return appErr
+	}
+
+	if err := uc.segmentRepo.Delete(segment.ID); err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error deleting segment")
+	}
+
+	return nil
+}
+
+// validate valida o segmento e garante que o nome seja único no seu escopo
+func (uc *SegmentUseCase) validate(segment *entity.Segment) error {
+	if validation := segment.Validate(); !validation.IsValid {
+		return validation.ToAppError()
+	}
+
+	existing, err := uc.segmentRepo.GetByName(segment.AppID, segment.Name)
+	if err == nil && existing.ID != segment.ID {
+		return entity.NewAppError(entity.ErrCodeAlreadyExists, "segment with this name already exists")
+	}
+	return nil
+}
+
+// checkApplication verifica se a aplicação existe; segmentos globais não têm aplicação
+func (uc *SegmentUseCase) checkApplication(appID *string) error {
+	if appID == nil {
+		return nil
+	}
+	if *appID == "" {
+		return entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+	if _, err := uc.appRepo.GetByID(*appID); err != nil {
+		return entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+	return nil
+}
+
+// sameScope verifica se o segmento pertence ao escopo informado (mesma aplicação ou ambos globais)
+func sameScope(segmentAppID, appID *string) bool {
+	if segmentAppID == nil || appID == nil {
+		return segmentAppID == nil && appID == nil
+	}
+	return *segmentAppID == *appID
+}
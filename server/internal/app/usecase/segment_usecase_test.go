@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+)
+
+func betaConstraints() entity.SegmentConstraints {
+	return entity.SegmentConstraints{
+		{Attribute: "user_id", Operator: entity.SegmentOperatorIn, Values: []string{"tester-1", "tester-2"}},
+	}
+}
+
+func TestSegmentUseCase_CreateSegment(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	segmentMock := NewMockSegmentRepository()
+	useCase := NewSegmentUseCase(segmentMock, appMock)
+
+	appID := "app123"
+	segment, err := useCase.CreateSegment(&appID, " Beta testers ", "", betaConstraints(), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if segment.Name != "Beta testers" || segment.IsGlobal() {
+		t.Errorf("Unexpected segment %+v", segment)
+	}
+
+	// O mesmo nome é permitido em outro escopo, mas não no mesmo
+	if _, err := useCase.CreateSegment(nil, "Beta testers", "", betaConstraints(), nil); err != nil {
+		t.Errorf("Expected global segment with the same name to be created, got %v", err)
+	}
+	_, err = useCase.CreateSegment(&appID, "Beta testers", "", betaConstraints(), nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeAlreadyExists {
+		t.Errorf("Expected already exists error, got %v", err)
+	}
+
+	_, err = useCase.CreateSegment(&appID, "Empty", "", nil, nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	missing := "missing"
+	_, err = useCase.CreateSegment(&missing, "Other", "", betaConstraints(), nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeNotFound {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestSegmentUseCase_ScopeIsolation(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app1"] = &entity.Application{ID: "app1"}
+	appMock.Applications["app2"] = &entity.Application{ID: "app2"}
+	useCase := NewSegmentUseCase(NewMockSegmentRepository(), appMock)
+
+	app1, app2 := "app1", "app2"
+	segment, _ := useCase.CreateSegment(&app1, "Beta", "", betaConstraints(), nil)
+
+	if _, err := useCase.GetSegment(segment.ID, &app2); err == nil {
+		t.Errorf("Expected segment to be hidden from another application")
+	}
+	if _, err := useCase.GetSegment(segment.ID, nil); err == nil {
+		t.Errorf("Expected application segment to be hidden from global routes")
+	}
+	if _, err := useCase.UpdateSegment(segment.ID, &app2, "Beta", "", betaConstraints(), nil); err == nil {
+		t.Errorf("Expected update from another application to fail")
+	}
+
+	segments, _ := useCase.ListSegments(&app1)
+	if len(segments) != 1 {
+		t.Errorf("Expected 1 segment, got %d", len(segments))
+	}
+	segments, _ = useCase.ListSegments(nil)
+	if len(segments) != 0 {
+		t.Errorf("Expected no global segments, got %d", len(segments))
+	}
+}
+
+func TestSegmentUseCase_DeleteReferencedSegment(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123"}
+	toggleMock := NewMockToggleRepository()
+	segmentMock := NewMockSegmentRepository()
+	segmentMock.ToggleRepo = toggleMock
+	segmentUseCase := NewSegmentUseCase(segmentMock, appMock)
+	toggleUseCase := NewToggleUseCase(toggleMock, appMock, segmentMock, NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	segment, _ := segmentUseCase.CreateSegment(nil, "Beta", "", betaConstraints(), nil)
+	toggleMock.Toggles["toggle123"] = &entity.Toggle{ID: "toggle123", Path: "checkout", AppID: "app123", Enabled: true}
+
+	rule := &entity.ActivationRule{Type: entity.ActivationRuleTypeSegment, Value: segment.ID}
+	if err := toggleUseCase.UpdateToggleWithRule("toggle123", true, true, rule, nil, nil, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err := segmentUseCase.DeleteSegment(segment.ID, nil, nil)
+	appErr, ok := err.(*entity.AppError)
+	if !ok || appErr.Code != entity.ErrCodeInUse {
+		t.Fatalf("Expected in use error, got %v", err)
+	}
+	if len(appErr.Details) != 1 || appErr.Details[0].Message != "app123:checkout" {
+		t.Errorf("Expected referencing toggle in details, got %+v", appErr.Details)
+	}
+
+	// Sem referências o segmento pode ser removido
+	if err := toggleUseCase.UpdateToggleWithRule("toggle123", true, false, nil, nil, nil, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := segmentUseCase.DeleteSegment(segment.ID, nil, nil); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestToggleUseCase_UpdateToggleWithRule_SegmentReferences(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	toggleMock := NewMockToggleRepository()
+	segmentMock := NewMockSegmentRepository()
+	useCase := NewToggleUseCase(toggleMock, appMock, segmentMock, NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	otherApp := "other"
+	foreign := entity.NewSegment(&otherApp, "Foreign", "", betaConstraints())
+	segmentMock.Segments[foreign.ID] = foreign
+	toggleMock.Toggles["toggle123"] = &entity.Toggle{ID: "toggle123", Path: "checkout", AppID: "app123", Enabled: true}
+
+	for _, id := range []string{"unknown", foreign.ID} {
+		rule := &entity.ActivationRule{Type: entity.ActivationRuleTypeSegment, Value: id}
+		err := useCase.UpdateToggleWithRule("toggle123", true, true, rule, nil, nil, "app123", nil)
+		appErr, ok := err.(*entity.AppError)
+		if !ok || appErr.Code != entity.ErrCodeValidation || appErr.Details[0].Field != "rules[0].conditions[0]" {
+			t.Errorf("Expected validation error for segment %s, got %v", id, err)
+		}
+	}
+}
+
+func TestEvaluationUseCase_EvaluateSegmentRule(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	segmentMock := NewMockSegmentRepository()
+	useCase := NewEvaluationUseCase(toggleMock, newEvaluationTestAppRepository(), segmentMock, nil)
+
+	beta := entity.NewSegment(nil, "Beta", "", betaConstraints())
+	segmentMock.Segments[beta.ID] = beta
+	toggle := &entity.Toggle{ID: "toggle123", Path: "checkout", AppID: "app123", Enabled: true}
+	toggle.SetActivationRule(&entity.ActivationRule{Type: entity.ActivationRuleTypeSegment, Value: beta.ID})
+	toggleMock.Toggles[toggle.ID] = toggle
+
+	result, err := useCase.Evaluate("app123", "checkout", &evaluator.Context{UserID: "tester-1"})
+	if err != nil || !result.Enabled {
+		t.Fatalf("Expected segment member to be enabled, got %+v, %v", result, err)
+	}
+
+	// Editar o segmento altera a avaliação de todos os toggles que o referenciam
+	beta.Constraints[0].Values = []string{"tester-3"}
+	result, _ = useCase.Evaluate("app123", "checkout", &evaluator.Context{UserID: "tester-1"})
+	if result.Enabled {
+		t.Errorf("Expected removed member to be disabled, got %+v", result)
+	}
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// SnapshotUseCase define os casos de uso para snapshots de aplicações
+type SnapshotUseCase struct {
+	toggleRepo    repository.ToggleRepository
+	appRepo       repository.ApplicationRepository
+	snapshotRepo  repository.ApplicationSnapshotRepository
+	toggleUseCase *ToggleUseCase
+}
+
+// NewSnapshotUseCase cria uma nova instância de SnapshotUseCase
+func NewSnapshotUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository, snapshotRepo repository.ApplicationSnapshotRepository, toggleUseCase *ToggleUseCase) *SnapshotUseCase {
+	return &SnapshotUseCase{
+		toggleRepo:    toggleRepo,
+		appRepo:       appRepo,
+		snapshotRepo:  snapshotRepo,
+		toggleUseCase: toggleUseCase,
+	}
+}
+
+// CreateSnapshot grava o estado atual de todos os toggles da aplicação
+func (uc *SnapshotUseCase) CreateSnapshot(appID string, name string, actor *entity.User) (*entity.ApplicationSnapshot, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+	if _, err := uc.appRepo.GetByID(appID); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	toggles, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+
+	snapshot := entity.NewApplicationSnapshot(appID, name, toggles, actor)
+	if validation := snapshot.Validate(); !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+	if err := uc.snapshotRepo.Create(snapshot); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error creating snapshot")
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots lista os snapshots da aplicação, do mais recente para o mais antigo
+func (uc *SnapshotUseCase) ListSnapshots(appID string) ([]*entity.ApplicationSnapshot, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+	snapshots, err := uc.snapshotRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching snapshots")
+	}
+	return snapshots, nil
+}
+
+// RestoreSnapshot substitui os toggles da aplicação pelos do snapshot em uma única transação.
+// Toggles criados depois do snapshot são removidos e os removidos voltam com o mesmo ID,
+// mantendo o histórico; cada toggle afetado ganha uma nova revisão.
+func (uc *SnapshotUseCase) RestoreSnapshot(appID string, snapshotID string, actor *entity.User) error {
+	if appID == "" || snapshotID == "" {
+		return entity.NewAppError(entity.ErrCodeValidation, "application ID and snapshot ID are required")
+	}
+
+	snapshot, err := uc.snapshotRepo.GetByID(snapshotID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeNotFound, "snapshot not found")
+	}
+	if snapshot.AppID != appID {
+		return entity.NewAppError(entity.ErrCodeValidation, "snapshot does not belong to this application")
+	}
+
+	if err := uc.toggleUseCase.checkFreeze(appID, actor, fmt.Sprintf("restore snapshot %q", snapshot.Name)); err != nil {
+		return err
+	}
+
+	// Os segmentos referenciados pelas regras podem ter sido removidos depois do snapshot
+	toggles := make([]*entity.Toggle, 0, len(snapshot.Toggles))
+	restored := make(map[string]bool, len(snapshot.Toggles))
+	for _, saved := range snapshot.Toggles {
+		toggle := saved.Toggle(appID)
+		if err := uc.toggleUseCase.validateSegmentReferences(toggle.Rules, appID); err != nil {
+			return err
+		}
+		toggles = append(toggles, toggle)
+		restored[toggle.ID] = true
+	}
+
+	current, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+	// A restauração substitui todos os toggles da aplicação, inclusive os gerenciados
+	if err := uc.toggleUseCase.checkManaged(actor, current...); err != nil {
+		return err
+	}
+	managed := make(map[string]bool, len(current))
+	removed := make([]*entity.Toggle, 0)
+	for _, toggle := range current {
+		managed[toggle.ID] = toggle.Managed
+		if !restored[toggle.ID] {
+			removed = append(removed, toggle)
+		}
+	}
+	// O snapshot não guarda se o toggle é gerenciado; os toggles que continuam na aplicação mantêm a marca
+	for _, toggle := range toggles {
+		toggle.Managed = managed[toggle.ID]
+	}
+
+	revisions := append(newRevisions(entity.RevisionActionDeleted, actor, removed...), newRevisions(entity.RevisionActionRestored, actor, toggles...)...)
+	if err := uc.toggleRepo.RestoreApplication(appID, toggles, revisions...); err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error restoring snapshot")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func TestSnapshotUseCase_CreateAndRestore(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	revisionMock := NewMockToggleRevisionRepository()
+	toggleMock.RevisionRepo = revisionMock
+	snapshotMock := NewMockApplicationSnapshotRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["checkout"] = &entity.Toggle{ID: "checkout", AppID: "app123", Path: "checkout", Value: "checkout", Enabled: true}
+	toggleMock.Toggles["search"] = &entity.Toggle{ID: "search", AppID: "app123", Path: "search", Value: "search", Enabled: true}
+
+	toggleUseCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), revisionMock, NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	useCase := NewSnapshotUseCase(toggleMock, appMock, snapshotMock, toggleUseCase)
+	admin := &entity.User{ID: "user1", Username: "admin"}
+
+	snapshot, err := useCase.CreateSnapshot("app123", " before release ", admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if snapshot.Name != "before release" || len(snapshot.Toggles) != 2 || snapshot.Actor != "admin" {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+
+	// Alterações depois do snapshot
+	if err := toggleUseCase.UpdateToggleByID("checkout", false, "app123", admin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := toggleUseCase.DeleteToggleByID("search", "app123", admin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	toggleMock.Toggles["beta"] = &entity.Toggle{ID: "beta", AppID: "app123", Path: "beta", Value: "beta", Enabled: true}
+
+	if err := useCase.RestoreSnapshot("app123", snapshot.ID, admin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggleMock.Toggles) != 2 || toggleMock.Toggles["beta"] != nil {
+		t.Errorf("Expected only the snapshot toggles, got %+v", toggleMock.Toggles)
+	}
+	if toggle := toggleMock.Toggles["checkout"]; toggle == nil || !toggle.Enabled {
+		t.Errorf("Expected checkout to be enabled again, got %+v", toggle)
+	}
+	if toggleMock.Toggles["search"] == nil {
+		t.Error("Expected search to be restored with the same ID")
+	}
+
+	history, _ := toggleUseCase.GetToggleHistory("search", "app123")
+	if len(history) != 2 || history[0].Action != entity.RevisionActionRestored {
+		t.Errorf("Expected restored revision for search, got %+v", history)
+	}
+	history, _ = toggleUseCase.GetToggleHistory("beta", "app123")
+	if len(history) != 1 || history[0].Action != entity.RevisionActionDeleted {
+		t.Errorf("Expected deleted revision for beta, got %+v", history)
+	}
+
+	if _, err := useCase.CreateSnapshot("app123", "", admin); !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error without name, got %v", err)
+	}
+	if _, err := useCase.CreateSnapshot("missing", "name", admin); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := useCase.RestoreSnapshot("app123", "missing", admin); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := useCase.RestoreSnapshot("other", snapshot.ID, admin); !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error for another application, got %v", err)
+	}
+}
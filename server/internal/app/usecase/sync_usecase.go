@@ -0,0 +1,488 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// SyncUseCase define os casos de uso da sincronização declarativa a partir de um arquivo de flags.
+// O plano é aplicado pelos casos de uso de aplicações, segmentos e toggles, com as mesmas validações,
+// revisões e janelas de congelamento das alterações manuais.
+type SyncUseCase struct {
+	toggleRepo     repository.ToggleRepository
+	appUseCase     *ApplicationUseCase
+	segmentUseCase *SegmentUseCase
+	toggleUseCase  *ToggleUseCase
+}
+
+// NewSyncUseCase cria uma nova instância de SyncUseCase
+func NewSyncUseCase(toggleRepo repository.ToggleRepository, appUseCase *ApplicationUseCase, segmentUseCase *SegmentUseCase, toggleUseCase *ToggleUseCase) *SyncUseCase {
+	return &SyncUseCase{
+		toggleRepo:     toggleRepo,
+		appUseCase:     appUseCase,
+		segmentUseCase: segmentUseCase,
+		toggleUseCase:  toggleUseCase,
+	}
+}
+
+// syncTarget guarda a aplicação sincronizada e os IDs dos segmentos por nome. Aplicações e
+// segmentos criados pelo plano só recebem ID ao aplicar, então os passos seguintes os leem daqui.
+type syncTarget struct {
+	appID    string
+	segments map[string]string
+}
+
+// syncStep é uma alteração do plano com a função que a aplica
+type syncStep struct {
+	change *entity.SyncChange
+	apply  func(actor *entity.User) error
+}
+
+// Sync compara o arquivo com o estado atual e retorna o plano. Fora do modo dry run o plano é
+// aplicado em ordem; aplicar o mesmo arquivo de novo resulta em um plano vazio. A aplicação para
+// na primeira alteração que falha: as anteriores continuam gravadas e cada alteração do plano
+// indica se foi aplicada, falhou ou ficou de fora.
+func (uc *SyncUseCase) Sync(file *entity.SyncFile, dryRun bool, actor *entity.User) (*entity.SyncPlan, error) {
+	if file == nil {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "flags file is required")
+	}
+	if validation := file.Validate(); !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	apps, err := uc.appUseCase.GetAllApplications()
+	if err != nil {
+		return nil, err
+	}
+	globals, err := uc.segmentUseCase.ListSegments(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]*syncStep, 0)
+	for _, declared := range file.Applications {
+		appSteps, err := uc.planApplication(declared, apps, globals)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, appSteps...)
+	}
+
+	plan := &entity.SyncPlan{Changes: make([]*entity.SyncChange, 0, len(steps))}
+	for _, step := range steps {
+		plan.Changes = append(plan.Changes, step.change)
+	}
+	if dryRun || len(steps) == 0 {
+		return plan, nil
+	}
+
+	syncActor := entity.NewSyncActor(actor)
+	for i, step := range steps {
+		if err := step.apply(syncActor); err != nil {
+			step.change.Status = entity.SyncStatusFailed
+			step.change.Error = resultError(err)
+			for _, skipped := range steps[i+1:] {
+				skipped.change.Status = entity.SyncStatusSkipped
+			}
+			return plan, nil
+		}
+		step.change.Status = entity.SyncStatusApplied
+		plan.Applied = true
+	}
+	return plan, nil
+}
+
+// planApplication monta os passos de uma aplicação: a aplicação, os segmentos, os toggles e,
+// por último, as remoções dos toggles e segmentos gerenciados que saíram do arquivo
+func (uc *SyncUseCase) planApplication(declared *entity.SyncApplication, apps []*entity.Application, globals []*entity.Segment) ([]*syncStep, error) {
+	var app *entity.Application
+	for _, candidate := range apps {
+		if candidate.Name != declared.Name {
+			continue
+		}
+		if app != nil {
+			return nil, entity.NewAppError(entity.ErrCodeValidation, fmt.Sprintf("more than one application is named %s", declared.Name))
+		}
+		app = candidate
+	}
+
+	target := &syncTarget{segments: make(map[string]string)}
+	segmentNames := make(map[string]string)
+	for _, segment := range globals {
+		target.segments[segment.Name] = segment.ID
+		segmentNames[segment.ID] = segment.Name
+	}
+
+	steps := make([]*syncStep, 0)
+	segments := make([]*entity.Segment, 0)
+	toggles := make([]*entity.Toggle, 0)
+	if app == nil {
+		steps = append(steps, &syncStep{
+			change: &entity.SyncChange{Action: entity.SyncActionCreate, Resource: entity.SyncResourceApplication, Application: declared.Name},
+			apply: func(actor *entity.User) error {
+				created, err := uc.appUseCase.CreateApplication(declared.Name, actor)
+				if err != nil {
+					return err
+				}
+				target.appID = created.ID
+				return nil
+			},
+		})
+	} else {
+		target.appID = app.ID
+		if !app.Managed {
+			steps = append(steps, &syncStep{
+				change: &entity.SyncChange{Action: entity.SyncActionUpdate, Resource: entity.SyncResourceApplication, Application: app.Name, Diff: []string{"managed: false -> true"}},
+				apply: func(actor *entity.User) error {
+					_, err := uc.appUseCase.UpdateApplication(app.ID, app.Name, actor)
+					return err
+				},
+			})
+		}
+
+		var err error
+		if segments, err = uc.segmentUseCase.ListSegments(&app.ID); err != nil {
+			return nil, err
+		}
+		if toggles, err = uc.toggleUseCase.GetAllTogglesByApp(app.ID); err != nil {
+			return nil, err
+		}
+	}
+	// Os segmentos da aplicação têm precedência sobre os globais de mesmo nome
+	for _, segment := range segments {
+		target.segments[segment.Name] = segment.ID
+		segmentNames[segment.ID] = segment.Name
+	}
+
+	segmentSteps, removedSegments := uc.planSegments(declared, segments, target)
+	steps = append(steps, segmentSteps...)
+
+	if err := checkSegmentReferences(declared, target); err != nil {
+		return nil, err
+	}
+	toggleSteps, deleteSteps := uc.planToggles(declared, toggles, target, segmentNames)
+	steps = append(steps, toggleSteps...)
+	steps = append(steps, deleteSteps...)
+
+	for _, segment := range removedSegments {
+		segment := segment
+		steps = append(steps, &syncStep{
+			change: &entity.SyncChange{Action: entity.SyncActionDelete, Resource: entity.SyncResourceSegment, Application: declared.Name, Name: segment.Name},
+			apply: func(actor *entity.User) error {
+				return uc.segmentUseCase.DeleteSegment(segment.ID, &target.appID, actor)
+			},
+		})
+	}
+	return steps, nil
+}
+
+// planSegments cria os segmentos declarados que não existem, atualiza os que mudaram e retorna
+// os segmentos gerenciados que saíram do arquivo para serem removidos depois dos toggles
+func (uc *SyncUseCase) planSegments(declared *entity.SyncApplication, existing []*entity.Segment, target *syncTarget) ([]*syncStep, []*entity.Segment) {
+	byName := make(map[string]*entity.Segment, len(existing))
+	for _, segment := range existing {
+		byName[segment.Name] = segment
+	}
+
+	steps := make([]*syncStep, 0)
+	declaredNames := make(map[string]bool, len(declared.Segments))
+	for _, desired := range declared.Segments {
+		desired := entity.NewSegment(nil, desired.Name, desired.Description, desired.Constraints)
+		declaredNames[desired.Name] = true
+
+		current := byName[desired.Name]
+		if current == nil {
+			steps = append(steps, &syncStep{
+				change: &entity.SyncChange{Action: entity.SyncActionCreate, Resource: entity.SyncResourceSegment, Application: declared.Name, Name: desired.Name},
+				apply: func(actor *entity.User) error {
+					created, err := uc.segmentUseCase.CreateSegment(&target.appID, desired.Name, desired.Description, desired.Constraints, actor)
+					if err != nil {
+						return err
+					}
+					target.segments[created.Name] = created.ID
+					return nil
+				},
+			})
+			continue
+		}
+
+		diff := make([]string, 0)
+		diff = appendDiff(diff, "description", current.Description, desired.Description)
+		diff = appendDiff(diff, "constraints", canonicalJSON(current.Constraints), canonicalJSON(desired.Constraints))
+		diff = appendDiff(diff, "managed", fmt.Sprint(current.Managed), "true")
+		if len(diff) == 0 {
+			continue
+		}
+		steps = append(steps, &syncStep{
+			change: &entity.SyncChange{Action: entity.SyncActionUpdate, Resource: entity.SyncResourceSegment, Application: declared.Name, Name: desired.Name, Diff: diff},
+			apply: func(actor *entity.User) error {
+				_, err := uc.segmentUseCase.UpdateSegment(current.ID, &target.appID, desired.Name, desired.Description, desired.Constraints, actor)
+				return err
+			},
+		})
+	}
+
+	removed := make([]*entity.Segment, 0)
+	for _, segment := range existing {
+		if segment.Managed && !declaredNames[segment.Name] {
+			removed = append(removed, segment)
+			delete(target.segments, segment.Name)
+		}
+	}
+	return steps, removed
+}
+
+// planToggles cria os toggles declarados que não existem e atualiza os que mudaram, dos pais para os
+// filhos. Os toggles gerenciados que saíram do arquivo são removidos a partir do mais alto, desde que
+// toda a subárvore seja gerenciada e nenhum descendente continue declarado.
+func (uc *SyncUseCase) planToggles(declared *entity.SyncApplication, existing []*entity.Toggle, target *syncTarget, segmentNames map[string]string) ([]*syncStep, []*syncStep) {
+	byPath := make(map[string]*entity.Toggle, len(existing))
+	for _, toggle := range existing {
+		byPath[toggle.Path] = toggle
+	}
+
+	desiredToggles := make([]*entity.SyncToggle, len(declared.Toggles))
+	copy(desiredToggles, declared.Toggles)
+	sort.Slice(desiredToggles, func(i, j int) bool { return desiredToggles[i].Path < desiredToggles[j].Path })
+
+	steps := make([]*syncStep, 0)
+	declaredPaths := make(map[string]bool, len(desiredToggles))
+	for _, desired := range desiredToggles {
+		desired := desired
+		declaredPaths[desired.Path] = true
+		metadata := desired.Metadata()
+		variants := desired.Variants
+		if variants == nil {
+			variants = entity.ToggleVariants{}
+		}
+		hasRules := len(desired.Rules) > 0 || len(variants) > 0
+
+		current := byPath[desired.Path]
+		if current == nil {
+			steps = append(steps, &syncStep{
+				change: &entity.SyncChange{Action: entity.SyncActionCreate, Resource: entity.SyncResourceToggle, Application: declared.Name, Name: desired.Path},
+				apply: func(actor *entity.User) error {
+					if err := uc.toggleUseCase.CreateToggleWithMetadata(desired.Path, desired.IsEnabled(), true, target.appID, metadata, actor); err != nil {
+						return err
+					}
+					if !hasRules {
+						return nil
+					}
+					created, err := uc.toggleRepo.GetByPath(desired.Path, target.appID)
+					if err != nil {
+						return entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggle")
+					}
+					return uc.toggleUseCase.UpdateToggleWithRule(created.ID, desired.IsEnabled(), false, nil, resolveSyncRules(desired.Rules, target), variants, target.appID, actor)
+				},
+			})
+			continue
+		}
+
+		metadataDiff := make([]string, 0)
+		metadataDiff = appendDiff(metadataDiff, "description", current.Description, metadata.Description)
+		metadataDiff = appendDiff(metadataDiff, "owner", current.Owner, metadata.Owner)
+		metadataDiff = appendDiff(metadataDiff, "tags", strings.Join(entity.NormalizeToggleTags(current.Tags), ","), strings.Join(metadata.Tags, ","))
+		metadataDiff = appendDiff(metadataDiff, "kind", string(current.Kind), string(metadata.Kind))
+		metadataDiff = appendDiff(metadataDiff, "expires_at", formatSyncTime(current.ExpiresAt), formatSyncTime(metadata.ExpiresAt))
+
+		ruleDiff := make([]string, 0)
+		ruleDiff = appendDiff(ruleDiff, "enabled", fmt.Sprint(current.Enabled), fmt.Sprint(desired.IsEnabled()))
+		ruleDiff = appendDiff(ruleDiff, "variants", canonicalJSON(current.Variants), canonicalJSON(variants))
+		ruleDiff = appendDiff(ruleDiff, "rules", canonicalSyncRules(current.Rules, segmentNames), canonicalSyncRules(desired.Rules, nil))
+
+		diff := append(metadataDiff, ruleDiff...)
+		diff = appendDiff(diff, "managed", fmt.Sprint(current.Managed), "true")
+		if len(diff) == 0 {
+			continue
+		}
+
+		toggleID := current.ID
+		updateMetadata := len(metadataDiff) > 0 || len(ruleDiff) == 0
+		updateRules := len(ruleDiff) > 0
+		steps = append(steps, &syncStep{
+			change: &entity.SyncChange{Action: entity.SyncActionUpdate, Resource: entity.SyncResourceToggle, Application: declared.Name, Name: desired.Path, Diff: diff},
+			apply: func(actor *entity.User) error {
+				if updateMetadata {
+					if err := uc.toggleUseCase.UpdateToggleMetadata(toggleID, target.appID, metadata, actor); err != nil {
+						return err
+					}
+				}
+				if updateRules {
+					return uc.toggleUseCase.UpdateToggleWithRule(toggleID, desired.IsEnabled(), false, nil, resolveSyncRules(desired.Rules, target), variants, target.appID, actor)
+				}
+				return nil
+			},
+		})
+	}
+
+	removable := make(map[string]bool)
+	for _, toggle := range existing {
+		removable[toggle.Path] = toggle.Managed && !declaredPaths[toggle.Path]
+	}
+	deletes := make([]*syncStep, 0)
+	removed := make([]string, 0)
+	for _, toggle := range sortTogglesByPath(indexTogglesByID(existing)) {
+		if !removable[toggle.Path] || hasRemovedAncestor(toggle.Path, removed) {
+			continue
+		}
+		whole := true
+		for path, ok := range removable {
+			if strings.HasPrefix(path, toggle.Path+".") && !ok {
+				whole = false
+				break
+			}
+		}
+		if !whole {
+			continue
+		}
+		removed = append(removed, toggle.Path)
+		path := toggle.Path
+		deletes = append(deletes, &syncStep{
+			change: &entity.SyncChange{Action: entity.SyncActionDelete, Resource: entity.SyncResourceToggle, Application: declared.Name, Name: path},
+			apply: func(actor *entity.User) error {
+				return uc.toggleUseCase.DeleteToggle(path, target.appID, actor)
+			},
+		})
+	}
+	return steps, deletes
+}
+
+// checkSegmentReferences verifica se os segmentos referenciados pelas regras estão declarados,
+// já existem na aplicação ou são globais
+func checkSegmentReferences(declared *entity.SyncApplication, target *syncTarget) error {
+	available := make(map[string]bool, len(target.segments)+len(declared.Segments))
+	for name := range target.segments {
+		available[name] = true
+	}
+	for _, segment := range declared.Segments {
+		available[strings.TrimSpace(segment.Name)] = true
+	}
+
+	validation := entity.NewValidationResult()
+	for _, toggle := range declared.Toggles {
+		for i, rule := range toggle.Rules {
+			for j, condition := range rule.Conditions {
+				if condition.Type != entity.ActivationRuleTypeSegment {
+					continue
+				}
+				for _, name := range entity.ParseSegmentIDs(condition.Value) {
+					if !available[name] {
+						validation.AddError(fmt.Sprintf("%s.rules[%d].conditions[%d]", toggle.Path, i, j), fmt.Sprintf("Segment '%s' is not declared and does not exist", name))
+					}
+				}
+			}
+		}
+	}
+	if !validation.IsValid {
+		return validation.ToAppError()
+	}
+	return nil
+}
+
+// resolveSyncRules copia as regras declaradas trocando os nomes dos segmentos pelos IDs
+func resolveSyncRules(rules []*entity.ToggleRule, target *syncTarget) []*entity.ToggleRule {
+	resolved := make([]*entity.ToggleRule, 0, len(rules))
+	for _, rule := range rules {
+		copied := &entity.ToggleRule{Variant: rule.Variant, Conditions: make(entity.RuleConditions, 0, len(rule.Conditions))}
+		for _, condition := range rule.Conditions {
+			value := condition.Value
+			if condition.Type == entity.ActivationRuleTypeSegment {
+				ids := make([]string, 0)
+				for _, name := range entity.ParseSegmentIDs(value) {
+					ids = append(ids, target.segments[name])
+				}
+				value = strings.Join(ids, ",")
+			}
+			copied.Conditions = append(copied.Conditions, &entity.RuleCondition{Type: condition.Type, Value: value, Config: condition.Config})
+		}
+		resolved = append(resolved, copied)
+	}
+	return resolved
+}
+
+// canonicalSyncRules serializa as regras para comparação, com as condições normalizadas e os segmentos
+// pelo nome. Com segmentNames nil os valores das condições de segmento já são nomes.
+func canonicalSyncRules(rules []*entity.ToggleRule, segmentNames map[string]string) string {
+	type syncRule struct {
+		Conditions []*entity.RuleCondition `json:"conditions"`
+		Variant    string                  `json:"variant,omitempty"`
+	}
+	canonical := make([]syncRule, 0, len(rules))
+	for _, rule := range rules {
+		conditions := make([]*entity.RuleCondition, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			copied := &entity.RuleCondition{Type: condition.Type, Value: condition.Value, Config: condition.Config}
+			copied.Normalize()
+			if copied.Type == entity.ActivationRuleTypeSegment {
+				names := entity.ParseSegmentIDs(copied.Value)
+				for i, id := range names {
+					if name, ok := segmentNames[id]; ok {
+						names[i] = name
+					}
+				}
+				copied.Value = strings.Join(names, ",")
+			}
+			conditions = append(conditions, copied)
+		}
+		canonical = append(canonical, syncRule{Conditions: conditions, Variant: rule.Variant})
+	}
+	return canonicalJSON(canonical)
+}
+
+// canonicalJSON serializa o valor de forma compacta; listas vazias e nulas são equivalentes
+func canonicalJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return "[]"
+	}
+	return string(data)
+}
+
+// appendDiff registra o campo quando o valor atual difere do desejado
+func appendDiff(diff []string, field, current, desired string) []string {
+	if current == desired {
+		return diff
+	}
+	return append(diff, fmt.Sprintf("%s: %s -> %s", field, quoteSyncValue(current), quoteSyncValue(desired)))
+}
+
+// quoteSyncValue destaca valores vazios no diff
+func quoteSyncValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	return value
+}
+
+// formatSyncTime formata a data de remoção para comparação
+func formatSyncTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+// indexTogglesByID indexa os toggles pelo ID, o formato esperado por sortTogglesByPath
+func indexTogglesByID(toggles []*entity.Toggle) map[string]*entity.Toggle {
+	indexed := make(map[string]*entity.Toggle, len(toggles))
+	for _, toggle := range toggles {
+		indexed[toggle.ID] = toggle
+	}
+	return indexed
+}
+
+// hasRemovedAncestor verifica se algum ancestral do caminho já será removido
+func hasRemovedAncestor(path string, removed []string) bool {
+	for _, ancestor := range removed {
+		if strings.HasPrefix(path, ancestor+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+const syncTestFile = `
+applications:
+  - name: Shop
+    segments:
+      - name: beta
+        constraints:
+          - attribute: user_id
+            operator: in
+            values: [u1, u2]
+    toggles:
+      - path: checkout
+        owner: payments
+        tags: [Checkout]
+      - path: checkout.new-flow
+        enabled: false
+        kind: experiment
+        variants:
+          - name: blue
+            payload_type: string
+            payload: blue
+            weight: 100
+        rules:
+          - conditions:
+              - type: segment
+                value: beta
+            variant: blue
+`
+
+// syncTestUseCase reúne o caso de uso de sincronização e os repositórios usados nas verificações
+type syncTestUseCase struct {
+	sync          *SyncUseCase
+	toggleUseCase *ToggleUseCase
+	appMock       *MockApplicationRepository
+	toggleMock    *MockToggleRepository
+	segmentMock   *MockSegmentRepository
+}
+
+func newSyncTestUseCase() *syncTestUseCase {
+	appMock := NewMockApplicationRepository()
+	toggleMock := NewMockToggleRepository()
+	segmentMock := NewMockSegmentRepository()
+	segmentMock.ToggleRepo = toggleMock
+	auditMock := NewMockAuditEventRepository()
+
+	toggleUseCase := NewToggleUseCase(toggleMock, appMock, segmentMock, NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), auditMock)
+	return &syncTestUseCase{
+		sync:          NewSyncUseCase(toggleMock, NewApplicationUseCase(appMock, auditMock), NewSegmentUseCase(segmentMock, appMock), toggleUseCase),
+		toggleUseCase: toggleUseCase,
+		appMock:       appMock,
+		toggleMock:    toggleMock,
+		segmentMock:   segmentMock,
+	}
+}
+
+// mustParseSyncFile lê o arquivo de flags dos testes
+func mustParseSyncFile(t *testing.T, data string) *entity.SyncFile {
+	t.Helper()
+	file, err := entity.ParseSyncFile([]byte(data))
+	if err != nil {
+		t.Fatalf("Expected valid flags file, got %v", err)
+	}
+	return file
+}
+
+// syncChanges descreve as alterações do plano para comparação
+func syncChanges(plan *entity.SyncPlan) []string {
+	changes := make([]string, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+	return changes
+}
+
+func TestSyncUseCase_Sync(t *testing.T) {
+	tc := newSyncTestUseCase()
+	file := mustParseSyncFile(t, syncTestFile)
+
+	// O dry run calcula o plano sem gravar nada
+	plan, err := tc.sync.Sync(file, true, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"+ application Shop", "+ segment Shop/beta", "+ toggle Shop/checkout", "+ toggle Shop/checkout.new-flow"}
+	if strings.Join(syncChanges(plan), "|") != strings.Join(expected, "|") || plan.Applied {
+		t.Fatalf("Unexpected dry run plan %v", syncChanges(plan))
+	}
+	if len(tc.appMock.Applications) != 0 {
+		t.Fatal("Expected dry run to leave the server untouched")
+	}
+
+	plan, err = tc.sync.Sync(file, false, &entity.User{ID: "root1", Username: "root", Role: entity.UserRoleRoot})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !plan.Applied || plan.Summary() != "Plan: 4 to create, 0 to update, 0 to delete." {
+		t.Fatalf("Unexpected plan %+v", plan)
+	}
+
+	apps, _ := tc.appMock.GetAll()
+	if len(apps) != 1 || !apps[0].Managed {
+		t.Fatalf("Expected one managed application, got %+v", apps)
+	}
+	appID := apps[0].ID
+	segment, err := tc.segmentMock.GetByName(&appID, "beta")
+	if err != nil || !segment.Managed {
+		t.Fatalf("Expected managed segment, got %+v", segment)
+	}
+	flow, err := tc.toggleMock.GetByPath("checkout.new-flow", appID)
+	if err != nil || !flow.Managed || flow.Enabled || flow.Kind != entity.ToggleKindExperiment {
+		t.Fatalf("Unexpected toggle %+v", flow)
+	}
+	if len(flow.Rules) != 1 || flow.Rules[0].Conditions[0].Value != segment.ID || flow.Rules[0].Variant != "blue" {
+		t.Errorf("Expected the rule to reference the segment by ID, got %+v", flow.Rules)
+	}
+	if checkout, _ := tc.toggleMock.GetByPath("checkout", appID); !checkout.Managed || checkout.Owner != "payments" || !checkout.Tags.Has("checkout") {
+		t.Errorf("Unexpected parent toggle %+v", checkout)
+	}
+
+	// Aplicar o mesmo arquivo de novo não altera nada
+	plan, err = tc.sync.Sync(file, false, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(plan.Changes) != 0 || plan.Applied {
+		t.Errorf("Expected an empty plan, got %v", syncChanges(plan))
+	}
+}
+
+func TestSyncUseCase_Sync_UpdatesAndDeletes(t *testing.T) {
+	tc := newSyncTestUseCase()
+	if _, err := tc.sync.Sync(mustParseSyncFile(t, syncTestFile), false, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	apps, _ := tc.appMock.GetAll()
+	appID := apps[0].ID
+
+	// Toggles criados manualmente não são removidos pela sincronização
+	if err := tc.toggleUseCase.CreateToggle("manual", true, true, appID, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	plan, err := tc.sync.Sync(mustParseSyncFile(t, `
+applications:
+  - name: Shop
+    toggles:
+      - path: checkout
+        owner: payments
+        tags: [checkout]
+        enabled: false
+`), false, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"~ toggle Shop/checkout", "- toggle Shop/checkout.new-flow", "- segment Shop/beta"}
+	if strings.Join(syncChanges(plan), "|") != strings.Join(expected, "|") {
+		t.Fatalf("Unexpected plan %v", syncChanges(plan))
+	}
+	if diff := plan.Changes[0].Diff; len(diff) != 1 || diff[0] != "enabled: true -> false" {
+		t.Errorf("Unexpected diff %v", diff)
+	}
+
+	if checkout, _ := tc.toggleMock.GetByPath("checkout", appID); checkout.Enabled {
+		t.Error("Expected checkout to be disabled")
+	}
+	if _, err := tc.toggleMock.GetByPath("checkout.new-flow", appID); err == nil {
+		t.Error("Expected checkout.new-flow to be deleted")
+	}
+	if _, err := tc.toggleMock.GetByPath("manual", appID); err != nil {
+		t.Error("Expected the manual toggle to be kept")
+	}
+	if len(tc.segmentMock.Segments) != 0 {
+		t.Error("Expected the managed segment to be deleted")
+	}
+}
+
+func TestSyncUseCase_Sync_AdoptsExistingResources(t *testing.T) {
+	tc := newSyncTestUseCase()
+	tc.appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Shop"}
+	tc.toggleMock.Toggles["checkout"] = &entity.Toggle{ID: "checkout", AppID: "app123", Path: "checkout", Value: "checkout", Enabled: true, Kind: entity.ToggleKindRelease, Tags: entity.ToggleTags{}}
+
+	plan, err := tc.sync.Sync(mustParseSyncFile(t, `{"applications": [{"name": "Shop", "toggles": [{"path": "checkout"}]}]}`), false, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"~ application Shop", "~ toggle Shop/checkout"}
+	if strings.Join(syncChanges(plan), "|") != strings.Join(expected, "|") {
+		t.Fatalf("Unexpected plan %v", syncChanges(plan))
+	}
+	if !tc.appMock.Applications["app123"].Managed || !tc.toggleMock.Toggles["checkout"].Managed {
+		t.Error("Expected the application and the toggle to become managed")
+	}
+}
+
+func TestSyncUseCase_Sync_StopsAtTheFailedChange(t *testing.T) {
+	tc := newSyncTestUseCase()
+	if _, err := tc.sync.Sync(mustParseSyncFile(t, syncTestFile), false, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	apps, _ := tc.appMock.GetAll()
+	appID := apps[0].ID
+
+	// A janela de congelamento bloqueia os toggles, mas não os segmentos
+	now := time.Now()
+	tc.toggleUseCase.freezeRepo.Create(entity.NewFreezeWindow(&appID, now.Add(-time.Hour), now.Add(time.Hour), "launch", nil, nil))
+	plan, err := tc.sync.Sync(mustParseSyncFile(t, `
+applications:
+  - name: Shop
+    segments:
+      - name: beta
+        constraints:
+          - attribute: user_id
+            operator: in
+            values: [u3]
+    toggles:
+      - path: checkout
+        owner: checkout-team
+      - path: checkout.new-flow
+        enabled: true
+`), false, nil)
+	if err != nil {
+		t.Fatalf("Expected the plan with the failure, got %v", err)
+	}
+
+	statuses := make([]string, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		statuses = append(statuses, string(change.Status))
+	}
+	if strings.Join(statuses, ",") != "applied,failed,skipped" || !plan.Applied {
+		t.Fatalf("Expected the segment applied and the toggles stopped, got %v", statuses)
+	}
+	failed := plan.Failed()
+	if failed == nil || failed.Name != "checkout" || failed.Error == nil || failed.Error.Code != entity.ErrCodeFrozen {
+		t.Errorf("Expected the frozen error on checkout, got %+v", failed)
+	}
+	if checkout, _ := tc.toggleMock.GetByPath("checkout", appID); checkout.Owner != "payments" {
+		t.Errorf("Expected checkout to be unchanged, got %+v", checkout)
+	}
+}
+
+func TestSyncUseCase_Sync_Errors(t *testing.T) {
+	tc := newSyncTestUseCase()
+
+	_, err := tc.sync.Sync(mustParseSyncFile(t, `
+applications:
+  - name: Shop
+    toggles:
+      - path: checkout
+        rules:
+          - conditions:
+              - type: segment
+                value: missing
+`), true, nil)
+	if !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error for an unknown segment, got %v", err)
+	}
+
+	tc.appMock.Applications["a1"] = &entity.Application{ID: "a1", Name: "Shop"}
+	tc.appMock.Applications["a2"] = &entity.Application{ID: "a2", Name: "Shop"}
+	_, err = tc.sync.Sync(mustParseSyncFile(t, "applications:\n  - name: Shop\n"), true, nil)
+	if !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error for an ambiguous application, got %v", err)
+	}
+}
+
+func TestManagedPolicy_Block(t *testing.T) {
+	tc := newSyncTestUseCase()
+	if _, err := tc.sync.Sync(mustParseSyncFile(t, syncTestFile), false, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	apps, _ := tc.appMock.GetAll()
+	appID := apps[0].ID
+	admin := &entity.User{ID: "admin1", Username: "admin", Role: entity.UserRoleAdmin}
+
+	// Com a política padrão a alteração manual é aceita
+	if err := tc.toggleUseCase.UpdateToggle("checkout", false, appID, admin); err != nil {
+		t.Fatalf("Expected manual change to be accepted, got %v", err)
+	}
+
+	tc.toggleUseCase.SetManagedPolicy(entity.ManagedPolicyBlock)
+	if err := tc.toggleUseCase.UpdateToggle("checkout", true, appID, admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error, got %v", err)
+	}
+	if err := tc.toggleUseCase.DeleteToggle("checkout", appID, admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error on delete, got %v", err)
+	}
+
+	// A sincronização continua alterando os recursos gerenciados
+	plan, err := tc.sync.Sync(mustParseSyncFile(t, syncTestFile), false, nil)
+	if err != nil {
+		t.Fatalf("Expected sync to bypass the policy, got %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Diff[0] != "enabled: false -> true" {
+		t.Errorf("Expected sync to restore the toggle, got %v", syncChanges(plan))
+	}
+}
+
+func TestManagedPolicy_BlockRestoresAndPrerequisites(t *testing.T) {
+	tc := newSyncTestUseCase()
+	if _, err := tc.sync.Sync(mustParseSyncFile(t, syncTestFile), false, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	apps, _ := tc.appMock.GetAll()
+	appID := apps[0].ID
+	admin := &entity.User{ID: "admin1", Username: "admin", Role: entity.UserRoleAdmin}
+	checkout, _ := tc.toggleMock.GetByPath("checkout", appID)
+	newFlow, _ := tc.toggleMock.GetByPath("checkout.new-flow", appID)
+
+	snapshots := NewSnapshotUseCase(tc.toggleMock, tc.appMock, NewMockApplicationSnapshotRepository(), tc.toggleUseCase)
+	snapshot, err := snapshots.CreateSnapshot(appID, "before", admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Com a política padrão a restauração é aceita e os toggles continuam gerenciados
+	if err := snapshots.RestoreSnapshot(appID, snapshot.ID, admin); err != nil {
+		t.Fatalf("Expected restore to be accepted, got %v", err)
+	}
+	if restored, _ := tc.toggleMock.GetByPath("checkout", appID); restored == nil || !restored.Managed {
+		t.Errorf("Expected restored toggle to stay managed, got %+v", restored)
+	}
+
+	tc.toggleUseCase.SetManagedPolicy(entity.ManagedPolicyBlock)
+	prerequisites := []*entity.TogglePrerequisite{{PrerequisiteID: checkout.ID, Enabled: true}}
+	if err := tc.toggleUseCase.UpdateTogglePrerequisites(newFlow.ID, appID, prerequisites, admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error on prerequisites, got %v", err)
+	}
+	if err := snapshots.RestoreSnapshot(appID, snapshot.ID, admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error on snapshot restore, got %v", err)
+	}
+
+	// Um toggle gerenciado removido pela sincronização só volta da lixeira pela sincronização
+	if err := tc.toggleUseCase.DeleteToggle("checkout", appID, entity.NewSyncActor(nil)); err != nil {
+		t.Fatalf("Expected sync to delete the toggle, got %v", err)
+	}
+	trash := NewTrashUseCase(tc.toggleMock, tc.appMock, tc.toggleUseCase)
+	if _, err := trash.RestoreToggle(appID, checkout.ID, admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Errorf("Expected managed error on trash restore, got %v", err)
+	}
+	if _, err := trash.RestoreToggle(appID, checkout.ID, entity.NewSyncActor(nil)); err != nil {
+		t.Errorf("Expected sync to restore the toggle, got %v", err)
+	}
+}
@@ -162,6 +162,31 @@ func (uc *TeamUseCase) GetTeamUsers(teamID string) ([]*entity.User, error) {
 	return uc.teamRepo.GetUsersByTeamID(teamID)
 }
 
+// ListTeamUsers lista os usuários de um time com paginação por cursor, ordenação e filtros
+func (uc *TeamUseCase) ListTeamUsers(teamID string, page *entity.PageRequest) (*entity.Page[*entity.User], error) {
+	if teamID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "team ID is required")
+	}
+
+	validation := page.Normalize(entity.UserListFields)
+	validateRoleFilter(page, validation)
+	if !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	// Verificar se o time existe
+	if _, err := uc.teamRepo.GetByID(teamID); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "team not found")
+	}
+
+	result, err := uc.teamRepo.ListUsersByTeamID(teamID, page)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching team users")
+	}
+
+	return result, nil
+}
+
 func (uc *TeamUseCase) GetUserTeams(userID string) ([]*entity.Team, error) {
 	if userID == "" {
 		return nil, errors.New("user ID is required")
@@ -358,6 +383,21 @@ func (uc *TeamUseCase) GetAllTeamsWithCounts() ([]*entity.TeamWithCounts, error)
 	return uc.teamRepo.GetTeamsWithCounts()
 }
 
+// ListTeamsWithCounts lista os times com contagens usando paginação por cursor, ordenação e filtros
+func (uc *TeamUseCase) ListTeamsWithCounts(page *entity.PageRequest) (*entity.Page[*entity.TeamWithCounts], error) {
+	validation := page.Normalize(entity.TeamListFields)
+	if !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	result, err := uc.teamRepo.ListTeamsWithCounts(page)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching teams")
+	}
+
+	return result, nil
+}
+
 func (uc *TeamUseCase) GetTeamWithCounts(id string) (*entity.TeamWithCounts, error) {
 	if id == "" {
 		return nil, errors.New("team ID is required")
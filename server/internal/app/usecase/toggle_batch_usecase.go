@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+)
+
+// ToggleBatchUseCase define os casos de uso das operações em lote sobre os toggles de uma aplicação
+type ToggleBatchUseCase struct {
+	toggleRepo    repository.ToggleRepository
+	appRepo       repository.ApplicationRepository
+	toggleUseCase *ToggleUseCase
+}
+
+// NewToggleBatchUseCase cria uma nova instância de ToggleBatchUseCase
+func NewToggleBatchUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository, toggleUseCase *ToggleUseCase) *ToggleBatchUseCase {
+	return &ToggleBatchUseCase{
+		toggleRepo:    toggleRepo,
+		appRepo:       appRepo,
+		toggleUseCase: toggleUseCase,
+	}
+}
+
+// batchState são os toggles da aplicação com as operações do lote já aplicadas em memória.
+// Cada operação enxerga o resultado das anteriores e só altera o estado se for válida.
+type batchState struct {
+	appID   string
+	toggles map[string]*entity.Toggle // Toggles vivos por ID, copiados do repositório
+	created map[string]bool
+	updated map[string]bool
+	deleted []*entity.Toggle // Toggles existentes removidos pelo lote, com o estado no momento da remoção
+}
+
+// newBatchState copia os toggles da aplicação para que o lote não altere os originais antes de ser gravado
+func newBatchState(appID string, toggles []*entity.Toggle) *batchState {
+	state := &batchState{
+		appID:   appID,
+		toggles: make(map[string]*entity.Toggle, len(toggles)),
+		created: make(map[string]bool),
+		updated: make(map[string]bool),
+		deleted: make([]*entity.Toggle, 0),
+	}
+	for _, toggle := range toggles {
+		copied := *toggle
+		state.toggles[toggle.ID] = &copied
+	}
+	return state
+}
+
+// byPath busca um toggle vivo pelo caminho
+func (s *batchState) byPath(path string) *entity.Toggle {
+	for _, toggle := range s.toggles {
+		if toggle.Path == path {
+			return toggle
+		}
+	}
+	return nil
+}
+
+// find busca o toggle alvo da operação pelo caminho ou pelo ID
+func (s *batchState) find(operation *entity.BatchOperation) (*entity.Toggle, error) {
+	var toggle *entity.Toggle
+	if operation.ToggleID != "" {
+		toggle = s.toggles[operation.ToggleID]
+	} else {
+		toggle = s.byPath(operation.Path)
+	}
+	if toggle == nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, fmt.Sprintf("toggle %s not found", operation.Target()))
+	}
+	return toggle, nil
+}
+
+// markUpdated registra a alteração de um toggle; toggles criados pelo lote continuam sendo criações
+func (s *batchState) markUpdated(toggle *entity.Toggle) {
+	if !s.created[toggle.ID] {
+		s.updated[toggle.ID] = true
+	}
+}
+
+// changes separa os toggles que o lote cria e altera, na ordem da hierarquia
+func (s *batchState) changes() (created []*entity.Toggle, updated []*entity.Toggle) {
+	created = make([]*entity.Toggle, 0, len(s.created))
+	updated = make([]*entity.Toggle, 0, len(s.updated))
+	for _, toggle := range sortTogglesByPath(s.toggles) {
+		if s.created[toggle.ID] {
+			created = append(created, toggle)
+		} else if s.updated[toggle.ID] {
+			updated = append(updated, toggle)
+		}
+	}
+	return created, updated
+}
+
+// ApplyBatch aplica as operações em ordem sobre os toggles da aplicação. Todas são validadas antes
+// de qualquer gravação e as alterações são gravadas em uma única transação. No modo atômico nada é
+// gravado se alguma operação falhar; no modo best_effort as operações que falham são ignoradas.
+// A janela de congelamento é verificada uma vez para o lote inteiro.
+func (uc *ToggleBatchUseCase) ApplyBatch(appID string, mode entity.BatchMode, operations []*entity.BatchOperation, actor *entity.User) (*entity.BatchResult, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+	if len(operations) == 0 {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "at least one operation is required")
+	}
+	if len(operations) > entity.MaxBatchOperations {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, fmt.Sprintf("a batch accepts at most %d operations", entity.MaxBatchOperations))
+	}
+	if _, err := uc.appRepo.GetByID(appID); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	if err := uc.toggleUseCase.checkFreeze(appID, actor, fmt.Sprintf("apply batch of %d operations", len(operations))); err != nil {
+		return nil, err
+	}
+
+	toggles, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+	state := newBatchState(appID, toggles)
+
+	result := &entity.BatchResult{Mode: mode, Results: make([]*entity.BatchOperationResult, 0, len(operations))}
+	for i, operation := range operations {
+		opResult := &entity.BatchOperationResult{Index: i, Op: operation.Op, Path: operation.Path, ToggleID: operation.ToggleID, Status: entity.BatchStatusApplied}
+		if err := uc.apply(state, operation, opResult, actor); err != nil {
+			opResult.Status = entity.BatchStatusFailed
+			opResult.Error = resultError(err)
+		}
+		result.Results = append(result.Results, opResult)
+	}
+
+	if mode == entity.BatchModeAtomic && result.Failed() > 0 {
+		for _, opResult := range result.Results {
+			if opResult.Status == entity.BatchStatusApplied {
+				opResult.Status = entity.BatchStatusSkipped
+			}
+		}
+		return result, nil
+	}
+
+	created, updated := state.changes()
+	if len(created)+len(updated)+len(state.deleted) == 0 {
+		return result, nil
+	}
+	revisions := newRevisions(entity.RevisionActionCreated, actor, created...)
+	revisions = append(revisions, newRevisions(entity.RevisionActionUpdated, actor, updated...)...)
+	revisions = append(revisions, newRevisions(entity.RevisionActionDeleted, actor, state.deleted...)...)
+	err = uc.toggleRepo.ApplyBatch(created, updated, state.deleted, revisions...)
+	if errors.Is(err, entity.ErrToggleChanged) {
+		return nil, entity.NewAppError(entity.ErrCodeConflict, "toggles were changed by another operation; retry the batch")
+	}
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error applying batch")
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// apply valida a operação e a aplica ao estado em memória, preenchendo o toggle afetado no resultado
+func (uc *ToggleBatchUseCase) apply(state *batchState, operation *entity.BatchOperation, result *entity.BatchOperationResult, actor *entity.User) error {
+	if validation := operation.Validate(); !validation.IsValid {
+		return validation.ToAppError()
+	}
+	if operation.Op == entity.BatchOperationCreate {
+		toggle, err := uc.create(state, operation)
+		if err != nil {
+			return err
+		}
+		result.ToggleID = toggle.ID
+		return nil
+	}
+
+	toggle, err := state.find(operation)
+	if err != nil {
+		return err
+	}
+	result.Path = toggle.Path
+	result.ToggleID = toggle.ID
+	if err := uc.toggleUseCase.checkManaged(actor, toggle); err != nil {
+		return err
+	}
+
+	switch operation.Op {
+	case entity.BatchOperationEnable, entity.BatchOperationDisable:
+		toggle.Enabled = operation.Op == entity.BatchOperationEnable
+		state.markUpdated(toggle)
+		return nil
+	case entity.BatchOperationSetRule:
+		return uc.setRule(state, toggle, operation)
+	default:
+		return uc.remove(state, toggle, actor)
+	}
+}
+
+// create cria o toggle e os ancestrais que faltam, habilitados como na criação individual
+func (uc *ToggleBatchUseCase) create(state *batchState, operation *entity.BatchOperation) (*entity.Toggle, error) {
+	if state.byPath(operation.Path) != nil {
+		return nil, entity.NewAppError(entity.ErrCodeAlreadyExists, fmt.Sprintf("toggle %s already exists", operation.Path))
+	}
+	enabled := true
+	if operation.Enabled != nil {
+		enabled = *operation.Enabled
+	}
+
+	parts := entity.ParseTogglePath(operation.Path)
+	var parentID *string
+	var toggle *entity.Toggle
+	for level := range parts {
+		path := strings.Join(parts[:level+1], ".")
+		toggle = state.byPath(path)
+		if toggle == nil {
+			toggle = entity.NewToggle(parts[level], enabled || level < len(parts)-1, path, level, parentID, state.appID)
+			state.toggles[toggle.ID] = toggle
+			state.created[toggle.ID] = true
+		}
+		id := toggle.ID
+		parentID = &id
+	}
+	return toggle, nil
+}
+
+// setRule substitui as regras do toggle com as mesmas regras de UpdateToggleWithRule, mantendo o estado
+func (uc *ToggleBatchUseCase) setRule(state *batchState, toggle *entity.Toggle, operation *entity.BatchOperation) error {
+	rules := operation.Rules
+	if rules == nil {
+		rules = []*entity.ToggleRule{}
+		if operation.ActivationRule != nil {
+			if err := operation.ActivationRule.ValidateRule(); err != nil {
+				return entity.NewAppError(entity.ErrCodeValidation, err.Error())
+			}
+			rules = append(rules, entity.NewToggleRuleFromActivationRule(operation.ActivationRule))
+		}
+	}
+	variants := operation.Variants
+	if variants == nil {
+		variants = toggle.Variants
+	}
+	if err := uc.toggleUseCase.validateRules(rules, variants, state.appID); err != nil {
+		return err
+	}
+
+	toggle.SetRules(rules)
+	toggle.Variants = variants
+	state.markUpdated(toggle)
+	return nil
+}
+
+// remove move o toggle e seus descendentes para a lixeira, desde que nenhum toggle fora
+// deles os declare como pré-requisito
+func (uc *ToggleBatchUseCase) remove(state *batchState, toggle *entity.Toggle, actor *entity.User) error {
+	subtree := make([]*entity.Toggle, 0)
+	ids := make([]string, 0)
+	for _, candidate := range sortTogglesByPath(state.toggles) {
+		if candidate.Path == toggle.Path || strings.HasPrefix(candidate.Path, toggle.Path+".") {
+			subtree = append(subtree, candidate)
+			ids = append(ids, candidate.ID)
+		}
+	}
+	if err := uc.toggleUseCase.checkManaged(actor, subtree...); err != nil {
+		return err
+	}
+
+	var appErr *entity.AppError
+	for _, dependent := range sortTogglesByPath(state.toggles) {
+		if containsID(ids, dependent.ID) {
+			continue
+		}
+		for _, prerequisite := range dependent.Prerequisites {
+			if containsID(ids, prerequisite.PrerequisiteID) {
+				if appErr == nil {
+					appErr = entity.NewAppError(entity.ErrCodeInUse, "toggle is a prerequisite of other toggles")
+				}
+				appErr.AddDetail("dependents", dependent.Path)
+				break
+			}
+		}
+	}
+	if appErr != nil {
+		return appErr
+	}
+
+	for _, removed := range subtree {
+		delete(state.toggles, removed.ID)
+		delete(state.updated, removed.ID)
+		if state.created[removed.ID] {
+			delete(state.created, removed.ID)
+			continue
+		}
+		state.deleted = append(state.deleted, removed)
+	}
+	return nil
+}
+
+// sortTogglesByPath ordena os toggles pelo caminho, deixando os pais antes dos filhos
+func sortTogglesByPath(toggles map[string]*entity.Toggle) []*entity.Toggle {
+	sorted := make([]*entity.Toggle, 0, len(toggles))
+	for _, toggle := range toggles {
+		sorted = append(sorted, toggle)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	return sorted
+}
+
+// resultError converte o erro de uma operação para o formato do resultado, no lote e na sincronização
+func resultError(err error) *entity.AppError {
+	if appErr, ok := err.(*entity.AppError); ok {
+		return appErr
+	}
+	return entity.NewAppError(entity.ErrCodeInternal, err.Error())
+}
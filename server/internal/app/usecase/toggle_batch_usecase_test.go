@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+// newBatchTestUseCase cria o caso de uso de lotes com a hierarquia checkout > payment e o toggle promo
+func newBatchTestUseCase() (*ToggleBatchUseCase, *ToggleUseCase, *MockToggleRepository) {
+	toggleMock := NewMockToggleRepository()
+	toggleMock.RevisionRepo = NewMockToggleRevisionRepository()
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+
+	checkoutID := "checkout"
+	toggleMock.Toggles["checkout"] = &entity.Toggle{ID: "checkout", AppID: "app123", Path: "checkout", Value: "checkout", Enabled: true}
+	toggleMock.Toggles["payment"] = &entity.Toggle{ID: "payment", AppID: "app123", Path: "checkout.payment", Value: "payment", Level: 1, ParentID: &checkoutID, Enabled: true}
+	toggleMock.Toggles["promo"] = &entity.Toggle{ID: "promo", AppID: "app123", Path: "promo", Value: "promo", Enabled: false}
+
+	toggleUseCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), toggleMock.RevisionRepo, NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	return NewToggleBatchUseCase(toggleMock, appMock, toggleUseCase), toggleUseCase, toggleMock
+}
+
+func TestToggleBatchUseCase_ApplyBatch(t *testing.T) {
+	useCase, toggleUseCase, toggleMock := newBatchTestUseCase()
+	disabled := false
+
+	result, err := useCase.ApplyBatch("app123", entity.BatchModeAtomic, []*entity.BatchOperation{
+		{Op: entity.BatchOperationCreate, Path: "launch.banner", Enabled: &disabled},
+		{Op: entity.BatchOperationSetRule, Path: "launch.banner", ActivationRule: &entity.ActivationRule{Type: entity.ActivationRuleTypePercentage, Value: "25"}},
+		{Op: entity.BatchOperationEnable, ToggleID: "promo"},
+		{Op: entity.BatchOperationDisable, Path: "checkout.payment"},
+		{Op: entity.BatchOperationDelete, Path: "checkout"},
+	}, &entity.User{ID: "user1", Username: "admin"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Applied || result.Failed() != 0 {
+		t.Fatalf("Expected batch to be applied, got %+v", result.Results)
+	}
+	if result.Results[2].Path != "promo" || result.Results[0].ToggleID == "" {
+		t.Errorf("Expected results to identify the toggles, got %+v %+v", result.Results[0], result.Results[2])
+	}
+
+	banner, err := toggleMock.GetByPath("launch.banner", "app123")
+	if err != nil || banner.Enabled || len(banner.Rules) != 1 {
+		t.Fatalf("Expected disabled banner with one rule, got %+v", banner)
+	}
+	if launch, _ := toggleMock.GetByPath("launch", "app123"); launch == nil || !launch.Enabled || banner.ParentID == nil || *banner.ParentID != launch.ID {
+		t.Errorf("Expected enabled parent to be created, got %+v", launch)
+	}
+	if !toggleMock.Toggles["promo"].Enabled {
+		t.Error("Expected promo to be enabled")
+	}
+	if toggleMock.Toggles["checkout"] != nil || toggleMock.Trash["payment"] == nil {
+		t.Error("Expected checkout and its children in the trash")
+	}
+
+	// Cada toggle alterado ganha uma revisão com a ação correspondente
+	actions := make(map[string]entity.RevisionAction)
+	for _, revision := range toggleUseCase.revisionRepo.(*MockToggleRevisionRepository).Revisions {
+		actions[revision.Path] = revision.Action
+	}
+	expected := map[string]entity.RevisionAction{
+		"launch":           entity.RevisionActionCreated,
+		"launch.banner":    entity.RevisionActionCreated,
+		"promo":            entity.RevisionActionUpdated,
+		"checkout":         entity.RevisionActionDeleted,
+		"checkout.payment": entity.RevisionActionDeleted,
+	}
+	if len(actions) != len(expected) {
+		t.Errorf("Expected %d revisions, got %v", len(expected), actions)
+	}
+	for path, action := range expected {
+		if actions[path] != action {
+			t.Errorf("Expected %s revision for %s, got %q", action, path, actions[path])
+		}
+	}
+}
+
+func TestToggleBatchUseCase_ApplyBatch_Modes(t *testing.T) {
+	operations := []*entity.BatchOperation{
+		{Op: entity.BatchOperationEnable, Path: "promo"},
+		{Op: entity.BatchOperationDisable, Path: "missing"},
+		{Op: entity.BatchOperationCreate, Path: "checkout"},
+		{Op: entity.BatchOperationSetRule, Path: "checkout", Rules: []*entity.ToggleRule{{Variant: "unknown"}}},
+	}
+	expectedErrors := []string{"", entity.ErrCodeNotFound, entity.ErrCodeAlreadyExists, entity.ErrCodeValidation}
+
+	t.Run("atomic", func(t *testing.T) {
+		useCase, _, toggleMock := newBatchTestUseCase()
+		result, err := useCase.ApplyBatch("app123", entity.BatchModeAtomic, operations, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Applied || result.Results[0].Status != entity.BatchStatusSkipped {
+			t.Errorf("Expected nothing to be applied, got %+v", result.Results[0])
+		}
+		for i, code := range expectedErrors[1:] {
+			if opResult := result.Results[i+1]; opResult.Status != entity.BatchStatusFailed || opResult.Error.Code != code {
+				t.Errorf("Expected operation %d to fail with %s, got %+v", i+1, code, opResult)
+			}
+		}
+		if toggleMock.Toggles["promo"].Enabled {
+			t.Error("Expected promo to stay disabled")
+		}
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		useCase, _, toggleMock := newBatchTestUseCase()
+		result, err := useCase.ApplyBatch("app123", entity.BatchModeBestEffort, operations, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.Applied || result.Results[0].Status != entity.BatchStatusApplied || result.Failed() != 3 {
+			t.Errorf("Expected only the valid operation to be applied, got %+v", result.Results)
+		}
+		if !toggleMock.Toggles["promo"].Enabled || len(toggleMock.Toggles["checkout"].Rules) != 0 {
+			t.Error("Expected promo enabled and checkout unchanged")
+		}
+	})
+}
+
+func TestToggleBatchUseCase_ApplyBatch_Errors(t *testing.T) {
+	useCase, toggleUseCase, toggleMock := newBatchTestUseCase()
+	toggleMock.Toggles["promo"].Prerequisites = []*entity.TogglePrerequisite{{ToggleID: "promo", PrerequisiteID: "payment", Enabled: true}}
+
+	// Um pré-requisito não pode ser removido, a menos que o dependente seja removido antes no mesmo lote
+	result, _ := useCase.ApplyBatch("app123", entity.BatchModeAtomic, []*entity.BatchOperation{{Op: entity.BatchOperationDelete, Path: "checkout"}}, nil)
+	if result.Results[0].Error == nil || result.Results[0].Error.Code != entity.ErrCodeInUse {
+		t.Errorf("Expected in use error, got %+v", result.Results[0])
+	}
+	result, _ = useCase.ApplyBatch("app123", entity.BatchModeAtomic, []*entity.BatchOperation{
+		{Op: entity.BatchOperationDelete, ToggleID: "promo"},
+		{Op: entity.BatchOperationDelete, Path: "checkout"},
+	}, nil)
+	if !result.Applied {
+		t.Errorf("Expected dependent and prerequisite to be deleted together, got %+v", result.Results)
+	}
+
+	_, err := useCase.ApplyBatch("app123", entity.BatchModeAtomic, nil, nil)
+	if !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error without operations, got %v", err)
+	}
+	tooMany := make([]*entity.BatchOperation, entity.MaxBatchOperations+1)
+	if _, err := useCase.ApplyBatch("app123", entity.BatchModeAtomic, tooMany, nil); !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error above the limit, got %v", err)
+	}
+	if _, err := useCase.ApplyBatch("missing", entity.BatchModeAtomic, []*entity.BatchOperation{{Op: entity.BatchOperationEnable, Path: "promo"}}, nil); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	// Um toggle alterado por outra operação durante o lote devolve conflito para que o lote seja refeito
+	toggleMock.UpdateError = entity.ErrToggleChanged
+	if _, err := useCase.ApplyBatch("app123", entity.BatchModeAtomic, []*entity.BatchOperation{{Op: entity.BatchOperationCreate, Path: "fresh"}}, nil); !isAppError(err, entity.ErrCodeConflict) {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+	toggleMock.UpdateError = nil
+
+	// A janela de congelamento bloqueia o lote inteiro
+	now := time.Now()
+	toggleUseCase.freezeRepo.Create(entity.NewFreezeWindow(nil, now.Add(-time.Hour), now.Add(time.Hour), "launch", nil, nil))
+	if _, err := useCase.ApplyBatch("app123", entity.BatchModeBestEffort, []*entity.BatchOperation{{Op: entity.BatchOperationEnable, Path: "launch"}}, nil); !isAppError(err, entity.ErrCodeFrozen) {
+		t.Errorf("Expected frozen error, got %v", err)
+	}
+}
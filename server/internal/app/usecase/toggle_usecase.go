@@ -1,7 +1,10 @@
 package usecase
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 	"github.com/manorfm/totoogle/internal/app/domain/repository"
@@ -9,20 +12,86 @@ import (
 
 // ToggleUseCase define os casos de uso para toggles
 type ToggleUseCase struct {
-	toggleRepo repository.ToggleRepository
-	appRepo    repository.ApplicationRepository
+	toggleRepo   repository.ToggleRepository
+	appRepo      repository.ApplicationRepository
+	segmentRepo  repository.SegmentRepository
+	revisionRepo repository.ToggleRevisionRepository
+	freezeRepo   repository.FreezeWindowRepository
+	auditRepo    repository.AuditEventRepository
+
+	managedPolicy entity.ManagedPolicy
 }
 
 // NewToggleUseCase cria uma nova instância de ToggleUseCase
-func NewToggleUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository) *ToggleUseCase {
+func NewToggleUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository, segmentRepo repository.SegmentRepository, revisionRepo repository.ToggleRevisionRepository, freezeRepo repository.FreezeWindowRepository, auditRepo repository.AuditEventRepository) *ToggleUseCase {
 	return &ToggleUseCase{
-		toggleRepo: toggleRepo,
-		appRepo:    appRepo,
+		toggleRepo:   toggleRepo,
+		appRepo:      appRepo,
+		segmentRepo:  segmentRepo,
+		revisionRepo: revisionRepo,
+		freezeRepo:   freezeRepo,
+		auditRepo:    auditRepo,
 	}
 }
 
+// SetManagedPolicy define se os usuários podem alterar manualmente os toggles gerenciados pela sincronização
+func (uc *ToggleUseCase) SetManagedPolicy(policy entity.ManagedPolicy) {
+	uc.managedPolicy = policy
+}
+
+// checkManaged aplica a política de alterações manuais aos toggles gerenciados pela sincronização
+func (uc *ToggleUseCase) checkManaged(actor *entity.User, toggles ...*entity.Toggle) error {
+	for _, toggle := range toggles {
+		if err := uc.managedPolicy.Check(toggle.Managed, actor, "toggle "+toggle.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFreeze bloqueia a alteração dos toggles da aplicação durante uma janela de congelamento em vigor.
+// Usuários isentos da janela passam; usuários root podem forçar a alteração informando uma
+// justificativa, que é registrada na auditoria da aplicação.
+func (uc *ToggleUseCase) checkFreeze(appID string, actor *entity.User, operation string) error {
+	windows, err := uc.freezeRepo.GetActive(appID, time.Now())
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error checking freeze windows")
+	}
+
+	var blocking *entity.FreezeWindow
+	for _, window := range windows {
+		if !window.Exempts(actor) {
+			blocking = window
+			break
+		}
+	}
+	if blocking == nil {
+		return nil
+	}
+
+	justification := ""
+	if actor != nil && actor.Role == entity.UserRoleRoot {
+		justification = strings.TrimSpace(actor.FreezeOverride)
+	}
+	if justification == "" {
+		return entity.NewAppError(entity.ErrCodeFrozen, fmt.Sprintf("changes are frozen until %s: %s", blocking.EndsAt.UTC().Format(time.RFC3339), blocking.Reason))
+	}
+
+	detail := fmt.Sprintf("%s during freeze window %s (%s): %s", operation, blocking.ID, blocking.Reason, justification)
+	if err := uc.auditRepo.Create(entity.NewAuditEvent(appID, entity.AuditActionFreezeOverridden, detail, actor)); err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error recording audit event")
+	}
+	return nil
+}
+
 // CreateToggle cria um novo toggle com estrutura hierárquica
-func (uc *ToggleUseCase) CreateToggle(path string, enabled bool, editable bool, appID string) error {
+func (uc *ToggleUseCase) CreateToggle(path string, enabled bool, editable bool, appID string, actor *entity.User) error {
+	return uc.CreateToggleWithMetadata(path, enabled, editable, appID, nil, actor)
+}
+
+// CreateToggleWithMetadata cria um novo toggle com estrutura hierárquica,
+// aplicando os metadados apenas ao toggle final
+func (uc *ToggleUseCase) CreateToggleWithMetadata(path string, enabled bool, editable bool, appID string, metadata *entity.ToggleMetadata, actor *entity.User) error {
 	if path == "" {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle path is required")
 	}
@@ -37,6 +106,10 @@ func (uc *ToggleUseCase) CreateToggle(path string, enabled bool, editable bool,
 		return entity.NewAppError(entity.ErrCodeNotFound, "application not found")
 	}
 
+	if err := uc.checkFreeze(appID, actor, "create toggle "+path); err != nil {
+		return err
+	}
+
 	// Verifica se o toggle final já existe
 	exists, err := uc.toggleRepo.Exists(path, appID)
 	if err != nil {
@@ -47,13 +120,19 @@ func (uc *ToggleUseCase) CreateToggle(path string, enabled bool, editable bool,
 		return entity.NewAppError(entity.ErrCodeAlreadyExists, "toggle already exists")
 	}
 
+	if metadata != nil {
+		if err := uc.validateMetadata(metadata, nil); err != nil {
+			return err
+		}
+	}
+
 	// Cria a estrutura hierárquica
 	parts := entity.ParseTogglePath(path)
-	return uc.createToggleHierarchy(parts, enabled, editable, appID, nil, 0)
+	return uc.createToggleHierarchy(parts, enabled, editable, appID, nil, 0, metadata, actor)
 }
 
 // createToggleHierarchy cria a estrutura hierárquica de toggles
-func (uc *ToggleUseCase) createToggleHierarchy(parts []string, enabled bool, editable bool, appID string, parentID *string, level int) error {
+func (uc *ToggleUseCase) createToggleHierarchy(parts []string, enabled bool, editable bool, appID string, parentID *string, level int, metadata *entity.ToggleMetadata, actor *entity.User) error {
 	if level >= len(parts) {
 		return nil
 	}
@@ -67,7 +146,7 @@ func (uc *ToggleUseCase) createToggleHierarchy(parts []string, enabled bool, edi
 		// Toggle já existe, usa ele como pai para os próximos níveis
 		if level+1 < len(parts) {
 			nextParentID := existingToggle.ID
-			return uc.createToggleHierarchy(parts, enabled, editable, appID, &nextParentID, level+1)
+			return uc.createToggleHierarchy(parts, enabled, editable, appID, &nextParentID, level+1, metadata, actor)
 		}
 		return nil
 	}
@@ -83,8 +162,13 @@ func (uc *ToggleUseCase) createToggleHierarchy(parts []string, enabled bool, edi
 	}
 
 	toggle := entity.NewToggle(currentPart, toggleEnabled, currentPath, level, parentID, appID)
+	if isFinalToggle && metadata != nil {
+		toggle.SetMetadata(metadata)
+	}
+	// Apenas o toggle declarado é gerenciado; os ancestrais criados junto continuam manuais
+	toggle.Managed = isFinalToggle && actor.IsSync()
 
-	err = uc.toggleRepo.Create(toggle)
+	err = uc.toggleRepo.Create(toggle, newRevisions(entity.RevisionActionCreated, actor, toggle)...)
 	if err != nil {
 		return entity.NewAppError(entity.ErrCodeDatabase, "error creating toggle")
 	}
@@ -92,7 +176,7 @@ func (uc *ToggleUseCase) createToggleHierarchy(parts []string, enabled bool, edi
 	// Se há mais partes, cria os filhos
 	if level+1 < len(parts) {
 		nextParentID := toggle.ID
-		return uc.createToggleHierarchy(parts, enabled, editable, appID, &nextParentID, level+1)
+		return uc.createToggleHierarchy(parts, enabled, editable, appID, &nextParentID, level+1, metadata, actor)
 	}
 
 	return nil
@@ -137,7 +221,7 @@ func (uc *ToggleUseCase) isToggleEnabled(toggle *entity.Toggle) bool {
 }
 
 // UpdateToggle atualiza um toggle
-func (uc *ToggleUseCase) UpdateToggle(path string, enabled bool, appID string) error {
+func (uc *ToggleUseCase) UpdateToggle(path string, enabled bool, appID string, actor *entity.User) error {
 	if path == "" {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle path is required")
 	}
@@ -151,9 +235,16 @@ func (uc *ToggleUseCase) UpdateToggle(path string, enabled bool, appID string) e
 		return entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
 	}
 
+	if err := uc.checkFreeze(appID, actor, "update toggle "+path); err != nil {
+		return err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return err
+	}
+
 	toggle.Enabled = enabled
 
-	err = uc.toggleRepo.Update(toggle)
+	err = uc.toggleRepo.Update(toggle, newRevisions(entity.RevisionActionUpdated, actor, toggle)...)
 	if err != nil {
 		return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle")
 	}
@@ -161,8 +252,8 @@ func (uc *ToggleUseCase) UpdateToggle(path string, enabled bool, appID string) e
 	return nil
 }
 
-// DeleteToggle remove um toggle e seus filhos
-func (uc *ToggleUseCase) DeleteToggle(path string, appID string) error {
+// DeleteToggle move um toggle e seus filhos para a lixeira
+func (uc *ToggleUseCase) DeleteToggle(path string, appID string, actor *entity.User) error {
 	if path == "" {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle path is required")
 	}
@@ -181,8 +272,32 @@ func (uc *ToggleUseCase) DeleteToggle(path string, appID string) error {
 		return entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
 	}
 
+	if err := uc.checkFreeze(appID, actor, "delete toggle "+path); err != nil {
+		return err
+	}
+
+	// Toggles que são pré-requisitos de outros não podem ser removidos
+	toggles, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+	subtree := make([]string, 0)
+	removed := make([]*entity.Toggle, 0)
+	for _, toggle := range toggles {
+		if toggle.Path == path || strings.HasPrefix(toggle.Path, path+".") {
+			subtree = append(subtree, toggle.ID)
+			removed = append(removed, toggle)
+		}
+	}
+	if err := uc.checkManaged(actor, removed...); err != nil {
+		return err
+	}
+	if err := uc.checkDependents(subtree); err != nil {
+		return err
+	}
+
 	// Remove o toggle e seus filhos
-	err = uc.toggleRepo.DeleteByPath(path, appID)
+	err = uc.toggleRepo.DeleteByPath(path, appID, newRevisions(entity.RevisionActionDeleted, actor, removed...)...)
 	if err != nil {
 		return entity.NewAppError(entity.ErrCodeDatabase, "error deleting toggle")
 	}
@@ -210,6 +325,137 @@ func (uc *ToggleUseCase) GetAllTogglesByApp(appID string) ([]*entity.Toggle, err
 	return toggles, nil
 }
 
+// GetTogglesByFilter busca os toggles de uma aplicação filtrando por tags, owner e kind
+func (uc *ToggleUseCase) GetTogglesByFilter(appID string, filter *entity.ToggleFilter) ([]*entity.Toggle, error) {
+	if filter.IsEmpty() {
+		return uc.GetAllTogglesByApp(appID)
+	}
+
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+
+	if filter.Kind != "" && !filter.Kind.IsValid() {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("kind", "Kind must be one of: release, experiment, ops, permission")
+		return nil, appErr
+	}
+
+	// Verifica se a aplicação existe
+	_, err := uc.appRepo.GetByID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	normalized := &entity.ToggleFilter{
+		Tags:  entity.NormalizeToggleTags(filter.Tags),
+		Owner: strings.TrimSpace(filter.Owner),
+		Kind:  filter.Kind,
+	}
+
+	toggles, err := uc.toggleRepo.GetByAppIDWithFilter(appID, normalized)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+
+	return toggles, nil
+}
+
+// UpdateToggleMetadata atualiza a descrição, owner, tags, kind e data de remoção de um toggle
+func (uc *ToggleUseCase) UpdateToggleMetadata(toggleID string, appID string, metadata *entity.ToggleMetadata, actor *entity.User) error {
+	if toggleID == "" || appID == "" {
+		return entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
+	}
+
+	if metadata == nil {
+		return entity.NewAppError(entity.ErrCodeValidation, "metadata is required")
+	}
+
+	toggle, err := uc.toggleRepo.GetByID(toggleID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
+	}
+
+	if toggle.AppID != appID {
+		return entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
+	}
+
+	if err := uc.checkFreeze(appID, actor, "update metadata of toggle "+toggle.Path); err != nil {
+		return err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return err
+	}
+
+	if err := uc.validateMetadata(metadata, toggle.ExpiresAt); err != nil {
+		return err
+	}
+
+	toggle.SetMetadata(metadata)
+	if actor.IsSync() {
+		toggle.Managed = true
+	}
+
+	if err := uc.toggleRepo.Update(toggle, newRevisions(entity.RevisionActionUpdated, actor, toggle)...); err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle")
+	}
+
+	return nil
+}
+
+// validateMetadata normaliza e valida os metadados de um toggle.
+// Uma data de remoção no passado só é aceita se já era a data atual do toggle.
+func (uc *ToggleUseCase) validateMetadata(metadata *entity.ToggleMetadata, currentExpiresAt *time.Time) error {
+	metadata.Normalize()
+
+	validation := entity.ValidateToggleMetadata(metadata)
+	if metadata.ExpiresAt != nil && metadata.ExpiresAt.Before(time.Now()) &&
+		(currentExpiresAt == nil || !currentExpiresAt.Equal(*metadata.ExpiresAt)) {
+		validation.AddError("expires_at", "Expiry date must be in the future")
+	}
+
+	if !validation.IsValid {
+		return validation.ToAppError()
+	}
+	return nil
+}
+
+// ListToggles lista os toggles de uma aplicação com paginação por cursor, ordenação e filtros
+func (uc *ToggleUseCase) ListToggles(appID string, page *entity.PageRequest) (*entity.Page[*entity.Toggle], error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+
+	validation := page.Normalize(entity.ToggleListFields)
+	if enabled := page.Filter("enabled"); enabled != "" {
+		if _, err := strconv.ParseBool(enabled); err != nil {
+			validation.AddError("enabled", "enabled must be true or false")
+		}
+	}
+	if kind := entity.ToggleKind(page.Filter("kind")); kind != "" && !kind.IsValid() {
+		validation.AddError("kind", "Kind must be one of: release, experiment, ops, permission")
+	}
+	if tags, ok := page.Filters["tag"]; ok {
+		page.Filters["tag"] = entity.NormalizeToggleTags(tags)
+	}
+	if !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	// Verifica se a aplicação existe
+	_, err := uc.appRepo.GetByID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	result, err := uc.toggleRepo.ListByAppID(appID, page)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+
+	return result, nil
+}
+
 // GetToggleHierarchy retorna a estrutura hierárquica dos toggles
 func (uc *ToggleUseCase) GetToggleHierarchy(appID string) ([]map[string]interface{}, error) {
 	if appID == "" {
@@ -272,33 +518,45 @@ func (uc *ToggleUseCase) buildToggleNodeRecursiveArray(toggle *entity.Toggle, by
 	return node
 }
 
-// UpdateEnabledRecursively atualiza o campo enabled do toggle e de todos os seus descendentes
-func (uc *ToggleUseCase) UpdateEnabledRecursively(toggleID string, enabled bool, appID string) error {
-	toggle, err := uc.toggleRepo.GetByID(toggleID)
+// UpdateEnabledRecursively atualiza o campo enabled do toggle e de todos os seus descendentes.
+// A subárvore inteira é carregada e verificada antes da primeira alteração.
+func (uc *ToggleUseCase) UpdateEnabledRecursively(toggleID string, enabled bool, appID string, actor *entity.User) error {
+	toggle, err := uc.GetToggleByID(toggleID, appID)
 	if err != nil {
-		return entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
-	}
-	if toggle.AppID != appID {
-		return entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
+		return err
 	}
-	// Atualiza o próprio toggle
-	toggle.Enabled = enabled
-	if err := uc.toggleRepo.Update(toggle); err != nil {
-		return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle")
+	if err := uc.checkFreeze(appID, actor, "update toggle "+toggle.Path); err != nil {
+		return err
 	}
-	// Atualiza recursivamente os filhos
-	children, err := uc.toggleRepo.GetChildren(toggleID)
+	subtree, err := uc.loadSubtree(toggle)
 	if err != nil {
-		return entity.NewAppError(entity.ErrCodeDatabase, "error fetching children")
+		return err
 	}
-	for _, child := range children {
-		if err := uc.UpdateEnabledRecursively(child.ID, enabled, appID); err != nil {
-			return err
+	if err := uc.checkManaged(actor, subtree...); err != nil {
+		return err
+	}
+	for _, current := range subtree {
+		current.Enabled = enabled
+		if err := uc.toggleRepo.Update(current, newRevisions(entity.RevisionActionUpdated, actor, current)...); err != nil {
+			return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle")
 		}
 	}
 	return nil
 }
 
+// loadSubtree retorna o toggle seguido de todos os seus descendentes, os pais antes dos filhos
+func (uc *ToggleUseCase) loadSubtree(toggle *entity.Toggle) ([]*entity.Toggle, error) {
+	subtree := []*entity.Toggle{toggle}
+	for i := 0; i < len(subtree); i++ {
+		children, err := uc.toggleRepo.GetChildren(subtree[i].ID)
+		if err != nil {
+			return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching children")
+		}
+		subtree = append(subtree, children...)
+	}
+	return subtree, nil
+}
+
 // GetToggleByID busca um toggle por ID e appID
 func (uc *ToggleUseCase) GetToggleByID(toggleID string, appID string) (*entity.Toggle, error) {
 	if toggleID == "" || appID == "" {
@@ -315,7 +573,7 @@ func (uc *ToggleUseCase) GetToggleByID(toggleID string, appID string) (*entity.T
 }
 
 // UpdateToggleByID atualiza o enabled de um toggle por ID e appID
-func (uc *ToggleUseCase) UpdateToggleByID(toggleID string, enabled bool, appID string) error {
+func (uc *ToggleUseCase) UpdateToggleByID(toggleID string, enabled bool, appID string, actor *entity.User) error {
 	if toggleID == "" || appID == "" {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
 	}
@@ -326,19 +584,36 @@ func (uc *ToggleUseCase) UpdateToggleByID(toggleID string, enabled bool, appID s
 	if toggle.AppID != appID {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
 	}
+	if err := uc.checkFreeze(appID, actor, "update toggle "+toggle.Path); err != nil {
+		return err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return err
+	}
 	toggle.Enabled = enabled
-	if err := uc.toggleRepo.Update(toggle); err != nil {
+	if err := uc.toggleRepo.Update(toggle, newRevisions(entity.RevisionActionUpdated, actor, toggle)...); err != nil {
 		return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle")
 	}
 	return nil
 }
 
-// DeleteToggleByID remove um toggle por ID e appID, subindo recursivamente se o pai ficar sem filhos
-func (uc *ToggleUseCase) DeleteToggleByID(toggleID string, appID string) error {
-	if toggleID == "" || appID == "" {
-		return entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
+// DeleteToggleByID move um toggle para a lixeira por ID e appID, subindo recursivamente se o pai ficar sem filhos
+func (uc *ToggleUseCase) DeleteToggleByID(toggleID string, appID string, actor *entity.User) error {
+	toggle, err := uc.GetToggleByID(toggleID, appID)
+	if err != nil {
+		return err
 	}
+	if err := uc.checkFreeze(appID, actor, "delete toggle "+toggle.Path); err != nil {
+		return err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return err
+	}
+	return uc.deleteToggleByID(toggleID, appID, actor)
+}
 
+// deleteToggleByID remove o toggle e sobe pelos pais sem filhos, depois da verificação de congelamento
+func (uc *ToggleUseCase) deleteToggleByID(toggleID string, appID string, actor *entity.User) error {
 	// Busca o toggle pelo id e appId
 	toggle, err := uc.toggleRepo.GetByID(toggleID)
 	if err != nil {
@@ -358,21 +633,112 @@ func (uc *ToggleUseCase) DeleteToggleByID(toggleID string, appID string) error {
 		return nil
 	}
 
+	// Toggles que são pré-requisitos de outros não podem ser removidos
+	if err := uc.checkDependents([]string{toggleID}); err != nil {
+		return err
+	}
+
 	// Não tem filhos, pode remover
-	err = uc.toggleRepo.Delete(toggleID)
+	err = uc.toggleRepo.Delete(toggleID, newRevisions(entity.RevisionActionDeleted, actor, toggle)...)
 	if err != nil {
 		return entity.NewAppError(entity.ErrCodeDatabase, "error deleting toggle")
 	}
 
-	// Se tem parent, tenta remover o pai recursivamente
+	// Se tem parent, tenta remover o pai recursivamente; um pai que é pré-requisito é mantido
 	if toggle.ParentID != nil {
-		return uc.DeleteToggleByID(*toggle.ParentID, appID)
+		err := uc.deleteToggleByID(*toggle.ParentID, appID, actor)
+		if appErr, ok := err.(*entity.AppError); ok && appErr.Code == entity.ErrCodeInUse {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// checkDependents impede a remoção de toggles que são pré-requisitos de toggles fora do conjunto removido
+func (uc *ToggleUseCase) checkDependents(toggleIDs []string) error {
+	dependents, err := uc.toggleRepo.GetDependents(toggleIDs)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error checking toggle dependents")
+	}
+
+	var appErr *entity.AppError
+	for _, dependent := range dependents {
+		if containsID(toggleIDs, dependent.ID) {
+			continue
+		}
+		if appErr == nil {
+			appErr = entity.NewAppError(entity.ErrCodeInUse, "toggle is a prerequisite of other toggles")
+		}
+		appErr.AddDetail("dependents", dependent.Path)
+	}
+	if appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
+// UpdateTogglePrerequisites substitui os pré-requisitos de um toggle.
+// Os pré-requisitos precisam pertencer à mesma aplicação e não podem formar ciclos,
+// considerando também a dependência de cada toggle com o seu pai.
+func (uc *ToggleUseCase) UpdateTogglePrerequisites(toggleID string, appID string, prerequisites []*entity.TogglePrerequisite, actor *entity.User) error {
+	if toggleID == "" || appID == "" {
+		return entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
+	}
+
+	toggle, err := uc.toggleRepo.GetByID(toggleID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeNotFound, "toggle not found")
+	}
+
+	if toggle.AppID != appID {
+		return entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
+	}
+
+	if err := uc.checkFreeze(appID, actor, "update prerequisites of toggle "+toggle.Path); err != nil {
+		return err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return err
+	}
+
+	if err := uc.validatePrerequisites(toggle, prerequisites, appID); err != nil {
+		return err
+	}
+
+	toggle.SetPrerequisites(prerequisites)
+
+	if err := uc.toggleRepo.UpdatePrerequisites(toggle, newRevisions(entity.RevisionActionUpdated, actor, toggle)...); err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle prerequisites")
+	}
+
+	return nil
+}
+
+// validatePrerequisites valida os pré-requisitos contra os toggles da aplicação, incluindo ciclos
+func (uc *ToggleUseCase) validatePrerequisites(toggle *entity.Toggle, prerequisites []*entity.TogglePrerequisite, appID string) error {
+	appToggles, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+
+	if validation := entity.ValidatePrerequisites(toggle, prerequisites, appToggles); !validation.IsValid {
+		return validation.ToAppError()
+	}
+
+	if cycle := entity.FindPrerequisiteCycle(toggle, prerequisites, appToggles); cycle != nil {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "prerequisites would create a cycle")
+		appErr.AddDetail("prerequisites", strings.Join(cycle, " -> "))
+		return appErr
 	}
 	return nil
 }
 
-// UpdateToggleWithRule atualiza um toggle incluindo regras de ativação
-func (uc *ToggleUseCase) UpdateToggleWithRule(toggleID string, enabled bool, hasActivationRule bool, activationRule *entity.ActivationRule, appID string) error {
+// UpdateToggleWithRule atualiza um toggle incluindo regras de ativação e variantes.
+// Regras compostas, quando informadas, substituem a regra simples; sem nenhuma das duas
+// as regras do toggle são removidas. Variantes nil mantêm as variantes atuais e uma
+// lista vazia remove todas.
+func (uc *ToggleUseCase) UpdateToggleWithRule(toggleID string, enabled bool, hasActivationRule bool, activationRule *entity.ActivationRule, rules []*entity.ToggleRule, variants entity.ToggleVariants, appID string, actor *entity.User) error {
 	if toggleID == "" || appID == "" {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
 	}
@@ -385,25 +751,174 @@ func (uc *ToggleUseCase) UpdateToggleWithRule(toggleID string, enabled bool, has
 	if toggle.AppID != appID {
 		return entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
 	}
+
+	if err := uc.checkFreeze(appID, actor, "update toggle "+toggle.Path); err != nil {
+		return err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return err
+	}
 	
 	// Atualizar campos básicos
 	toggle.Enabled = enabled
-	toggle.HasActivationRule = hasActivationRule
+	if actor.IsSync() {
+		toggle.Managed = true
+	}
 	
-	// Atualizar regra de ativação
-	if hasActivationRule && activationRule != nil {
-		err := toggle.SetActivationRule(activationRule)
-		if err != nil {
-			return entity.NewAppError(entity.ErrCodeValidation, err.Error())
+	// A regra simples é convertida em uma regra composta com uma única condição
+	if rules == nil {
+		rules = []*entity.ToggleRule{}
+		if hasActivationRule && activationRule != nil {
+			if err := activationRule.ValidateRule(); err != nil {
+				return entity.NewAppError(entity.ErrCodeValidation, err.Error())
+			}
+			rules = append(rules, entity.NewToggleRuleFromActivationRule(activationRule))
 		}
-	} else {
-		toggle.ClearActivationRule()
 	}
+
+	// Validar variantes e regras, incluindo as variantes referenciadas pelas regras
+	if variants == nil {
+		variants = toggle.Variants
+	}
+	if err := uc.validateRules(rules, variants, appID); err != nil {
+		return err
+	}
+
+	toggle.SetRules(rules)
+	toggle.Variants = variants
 	
 	// Salvar no banco
-	if err := uc.toggleRepo.Update(toggle); err != nil {
+	if err := uc.toggleRepo.UpdateWithRules(toggle, newRevisions(entity.RevisionActionUpdated, actor, toggle)...); err != nil {
 		return entity.NewAppError(entity.ErrCodeDatabase, "error updating toggle")
 	}
 	
 	return nil
 }
+
+// validateRules valida as variantes e as regras, incluindo as variantes e os segmentos referenciados
+func (uc *ToggleUseCase) validateRules(rules []*entity.ToggleRule, variants entity.ToggleVariants, appID string) error {
+	validation := entity.ValidateVariants(variants)
+	for _, ruleErr := range entity.ValidateToggleRules(rules, variants).Errors {
+		validation.AddError(ruleErr.Field, ruleErr.Message)
+	}
+	if !validation.IsValid {
+		return validation.ToAppError()
+	}
+	return uc.validateSegmentReferences(rules, appID)
+}
+
+// validateSegmentReferences verifica se os segmentos referenciados pelas regras existem
+// e pertencem à aplicação ou são globais
+func (uc *ToggleUseCase) validateSegmentReferences(rules []*entity.ToggleRule, appID string) error {
+	ids := entity.RuleSegmentIDs(rules)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	segments, err := uc.segmentRepo.GetByIDs(ids)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error fetching segments")
+	}
+	available := make(map[string]bool, len(segments))
+	for _, segment := range segments {
+		available[segment.ID] = segment.AvailableTo(appID)
+	}
+
+	validation := entity.NewValidationResult()
+	for i, rule := range rules {
+		for j, condition := range rule.Conditions {
+			if condition.Type != entity.ActivationRuleTypeSegment {
+				continue
+			}
+			for _, id := range entity.ParseSegmentIDs(condition.Value) {
+				if !available[id] {
+					validation.AddError(fmt.Sprintf("rules[%d].conditions[%d]", i, j), fmt.Sprintf("Segment '%s' does not exist or is not available to this application", id))
+				}
+			}
+		}
+	}
+	if !validation.IsValid {
+		return validation.ToAppError()
+	}
+	return nil
+}
+
+// newRevisions monta uma revisão com o estado de cada toggle alterado, para ser gravada pelo
+// repositório de toggles na mesma transação da alteração
+func newRevisions(action entity.RevisionAction, actor *entity.User, toggles ...*entity.Toggle) []*entity.ToggleRevision {
+	revisions := make([]*entity.ToggleRevision, 0, len(toggles))
+	for _, toggle := range toggles {
+		revisions = append(revisions, entity.NewToggleRevision(toggle, action, actor))
+	}
+	return revisions
+}
+
+// GetToggleHistory lista as revisões de um toggle, da mais recente para a mais antiga.
+// O histórico continua disponível depois que o toggle é removido.
+func (uc *ToggleUseCase) GetToggleHistory(toggleID string, appID string) ([]*entity.ToggleRevision, error) {
+	if toggleID == "" || appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
+	}
+
+	revisions, err := uc.revisionRepo.GetByToggleID(toggleID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggle history")
+	}
+	if len(revisions) == 0 {
+		if _, err := uc.GetToggleByID(toggleID, appID); err != nil {
+			return nil, err
+		}
+		return revisions, nil
+	}
+	if revisions[0].AppID != appID {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "toggle does not belong to this application")
+	}
+	return revisions, nil
+}
+
+// RollbackToggle restaura o estado de uma revisão anterior do toggle. O estado passa pelas mesmas
+// validações de uma atualização (regras, variantes, segmentos, pré-requisitos e metadados) e a
+// restauração gera uma nova revisão; o histórico nunca é reescrito.
+func (uc *ToggleUseCase) RollbackToggle(toggleID string, appID string, revision int, actor *entity.User) (*entity.ToggleRevision, error) {
+	if revision <= 0 {
+		appErr := entity.NewAppError(entity.ErrCodeValidation, "validation failed")
+		appErr.AddDetail("revision", "Revision must be a positive integer")
+		return nil, appErr
+	}
+
+	toggle, err := uc.GetToggleByID(toggleID, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkFreeze(appID, actor, fmt.Sprintf("roll back toggle %s to revision %d", toggle.Path, revision)); err != nil {
+		return nil, err
+	}
+	if err := uc.checkManaged(actor, toggle); err != nil {
+		return nil, err
+	}
+
+	target, err := uc.revisionRepo.GetRevision(toggleID, revision)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "revision not found")
+	}
+
+	target.State.Apply(toggle)
+	if err := uc.validateRules(toggle.Rules, toggle.Variants, appID); err != nil {
+		return nil, err
+	}
+	if err := uc.validatePrerequisites(toggle, toggle.Prerequisites, appID); err != nil {
+		return nil, err
+	}
+	// Uma data de remoção que já passou é aceita quando é a mesma da revisão restaurada
+	if err := uc.validateMetadata(target.State.Metadata(), target.State.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	rolledBack := entity.NewToggleRevision(toggle, entity.RevisionActionRolledBack, actor)
+	rolledBack.RestoredFrom = &target.Revision
+	if err := uc.toggleRepo.Restore(toggle, rolledBack); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error restoring toggle")
+	}
+	return rolledBack, nil
+}
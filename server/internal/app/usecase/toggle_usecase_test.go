@@ -1,8 +1,11 @@
 package usecase
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/manorfm/totoogle/internal/app/domain/entity"
 )
@@ -60,8 +63,8 @@ func TestToggleUseCase_CreateToggle(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
-			err := useCase.CreateToggle(tt.path, tt.enabled, true, tt.appID)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+			err := useCase.CreateToggle(tt.path, tt.enabled, true, tt.appID, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -148,7 +151,7 @@ func TestToggleUseCase_GetToggleStatus(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 			result, err := useCase.GetToggleStatus(tt.path, tt.appID)
 
 			if tt.expectedError != "" {
@@ -217,8 +220,8 @@ func TestToggleUseCase_UpdateToggle(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
-			err := useCase.UpdateToggle(tt.path, tt.enabled, tt.appID)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+			err := useCase.UpdateToggle(tt.path, tt.enabled, tt.appID, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -282,7 +285,7 @@ func TestToggleUseCase_GetAllTogglesByApp(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 			toggles, err := useCase.GetAllTogglesByApp(tt.appID)
 
 			if tt.expectedError != "" {
@@ -318,7 +321,7 @@ func TestToggleUseCase_GetToggleByID(t *testing.T) {
 	toggleID := "toggle1"
 	appMock.Applications[appID] = &entity.Application{ID: appID, Name: "Test App"}
 	toggleMock.Toggles[toggleID] = &entity.Toggle{ID: toggleID, AppID: appID, Path: "test.path", Enabled: true}
-	useCase := NewToggleUseCase(toggleMock, appMock)
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 
 	toggle, err := useCase.GetToggleByID(toggleID, appID)
 	if err != nil {
@@ -346,9 +349,9 @@ func TestToggleUseCase_UpdateToggleByID(t *testing.T) {
 	toggleID := "toggle1"
 	appMock.Applications[appID] = &entity.Application{ID: appID, Name: "Test App"}
 	toggleMock.Toggles[toggleID] = &entity.Toggle{ID: toggleID, AppID: appID, Path: "test.path", Enabled: false}
-	useCase := NewToggleUseCase(toggleMock, appMock)
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 
-	err := useCase.UpdateToggleByID(toggleID, true, appID)
+	err := useCase.UpdateToggleByID(toggleID, true, appID, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -356,12 +359,12 @@ func TestToggleUseCase_UpdateToggleByID(t *testing.T) {
 		t.Errorf("Expected toggle to be enabled")
 	}
 
-	err = useCase.UpdateToggleByID("notfound", true, appID)
+	err = useCase.UpdateToggleByID("notfound", true, appID, nil)
 	if err == nil {
 		t.Errorf("Expected error for not found toggle")
 	}
 
-	err = useCase.UpdateToggleByID(toggleID, true, "wrongapp")
+	err = useCase.UpdateToggleByID(toggleID, true, "wrongapp", nil)
 	if err == nil {
 		t.Errorf("Expected error for wrong appID")
 	}
@@ -412,7 +415,7 @@ func TestToggleUseCase_GetToggleHierarchy(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 			hierarchy, err := useCase.GetToggleHierarchy(tt.appID)
 
 			if tt.expectedError != "" {
@@ -442,7 +445,7 @@ func TestToggleUseCase_GetToggleHierarchy(t *testing.T) {
 }
 
 func TestToggleUseCase_buildHierarchyArray(t *testing.T) {
-	useCase := NewToggleUseCase(nil, nil)
+	useCase := NewToggleUseCase(nil, nil, nil, nil, nil, nil)
 
 	toggles := []*entity.Toggle{
 		{
@@ -479,7 +482,7 @@ func TestToggleUseCase_buildHierarchyArray(t *testing.T) {
 }
 
 func TestToggleUseCase_buildToggleNodeArray(t *testing.T) {
-	useCase := NewToggleUseCase(nil, nil)
+	useCase := NewToggleUseCase(nil, nil, nil, nil, nil, nil)
 
 	toggle := &entity.Toggle{
 		ID:      "test",
@@ -505,7 +508,7 @@ func TestToggleUseCase_buildToggleNodeArray(t *testing.T) {
 }
 
 func TestToggleUseCase_buildToggleNodeRecursiveArray(t *testing.T) {
-	useCase := NewToggleUseCase(nil, nil)
+	useCase := NewToggleUseCase(nil, nil, nil, nil, nil, nil)
 
 	parent := &entity.Toggle{
 		ID:      "parent",
@@ -621,8 +624,8 @@ func TestToggleUseCase_UpdateEnabledRecursively(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
-			err := useCase.UpdateEnabledRecursively(tt.toggleID, tt.enabled, tt.appID)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+			err := useCase.UpdateEnabledRecursively(tt.toggleID, tt.enabled, tt.appID, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -726,8 +729,8 @@ func TestToggleUseCase_DeleteToggleByID(t *testing.T) {
 			appMock := NewMockApplicationRepository()
 			tt.setupMock(toggleMock, appMock)
 
-			useCase := NewToggleUseCase(toggleMock, appMock)
-			err := useCase.DeleteToggleByID(tt.toggleID, tt.appID)
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+			err := useCase.DeleteToggleByID(tt.toggleID, tt.appID, nil)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -767,9 +770,9 @@ func TestToggleUseCase_DeleteToggleByID_Recursion(t *testing.T) {
 	toggleMock.Toggles[b.ID] = b
 	toggleMock.Toggles[c.ID] = c
 
-	useCase := NewToggleUseCase(toggleMock, appMock)
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 
-	err := useCase.DeleteToggleByID("c", appID)
+	err := useCase.DeleteToggleByID("c", appID, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -795,9 +798,9 @@ func TestToggleUseCase_DeleteToggleByID_StopsOnSibling(t *testing.T) {
 	toggleMock.Toggles[b.ID] = b
 	toggleMock.Toggles[d.ID] = d
 
-	useCase := NewToggleUseCase(toggleMock, appMock)
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 
-	err := useCase.DeleteToggleByID("b", appID)
+	err := useCase.DeleteToggleByID("b", appID, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -816,7 +819,7 @@ func TestToggleUseCase_DeleteToggleByID_StopsOnSibling(t *testing.T) {
 func TestToggleUseCase_UpdateToggleWithRule(t *testing.T) {
 	appMock := NewMockApplicationRepository()
 	toggleMock := NewMockToggleRepository()
-	useCase := NewToggleUseCase(toggleMock, appMock)
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 
 	appID := "app123"
 	toggleID := "toggle123"
@@ -962,7 +965,7 @@ func TestToggleUseCase_UpdateToggleWithRule(t *testing.T) {
 			}
 
 			// Execute the method
-			err := useCase.UpdateToggleWithRule(toggleID, tt.enabled, tt.hasActivationRule, tt.activationRule, appID)
+			err := useCase.UpdateToggleWithRule(toggleID, tt.enabled, tt.hasActivationRule, tt.activationRule, nil, nil, appID, nil)
 
 			// Check error expectations
 			if tt.expectError {
@@ -1019,13 +1022,167 @@ func TestToggleUseCase_UpdateToggleWithRule(t *testing.T) {
 	}
 }
 
+func TestToggleUseCase_UpdateToggleWithRule_Variants(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	toggleMock := NewMockToggleRepository()
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	toggleMock.Toggles["toggle123"] = &entity.Toggle{ID: "toggle123", Path: "checkout", AppID: "app123", Enabled: true}
+
+	variants := entity.ToggleVariants{
+		{Name: "control", PayloadType: entity.VariantPayloadString, Payload: json.RawMessage(`"blue"`), Weight: 50},
+		{Name: "treatment", PayloadType: entity.VariantPayloadJSON, Payload: json.RawMessage(`{"color":"green"}`), Weight: 50},
+	}
+	rule := &entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1", Variant: "treatment"}
+
+	if err := useCase.UpdateToggleWithRule("toggle123", true, true, rule, nil, variants, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggleMock.Toggles["toggle123"].Variants) != 2 {
+		t.Fatalf("Expected variants to be saved")
+	}
+
+	// Variantes ausentes mantêm as atuais
+	if err := useCase.UpdateToggleWithRule("toggle123", true, false, nil, nil, nil, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggleMock.Toggles["toggle123"].Variants) != 2 {
+		t.Errorf("Expected variants to be kept when omitted")
+	}
+
+	t.Run("rule references undefined variant", func(t *testing.T) {
+		undefined := &entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1", Variant: "missing"}
+		err := useCase.UpdateToggleWithRule("toggle123", true, true, undefined, nil, nil, "app123", nil)
+		if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("invalid weights", func(t *testing.T) {
+		invalid := entity.ToggleVariants{{Name: "only", PayloadType: entity.VariantPayloadNumber, Payload: json.RawMessage(`1`), Weight: 60}}
+		err := useCase.UpdateToggleWithRule("toggle123", true, false, nil, nil, invalid, "app123", nil)
+		if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("empty list clears variants", func(t *testing.T) {
+		if err := useCase.UpdateToggleWithRule("toggle123", true, false, nil, nil, entity.ToggleVariants{}, "app123", nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(toggleMock.Toggles["toggle123"].Variants) != 0 {
+			t.Errorf("Expected variants to be removed")
+		}
+	})
+}
+
+func TestToggleUseCase_UpdateToggleWithRule_CompositeRules(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	toggleMock := NewMockToggleRepository()
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	toggleMock.Toggles["toggle123"] = &entity.Toggle{ID: "toggle123", Path: "checkout", AppID: "app123", Enabled: true}
+
+	rules := []*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{
+			{Type: entity.ActivationRuleTypeCountry, Value: "BR,PT"},
+			{Type: entity.ActivationRuleTypePercentage, Value: "10"},
+		}},
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: "u1"}}},
+	}
+
+	// As regras compostas têm precedência sobre a regra simples
+	legacy := &entity.ActivationRule{Type: entity.ActivationRuleTypeIP, Value: "10.0.0.1"}
+	if err := useCase.UpdateToggleWithRule("toggle123", true, true, legacy, rules, nil, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	toggle := toggleMock.Toggles["toggle123"]
+	if len(toggle.Rules) != 2 || !toggle.HasActivationRule || toggle.ActivationRule != nil {
+		t.Errorf("Expected composite rules to be saved, got %+v", toggle)
+	}
+
+	// Sem regras compostas, a regra simples vira uma regra com uma condição
+	if err := useCase.UpdateToggleWithRule("toggle123", true, true, legacy, nil, nil, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggle.Rules) != 1 || toggle.ActivationRule == nil || toggle.ActivationRule.Value != "10.0.0.1" {
+		t.Errorf("Expected legacy rule to be converted, got %+v", toggle.Rules)
+	}
+
+	invalid := []*entity.ToggleRule{{Conditions: entity.RuleConditions{}}}
+	err := useCase.UpdateToggleWithRule("toggle123", true, true, nil, invalid, nil, "app123", nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestToggleUseCase_UpdateTogglePrerequisites(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	toggleMock := NewMockToggleRepository()
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	toggleMock.Toggles["payment"] = &entity.Toggle{ID: "payment", Path: "checkout.payment", AppID: "app123", Enabled: true}
+	toggleMock.Toggles["invoice"] = &entity.Toggle{ID: "invoice", Path: "billing.invoice", AppID: "app123", Enabled: true}
+
+	prerequisites := []*entity.TogglePrerequisite{{PrerequisiteID: "invoice", Enabled: true}}
+	if err := useCase.UpdateTogglePrerequisites("payment", "app123", prerequisites, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	payment := toggleMock.Toggles["payment"]
+	if len(payment.Prerequisites) != 1 || payment.Prerequisites[0].ToggleID != "payment" {
+		t.Errorf("Expected prerequisite to be saved, got %+v", payment.Prerequisites)
+	}
+
+	// billing.invoice exigir checkout.payment fecharia um ciclo
+	err := useCase.UpdateTogglePrerequisites("invoice", "app123", []*entity.TogglePrerequisite{{PrerequisiteID: "payment", Enabled: true}}, nil)
+	appErr, ok := err.(*entity.AppError)
+	if !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if appErr.Details[0].Message != "billing.invoice -> checkout.payment -> billing.invoice" {
+		t.Errorf("Expected cycle in the error details, got %+v", appErr.Details)
+	}
+
+	err = useCase.UpdateTogglePrerequisites("payment", "other-app", prerequisites, nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Errorf("Expected validation error for another application, got %v", err)
+	}
+}
+
+func TestToggleUseCase_DeleteToggle_Prerequisite(t *testing.T) {
+	appMock := NewMockApplicationRepository()
+	toggleMock := NewMockToggleRepository()
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	toggleMock.Toggles["payment"] = &entity.Toggle{ID: "payment", Path: "payment", AppID: "app123", Enabled: true}
+	toggleMock.Toggles["invoice"] = &entity.Toggle{ID: "invoice", Path: "invoice", AppID: "app123", Enabled: true}
+	if err := useCase.UpdateTogglePrerequisites("payment", "app123", []*entity.TogglePrerequisite{{PrerequisiteID: "invoice", Enabled: true}}, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err := useCase.DeleteToggleByID("invoice", "app123", nil)
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeInUse {
+		t.Fatalf("Expected in use error, got %v", err)
+	}
+	if _, exists := toggleMock.Toggles["invoice"]; !exists {
+		t.Error("Expected prerequisite toggle to be kept")
+	}
+
+	if err := useCase.UpdateTogglePrerequisites("payment", "app123", nil, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := useCase.DeleteToggleByID("invoice", "app123", nil); err != nil {
+		t.Errorf("Expected delete to succeed once no toggle depends on it, got %v", err)
+	}
+}
+
 func TestToggleUseCase_UpdateToggleWithRule_EdgeCases(t *testing.T) {
 	appMock := NewMockApplicationRepository()
 	toggleMock := NewMockToggleRepository()
-	useCase := NewToggleUseCase(toggleMock, appMock)
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
 
 	t.Run("empty_toggle_id", func(t *testing.T) {
-		err := useCase.UpdateToggleWithRule("", true, false, nil, "app123")
+		err := useCase.UpdateToggleWithRule("", true, false, nil, nil, nil, "app123", nil)
 		if err == nil {
 			t.Errorf("Expected error for empty toggle ID")
 		}
@@ -1037,7 +1194,7 @@ func TestToggleUseCase_UpdateToggleWithRule_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("empty_app_id", func(t *testing.T) {
-		err := useCase.UpdateToggleWithRule("toggle123", true, false, nil, "")
+		err := useCase.UpdateToggleWithRule("toggle123", true, false, nil, nil, nil, "", nil)
 		if err == nil {
 			t.Errorf("Expected error for empty app ID")
 		}
@@ -1062,7 +1219,7 @@ func TestToggleUseCase_UpdateToggleWithRule_EdgeCases(t *testing.T) {
 		}
 		toggleMock.Toggles[toggleID] = toggle
 
-		err := useCase.UpdateToggleWithRule(toggleID, true, true, nil, appID)
+		err := useCase.UpdateToggleWithRule(toggleID, true, true, nil, nil, nil, appID, nil)
 		if err != nil {
 			t.Errorf("Expected no error when hasActivationRule is true but rule is nil, got: %v", err)
 		}
@@ -1077,3 +1234,344 @@ func TestToggleUseCase_UpdateToggleWithRule_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestToggleUseCase_CreateToggleWithMetadata(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	err := useCase.CreateToggleWithMetadata("checkout.new-flow", true, true, "app123", &entity.ToggleMetadata{
+		Description: "New checkout flow",
+		Owner:       "payments",
+		Tags:        []string{"Q3", "web"},
+		Kind:        entity.ToggleKindExperiment,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parent, _ := toggleMock.GetByPath("checkout", "app123")
+	if parent.Owner != "" || parent.Kind != entity.ToggleKindRelease {
+		t.Errorf("Expected intermediate toggle without metadata, got owner=%q kind=%q", parent.Owner, parent.Kind)
+	}
+
+	leaf, _ := toggleMock.GetByPath("checkout.new-flow", "app123")
+	if leaf.Owner != "payments" || leaf.Kind != entity.ToggleKindExperiment || leaf.Description != "New checkout flow" {
+		t.Errorf("Expected metadata on final toggle, got %+v", leaf)
+	}
+	if len(leaf.Tags) != 2 || leaf.Tags[0] != "q3" {
+		t.Errorf("Expected normalized tags [q3 web], got %v", leaf.Tags)
+	}
+}
+
+func TestToggleUseCase_CreateToggleWithMetadata_Invalid(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	err := useCase.CreateToggleWithMetadata("checkout", true, true, "app123", &entity.ToggleMetadata{Kind: "temporary"}, nil)
+
+	appErr, ok := err.(*entity.AppError)
+	if !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if len(toggleMock.Toggles) != 0 {
+		t.Errorf("Expected no toggles to be created, got %d", len(toggleMock.Toggles))
+	}
+}
+
+func TestToggleUseCase_UpdateToggleMetadata(t *testing.T) {
+	past := time.Now().Add(-24 * time.Hour).UTC()
+	future := time.Now().Add(30 * 24 * time.Hour).UTC()
+
+	tests := []struct {
+		name          string
+		toggle        *entity.Toggle
+		metadata      *entity.ToggleMetadata
+		expectedError string
+	}{
+		{
+			name:     "successful update",
+			toggle:   &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout"},
+			metadata: &entity.ToggleMetadata{Owner: "payments", Tags: []string{"q3"}, Kind: entity.ToggleKindOps, ExpiresAt: &future},
+		},
+		{
+			name:          "expiry in the past",
+			toggle:        &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout"},
+			metadata:      &entity.ToggleMetadata{ExpiresAt: &past},
+			expectedError: "validation failed",
+		},
+		{
+			name:     "unchanged past expiry is kept",
+			toggle:   &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", ExpiresAt: &past},
+			metadata: &entity.ToggleMetadata{Description: "overdue", ExpiresAt: &past},
+		},
+		{
+			name:          "toggle from another application",
+			toggle:        &entity.Toggle{ID: "toggle1", AppID: "other", Path: "checkout"},
+			metadata:      &entity.ToggleMetadata{},
+			expectedError: "toggle does not belong to this application",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toggleMock := NewMockToggleRepository()
+			appMock := NewMockApplicationRepository()
+			toggleMock.Toggles[tt.toggle.ID] = tt.toggle
+
+			useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+			err := useCase.UpdateToggleMetadata("toggle1", "app123", tt.metadata, nil)
+
+			if tt.expectedError != "" {
+				appErr, ok := err.(*entity.AppError)
+				if !ok || appErr.Message != tt.expectedError {
+					t.Errorf("Expected error '%s', got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			updated := toggleMock.Toggles["toggle1"]
+			if updated.Owner != tt.metadata.Owner || updated.Kind != tt.metadata.Kind {
+				t.Errorf("Expected metadata to be applied, got %+v", updated)
+			}
+		})
+	}
+}
+
+func TestToggleUseCase_GetTogglesByFilter(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Owner: "payments", Tags: entity.ToggleTags{"q3"}}
+	toggleMock.Toggles["toggle2"] = &entity.Toggle{ID: "toggle2", AppID: "app123", Path: "search", Owner: "search"}
+
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+
+	toggles, err := useCase.GetTogglesByFilter("app123", &entity.ToggleFilter{Tags: []string{"Q3"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toggles) != 1 || toggles[0].ID != "toggle1" {
+		t.Errorf("Expected only toggle1, got %d toggles", len(toggles))
+	}
+
+	toggles, err = useCase.GetTogglesByFilter("app123", &entity.ToggleFilter{})
+	if err != nil || len(toggles) != 2 {
+		t.Errorf("Expected all toggles for empty filter, got %d (%v)", len(toggles), err)
+	}
+
+	_, err = useCase.GetTogglesByFilter("app123", &entity.ToggleFilter{Kind: "temporary"})
+	if appErr, ok := err.(*entity.AppError); !ok || appErr.Code != entity.ErrCodeValidation {
+		t.Errorf("Expected validation error for invalid kind, got %v", err)
+	}
+}
+
+func TestToggleUseCase_RevisionsAndRollback(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	revisionMock := NewMockToggleRevisionRepository()
+	toggleMock.RevisionRepo = revisionMock
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Enabled: true}
+
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), revisionMock, NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	admin := &entity.User{ID: "user1", Username: "admin"}
+
+	rule := &entity.ActivationRule{Type: entity.ActivationRuleTypeUserID, Value: "u1"}
+	if err := useCase.UpdateToggleWithRule("toggle1", true, true, rule, nil, nil, "app123", admin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := useCase.UpdateToggleByID("toggle1", false, "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	history, err := useCase.GetToggleHistory("toggle1", "app123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 2 || history[0].Revision != 2 || history[1].Revision != 1 {
+		t.Fatalf("Expected 2 revisions newest first, got %+v", history)
+	}
+	if history[1].Actor != "admin" || history[0].Actor != "system" || history[1].Action != entity.RevisionActionUpdated {
+		t.Errorf("Unexpected revision authors %+v", history)
+	}
+
+	rolledBack, err := useCase.RollbackToggle("toggle1", "app123", 1, admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	restored := toggleMock.Toggles["toggle1"]
+	if !restored.Enabled || len(restored.Rules) != 1 || restored.Rules[0].Conditions[0].Value != "u1" {
+		t.Errorf("Expected revision 1 to be restored, got %+v", restored)
+	}
+	if rolledBack.Revision != 3 || rolledBack.Action != entity.RevisionActionRolledBack || rolledBack.RestoredFrom == nil || *rolledBack.RestoredFrom != 1 {
+		t.Errorf("Unexpected rollback revision %+v", rolledBack)
+	}
+
+	if _, err := useCase.RollbackToggle("toggle1", "app123", 10, admin); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if _, err := useCase.RollbackToggle("toggle1", "app123", 0, admin); !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	if _, err := useCase.GetToggleHistory("toggle1", "other"); !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error for another application, got %v", err)
+	}
+
+	// O histórico continua disponível depois da remoção
+	if err := useCase.DeleteToggleByID("toggle1", "app123", admin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	history, err = useCase.GetToggleHistory("toggle1", "app123")
+	if err != nil || len(history) != 4 || history[0].Action != entity.RevisionActionDeleted {
+		t.Errorf("Expected deleted revision in history, got %+v (%v)", history, err)
+	}
+}
+
+func TestToggleUseCase_UpdateEnabledRecursivelyChecksManagedSubtree(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	parentID, childID := "parent", "child"
+	toggleMock.Toggles["parent"] = &entity.Toggle{ID: "parent", AppID: "app123", Path: "checkout", Enabled: true}
+	toggleMock.Toggles["child"] = &entity.Toggle{ID: "child", AppID: "app123", Path: "checkout.payment", Level: 1, ParentID: &parentID, Enabled: true}
+	toggleMock.Toggles["grandchild"] = &entity.Toggle{ID: "grandchild", AppID: "app123", Path: "checkout.payment.pix", Level: 2, ParentID: &childID, Enabled: true, Managed: true}
+
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	useCase.SetManagedPolicy(entity.ManagedPolicyBlock)
+	admin := &entity.User{ID: "admin1", Username: "admin", Role: entity.UserRoleAdmin}
+
+	// Um descendente gerenciado bloqueia a operação antes de qualquer alteração
+	if err := useCase.UpdateEnabledRecursively("parent", false, "app123", admin); !isAppError(err, entity.ErrCodeManaged) {
+		t.Fatalf("Expected managed error, got %v", err)
+	}
+	for id, toggle := range toggleMock.Toggles {
+		if !toggle.Enabled {
+			t.Errorf("Expected %s to be unchanged", id)
+		}
+	}
+
+	if err := useCase.UpdateEnabledRecursively("parent", false, "app123", entity.NewSyncActor(admin)); err != nil {
+		t.Fatalf("Expected sync to update the subtree, got %v", err)
+	}
+	for id, toggle := range toggleMock.Toggles {
+		if toggle.Enabled {
+			t.Errorf("Expected %s to be disabled", id)
+		}
+	}
+}
+
+func TestToggleUseCase_FreezeWindows(t *testing.T) {
+	toggleMock := NewMockToggleRepository()
+	appMock := NewMockApplicationRepository()
+	freezeMock := NewMockFreezeWindowRepository()
+	auditMock := NewMockAuditEventRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+	toggleMock.Toggles["toggle1"] = &entity.Toggle{ID: "toggle1", AppID: "app123", Path: "checkout", Enabled: true}
+	useCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), NewMockToggleRevisionRepository(), freezeMock, auditMock)
+
+	appID := "app123"
+	now := time.Now()
+	freezeMock.Create(entity.NewFreezeWindow(&appID, now.Add(-time.Hour), now.Add(time.Hour), "black friday", []string{"release-manager"}, nil))
+
+	admin := &entity.User{ID: "admin1", Username: "admin", Role: entity.UserRoleAdmin}
+	mutations := []struct {
+		name   string
+		mutate func(actor *entity.User) error
+	}{
+		{"create", func(actor *entity.User) error {
+			return useCase.CreateToggle("search", true, true, "app123", actor)
+		}},
+		{"update", func(actor *entity.User) error {
+			return useCase.UpdateToggle("checkout", false, "app123", actor)
+		}},
+		{"update by id", func(actor *entity.User) error {
+			return useCase.UpdateToggleByID("toggle1", false, "app123", actor)
+		}},
+		{"update enabled", func(actor *entity.User) error {
+			return useCase.UpdateEnabledRecursively("toggle1", false, "app123", actor)
+		}},
+		{"update rules", func(actor *entity.User) error {
+			return useCase.UpdateToggleWithRule("toggle1", false, false, nil, nil, nil, "app123", actor)
+		}},
+		{"metadata", func(actor *entity.User) error {
+			return useCase.UpdateToggleMetadata("toggle1", "app123", &entity.ToggleMetadata{}, actor)
+		}},
+		{"prerequisites", func(actor *entity.User) error {
+			return useCase.UpdateTogglePrerequisites("toggle1", "app123", nil, actor)
+		}},
+		{"delete", func(actor *entity.User) error {
+			return useCase.DeleteToggle("checkout", "app123", actor)
+		}},
+		{"delete by id", func(actor *entity.User) error {
+			return useCase.DeleteToggleByID("toggle1", "app123", actor)
+		}},
+		{"rollback", func(actor *entity.User) error {
+			_, err := useCase.RollbackToggle("toggle1", "app123", 1, actor)
+			return err
+		}},
+	}
+	for _, tt := range mutations {
+		if err := tt.mutate(admin); !isAppError(err, entity.ErrCodeFrozen) {
+			t.Errorf("%s: expected frozen error, got %v", tt.name, err)
+		}
+	}
+	if !toggleMock.Toggles["toggle1"].Enabled || len(toggleMock.Toggles) != 1 {
+		t.Fatal("Expected no change during the freeze")
+	}
+
+	// Root sem justificativa também é bloqueado
+	root := &entity.User{ID: "root1", Username: "root", Role: entity.UserRoleRoot}
+	if err := useCase.UpdateToggleByID("toggle1", false, "app123", root); !isAppError(err, entity.ErrCodeFrozen) {
+		t.Errorf("Expected frozen error for root without justification, got %v", err)
+	}
+
+	// A justificativa só vale para root
+	admin.FreezeOverride = "incident"
+	if err := useCase.UpdateToggleByID("toggle1", false, "app123", admin); !isAppError(err, entity.ErrCodeFrozen) {
+		t.Errorf("Expected frozen error for admin override, got %v", err)
+	}
+	if len(auditMock.Events) != 0 {
+		t.Fatalf("Expected no audit events, got %d", len(auditMock.Events))
+	}
+
+	root.FreezeOverride = "INC-42 payment outage"
+	if err := useCase.UpdateToggleByID("toggle1", false, "app123", root); err != nil {
+		t.Fatalf("Expected root override, got %v", err)
+	}
+	if len(auditMock.Events) != 1 || auditMock.Events[0].Action != entity.AuditActionFreezeOverridden || auditMock.Events[0].ActorID != "root1" {
+		t.Fatalf("Expected the override to be audited, got %+v", auditMock.Events)
+	}
+	if detail := auditMock.Events[0].Detail; !strings.Contains(detail, "INC-42 payment outage") || !strings.Contains(detail, "black friday") {
+		t.Errorf("Expected justification and window reason in the audit, got %q", detail)
+	}
+
+	// Usuários isentos alteram sem justificativa
+	manager := &entity.User{ID: "manager1", Username: "release-manager", Role: entity.UserRoleAdmin}
+	if err := useCase.UpdateToggleByID("toggle1", true, "app123", manager); err != nil {
+		t.Errorf("Expected exempt user to change toggles, got %v", err)
+	}
+	if len(auditMock.Events) != 1 {
+		t.Errorf("Expected no audit for exempt users, got %d events", len(auditMock.Events))
+	}
+
+	// Janelas globais valem para todas as aplicações; janelas encerradas não bloqueiam
+	freezeMock.Windows = map[string]*entity.FreezeWindow{}
+	freezeMock.Create(entity.NewFreezeWindow(nil, now.Add(-2*time.Hour), now.Add(-time.Hour), "past", nil, nil))
+	if err := useCase.UpdateToggleByID("toggle1", false, "app123", admin); err != nil {
+		t.Errorf("Expected no freeze after the window ended, got %v", err)
+	}
+	freezeMock.Create(entity.NewFreezeWindow(nil, now.Add(-time.Hour), now.Add(time.Hour), "global", []string{"release-manager"}, nil))
+	if err := useCase.UpdateToggleByID("toggle1", false, "app123", admin); !isAppError(err, entity.ErrCodeFrozen) {
+		t.Errorf("Expected frozen error from the global window, got %v", err)
+	}
+}
+
+func isAppError(err error, code string) bool {
+	appErr, ok := err.(*entity.AppError)
+	return ok && appErr.Code == code
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/config"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/domain/repository"
+	"gorm.io/gorm"
+)
+
+// DefaultTrashPurgeInterval é o intervalo padrão entre as execuções do expurgo da lixeira
+const DefaultTrashPurgeInterval = time.Hour
+
+// TrashUseCase define os casos de uso da lixeira de toggles e aplicações
+type TrashUseCase struct {
+	toggleRepo    repository.ToggleRepository
+	appRepo       repository.ApplicationRepository
+	toggleUseCase *ToggleUseCase
+}
+
+// NewTrashUseCase cria uma nova instância de TrashUseCase
+func NewTrashUseCase(toggleRepo repository.ToggleRepository, appRepo repository.ApplicationRepository, toggleUseCase *ToggleUseCase) *TrashUseCase {
+	return &TrashUseCase{
+		toggleRepo:    toggleRepo,
+		appRepo:       appRepo,
+		toggleUseCase: toggleUseCase,
+	}
+}
+
+// GetToggleTrash lista os toggles da aplicação que estão na lixeira
+func (uc *TrashUseCase) GetToggleTrash(appID string) ([]*entity.Toggle, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+	if _, err := uc.appRepo.GetByID(appID); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found")
+	}
+
+	toggles, err := uc.toggleRepo.GetTrashByAppID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching trash")
+	}
+	return toggles, nil
+}
+
+// RestoreToggle tira um toggle da lixeira reconstruindo a hierarquia: os ancestrais que estão na
+// lixeira voltam junto, assim como os descendentes removidos na mesma operação. Cada toggle
+// restaurado ganha uma nova revisão.
+func (uc *TrashUseCase) RestoreToggle(appID string, toggleID string, actor *entity.User) ([]*entity.Toggle, error) {
+	if appID == "" || toggleID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "toggle ID and application ID are required")
+	}
+
+	trash, err := uc.GetToggleTrash(appID)
+	if err != nil {
+		return nil, err
+	}
+	trashed := make(map[string]*entity.Toggle, len(trash))
+	for _, toggle := range trash {
+		trashed[toggle.ID] = toggle
+	}
+	target, exists := trashed[toggleID]
+	if !exists {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "toggle not found in trash")
+	}
+
+	if err := uc.toggleUseCase.checkFreeze(appID, actor, "restore toggle "+target.Path+" from the trash"); err != nil {
+		return nil, err
+	}
+
+	restoring := make([]*entity.Toggle, 0)
+	for parentID := target.ParentID; parentID != nil; {
+		parent, exists := trashed[*parentID]
+		if !exists {
+			break
+		}
+		restoring = append([]*entity.Toggle{parent}, restoring...)
+		parentID = parent.ParentID
+	}
+	restoring = append(restoring, target)
+	restoring = append(restoring, deletedTogether(target, trash)...)
+
+	if err := uc.toggleUseCase.checkManaged(actor, restoring...); err != nil {
+		return nil, err
+	}
+
+	if err := uc.validateRestore(appID, restoring); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(restoring))
+	for _, toggle := range restoring {
+		ids = append(ids, toggle.ID)
+	}
+	if err := uc.toggleRepo.Undelete(ids, newRevisions(entity.RevisionActionRestored, actor, restoring...)...); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error restoring toggle")
+	}
+	for _, toggle := range restoring {
+		toggle.DeletedAt = gorm.DeletedAt{}
+	}
+	return restoring, nil
+}
+
+// validateRestore verifica se os toggles podem voltar: o caminho não pode estar em uso por outro
+// toggle, os pré-requisitos precisam existir e os segmentos das regras não podem ter sido removidos
+func (uc *TrashUseCase) validateRestore(appID string, restoring []*entity.Toggle) error {
+	live, err := uc.toggleRepo.GetByAppID(appID)
+	if err != nil {
+		return entity.NewAppError(entity.ErrCodeDatabase, "error fetching toggles")
+	}
+	paths := make(map[string]bool, len(live))
+	available := make(map[string]bool, len(live)+len(restoring))
+	for _, toggle := range live {
+		paths[toggle.Path] = true
+		available[toggle.ID] = true
+	}
+	for _, toggle := range restoring {
+		available[toggle.ID] = true
+	}
+
+	for _, toggle := range restoring {
+		if paths[toggle.Path] {
+			return entity.NewAppError(entity.ErrCodeAlreadyExists, fmt.Sprintf("a toggle with path %q already exists", toggle.Path))
+		}
+		for _, prerequisite := range toggle.Prerequisites {
+			if !available[prerequisite.PrerequisiteID] {
+				return entity.NewAppError(entity.ErrCodeValidation, fmt.Sprintf("toggle %q requires a prerequisite that is in the trash; restore it first", toggle.Path))
+			}
+		}
+		if err := uc.toggleUseCase.validateSegmentReferences(toggle.Rules, appID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletedTogether retorna os descendentes do toggle que foram para a lixeira na mesma operação
+func deletedTogether(target *entity.Toggle, trash []*entity.Toggle) []*entity.Toggle {
+	children := make(map[string][]*entity.Toggle)
+	for _, toggle := range trash {
+		if toggle.ParentID != nil {
+			children[*toggle.ParentID] = append(children[*toggle.ParentID], toggle)
+		}
+	}
+
+	descendants := make([]*entity.Toggle, 0)
+	pending := []*entity.Toggle{target}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, child := range children[current.ID] {
+			if child.DeletedAt.Time.Equal(target.DeletedAt.Time) {
+				descendants = append(descendants, child)
+				pending = append(pending, child)
+			}
+		}
+	}
+	return descendants
+}
+
+// GetApplicationTrash lista as aplicações que estão na lixeira
+func (uc *TrashUseCase) GetApplicationTrash() ([]*entity.Application, error) {
+	apps, err := uc.appRepo.GetTrash()
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching trash")
+	}
+	return apps, nil
+}
+
+// RestoreApplication tira a aplicação da lixeira com os toggles removidos junto com ela.
+// Toggles que já estavam na lixeira antes da remoção da aplicação continuam lá.
+func (uc *TrashUseCase) RestoreApplication(appID string) (*entity.Application, error) {
+	if appID == "" {
+		return nil, entity.NewAppError(entity.ErrCodeValidation, "application ID is required")
+	}
+
+	apps, err := uc.GetApplicationTrash()
+	if err != nil {
+		return nil, err
+	}
+	var app *entity.Application
+	for _, trashed := range apps {
+		if trashed.ID == appID {
+			app = trashed
+		}
+	}
+	if app == nil {
+		return nil, entity.NewAppError(entity.ErrCodeNotFound, "application not found in trash")
+	}
+
+	trash, err := uc.toggleRepo.GetTrashByAppID(appID)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching trash")
+	}
+	toggleIDs := make([]string, 0)
+	for _, toggle := range trash {
+		if toggle.DeletedAt.Time.Equal(app.DeletedAt.Time) {
+			toggleIDs = append(toggleIDs, toggle.ID)
+		}
+	}
+
+	if err := uc.appRepo.Undelete(appID, toggleIDs); err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error restoring application")
+	}
+	app.DeletedAt = gorm.DeletedAt{}
+	return app, nil
+}
+
+// Purge remove definitivamente as aplicações e os toggles que estão na lixeira há mais tempo que a retenção
+func (uc *TrashUseCase) Purge(retention time.Duration) (*entity.TrashPurgeResult, error) {
+	before := time.Now().Add(-retention)
+
+	// As aplicações primeiro: os seus toggles são removidos junto, qualquer que seja a data de remoção
+	apps, err := uc.appRepo.Purge(before)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error purging applications")
+	}
+	toggles, err := uc.toggleRepo.Purge(before)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error purging toggles")
+	}
+	return &entity.TrashPurgeResult{Applications: apps, Toggles: toggles}, nil
+}
+
+// StartPurge expurga a lixeira ao iniciar e depois periodicamente, e retorna a função para parar
+func (uc *TrashUseCase) StartPurge(retention time.Duration, interval time.Duration) func() {
+	logger := config.GetLogger("trash")
+	purge := func() {
+		result, err := uc.Purge(retention)
+		if err != nil {
+			logger.Warnf("purging trash: %v", err)
+			return
+		}
+		if result.Applications > 0 || result.Toggles > 0 {
+			logger.Infof("purged %d applications and %d toggles from the trash", result.Applications, result.Toggles)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		purge()
+		for {
+			select {
+			case <-ticker.C:
+				purge()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
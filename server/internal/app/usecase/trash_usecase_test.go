@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+)
+
+func newTrashTestUseCase() (*TrashUseCase, *ToggleUseCase, *MockToggleRepository, *MockApplicationRepository) {
+	toggleMock := NewMockToggleRepository()
+	toggleMock.RevisionRepo = NewMockToggleRevisionRepository()
+	appMock := NewMockApplicationRepository()
+	appMock.Applications["app123"] = &entity.Application{ID: "app123", Name: "Test App"}
+
+	checkoutID, paymentID := "checkout", "payment"
+	toggleMock.Toggles["checkout"] = &entity.Toggle{ID: "checkout", AppID: "app123", Path: "checkout", Value: "checkout", Enabled: true}
+	toggleMock.Toggles["payment"] = &entity.Toggle{ID: "payment", AppID: "app123", Path: "checkout.payment", Value: "payment", Level: 1, ParentID: &checkoutID, Enabled: true}
+	toggleMock.Toggles["pix"] = &entity.Toggle{ID: "pix", AppID: "app123", Path: "checkout.payment.pix", Value: "pix", Level: 2, ParentID: &paymentID, Enabled: true}
+
+	toggleUseCase := NewToggleUseCase(toggleMock, appMock, NewMockSegmentRepository(), toggleMock.RevisionRepo, NewMockFreezeWindowRepository(), NewMockAuditEventRepository())
+	return NewTrashUseCase(toggleMock, appMock, toggleUseCase), toggleUseCase, toggleMock, appMock
+}
+
+func TestTrashUseCase_RestoreToggle(t *testing.T) {
+	useCase, toggleUseCase, toggleMock, _ := newTrashTestUseCase()
+	admin := &entity.User{ID: "user1", Username: "admin"}
+
+	// Remover a folha remove os pais que ficaram sem filhos
+	if err := toggleUseCase.DeleteToggleByID("pix", "app123", admin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	trash, err := useCase.GetToggleTrash("app123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(trash) != 3 || len(toggleMock.Toggles) != 0 {
+		t.Fatalf("Expected the whole branch in the trash, got %d", len(trash))
+	}
+
+	// Restaurar a folha reconstrói a hierarquia
+	restored, err := useCase.RestoreToggle("app123", "pix", admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(restored) != 3 || restored[0].ID != "checkout" || restored[2].ID != "pix" {
+		t.Errorf("Expected ancestors to be restored first, got %+v", restored)
+	}
+	if len(toggleMock.Toggles) != 3 || len(toggleMock.Trash) != 0 {
+		t.Errorf("Expected all toggles back, got %d live and %d trashed", len(toggleMock.Toggles), len(toggleMock.Trash))
+	}
+	history, _ := toggleUseCase.GetToggleHistory("pix", "app123")
+	if len(history) != 2 || history[0].Action != entity.RevisionActionRestored {
+		t.Errorf("Expected restored revision, got %+v", history)
+	}
+
+	if _, err := useCase.RestoreToggle("app123", "pix", admin); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error for a toggle outside the trash, got %v", err)
+	}
+}
+
+func TestTrashUseCase_RestoreToggle_DeletedTogether(t *testing.T) {
+	useCase, toggleUseCase, toggleMock, _ := newTrashTestUseCase()
+
+	// O filho removido antes do pai continua na lixeira quando o pai é restaurado
+	toggleMock.trash("pix", time.Now().Add(-time.Hour))
+	if err := toggleUseCase.DeleteToggle("checkout", "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	restored, err := useCase.RestoreToggle("app123", "checkout", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(restored) != 2 || toggleMock.Trash["pix"] == nil {
+		t.Errorf("Expected only toggles deleted together to be restored, got %+v", restored)
+	}
+}
+
+func TestTrashUseCase_RestoreToggle_Conflicts(t *testing.T) {
+	useCase, toggleUseCase, toggleMock, _ := newTrashTestUseCase()
+	toggleMock.Toggles["billing"] = &entity.Toggle{ID: "billing", AppID: "app123", Path: "billing", Value: "billing", Enabled: true}
+	toggleMock.Toggles["pix"].SetPrerequisites([]*entity.TogglePrerequisite{{PrerequisiteID: "billing", Enabled: true}})
+
+	if err := toggleUseCase.DeleteToggle("checkout", "app123", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	toggleMock.trash("billing", time.Now())
+
+	// O pré-requisito está na lixeira
+	if _, err := useCase.RestoreToggle("app123", "checkout", nil); !isAppError(err, entity.ErrCodeValidation) {
+		t.Errorf("Expected validation error for a trashed prerequisite, got %v", err)
+	}
+	if _, err := useCase.RestoreToggle("app123", "billing", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// O caminho foi ocupado por outro toggle
+	toggleMock.Toggles["new-checkout"] = &entity.Toggle{ID: "new-checkout", AppID: "app123", Path: "checkout", Value: "checkout", Enabled: true}
+	if _, err := useCase.RestoreToggle("app123", "checkout", nil); !isAppError(err, entity.ErrCodeAlreadyExists) {
+		t.Errorf("Expected conflict error for a path in use, got %v", err)
+	}
+}
+
+func TestTrashUseCase_RestoreApplicationAndPurge(t *testing.T) {
+	useCase, _, toggleMock, appMock := newTrashTestUseCase()
+
+	if err := appMock.Delete("app123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	apps, err := useCase.GetApplicationTrash()
+	if err != nil || len(apps) != 1 {
+		t.Fatalf("Expected application in the trash, got %+v (%v)", apps, err)
+	}
+
+	app, err := useCase.RestoreApplication("app123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if app.DeletedAt.Valid || appMock.Applications["app123"] == nil {
+		t.Errorf("Expected application to be restored, got %+v", app)
+	}
+	if _, err := useCase.RestoreApplication("app123"); !isAppError(err, entity.ErrCodeNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	// Só o que está na lixeira há mais tempo que a retenção é expurgado
+	toggleMock.trash("pix", time.Now().Add(-48*time.Hour))
+	toggleMock.trash("payment", time.Now())
+	result, err := useCase.Purge(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Toggles != 1 || result.Applications != 0 || toggleMock.Trash["payment"] == nil {
+		t.Errorf("Unexpected purge result %+v", result)
+	}
+}
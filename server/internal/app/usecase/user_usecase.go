@@ -85,6 +85,31 @@ func (uc *UserUseCase) GetAllUsersPtr() ([]*entity.User, error) {
 	return uc.userRepo.GetAll()
 }
 
+// ListUsers lista os usuários com paginação por cursor, ordenação e filtros
+func (uc *UserUseCase) ListUsers(page *entity.PageRequest) (*entity.Page[*entity.User], error) {
+	validation := page.Normalize(entity.UserListFields)
+	validateRoleFilter(page, validation)
+	if !validation.IsValid {
+		return nil, validation.ToAppError()
+	}
+
+	result, err := uc.userRepo.List(page)
+	if err != nil {
+		return nil, entity.NewAppError(entity.ErrCodeDatabase, "error fetching users")
+	}
+
+	return result, nil
+}
+
+// validateRoleFilter valida o filtro de papel das listagens de usuários
+func validateRoleFilter(page *entity.PageRequest, validation *entity.ValidationResult) {
+	switch entity.UserRole(page.Filter("role")) {
+	case "", entity.UserRoleRoot, entity.UserRoleAdmin, entity.UserRoleUser:
+	default:
+		validation.AddError("role", "Role must be one of: root, admin, user")
+	}
+}
+
 // GetUserByID retorna um usuário pelo ID
 func (uc *UserUseCase) GetUserByID(id string) (*entity.User, error) {
 	return uc.userRepo.GetByID(id)
@@ -1,23 +1,21 @@
 package main
 
 import (
-	"github.com/manorfm/totoogle/internal/app/config"
-	"github.com/manorfm/totoogle/internal/app/router"
-)
+	"errors"
+	"os"
 
-var (
-	logger config.Logger
+	"github.com/manorfm/totoogle/internal/app/cli"
+	"github.com/manorfm/totoogle/internal/app/config"
 )
 
 func main() {
 	logger := config.GetLogger("main")
 
-	err := config.Init()
-
+	err := cli.Execute(cli.NewRootCommand(), os.Args[1:], os.Stdout, os.Stderr)
 	if err != nil {
-		logger.Errorf("config initialization error: %v", err)
-		return
+		if !errors.Is(err, cli.ErrUsage) {
+			logger.Errorf("%v", err)
+		}
+		os.Exit(1)
 	}
-
-	router.Initialize()
 }
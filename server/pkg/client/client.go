@@ -0,0 +1,335 @@
+// Package client é o SDK Go do ToToggle. Ele mantém em memória a configuração de uma
+// aplicação, obtida com uma secret key, e avalia os toggles localmente com as mesmas
+// regras e o mesmo hashing determinístico do servidor.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+)
+
+const (
+	DefaultRefreshInterval = 5 * time.Minute
+	DefaultTimeout         = 15 * time.Second
+)
+
+var (
+	ErrNotReady = errors.New("toggles not loaded yet")
+	ErrNotFound = errors.New("toggle not found")
+)
+
+// Options configura o cliente
+type Options struct {
+	ServerURL       string        // Endereço do servidor (obrigatório)
+	SecretKey       string        // Secret key da aplicação (obrigatória)
+	RefreshInterval time.Duration // Intervalo de atualização; no modo streaming é o intervalo entre reconexões
+	Streaming       bool          // Recebe as mudanças por GET /api/toggles/stream em vez de consultar periodicamente
+	BootstrapFile   string        // Arquivo com o último snapshot, usado quando o servidor não está disponível na inicialização
+	Timeout         time.Duration // Timeout das requisições de atualização
+	HTTPClient      *http.Client  // Cliente HTTP; quando informado o Timeout é ignorado
+	Logger          *log.Logger
+}
+
+// Client mantém o snapshot da aplicação atualizado em segundo plano
+type Client struct {
+	options  Options
+	http     *http.Client
+	snapshot atomic.Pointer[Snapshot]
+
+	mu        sync.Mutex
+	lastError error
+	lastSync  time.Time
+	listeners []func(*Snapshot)
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New cria um cliente com as opções informadas
+func New(options Options) (*Client, error) {
+	if options.ServerURL == "" {
+		return nil, fmt.Errorf("server URL is required")
+	}
+	if options.SecretKey == "" {
+		return nil, fmt.Errorf("secret key is required")
+	}
+	options.ServerURL = strings.TrimRight(options.ServerURL, "/")
+	if options.RefreshInterval <= 0 {
+		options.RefreshInterval = DefaultRefreshInterval
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.Logger == nil {
+		options.Logger = log.Default()
+	}
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: options.Timeout}
+	}
+
+	return &Client{options: options, http: httpClient}, nil
+}
+
+// Start carrega o arquivo de bootstrap, busca a configuração atual e inicia a atualização
+// em segundo plano. Só retorna erro quando nenhuma configuração pôde ser carregada; mesmo
+// assim a atualização continua e o cliente fica pronto quando o servidor responder.
+func (c *Client) Start(ctx context.Context) error {
+	c.LoadBootstrap()
+
+	err := c.Refresh(ctx)
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.run(loopCtx)
+	}()
+
+	if err != nil && !c.Ready() {
+		return err
+	}
+	return nil
+}
+
+// LoadBootstrap carrega o arquivo de bootstrap quando o cliente ainda não tem um snapshot. Start
+// já o carrega; chamá-lo antes permite usar o snapshot gravado sem esperar a resposta do servidor.
+func (c *Client) LoadBootstrap() {
+	if c.options.BootstrapFile == "" || c.Ready() {
+		return
+	}
+	if snapshot, err := LoadSnapshot(c.options.BootstrapFile); err == nil {
+		c.snapshot.CompareAndSwap(nil, snapshot)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		c.options.Logger.Printf("totoogle: ignoring bootstrap file %s: %v", c.options.BootstrapFile, err)
+	}
+}
+
+// Close interrompe a atualização em segundo plano
+func (c *Client) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+}
+
+// run atualiza o snapshot até o cliente ser fechado
+func (c *Client) run(ctx context.Context) {
+	ticker := time.NewTicker(c.options.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if c.options.Streaming {
+			if err := c.stream(ctx); err != nil && ctx.Err() == nil {
+				c.setError(err)
+				c.options.Logger.Printf("totoogle: stream interrupted: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Com o stream interrompido a consulta mantém o snapshot atualizado até a reconexão
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			c.options.Logger.Printf("totoogle: refresh failed: %v", err)
+		}
+	}
+}
+
+// Refresh busca a configuração atual no servidor. O ETag do snapshot atual é enviado
+// para que o servidor responda 304 quando nada mudou.
+func (c *Client) Refresh(ctx context.Context) error {
+	req, err := c.newRequest(ctx, "/api/toggles")
+	if err != nil {
+		return err
+	}
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		req.Header.Set("If-None-Match", `"`+snapshot.Version+`"`)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return c.setError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return c.setError(nil)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return c.setError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return c.setError(responseError(resp.StatusCode, data))
+	}
+	return c.setError(c.apply(data))
+}
+
+// newRequest cria uma requisição autenticada com a secret key
+func (c *Client) newRequest(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.options.ServerURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Key", c.options.SecretKey)
+	return req, nil
+}
+
+// ResponseError é uma resposta de erro do servidor. Os status 401 e 404 indicam que a
+// secret key é inválida, expirou ou foi removida.
+type ResponseError struct {
+	Status  int
+	Message string
+}
+
+// Error descreve o erro com o status HTTP e a mensagem do servidor
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("server returned %d: %s", e.Status, e.Message)
+}
+
+// responseError lê a mensagem de uma resposta de erro do servidor
+func responseError(status int, data []byte) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.Unmarshal(data, &body)
+	if body.Error == "" {
+		body.Error = http.StatusText(status)
+	}
+	return &ResponseError{Status: status, Message: body.Error}
+}
+
+// apply troca o snapshot atual, grava o bootstrap e avisa os interessados
+func (c *Client) apply(data []byte) error {
+	snapshot, err := ParseSnapshot(data)
+	if err != nil {
+		return err
+	}
+	if current := c.snapshot.Load(); current != nil && current.Version == snapshot.Version {
+		return nil
+	}
+	c.snapshot.Store(snapshot)
+
+	if c.options.BootstrapFile != "" {
+		if err := SaveSnapshot(c.options.BootstrapFile, snapshot); err != nil {
+			c.options.Logger.Printf("totoogle: could not save bootstrap file %s: %v", c.options.BootstrapFile, err)
+		}
+	}
+
+	c.mu.Lock()
+	listeners := append([]func(*Snapshot){}, c.listeners...)
+	c.mu.Unlock()
+	for _, listener := range listeners {
+		listener(snapshot)
+	}
+	return nil
+}
+
+// setError registra o resultado da última atualização e o retorna
+func (c *Client) setError(err error) error {
+	c.mu.Lock()
+	c.lastError = err
+	if err == nil {
+		c.lastSync = time.Now()
+	}
+	c.mu.Unlock()
+	return err
+}
+
+// OnChange registra uma função chamada sempre que um novo snapshot é carregado do servidor
+func (c *Client) OnChange(listener func(*Snapshot)) {
+	c.mu.Lock()
+	c.listeners = append(c.listeners, listener)
+	c.mu.Unlock()
+}
+
+// Ready informa se alguma configuração já foi carregada
+func (c *Client) Ready() bool {
+	return c.snapshot.Load() != nil
+}
+
+// LastError retorna o erro da última atualização, ou nil quando ela funcionou
+func (c *Client) LastError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastError
+}
+
+// LastSync retorna o momento da última resposta do servidor, ou zero quando ele nunca respondeu.
+// No modo streaming os heartbeats também contam.
+func (c *Client) LastSync() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastSync
+}
+
+// Snapshot retorna o snapshot atual, ou nil quando nenhum foi carregado
+func (c *Client) Snapshot() *Snapshot {
+	return c.snapshot.Load()
+}
+
+// Evaluate avalia o toggle do caminho informado. Os pais, os pré-requisitos, os segmentos
+// e o kill switch da aplicação são considerados como no servidor.
+func (c *Client) Evaluate(path string, ctx *Context) (*Result, error) {
+	snapshot := c.snapshot.Load()
+	if snapshot == nil {
+		return nil, ErrNotReady
+	}
+	toggle, ok := snapshot.models[path]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+
+	evalCtx := evaluator.Context{Segments: snapshot.segments, KillSwitch: snapshot.KillSwitch}
+	if ctx != nil {
+		evalCtx.Key = ctx.Key
+		evalCtx.UserID = ctx.UserID
+		evalCtx.Parameter = ctx.Parameter
+		evalCtx.IP = ctx.IP
+		evalCtx.Country = strings.ToUpper(strings.TrimSpace(ctx.Country))
+		evalCtx.Attributes = ctx.Attributes
+		evalCtx.Now = ctx.Now
+	}
+
+	evaluated := evaluator.Evaluate(toggle, &evalCtx)
+	result := &Result{Path: evaluated.Path, Enabled: evaluated.Enabled, Reason: Reason(evaluated.Reason)}
+	if variant := evaluated.Variant; variant != nil {
+		result.Variant = &Variant{Name: variant.Name, PayloadType: VariantPayloadType(variant.PayloadType), Payload: variant.Payload, Weight: variant.Weight}
+	}
+	return result, nil
+}
+
+// IsActive informa se o toggle está ativo para o contexto. Toggles desconhecidos ou
+// avaliados antes da primeira carga são considerados inativos.
+func (c *Client) IsActive(path string, ctx *Context) bool {
+	result, err := c.Evaluate(path, ctx)
+	return err == nil && result.Enabled
+}
+
+// Variant retorna a variante do toggle para o contexto, ou nil quando o toggle está
+// inativo ou não tem variantes
+func (c *Client) Variant(path string, ctx *Context) *Variant {
+	result, err := c.Evaluate(path, ctx)
+	if err != nil || !result.Enabled {
+		return nil
+	}
+	return result.Variant
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/manorfm/totoogle/internal/app/apitest"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/handler"
+	"gorm.io/gorm"
+)
+
+const testAppID = "01JZNM42NKSANGHZ3G4KKXGCNW"
+
+// testServer sobe o servidor completo com uma aplicação, seus toggles e uma secret key
+func testServer(t *testing.T) (server *httptest.Server, secretKey string, db *gorm.DB) {
+	t.Helper()
+	api := apitest.New(t, handler.Options{StreamPollInterval: 20 * time.Millisecond})
+	db = api.DB
+
+	db.Create(&entity.Application{ID: testAppID, Name: "Shop"})
+	appID := testAppID
+	segment := &entity.Segment{AppID: &appID, Name: "gold", Constraints: entity.SegmentConstraints{
+		{Attribute: "plan", Operator: entity.SegmentOperatorIn, Values: []string{"gold"}},
+	}}
+	db.Create(segment)
+
+	checkout := entity.NewToggle("checkout", true, "checkout", 1, nil, testAppID)
+	db.Create(checkout)
+	rollout := entity.NewToggle("new-flow", true, "checkout.new-flow", 2, &checkout.ID, testAppID)
+	rollout.Rules = []*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypePercentage, Value: "50"}}}}
+	rollout.Variants = entity.ToggleVariants{
+		{Name: "blue", PayloadType: entity.VariantPayloadJSON, Payload: json.RawMessage(`{"color":"blue"}`), Weight: 50},
+		{Name: "green", PayloadType: entity.VariantPayloadJSON, Payload: json.RawMessage(`{"color":"green"}`), Weight: 50},
+	}
+	db.Create(rollout)
+	search := api.CreateToggle(t, entity.NewToggle("search", false, "search", 1, nil, testAppID))
+	gated := entity.NewToggle("gated", true, "gated", 1, nil, testAppID)
+	gated.Prerequisites = []*entity.TogglePrerequisite{{PrerequisiteID: search.ID, Enabled: true}}
+	db.Create(gated)
+	premium := entity.NewToggle("premium", true, "premium", 1, nil, testAppID)
+	premium.Rules = []*entity.ToggleRule{
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeSegment, Value: segment.ID}}},
+		{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}}},
+	}
+	db.Create(premium)
+
+	secretKey = api.CreateSecretKey(t, testAppID, "SDK")
+
+	return api.Server, secretKey, db
+}
+
+// newTestClient cria um cliente sem logs para o servidor de teste
+func newTestClient(t *testing.T, options Options) *Client {
+	t.Helper()
+	options.Logger = log.New(io.Discard, "", 0)
+	client, err := New(options)
+	if err != nil {
+		t.Fatalf("Expected the client to be created, got %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestNew_RequiresServerAndKey(t *testing.T) {
+	if _, err := New(Options{SecretKey: "sk"}); err == nil {
+		t.Error("Expected an error without the server URL")
+	}
+	if _, err := New(Options{ServerURL: "http://localhost"}); err == nil {
+		t.Error("Expected an error without the secret key")
+	}
+}
+
+func TestClient_EvaluatesLikeTheServer(t *testing.T) {
+	server, secretKey, _ := testServer(t)
+	client := newTestClient(t, Options{ServerURL: server.URL + "/", SecretKey: secretKey})
+
+	if _, err := client.Evaluate("checkout", nil); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Expected ErrNotReady before start, got %v", err)
+	}
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Expected start to succeed, got %v", err)
+	}
+	if snapshot := client.Snapshot(); snapshot.ApplicationName != "Shop" || len(snapshot.Toggles) != 5 || len(snapshot.Segments) != 1 {
+		t.Fatalf("Unexpected snapshot %+v", snapshot)
+	}
+
+	remote := func(path string, ctx *Context) *Result {
+		body, _ := json.Marshal(map[string]interface{}{"path": path, "context": ctx})
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/evaluate", bytes.NewReader(body))
+		req.Header.Set("X-API-Key", secretKey)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected the server to evaluate %s, got %v", path, err)
+		}
+		defer resp.Body.Close()
+		var result Result
+		json.NewDecoder(resp.Body).Decode(&result)
+		return &result
+	}
+
+	contexts := []*Context{
+		{UserID: "anonymous"},
+		{UserID: "u1", IP: "10.0.0.1", Country: "br"},
+		{Key: "device-9", Attributes: map[string]string{"plan": "gold"}},
+		{UserID: "u2", IP: "10.0.0.2", Attributes: map[string]string{"plan": "free"}},
+	}
+	for i := 0; i < 40; i++ {
+		contexts = append(contexts, &Context{UserID: fmt.Sprintf("user-%d", i), IP: "10.0.0.3"})
+	}
+
+	for _, path := range []string{"checkout", "checkout.new-flow", "search", "gated", "premium"} {
+		for _, ctx := range contexts {
+			local, err := client.Evaluate(path, ctx)
+			if err != nil {
+				t.Fatalf("Expected %s to be evaluated, got %v", path, err)
+			}
+			expected := remote(path, ctx)
+			localVariant, expectedVariant := "", ""
+			if local.Variant != nil {
+				localVariant = local.Variant.Name
+			}
+			if expected.Variant != nil {
+				expectedVariant = expected.Variant.Name
+			}
+			if local.Enabled != expected.Enabled || local.Reason != expected.Reason || localVariant != expectedVariant {
+				t.Errorf("%s with %+v: local %+v (%s), server %+v (%s)", path, ctx, local, localVariant, expected, expectedVariant)
+			}
+		}
+	}
+
+	if client.IsActive("search", nil) || client.IsActive("missing", nil) {
+		t.Error("Expected disabled and unknown toggles to be inactive")
+	}
+	if _, err := client.Evaluate("missing", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if result, _ := client.Evaluate("gated", nil); result.Reason != ReasonPrerequisite {
+		t.Errorf("Expected the prerequisite to fail, got %s", result.Reason)
+	}
+	if !client.IsActive("premium", &Context{Attributes: map[string]string{"plan": "gold"}}) {
+		t.Error("Expected the segment rule to match")
+	}
+	if variant := client.Variant("search", nil); variant != nil {
+		t.Errorf("Expected no variant for an inactive toggle, got %+v", variant)
+	}
+}
+
+func TestClient_RefreshAndKillSwitch(t *testing.T) {
+	server, secretKey, db := testServer(t)
+	client := newTestClient(t, Options{ServerURL: server.URL, SecretKey: secretKey, RefreshInterval: time.Hour})
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Expected start to succeed, got %v", err)
+	}
+
+	changes := 0
+	client.OnChange(func(*Snapshot) { changes++ })
+	version := client.Snapshot().Version
+
+	// Sem mudanças o servidor responde 304 e o snapshot é mantido
+	if err := client.Refresh(context.Background()); err != nil || client.Snapshot().Version != version || changes != 0 {
+		t.Fatalf("Expected an unchanged snapshot, got %v %d", err, changes)
+	}
+
+	db.Model(&entity.Application{}).Where("id = ?", testAppID).Update("kill_switch", true)
+	if err := client.Refresh(context.Background()); err != nil || changes != 1 {
+		t.Fatalf("Expected a new snapshot, got %v %d", err, changes)
+	}
+	if result, _ := client.Evaluate("checkout", nil); result.Enabled || result.Reason != ReasonKillSwitch {
+		t.Errorf("Expected the kill switch to disable every toggle, got %+v", result)
+	}
+}
+
+func TestClient_Streaming(t *testing.T) {
+	server, secretKey, db := testServer(t)
+	client := newTestClient(t, Options{ServerURL: server.URL, SecretKey: secretKey, Streaming: true, RefreshInterval: time.Hour})
+
+	changed := make(chan *Snapshot, 1)
+	client.OnChange(func(snapshot *Snapshot) {
+		select {
+		case changed <- snapshot:
+		default:
+		}
+	})
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Expected start to succeed, got %v", err)
+	}
+	<-changed
+	if !client.IsActive("checkout", nil) {
+		t.Fatal("Expected checkout to be active")
+	}
+
+	db.Model(&entity.Toggle{}).Where("path = ?", "checkout").Update("enabled", false)
+	select {
+	case snapshot := <-changed:
+		if toggle, _ := snapshot.Toggle("checkout"); toggle.Enabled {
+			t.Error("Expected the streamed snapshot to have checkout disabled")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the streamed snapshot")
+	}
+
+	// Com o pai desligado o filho também fica inativo
+	if result, _ := client.Evaluate("checkout.new-flow", &Context{UserID: "u1"}); result.Enabled || result.Reason != ReasonParentDisabled {
+		t.Errorf("Expected the parent to disable the child, got %+v", result)
+	}
+	if client.LastError() != nil {
+		t.Errorf("Expected no error while streaming, got %v", client.LastError())
+	}
+}
+
+func TestClient_BootstrapFile(t *testing.T) {
+	server, secretKey, _ := testServer(t)
+	bootstrap := filepath.Join(t.TempDir(), "cache", "toggles.json")
+
+	online := newTestClient(t, Options{ServerURL: server.URL, SecretKey: secretKey, BootstrapFile: bootstrap})
+	if err := online.Start(context.Background()); err != nil {
+		t.Fatalf("Expected start to succeed, got %v", err)
+	}
+	online.Close()
+	if _, err := os.Stat(bootstrap); err != nil {
+		t.Fatalf("Expected the snapshot to be saved, got %v", err)
+	}
+	server.Close()
+
+	offline := newTestClient(t, Options{ServerURL: server.URL, SecretKey: secretKey, BootstrapFile: bootstrap})
+	if err := offline.Start(context.Background()); err != nil {
+		t.Fatalf("Expected the bootstrap file to be enough to start, got %v", err)
+	}
+	if !offline.IsActive("checkout", nil) || offline.LastError() == nil {
+		t.Errorf("Expected the bootstrap snapshot and the refresh error, got %v", offline.LastError())
+	}
+	if offline.Snapshot().Version != online.Snapshot().Version {
+		t.Error("Expected the bootstrap snapshot to keep the server version")
+	}
+
+	empty := newTestClient(t, Options{ServerURL: server.URL, SecretKey: secretKey, BootstrapFile: filepath.Join(t.TempDir(), "missing.json")})
+	if err := empty.Start(context.Background()); err == nil || empty.Ready() {
+		t.Error("Expected start to fail without the server and a bootstrap file")
+	}
+}
+
+func TestClient_InvalidKey(t *testing.T) {
+	server, _, _ := testServer(t)
+	client := newTestClient(t, Options{ServerURL: server.URL, SecretKey: "sk_invalid"})
+
+	err := client.Start(context.Background())
+	if err == nil || err.Error() != "server returned 404: Invalid or expired secret key" {
+		t.Errorf("Expected the server error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manorfm/totoogle/internal/app/domain/evaluator"
+	"github.com/manorfm/totoogle/internal/app/domain/rules"
+)
+
+// Snapshot é a configuração de uma aplicação como retornada por GET /api/toggles.
+// Um snapshot nunca é alterado depois de criado; cada atualização gera um novo.
+type Snapshot struct {
+	ApplicationID   string
+	ApplicationName string
+	KillSwitch      bool
+	Version         string // Mesmo valor do ETag enviado pelo servidor
+	Toggles         []*Toggle
+	Segments        []*Segment
+
+	data     []byte
+	byPath   map[string]*Toggle
+	models   map[string]*evaluator.Toggle
+	segments map[string]*evaluator.Segment
+}
+
+// snapshotPayload é o formato do corpo de GET /api/toggles
+type snapshotPayload struct {
+	Application struct {
+		ID         string     `json:"id"`
+		Name       string     `json:"name"`
+		KillSwitch bool       `json:"kill_switch"`
+		Toggles    []*Toggle  `json:"toggles"`
+		Segments   []*Segment `json:"segments"`
+	} `json:"application"`
+}
+
+// ParseSnapshot interpreta o corpo de GET /api/toggles e monta os toggles do avaliador,
+// ligados ao pai e aos pré-requisitos
+func ParseSnapshot(data []byte) (*Snapshot, error) {
+	var payload snapshotPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if payload.Application.ID == "" {
+		return nil, fmt.Errorf("invalid snapshot: missing application")
+	}
+
+	hash := sha256.Sum256(data)
+	snapshot := &Snapshot{
+		ApplicationID:   payload.Application.ID,
+		ApplicationName: payload.Application.Name,
+		KillSwitch:      payload.Application.KillSwitch,
+		Version:         hex.EncodeToString(hash[:16]),
+		Toggles:         payload.Application.Toggles,
+		Segments:        payload.Application.Segments,
+		data:            data,
+		byPath:          make(map[string]*Toggle, len(payload.Application.Toggles)),
+		models:          make(map[string]*evaluator.Toggle, len(payload.Application.Toggles)),
+		segments:        make(map[string]*evaluator.Segment, len(payload.Application.Segments)),
+	}
+
+	byID := make(map[string]*evaluator.Toggle, len(snapshot.Toggles))
+	for _, toggle := range snapshot.Toggles {
+		model := &evaluator.Toggle{Path: toggle.Path, Enabled: toggle.Enabled, Variants: make(rules.Variants, 0, len(toggle.Variants))}
+		for _, rule := range toggle.Rules {
+			conditions := make([]*evaluator.Condition, 0, len(rule.Conditions))
+			for _, condition := range rule.Conditions {
+				conditions = append(conditions, &evaluator.Condition{Type: rules.Type(condition.Type), Value: condition.Value, Config: condition.Config})
+			}
+			model.Rules = append(model.Rules, &evaluator.Rule{Position: rule.Position, Variant: rule.Variant, Conditions: conditions})
+		}
+		for _, variant := range toggle.Variants {
+			model.Variants = append(model.Variants, &rules.Variant{Name: variant.Name, PayloadType: rules.VariantPayloadType(variant.PayloadType), Payload: variant.Payload, Weight: variant.Weight})
+		}
+		byID[toggle.ID] = model
+		snapshot.byPath[toggle.Path] = toggle
+		snapshot.models[toggle.Path] = model
+	}
+	for _, toggle := range snapshot.Toggles {
+		model := byID[toggle.ID]
+		if toggle.ParentID != nil {
+			model.Parent = byID[*toggle.ParentID]
+		}
+		for _, prerequisite := range toggle.Prerequisites {
+			model.Prerequisites = append(model.Prerequisites, &evaluator.Prerequisite{ID: prerequisite.ToggleID, Enabled: prerequisite.Enabled, Toggle: byID[prerequisite.ToggleID]})
+		}
+	}
+	for _, segment := range snapshot.Segments {
+		model := &evaluator.Segment{ID: segment.ID, Name: segment.Name}
+		for _, constraint := range segment.Constraints {
+			model.Constraints = append(model.Constraints, &evaluator.Constraint{Attribute: constraint.Attribute, Operator: rules.SegmentOperator(constraint.Operator), Values: constraint.Values})
+		}
+		snapshot.segments[segment.ID] = model
+	}
+
+	return snapshot, nil
+}
+
+// Data retorna o corpo original do snapshot, no formato de GET /api/toggles
+func (s *Snapshot) Data() []byte {
+	return s.data
+}
+
+// Toggle retorna o toggle do caminho informado
+func (s *Snapshot) Toggle(path string) (*Toggle, bool) {
+	toggle, ok := s.byPath[path]
+	return toggle, ok
+}
+
+// LoadSnapshot lê um snapshot salvo em disco
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSnapshot(data)
+}
+
+// SaveSnapshot grava o snapshot em disco de forma atômica, para que um arquivo
+// interrompido no meio nunca seja lido como bootstrap
+func SaveSnapshot(path string, snapshot *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(snapshot.data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// stream recebe os snapshots enviados por GET /api/toggles/stream até a conexão cair.
+// O Last-Event-ID evita que o servidor reenvie o snapshot que o cliente já tem.
+func (c *Client) stream(ctx context.Context) error {
+	req, err := c.newRequest(ctx, "/api/toggles/stream")
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		req.Header.Set("Last-Event-ID", snapshot.Version)
+	}
+
+	// O timeout do cliente encerraria a conexão, que fica aberta indefinidamente
+	streamClient := *c.http
+	streamClient.Timeout = 0
+
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return responseError(resp.StatusCode, data)
+	}
+	c.setError(nil)
+
+	reader := bufio.NewReader(resp.Body)
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("stream closed by the server")
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if event == "snapshot" && data != "" {
+				c.setError(c.apply([]byte(data)))
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, ":"):
+			// Comentário usado como heartbeat: o servidor continua respondendo
+			c.setError(nil)
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data != "" {
+				data += "\n"
+			}
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Reason descreve por que uma avaliação chegou ao resultado
+type Reason string
+
+const (
+	ReasonDisabled       Reason = "disabled"            // O toggle está desligado
+	ReasonParentDisabled Reason = "parent_disabled"     // Algum toggle ancestral está desligado
+	ReasonParentNoMatch  Reason = "parent_no_match"     // A regra de ativação de algum toggle ancestral não foi satisfeita
+	ReasonPrerequisite   Reason = "prerequisite_failed" // Algum pré-requisito não está no estado exigido
+	ReasonRuleMatch      Reason = "rule_match"          // A regra de ativação foi satisfeita
+	ReasonRuleNoMatch    Reason = "rule_no_match"       // A regra de ativação não foi satisfeita
+	ReasonDefault        Reason = "default"             // Toggle ligado sem regra de ativação
+	ReasonKillSwitch     Reason = "kill_switch"         // O kill switch da aplicação está ativo
+)
+
+// Context são os dados usados para avaliar as regras de um toggle
+type Context struct {
+	Key        string            `json:"key"` // Identificador estável para rollouts; usa o user_id quando vazio
+	UserID     string            `json:"user_id"`
+	Parameter  string            `json:"parameter"`
+	IP         string            `json:"ip"`
+	Country    string            `json:"country"`
+	Attributes map[string]string `json:"attributes"`
+
+	Now time.Time `json:"-"` // Momento da avaliação; quando vazio é usado o horário atual
+}
+
+// Result é o resultado da avaliação de um toggle
+type Result struct {
+	Path    string   `json:"path"`
+	Enabled bool     `json:"enabled"`
+	Variant *Variant `json:"variant,omitempty"`
+	Reason  Reason   `json:"reason"`
+}
+
+// VariantPayloadType define o tipo do payload de uma variante
+type VariantPayloadType string
+
+const (
+	VariantPayloadString VariantPayloadType = "string"
+	VariantPayloadNumber VariantPayloadType = "number"
+	VariantPayloadJSON   VariantPayloadType = "json"
+)
+
+// Variant é uma variante de um toggle
+type Variant struct {
+	Name        string             `json:"name"`
+	PayloadType VariantPayloadType `json:"payload_type"`
+	Payload     json.RawMessage    `json:"payload,omitempty"`
+	Weight      int                `json:"weight"`
+}
+
+// Toggle é um toggle como enviado no snapshot da aplicação
+type Toggle struct {
+	ID            string          `json:"id"`
+	Path          string          `json:"path"`
+	Enabled       bool            `json:"enabled"`
+	ParentID      *string         `json:"parent_id"`
+	Rules         []*Rule         `json:"rules"`
+	Prerequisites []*Prerequisite `json:"prerequisites"`
+	Variants      []*Variant      `json:"variants"`
+	Description   string          `json:"description"`
+	Owner         string          `json:"owner"`
+	Tags          []string        `json:"tags"`
+	Kind          string          `json:"kind"`
+	ExpiresAt     *time.Time      `json:"expires_at"`
+}
+
+// Rule é uma regra de um toggle; todas as condições precisam ser satisfeitas
+type Rule struct {
+	Position   int          `json:"position"`
+	Conditions []*Condition `json:"conditions"`
+	Variant    string       `json:"variant,omitempty"` // Variante retornada quando a regra é satisfeita
+}
+
+// Condition é uma condição de uma regra
+type Condition struct {
+	Type   string          `json:"type"`
+	Value  string          `json:"value"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// Prerequisite é um toggle que precisa estar no estado exigido
+type Prerequisite struct {
+	ToggleID string `json:"toggle_id"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// Segment é um segmento referenciado pelas regras do tipo segment
+type Segment struct {
+	ID          string        `json:"id"`
+	AppID       *string       `json:"app_id"`
+	Name        string        `json:"name"`
+	Constraints []*Constraint `json:"constraints"`
+}
+
+// Constraint é uma restrição de um segmento sobre um atributo do contexto
+type Constraint struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+}
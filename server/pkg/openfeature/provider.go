@@ -0,0 +1,366 @@
+// Package openfeature implementa um provider OpenFeature sobre o SDK Go do ToToggle.
+// Os toggles são avaliados localmente pelo pkg/client; as variantes dão o valor das
+// avaliações de string, número e objeto.
+package openfeature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/manorfm/totoogle/pkg/client"
+)
+
+// ProviderName é o nome informado nos metadados do provider
+const ProviderName = "totoogle"
+
+// eventBuffer é o número de eventos guardados enquanto o SDK não os consome
+const eventBuffer = 16
+
+// Provider avalia os toggles de uma aplicação para o OpenFeature
+type Provider struct {
+	client *client.Client
+	events chan of.Event
+
+	mu          sync.Mutex
+	initialized bool
+	previous    *client.Snapshot
+}
+
+// New cria um provider com um cliente próprio, iniciado em Init e encerrado em Shutdown
+func New(options client.Options) (*Provider, error) {
+	c, err := client.New(options)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{client: c, events: make(chan of.Event, eventBuffer)}
+	c.OnChange(p.onChange)
+	return p, nil
+}
+
+// Client retorna o cliente usado pelo provider
+func (p *Provider) Client() *client.Client {
+	return p.client
+}
+
+// Metadata identifica o provider
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: ProviderName}
+}
+
+// Hooks retorna os hooks do provider; não há nenhum
+func (p *Provider) Hooks() []of.Hook {
+	return nil
+}
+
+// Init carrega a configuração. Sem servidor nem arquivo de bootstrap o provider fica em
+// erro, e o evento PROVIDER_READY é emitido quando o primeiro snapshot chegar.
+func (p *Provider) Init(evaluationContext of.EvaluationContext) error {
+	err := p.client.Start(context.Background())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.initialized = true
+	if snapshot := p.client.Snapshot(); snapshot != nil {
+		p.previous = snapshot
+		return nil
+	}
+	return err
+}
+
+// Shutdown encerra a atualização em segundo plano
+func (p *Provider) Shutdown() {
+	p.client.Close()
+}
+
+// EventChannel retorna os eventos emitidos quando a configuração muda
+func (p *Provider) EventChannel() <-chan of.Event {
+	return p.events
+}
+
+// onChange emite PROVIDER_READY para o primeiro snapshot recebido depois de um Init com
+// erro e PROVIDER_CONFIGURATION_CHANGED, com os toggles alterados, para os seguintes
+func (p *Provider) onChange(snapshot *client.Snapshot) {
+	p.mu.Lock()
+	previous, initialized := p.previous, p.initialized
+	p.previous = snapshot
+	p.mu.Unlock()
+
+	if !initialized {
+		return
+	}
+	if previous == nil {
+		p.emit(of.Event{
+			ProviderName:         ProviderName,
+			EventType:            of.ProviderReady,
+			ProviderEventDetails: of.ProviderEventDetails{Message: "toggles loaded"},
+		})
+		return
+	}
+	p.emit(of.Event{
+		ProviderName: ProviderName,
+		EventType:    of.ProviderConfigChange,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:       "toggles changed",
+			FlagChanges:   changedFlags(previous, snapshot),
+			EventMetadata: map[string]any{"version": snapshot.Version},
+		},
+	})
+}
+
+// emit envia o evento sem bloquear a atualização do cliente; com o buffer cheio o evento é descartado
+func (p *Provider) emit(event of.Event) {
+	select {
+	case p.events <- event:
+	default:
+	}
+}
+
+// changedFlags lista os caminhos dos toggles criados, removidos ou alterados entre dois snapshots.
+// A mudança de um pai também marca os filhos, que herdam o seu estado e as suas regras.
+// Mudanças no kill switch ou nos segmentos podem mudar qualquer avaliação, então todos são listados.
+func changedFlags(previous, current *client.Snapshot) []string {
+	all := previous.KillSwitch != current.KillSwitch || !sameJSON(previous.Segments, current.Segments)
+
+	byID := make(map[string]*client.Toggle, len(current.Toggles))
+	for _, toggle := range current.Toggles {
+		byID[toggle.ID] = toggle
+	}
+	modified := func(toggle *client.Toggle) bool {
+		old, ok := previous.Toggle(toggle.Path)
+		return !ok || !sameJSON(old, toggle)
+	}
+
+	parent := func(toggle *client.Toggle) *client.Toggle {
+		if toggle.ParentID == nil {
+			return nil
+		}
+		return byID[*toggle.ParentID]
+	}
+
+	changed := make(map[string]bool)
+	for _, toggle := range current.Toggles {
+		for ancestor := toggle; ancestor != nil; ancestor = parent(ancestor) {
+			if all || modified(ancestor) {
+				changed[toggle.Path] = true
+				break
+			}
+		}
+	}
+	for _, toggle := range previous.Toggles {
+		if _, ok := current.Toggle(toggle.Path); !ok {
+			changed[toggle.Path] = true
+		}
+	}
+
+	flags := make([]string, 0, len(changed))
+	for path := range changed {
+		flags = append(flags, path)
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+// sameJSON compara dois valores pela sua representação em JSON
+func sameJSON(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// BooleanEvaluation retorna o estado do toggle
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx of.FlattenedContext) of.BoolResolutionDetail {
+	result, detail := p.evaluate(flag, flatCtx)
+	if result == nil {
+		return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	return of.BoolResolutionDetail{Value: result.Enabled, ProviderResolutionDetail: detail}
+}
+
+// StringEvaluation retorna o payload da variante, que precisa ser do tipo string
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx of.FlattenedContext) of.StringResolutionDetail {
+	variant, detail := p.evaluateVariant(flag, flatCtx, client.VariantPayloadString)
+	if variant == nil {
+		return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	var value string
+	if err := json.Unmarshal(variant.Payload, &value); err != nil {
+		return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: parseError(flag, err)}
+	}
+	return of.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// FloatEvaluation retorna o payload da variante, que precisa ser do tipo number
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx of.FlattenedContext) of.FloatResolutionDetail {
+	variant, detail := p.evaluateVariant(flag, flatCtx, client.VariantPayloadNumber)
+	if variant == nil {
+		return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	value, err := strconv.ParseFloat(string(bytes.TrimSpace(variant.Payload)), 64)
+	if err != nil {
+		return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: parseError(flag, err)}
+	}
+	return of.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// IntEvaluation retorna o payload da variante, que precisa ser um número inteiro
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx of.FlattenedContext) of.IntResolutionDetail {
+	variant, detail := p.evaluateVariant(flag, flatCtx, client.VariantPayloadNumber)
+	if variant == nil {
+		return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	value, err := strconv.ParseInt(string(bytes.TrimSpace(variant.Payload)), 10, 64)
+	if err != nil {
+		return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(of.NewTypeMismatchResolutionError(
+			fmt.Sprintf("variant %q of %q is not an integer", variant.Name, flag)))}
+	}
+	return of.IntResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// ObjectEvaluation retorna o payload da variante decodificado, de qualquer tipo
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, flatCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	variant, detail := p.evaluateVariant(flag, flatCtx, "")
+	if variant == nil {
+		return of.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	var value any
+	if err := json.Unmarshal(variant.Payload, &value); err != nil {
+		return of.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: parseError(flag, err)}
+	}
+	return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// evaluate avalia o toggle e traduz o motivo da avaliação. O resultado é nil em caso de erro.
+func (p *Provider) evaluate(flag string, flatCtx of.FlattenedContext) (*client.Result, of.ProviderResolutionDetail) {
+	result, err := p.client.Evaluate(flag, toContext(flatCtx))
+	switch {
+	case errors.Is(err, client.ErrNotReady):
+		return nil, errorDetail(of.NewProviderNotReadyResolutionError(err.Error()))
+	case errors.Is(err, client.ErrNotFound):
+		return nil, errorDetail(of.NewFlagNotFoundResolutionError(err.Error()))
+	case err != nil:
+		return nil, errorDetail(of.NewGeneralResolutionError(err.Error()))
+	}
+
+	detail := of.ProviderResolutionDetail{
+		Reason:       reason(result.Reason),
+		FlagMetadata: of.FlagMetadata{"reason": string(result.Reason)},
+	}
+	if result.Variant != nil {
+		detail.Variant = result.Variant.Name
+	}
+	return result, detail
+}
+
+// evaluateVariant avalia o toggle e retorna a variante escolhida quando o toggle está ativo.
+// Sem variantes, ou com um payload de outro tipo, a avaliação é um TYPE_MISMATCH.
+func (p *Provider) evaluateVariant(flag string, flatCtx of.FlattenedContext, payloadType client.VariantPayloadType) (*client.Variant, of.ProviderResolutionDetail) {
+	result, detail := p.evaluate(flag, flatCtx)
+	if result == nil || !result.Enabled {
+		return nil, detail
+	}
+	if result.Variant == nil {
+		// Sem targeting key o contexto não participa da divisão entre as variantes
+		if snapshot := p.client.Snapshot(); snapshot != nil {
+			if toggle, ok := snapshot.Toggle(flag); ok && len(toggle.Variants) > 0 {
+				return nil, of.ProviderResolutionDetail{Reason: of.DefaultReason, FlagMetadata: detail.FlagMetadata}
+			}
+		}
+		return nil, errorDetail(of.NewTypeMismatchResolutionError(fmt.Sprintf("toggle %q has no variants", flag)))
+	}
+	if payloadType != "" && result.Variant.PayloadType != payloadType {
+		return nil, errorDetail(of.NewTypeMismatchResolutionError(
+			fmt.Sprintf("variant %q of %q has a %s payload", result.Variant.Name, flag, result.Variant.PayloadType)))
+	}
+	return result.Variant, detail
+}
+
+// reason traduz o motivo do avaliador para o OpenFeature: uma regra satisfeita é TARGETING_MATCH,
+// regras não satisfeitas do toggle ou de um ancestral são DEFAULT, toggles ligados sem regras são
+// STATIC e os desligados por qualquer outro motivo são DISABLED
+func reason(r client.Reason) of.Reason {
+	switch r {
+	case client.ReasonRuleMatch:
+		return of.TargetingMatchReason
+	case client.ReasonRuleNoMatch, client.ReasonParentNoMatch:
+		return of.DefaultReason
+	case client.ReasonDefault:
+		return of.StaticReason
+	}
+	return of.DisabledReason
+}
+
+// errorDetail cria o detalhe de uma avaliação com erro
+func errorDetail(resolutionError of.ResolutionError) of.ProviderResolutionDetail {
+	return of.ProviderResolutionDetail{ResolutionError: resolutionError, Reason: of.ErrorReason}
+}
+
+// parseError cria o detalhe de um payload que não pôde ser lido
+func parseError(flag string, err error) of.ProviderResolutionDetail {
+	return errorDetail(of.NewParseErrorResolutionError(fmt.Sprintf("invalid payload for %q: %v", flag, err)))
+}
+
+// toContext converte o contexto do OpenFeature no contexto das regras. O targetingKey é a
+// chave dos rollouts; user_id (ou userId), parameter, ip e country preenchem os campos de
+// mesmo nome e os demais atributos são convertidos em texto.
+func toContext(flatCtx of.FlattenedContext) *client.Context {
+	ctx := &client.Context{}
+	for name, value := range flatCtx {
+		text, ok := attributeText(value)
+		if !ok {
+			continue
+		}
+		switch name {
+		case of.TargetingKey:
+			ctx.Key = text
+		case "user_id", "userId":
+			ctx.UserID = text
+		case "parameter":
+			ctx.Parameter = text
+		case "ip":
+			ctx.IP = text
+		case "country":
+			ctx.Country = text
+		default:
+			if ctx.Attributes == nil {
+				ctx.Attributes = make(map[string]string)
+			}
+			ctx.Attributes[name] = text
+		}
+	}
+	return ctx
+}
+
+// attributeText converte o valor de um atributo em texto; valores nulos são ignorados
+func attributeText(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		return v.UTC().Format(time.RFC3339), true
+	}
+	return fmt.Sprint(value), true
+}
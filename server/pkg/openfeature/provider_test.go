@@ -0,0 +1,375 @@
+package openfeature
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"gorm.io/gorm"
+
+	"github.com/manorfm/totoogle/internal/app/apitest"
+	"github.com/manorfm/totoogle/internal/app/domain/entity"
+	"github.com/manorfm/totoogle/internal/app/handler"
+	"github.com/manorfm/totoogle/pkg/client"
+)
+
+const testAppID = "01JZNM42NKSANGHZ3G4KKXGCNW"
+
+// testServer sobe o servidor completo com uma aplicação, seus toggles e uma secret key.
+// Enquanto down estiver ligado o servidor responde 503.
+func testServer(t *testing.T) (server *httptest.Server, secretKey string, db *gorm.DB, down *atomic.Bool) {
+	t.Helper()
+	api := apitest.New(t, handler.Options{})
+	db = api.DB
+
+	db.Create(&entity.Application{ID: testAppID, Name: "Shop"})
+	appID := testAppID
+	segment := &entity.Segment{AppID: &appID, Name: "gold", Constraints: entity.SegmentConstraints{
+		{Attribute: "plan", Operator: entity.SegmentOperatorIn, Values: []string{"gold"}},
+	}}
+	db.Create(segment)
+
+	create := func(path string, enabled bool, parent *entity.Toggle, configure func(*entity.Toggle)) *entity.Toggle {
+		level, parentID := 1, (*string)(nil)
+		if parent != nil {
+			level, parentID = 2, &parent.ID
+		}
+		toggle := entity.NewToggle(path, enabled, path, level, parentID, testAppID)
+		if configure != nil {
+			configure(toggle)
+		}
+		return api.CreateToggle(t, toggle)
+	}
+	variant := func(name string, payloadType entity.VariantPayloadType, payload string) func(*entity.Toggle) {
+		return func(toggle *entity.Toggle) {
+			toggle.Variants = entity.ToggleVariants{{Name: name, PayloadType: payloadType, Payload: json.RawMessage(payload), Weight: 100}}
+		}
+	}
+
+	create("static", true, nil, nil)
+	create("off", false, nil, nil)
+	checkout := create("checkout", false, nil, nil)
+	create("checkout.new-flow", true, checkout, nil)
+	create("premium", true, nil, func(toggle *entity.Toggle) {
+		toggle.Rules = []*entity.ToggleRule{
+			{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeSegment, Value: segment.ID}}},
+			{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeCountry, Value: "BR"}}},
+			{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypeUserID, Value: "vip"}}},
+		}
+	})
+	create("rollout", true, nil, func(toggle *entity.Toggle) {
+		toggle.Rules = []*entity.ToggleRule{{Conditions: entity.RuleConditions{{Type: entity.ActivationRuleTypePercentage, Value: "50"}}}}
+	})
+	create("banner", true, nil, variant("blue", entity.VariantPayloadString, `"blue banner"`))
+	create("limit", true, nil, variant("high", entity.VariantPayloadNumber, `42`))
+	create("ratio", true, nil, variant("half", entity.VariantPayloadNumber, `0.5`))
+	create("theme", true, nil, variant("dark", entity.VariantPayloadJSON, `{"dark":true,"accent":"red"}`))
+	create("split", true, nil, func(toggle *entity.Toggle) {
+		toggle.Variants = entity.ToggleVariants{
+			{Name: "a", PayloadType: entity.VariantPayloadString, Payload: json.RawMessage(`"a"`), Weight: 50},
+			{Name: "b", PayloadType: entity.VariantPayloadString, Payload: json.RawMessage(`"b"`), Weight: 50},
+		}
+	})
+
+	secretKey = api.CreateSecretKey(t, testAppID, "OpenFeature")
+
+	return api.Server, secretKey, db, &api.Down
+}
+
+// setProvider registra um provider num domínio próprio do teste e retorna o cliente OpenFeature
+func setProvider(t *testing.T, server *httptest.Server, secretKey string) (*Provider, *of.Client, error) {
+	t.Helper()
+	provider, err := New(client.Options{
+		ServerURL:       server.URL,
+		SecretKey:       secretKey,
+		RefreshInterval: time.Hour,
+		Logger:          log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("Expected the provider to be created, got %v", err)
+	}
+
+	domain := t.Name()
+	err = of.SetNamedProviderAndWait(domain, provider)
+	t.Cleanup(func() { of.SetNamedProviderAndWait(domain, of.NoopProvider{}) })
+	return provider, of.NewClient(domain), err
+}
+
+// waitEvent espera um evento do provider
+func waitEvent(t *testing.T, events <-chan of.EventDetails) of.EventDetails {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a provider event")
+		return of.EventDetails{}
+	}
+}
+
+func TestProvider_Metadata(t *testing.T) {
+	server, secretKey, _, _ := testServer(t)
+	provider, ofClient, err := setProvider(t, server, secretKey)
+	if err != nil {
+		t.Fatalf("Expected the provider to initialize, got %v", err)
+	}
+	if provider.Metadata().Name != "totoogle" || ofClient.State() != of.ReadyState {
+		t.Errorf("Expected a ready totoogle provider, got %q %s", provider.Metadata().Name, ofClient.State())
+	}
+	if provider.Hooks() != nil {
+		t.Error("Expected no provider hooks")
+	}
+	if _, err := New(client.Options{}); err == nil {
+		t.Error("Expected the client options to be validated")
+	}
+}
+
+func TestProvider_BooleanEvaluation(t *testing.T) {
+	server, secretKey, _, _ := testServer(t)
+	_, ofClient, err := setProvider(t, server, secretKey)
+	if err != nil {
+		t.Fatalf("Expected the provider to initialize, got %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		flag         string
+		defaultValue bool
+		evalCtx      of.EvaluationContext
+		value        bool
+		reason       of.Reason
+		errorCode    of.ErrorCode
+	}{
+		{"static toggle", "static", false, of.EvaluationContext{}, true, of.StaticReason, ""},
+		{"disabled toggle", "off", true, of.EvaluationContext{}, false, of.DisabledReason, ""},
+		{"disabled parent", "checkout.new-flow", true, of.EvaluationContext{}, false, of.DisabledReason, ""},
+		{"segment attribute", "premium", false, of.NewEvaluationContext("u1", map[string]any{"plan": "gold"}), true, of.TargetingMatchReason, ""},
+		{"country attribute", "premium", false, of.NewEvaluationContext("u1", map[string]any{"country": "br"}), true, of.TargetingMatchReason, ""},
+		{"user_id attribute", "premium", false, of.NewEvaluationContext("", map[string]any{"userId": "vip"}), true, of.TargetingMatchReason, ""},
+		{"no rule matched", "premium", true, of.NewEvaluationContext("u1", map[string]any{"plan": "free", "age": 30}), false, of.DefaultReason, ""},
+		{"unknown flag", "missing", true, of.EvaluationContext{}, true, of.ErrorReason, of.FlagNotFoundCode},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			details, err := ofClient.BooleanValueDetails(context.Background(), test.flag, test.defaultValue, test.evalCtx)
+			if details.Value != test.value || details.Reason != test.reason || details.ErrorCode != test.errorCode {
+				t.Errorf("Expected %v %s %q, got %v %s %q (%v)", test.value, test.reason, test.errorCode, details.Value, details.Reason, details.ErrorCode, err)
+			}
+			if (test.errorCode != "") != (err != nil) {
+				t.Errorf("Expected an error only with an error code, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProvider_TargetingKeyMatchesTheSDK(t *testing.T) {
+	server, secretKey, _, _ := testServer(t)
+	provider, ofClient, err := setProvider(t, server, secretKey)
+	if err != nil {
+		t.Fatalf("Expected the provider to initialize, got %v", err)
+	}
+
+	enabled := 0
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		value, _ := ofClient.BooleanValue(context.Background(), "rollout", false, of.NewEvaluationContext(key, nil))
+		if value != provider.Client().IsActive("rollout", &client.Context{Key: key}) {
+			t.Errorf("Expected the targeting key %q to be the rollout key", key)
+		}
+		if value {
+			enabled++
+		}
+	}
+	if enabled == 0 || enabled == 12 {
+		t.Errorf("Expected the 50%% rollout to split the keys, got %d of 12", enabled)
+	}
+}
+
+func TestProvider_VariantEvaluation(t *testing.T) {
+	server, secretKey, _, _ := testServer(t)
+	_, ofClient, err := setProvider(t, server, secretKey)
+	if err != nil {
+		t.Fatalf("Expected the provider to initialize, got %v", err)
+	}
+	ctx := context.Background()
+	evalCtx := of.EvaluationContext{}
+
+	banner, err := ofClient.StringValueDetails(ctx, "banner", "none", evalCtx)
+	if err != nil || banner.Value != "blue banner" || banner.Variant != "blue" || banner.Reason != of.StaticReason {
+		t.Errorf("Unexpected string details %+v %v", banner, err)
+	}
+	if banner.FlagMetadata["reason"] != "default" {
+		t.Errorf("Expected the toggle reason in the metadata, got %+v", banner.FlagMetadata)
+	}
+
+	limit, err := ofClient.IntValueDetails(ctx, "limit", 1, evalCtx)
+	if err != nil || limit.Value != 42 || limit.Variant != "high" {
+		t.Errorf("Unexpected int details %+v %v", limit, err)
+	}
+	ratio, err := ofClient.FloatValueDetails(ctx, "ratio", 1, evalCtx)
+	if err != nil || ratio.Value != 0.5 {
+		t.Errorf("Unexpected float details %+v %v", ratio, err)
+	}
+	theme, err := ofClient.ObjectValueDetails(ctx, "theme", nil, evalCtx)
+	if value, ok := theme.Value.(map[string]any); err != nil || !ok || value["accent"] != "red" || value["dark"] != true {
+		t.Errorf("Unexpected object details %+v %v", theme, err)
+	}
+
+	// Tipos incompatíveis retornam o valor padrão com TYPE_MISMATCH
+	mismatches := []func() (any, of.ResolutionDetail){
+		func() (any, of.ResolutionDetail) {
+			d, _ := ofClient.StringValueDetails(ctx, "theme", "none", evalCtx)
+			return d.Value, d.ResolutionDetail
+		},
+		func() (any, of.ResolutionDetail) {
+			d, _ := ofClient.IntValueDetails(ctx, "ratio", 1, evalCtx)
+			return d.Value, d.ResolutionDetail
+		},
+		func() (any, of.ResolutionDetail) {
+			d, _ := ofClient.FloatValueDetails(ctx, "banner", 1, evalCtx)
+			return d.Value, d.ResolutionDetail
+		},
+		func() (any, of.ResolutionDetail) {
+			d, _ := ofClient.ObjectValueDetails(ctx, "static", "none", evalCtx)
+			return d.Value, d.ResolutionDetail
+		},
+	}
+	for i, mismatch := range mismatches {
+		value, detail := mismatch()
+		if detail.ErrorCode != of.TypeMismatchCode || detail.Reason != of.ErrorReason {
+			t.Errorf("Mismatch %d: expected TYPE_MISMATCH, got %+v", i, detail)
+		}
+		if value != "none" && value != int64(1) && value != float64(1) {
+			t.Errorf("Mismatch %d: expected the default value, got %v", i, value)
+		}
+	}
+
+	// Sem targeting key o contexto não participa da divisão entre as variantes
+	split, err := ofClient.StringValueDetails(ctx, "split", "none", evalCtx)
+	if err != nil || split.Value != "none" || split.Reason != of.DefaultReason {
+		t.Errorf("Unexpected details for an anonymous split %+v %v", split, err)
+	}
+	split, err = ofClient.StringValueDetails(ctx, "split", "none", of.NewEvaluationContext("user-1", nil))
+	if err != nil || (split.Value != "a" && split.Value != "b") {
+		t.Errorf("Expected a variant for a targeting key, got %+v %v", split, err)
+	}
+
+	// Um toggle desligado retorna o valor padrão sem erro
+	off, err := ofClient.StringValueDetails(ctx, "off", "none", evalCtx)
+	if err != nil || off.Value != "none" || off.Reason != of.DisabledReason {
+		t.Errorf("Unexpected details for a disabled toggle %+v %v", off, err)
+	}
+}
+
+func TestProvider_ConfigurationChangedEvent(t *testing.T) {
+	server, secretKey, db, _ := testServer(t)
+	provider, ofClient, err := setProvider(t, server, secretKey)
+	if err != nil {
+		t.Fatalf("Expected the provider to initialize, got %v", err)
+	}
+
+	events := make(chan of.EventDetails, 4)
+	callback := func(details of.EventDetails) { events <- details }
+	ofClient.AddHandler(of.ProviderConfigChange, &callback)
+
+	db.Model(&entity.Toggle{}).Where("path = ?", "checkout").Update("enabled", true)
+	if err := provider.Client().Refresh(context.Background()); err != nil {
+		t.Fatalf("Expected the refresh to succeed, got %v", err)
+	}
+
+	event := waitEvent(t, events)
+	expected := []string{"checkout", "checkout.new-flow"}
+	if len(event.FlagChanges) != 2 || event.FlagChanges[0] != expected[0] || event.FlagChanges[1] != expected[1] {
+		t.Errorf("Expected the toggle and its child to be reported, got %v", event.FlagChanges)
+	}
+	if value, _ := ofClient.BooleanValue(context.Background(), "checkout.new-flow", false, of.EvaluationContext{}); !value {
+		t.Error("Expected the child to be active after the change")
+	}
+
+	// O kill switch afeta todos os toggles
+	db.Model(&entity.Application{}).Where("id = ?", testAppID).Update("kill_switch", true)
+	provider.Client().Refresh(context.Background())
+	if event := waitEvent(t, events); len(event.FlagChanges) != 11 {
+		t.Errorf("Expected every toggle to be reported, got %v", event.FlagChanges)
+	}
+	details, _ := ofClient.BooleanValueDetails(context.Background(), "static", true, of.EvaluationContext{})
+	if details.Value || details.Reason != of.DisabledReason || details.FlagMetadata["reason"] != "kill_switch" {
+		t.Errorf("Expected the kill switch to disable the toggle, got %+v", details)
+	}
+}
+
+func TestProvider_ReadyAfterFailedInit(t *testing.T) {
+	server, secretKey, _, down := testServer(t)
+	down.Store(true)
+
+	provider, err := New(client.Options{ServerURL: server.URL, SecretKey: secretKey, RefreshInterval: time.Hour, Logger: log.New(io.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("Expected the provider to be created, got %v", err)
+	}
+	domain := t.Name()
+	ofClient := of.NewClient(domain)
+	t.Cleanup(func() { of.SetNamedProviderAndWait(domain, of.NoopProvider{}) })
+
+	// Com a inicialização assíncrona o erro é informado como evento e o provider continua
+	// registrado. Um handler adicionado depois do erro também é chamado.
+	of.SetNamedProvider(domain, provider)
+	failures := make(chan of.EventDetails, 1)
+	onError := func(details of.EventDetails) { failures <- details }
+	ofClient.AddHandler(of.ProviderError, &onError)
+	if event := waitEvent(t, failures); ofClient.State() != of.ErrorState {
+		t.Fatalf("Expected the provider to fail without a snapshot, got %s %+v", ofClient.State(), event)
+	}
+	details, _ := ofClient.BooleanValueDetails(context.Background(), "static", false, of.EvaluationContext{})
+	if details.Value || details.Reason != of.ErrorReason || details.ErrorCode != of.ProviderNotReadyCode {
+		t.Errorf("Expected the default value with an error, got %+v", details)
+	}
+
+	events := make(chan of.EventDetails, 1)
+	onReady := func(details of.EventDetails) { events <- details }
+	ofClient.AddHandler(of.ProviderReady, &onReady)
+
+	down.Store(false)
+	provider.Client().Refresh(context.Background())
+	waitEvent(t, events)
+
+	if ofClient.State() != of.ReadyState {
+		t.Errorf("Expected the provider to be ready, got %s", ofClient.State())
+	}
+	if value, _ := ofClient.BooleanValue(context.Background(), "static", false, of.EvaluationContext{}); !value {
+		t.Error("Expected the toggle to be evaluated once the provider is ready")
+	}
+}
+
+func TestToContext(t *testing.T) {
+	ctx := toContext(of.FlattenedContext{
+		of.TargetingKey: "device-1",
+		"user_id":       "u1",
+		"parameter":     "beta",
+		"ip":            "10.0.0.1",
+		"country":       "BR",
+		"age":           int64(30),
+		"score":         1.5,
+		"admin":         true,
+		"since":         time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		"empty":         nil,
+	})
+
+	if ctx.Key != "device-1" || ctx.UserID != "u1" || ctx.Parameter != "beta" || ctx.IP != "10.0.0.1" || ctx.Country != "BR" {
+		t.Errorf("Unexpected context fields %+v", ctx)
+	}
+	expected := map[string]string{"age": "30", "score": "1.5", "admin": "true", "since": "2025-01-02T03:04:05Z"}
+	if len(ctx.Attributes) != len(expected) {
+		t.Errorf("Unexpected attributes %v", ctx.Attributes)
+	}
+	for name, value := range expected {
+		if ctx.Attributes[name] != value {
+			t.Errorf("Expected attribute %s=%s, got %q", name, value, ctx.Attributes[name])
+		}
+	}
+}
@@ -33,6 +33,7 @@ function initializeEventListeners() {
     document.getElementById('app-form').addEventListener('submit', handleCreateApplication);
     document.getElementById('toggle-form').addEventListener('submit', handleCreateToggle);
     document.getElementById('edit-toggle-form').addEventListener('submit', handleUpdateToggle);
+    document.getElementById('explain-form').addEventListener('submit', handleExplain);
     
     // Event listener para o checkbox de regras de ativação
     document.getElementById('edit-toggle-activation-rule-input').addEventListener('change', function() {
@@ -710,7 +711,7 @@ async function handleUpdateToggle(event) {
         loadToggles(currentAppId);
         editingToggleId = null;
     } catch (error) {
-        showError('Error saving toggle');
+        showError(error.message || 'Error saving toggle');
     }
 }
 
@@ -794,6 +795,12 @@ function renderToggles(toggles) {
                 <div class="toggle-card-header">
                     <div class="toggle-header-left"><span class="toggle-status-dot">${statusSVG}</span></div>
                     <div class="toggle-header-right">
+                        <button class="icon-btn" title="Explicar Avaliação" onclick="openExplainModal('${pathStr}')">
+                            <svg width="16" height="16" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2">
+                                <circle cx="11" cy="11" r="8"/>
+                                <line x1="21" y1="21" x2="16.65" y2="16.65"/>
+                            </svg>
+                        </button>
                         ${isAdminOrRoot ? `
                         <button class="icon-btn danger" title="Excluir Toggle" onclick="deleteToggle('${toggle.id}', '${pathStr}')">
                             <svg width="16" height="16" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2">
@@ -816,6 +823,68 @@ function renderToggles(toggles) {
     }).join('');
 }
 
+// Funções de explicação da avaliação
+let explainingTogglePath = null;
+
+function openExplainModal(path) {
+    explainingTogglePath = path;
+    document.getElementById('explain-modal-subtitle').textContent = `What would this user see for "${path}"?`;
+    document.getElementById('explain-result').classList.add('hidden');
+    openModal('explain-modal');
+}
+
+async function handleExplain(event) {
+    event.preventDefault();
+    if (!explainingTogglePath) return;
+
+    let context;
+    try {
+        context = JSON.parse(document.getElementById('explain-context-input').value || '{}');
+    } catch (e) {
+        showError('Context must be valid JSON');
+        return;
+    }
+
+    try {
+        const trace = await apiCall(`/applications/${currentAppId}/explain`, {
+            method: 'POST',
+            body: JSON.stringify({ path: explainingTogglePath, context })
+        });
+        renderExplainTrace(trace);
+    } catch (error) {
+        showError(error.message || 'Error explaining toggle evaluation');
+    }
+}
+
+function renderExplainTrace(trace) {
+    const escape = text => String(text).replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+    const mark = ok => ok ? '✅' : '❌';
+    const lines = [];
+
+    lines.push(`<strong>${mark(trace.result.enabled)} ${escape(trace.result.path)}: ${escape(trace.result.reason)}${trace.result.variant ? ` (variant ${escape(trace.result.variant.name)})` : ''}</strong>`);
+    trace.ancestors.forEach(ancestor => lines.push(`${mark(ancestor.enabled)} ancestor ${escape(ancestor.path)}`));
+    lines.push(`${mark(trace.enabled)} toggle ${escape(trace.result.path)}`);
+    trace.prerequisites.forEach(prerequisite => {
+        const state = prerequisite.path
+            ? `${escape(prerequisite.path)} is ${prerequisite.actual ? 'on' : 'off'} (${escape(prerequisite.reason)}), requires ${prerequisite.required ? 'on' : 'off'}`
+            : escape(prerequisite.detail);
+        lines.push(`${mark(prerequisite.met)} prerequisite of ${escape(prerequisite.declared_by)}: ${state}`);
+    });
+    trace.rules.forEach((rule, idx) => {
+        lines.push(`${mark(rule.matched)} rule ${idx + 1}${rule.selected ? ' (selected)' : ''}${rule.variant ? ` → ${escape(rule.variant)}` : ''}`);
+        rule.conditions.forEach(condition => {
+            lines.push(`&nbsp;&nbsp;&nbsp;&nbsp;${mark(condition.matched)} ${escape(condition.type)}: ${escape(condition.detail || condition.value)}`);
+        });
+    });
+    if (trace.variant) {
+        lines.push(`variant bucket ${trace.variant.bucket} for key "${escape(trace.variant.key)}"`);
+    }
+
+    const result = document.getElementById('explain-result');
+    result.innerHTML = lines.map(line => `<div>${line}</div>`).join('');
+    result.classList.remove('hidden');
+}
+
 // Funções de Edição
 function editToggle(path, enabled) {
     lastEditedTogglePath = path;
@@ -847,7 +916,11 @@ async function editTogglePath(toggleId) {
             document.getElementById('activation-rule-value').value = '';
         }
         
-        document.getElementById('edit-toggle-title').textContent = 'Edit Toggle';
+        // Toggles gerenciados pela sincronização voltam ao estado do arquivo de flags na próxima execução
+        document.getElementById('edit-toggle-title').textContent = toggle.managed ? 'Edit Toggle (managed by sync)' : 'Edit Toggle';
+        if (toggle.managed) {
+            showWarning('This toggle is managed by the flags file. Manual changes will be overwritten by the next sync.');
+        }
         openModal('edit-toggle-modal');
     } catch (e) {
         showError('Error finding toggle for editing');
@@ -874,7 +947,7 @@ async function deleteToggle(toggleId, togglePath) {
     const confirmModal = createConfirmModal(
         'Delete Toggle',
         `Are you sure you want to delete the toggle "${togglePath}"?`,
-        'This action will permanently delete this toggle and all its child toggles. This action cannot be undone.',
+        'This toggle and all its child toggles will be moved to the trash. They can be restored until the trash is purged.',
         'Delete',
         'Cancel'
     );
@@ -1121,7 +1194,7 @@ async function deleteApplication(appId, appName) {
     const confirmModal = createConfirmModal(
         'Delete Application',
         `Are you sure you want to delete the application "${appName}"?`,
-        '⚠️ WARNING: This action will permanently delete this application and ALL its toggles. This action cannot be undone.',
+        '⚠️ WARNING: This application and ALL its toggles will be moved to the trash. A root user can restore them until the trash is purged.',
         'Delete',
         'Cancel',
         'warning'
@@ -1822,7 +1895,7 @@ function updateRuleValueHints(ruleType) {
             description: 'Specific user identifiers for targeted activation'
         },
         'ip': {
-            text: 'Enter IP addresses or ranges (e.g., "192.168.1.1" or "10.0.0.0/24")',
+            text: 'Enter comma-separated IPv4/IPv6 addresses or CIDR blocks (e.g., "192.168.1.1, 10.0.0.0/24, 2001:db8::/32")',
             description: 'IP addresses or CIDR ranges for geo-targeted activation'
         },
         'country': {
@@ -1834,8 +1907,8 @@ function updateRuleValueHints(ruleType) {
             description: 'Time windows when the toggle should be active'
         },
         'canary': {
-            text: 'Enter deployment version or environment (e.g., "v2.1.0", "staging")',
-            description: 'Version or environment identifier for canary deployments'
+            text: 'Enter the canary instance IDs (e.g., "pod-a, pod-b")',
+            description: 'Instances whose instance_id attribute is listed get the toggle on'
         }
     };
     
@@ -1896,10 +1969,10 @@ function updateRuleValuePlaceholder(ruleType) {
         'percentage': 'e.g., 25',
         'parameter': 'e.g., premium_user',
         'user_id': 'e.g., user123, user456',
-        'ip': 'e.g., 192.168.1.1',
+        'ip': 'e.g., 192.168.1.1, 10.0.0.0/24, 2001:db8::/32',
         'country': 'e.g., US, BR, CA',
         'time': 'e.g., 09:00-17:00',
-        'canary': 'e.g., v2.1.0'
+        'canary': 'e.g., pod-a, pod-b'
     };
     
     inputElement.placeholder = placeholders[ruleType] || 'Enter rule value...';